@@ -1,7 +1,11 @@
 package main
 
 import (
+	"archive/tar"
+	"archive/zip"
 	"bytes"
+	"compress/gzip"
+	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -586,3 +590,869 @@ func TestIntegration_ExcelConversion(t *testing.T) {
 		t.Error("Round-trip data missing expected values")
 	}
 }
+
+// Test extracting tables from a prose document
+func TestIntegration_DocumentTables(t *testing.T) {
+	tmpDir := t.TempDir()
+	document := `# Inventory
+
+Some prose.
+
+| name | qty |
+|------|-----|
+| tea  | 3   |
+
+More prose.
+
+ id | owner
+----+-------
+  1 | Ann
+(1 row)
+`
+	docFile := filepath.Join(tmpDir, "notes.md")
+	if err := os.WriteFile(docFile, []byte(document), 0644); err != nil {
+		t.Fatalf("Failed to write document: %v", err)
+	}
+
+	t.Run("select table", func(t *testing.T) {
+		stdout, stderr, exitCode := runMorph(t, "-table", "2", "-out", "csv", docFile)
+		if exitCode != 0 {
+			t.Fatalf("morph exited with code %d, stderr: %s", exitCode, stderr)
+		}
+		if stdout != "id,owner\n1,Ann\n" {
+			t.Errorf("unexpected output: %q", stdout)
+		}
+	})
+
+	t.Run("all tables to stdout", func(t *testing.T) {
+		stdout, stderr, exitCode := runMorphWithStdin(t, document, "-table", "all", "-out", "csv")
+		if exitCode != 0 {
+			t.Fatalf("morph exited with code %d, stderr: %s", exitCode, stderr)
+		}
+		if stdout != "name,qty\ntea,3\n\nid,owner\n1,Ann\n" {
+			t.Errorf("unexpected output: %q", stdout)
+		}
+	})
+
+	t.Run("all tables to numbered files", func(t *testing.T) {
+		output := filepath.Join(tmpDir, "tables.json")
+		_, stderr, exitCode := runMorph(t, "-table", "all", docFile, output)
+		if exitCode != 0 {
+			t.Fatalf("morph exited with code %d, stderr: %s", exitCode, stderr)
+		}
+		for _, name := range []string{"tables-1.json", "tables-2.json"} {
+			if _, err := os.Stat(filepath.Join(tmpDir, name)); err != nil {
+				t.Errorf("expected %s to be written: %v", name, err)
+			}
+		}
+	})
+
+	t.Run("all tables to files with the CSV output dialect", func(t *testing.T) {
+		output := filepath.Join(tmpDir, "dialect.csv")
+		_, stderr, exitCode := runMorph(t, "-table", "all", "-out-delimiter", ";", docFile, output)
+		if exitCode != 0 {
+			t.Fatalf("morph exited with code %d, stderr: %s", exitCode, stderr)
+		}
+		for name, want := range map[string]string{
+			"dialect-1.csv": "name;qty\ntea;3\n",
+			"dialect-2.csv": "id;owner\n1;Ann\n",
+		} {
+			got, err := os.ReadFile(filepath.Join(tmpDir, name))
+			if err != nil {
+				t.Fatalf("expected %s to be written: %v", name, err)
+			}
+			if string(got) != want {
+				t.Errorf("%s = %q, want %q", name, got, want)
+			}
+		}
+	})
+
+	t.Run("table out of range", func(t *testing.T) {
+		_, stderr, exitCode := runMorph(t, "-table", "5", "-out", "csv", docFile)
+		if exitCode != 2 {
+			t.Errorf("expected exit code 2, got %d", exitCode)
+		}
+		if !strings.Contains(stderr, "has 2 table(s)") {
+			t.Errorf("unexpected error message: %s", stderr)
+		}
+	})
+}
+
+// Test fixed-width conversion with declared and inferred columns
+func TestIntegration_FixedWidth(t *testing.T) {
+	tmpDir := t.TempDir()
+	specFile := filepath.Join(tmpDir, "layout.txt")
+	spec := "# id, name and amount\nid:1-4:right\nname:5-14\namount:15-22:right\n"
+	if err := os.WriteFile(specFile, []byte(spec), 0644); err != nil {
+		t.Fatalf("Failed to write spec file: %v", err)
+	}
+
+	input := "0001Alice Smit00012.50\n0022Bob       00007.00\n"
+	stdout, stderr, exitCode := runMorphWithStdin(t, input,
+		"-in", "fixed", "-columns-file", specFile, "-pad-char", "0", "-out", "csv")
+	if exitCode != 0 {
+		t.Fatalf("morph exited with code %d, stderr: %s", exitCode, stderr)
+	}
+	if stdout != "id,name,amount\n1,Alice Smit,12.50\n22,Bob,7.00\n" {
+		t.Errorf("unexpected CSV output: %q", stdout)
+	}
+
+	// Zero padding round-trips through declared right-aligned columns
+	zeroPadded, stderr, exitCode := runMorphWithStdin(t, "a,b\n100,x0\n7,y\n",
+		"-in", "csv", "-out", "fixed", "-columns", "a:1-4:right,b:6-8", "-pad-char", "0")
+	if exitCode != 0 {
+		t.Fatalf("morph exited with code %d, stderr: %s", exitCode, stderr)
+	}
+	if zeroPadded != "0100 x0 \n0007 y  \n" {
+		t.Errorf("unexpected zero-padded output: %q", zeroPadded)
+	}
+	stdout, stderr, exitCode = runMorphWithStdin(t, zeroPadded,
+		"-in", "fixed", "-columns", "a:1-4:right,b:6-8", "-pad-char", "0", "-out", "csv")
+	if exitCode != 0 {
+		t.Fatalf("morph exited with code %d, stderr: %s", exitCode, stderr)
+	}
+	if stdout != "a,b\n100,x0\n7,y\n" {
+		t.Errorf("unexpected zero-padded round trip: %q", stdout)
+	}
+
+	// Without declared columns zeros could not be told from values
+	if _, stderr, exitCode = runMorphWithStdin(t, "a,b\n100,x\n7,y\n", "-in", "csv", "-out", "fixed", "-pad-char", "0"); exitCode != 2 {
+		t.Errorf("expected exit code 2 for -pad-char 0 without -columns, got %d, stderr: %s", exitCode, stderr)
+	}
+
+	// Sized-to-fit output reads back with inferred columns
+	fixed, stderr, exitCode := runMorphWithStdin(t, "name,city\nAlice Smith,Paris\nBob,Rome\n", "-in", "csv", "-out", "fixed")
+	if exitCode != 0 {
+		t.Fatalf("morph exited with code %d, stderr: %s", exitCode, stderr)
+	}
+	stdout, stderr, exitCode = runMorphWithStdin(t, fixed, "-in", "fixed", "-out", "csv")
+	if exitCode != 0 {
+		t.Fatalf("morph exited with code %d, stderr: %s", exitCode, stderr)
+	}
+	if stdout != "name,city\nAlice Smith,Paris\nBob,Rome\n" {
+		t.Errorf("unexpected round-trip output: %q", stdout)
+	}
+}
+
+func TestIntegration_VerticalStylesRoundTrip(t *testing.T) {
+	input := "id,name,note\n1,Alice,\n2,Bob Smith,\"a\nb\"\n"
+	for _, style := range []string{"psql-expanded", "mysql-vertical", "sqlite-line"} {
+		t.Run(style, func(t *testing.T) {
+			vertical, stderr, exitCode := runMorphWithStdin(t, input, "-in", "csv", "-out", "ascii", "-f", style)
+			if exitCode != 0 {
+				t.Fatalf("morph exited with code %d, stderr: %s", exitCode, stderr)
+			}
+			stdout, stderr, exitCode := runMorphWithStdin(t, vertical, "-in", "ascii", "-out", "csv")
+			if exitCode != 0 {
+				t.Fatalf("morph exited with code %d, stderr: %s", exitCode, stderr)
+			}
+			if stdout != input {
+				t.Errorf("unexpected round-trip output: %q\nfrom:\n%s", stdout, vertical)
+			}
+		})
+	}
+}
+
+func TestIntegration_AutoInputFormat(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	// Standard input without -in is detected from its content
+	stdout, stderr, exitCode := runMorphWithStdin(t, `[{"name":"Alice"}]`, "-out", "csv")
+	if exitCode != 0 {
+		t.Fatalf("morph exited with code %d, stderr: %s", exitCode, stderr)
+	}
+	if stdout != "name\nAlice\n" {
+		t.Errorf("unexpected CSV output: %q", stdout)
+	}
+
+	// A workbook without an extension is recognised by its ZIP signature
+	xlsxFile := filepath.Join(tmpDir, "data.xlsx")
+	if _, stderr, exitCode := runMorphWithStdin(t, "name,age\nBob,25\n", "-in", "csv", "-", xlsxFile); exitCode != 0 {
+		t.Fatalf("morph exited with code %d, stderr: %s", exitCode, stderr)
+	}
+	exportFile := filepath.Join(tmpDir, "export")
+	if err := os.Rename(xlsxFile, exportFile); err != nil {
+		t.Fatalf("Failed to rename workbook: %v", err)
+	}
+	stdout, stderr, exitCode = runMorph(t, "-out", "csv", exportFile)
+	if exitCode != 0 {
+		t.Fatalf("morph exited with code %d, stderr: %s", exitCode, stderr)
+	}
+	if stdout != "name,age\nBob,25\n" {
+		t.Errorf("unexpected CSV output: %q", stdout)
+	}
+
+	// -detect explains the choice
+	stdout, stderr, exitCode = runMorph(t, "-detect", exportFile)
+	if exitCode != 0 {
+		t.Fatalf("morph exited with code %d, stderr: %s", exitCode, stderr)
+	}
+	if !strings.Contains(stdout, "OOXML") || !strings.Contains(stdout, "detected: excel") {
+		t.Errorf("unexpected detection report: %q", stdout)
+	}
+
+	// Tab- and semicolon-separated text is read with its own delimiter
+	for _, input := range []string{"name\tage\nBob\t25\n", "name;age\nBob;25\n"} {
+		stdout, stderr, exitCode = runMorphWithStdin(t, input, "-out", "csv")
+		if exitCode != 0 {
+			t.Fatalf("morph exited with code %d, stderr: %s", exitCode, stderr)
+		}
+		if stdout != "name,age\nBob,25\n" {
+			t.Errorf("unexpected CSV output for %q: %q", input, stdout)
+		}
+	}
+
+	// Content that matches no format asks for -in
+	_, stderr, exitCode = runMorphWithStdin(t, "just some words\n", "-out", "csv")
+	if exitCode == 0 || !strings.Contains(stderr, "cannot detect input format") {
+		t.Errorf("expected a detection error, got exit %d: %s", exitCode, stderr)
+	}
+}
+
+func TestIntegration_CompressedFiles(t *testing.T) {
+	tmpDir := t.TempDir()
+	inputFile := filepath.Join(tmpDir, "data.csv")
+	if err := os.WriteFile(inputFile, []byte("name\nAlice\n"), 0644); err != nil {
+		t.Fatalf("Failed to write input file: %v", err)
+	}
+
+	for _, suffix := range []string{".gz", ".zst", ".bz2", ".xz"} {
+		t.Run(suffix, func(t *testing.T) {
+			// Output is compressed by suffix and the format read through it
+			compressed := filepath.Join(tmpDir, "data.yaml"+suffix)
+			if _, stderr, exitCode := runMorph(t, inputFile, compressed); exitCode != 0 {
+				t.Fatalf("morph exited with code %d, stderr: %s", exitCode, stderr)
+			}
+
+			stdout, stderr, exitCode := runMorph(t, "-out", "csv", compressed)
+			if exitCode != 0 {
+				t.Fatalf("morph exited with code %d, stderr: %s", exitCode, stderr)
+			}
+			if stdout != "name\nAlice\n" {
+				t.Errorf("unexpected CSV output: %q", stdout)
+			}
+		})
+	}
+
+	// The compressed stream is finished when the output is closed, so a
+	// failing last write must not be reported as success
+	if _, err := os.Stat("/dev/full"); err != nil {
+		t.Skip("/dev/full is not available")
+	}
+	full := filepath.Join(tmpDir, "full.csv.zst")
+	if err := os.Symlink("/dev/full", full); err != nil {
+		t.Fatalf("Failed to link /dev/full: %v", err)
+	}
+	for _, args := range [][]string{{inputFile, full}, {"cat", "-o", full, inputFile}} {
+		if _, stderr, exitCode := runMorph(t, args...); exitCode == 0 {
+			t.Errorf("morph %v succeeded writing to a full device", args)
+		} else if !strings.Contains(stderr, "Failed to write file") {
+			t.Errorf("morph %v: unexpected stderr: %s", args, stderr)
+		}
+	}
+}
+
+func TestIntegration_Archives(t *testing.T) {
+	tmpDir := t.TempDir()
+	members := map[string]string{
+		"people.csv":       "name\nAlice\n",
+		"nested/pets.json": `[{"name":"Rex"}]`,
+	}
+
+	var zipBuf bytes.Buffer
+	zw := zip.NewWriter(&zipBuf)
+	for name, content := range members {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatalf("Failed to add zip member: %v", err)
+		}
+		io.WriteString(w, content)
+	}
+	zw.Close()
+	zipFile := filepath.Join(tmpDir, "exports.zip")
+	if err := os.WriteFile(zipFile, zipBuf.Bytes(), 0644); err != nil {
+		t.Fatalf("Failed to write zip: %v", err)
+	}
+
+	var tarBuf bytes.Buffer
+	gw := gzip.NewWriter(&tarBuf)
+	tw := tar.NewWriter(gw)
+	for name, content := range members {
+		tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(content)), Typeflag: tar.TypeReg})
+		io.WriteString(tw, content)
+	}
+	tw.Close()
+	gw.Close()
+	tarFile := filepath.Join(tmpDir, "exports.tar.gz")
+	if err := os.WriteFile(tarFile, tarBuf.Bytes(), 0644); err != nil {
+		t.Fatalf("Failed to write tar: %v", err)
+	}
+
+	for _, archive := range []string{zipFile, tarFile} {
+		t.Run(filepath.Base(archive), func(t *testing.T) {
+			outDir := filepath.Join(tmpDir, filepath.Base(archive)+"-out")
+			if _, stderr, exitCode := runMorph(t, "-out", "csv", archive, outDir); exitCode != 0 {
+				t.Fatalf("morph exited with code %d, stderr: %s", exitCode, stderr)
+			}
+
+			for path, want := range map[string]string{
+				"people.csv":      "name\nAlice\n",
+				"nested/pets.csv": "name\nRex\n",
+			} {
+				got, err := os.ReadFile(filepath.Join(outDir, path))
+				if err != nil {
+					t.Fatalf("Failed to read converted member: %v", err)
+				}
+				if string(got) != want {
+					t.Errorf("%s = %q, want %q", path, got, want)
+				}
+			}
+		})
+	}
+
+	// Members written one after another would not form one JSON document
+	if _, stderr, exitCode := runMorph(t, "-out", "json", zipFile); exitCode == 0 {
+		t.Error("expected morph to reject JSON output of an archive to stdout")
+	} else if !strings.Contains(stderr, "archive input cannot be written as json to stdout") {
+		t.Errorf("unexpected stderr: %s", stderr)
+	}
+}
+
+func TestIntegration_CharacterEncodings(t *testing.T) {
+	tmpDir := t.TempDir()
+	inputs := map[string][]byte{
+		"utf-16le with bom": []byte("\xFF\xFEn\x00a\x00m\x00e\x00\n\x00J\x00o\x00s\x00\xe9\x00\n\x00"),
+		"windows-1252":      []byte("name\nJos\xe9\n"),
+		"utf-8 with bom":    []byte("\xEF\xBB\xBFname\nJosé\n"),
+	}
+
+	for name, content := range inputs {
+		t.Run(name, func(t *testing.T) {
+			inputFile := filepath.Join(tmpDir, strings.ReplaceAll(name, " ", "-")+".csv")
+			if err := os.WriteFile(inputFile, content, 0644); err != nil {
+				t.Fatalf("Failed to write input file: %v", err)
+			}
+
+			stdout, stderr, exitCode := runMorph(t, "-out", "json", inputFile)
+			if exitCode != 0 {
+				t.Fatalf("morph exited with code %d, stderr: %s", exitCode, stderr)
+			}
+			if !strings.Contains(stdout, `"name": "José"`) {
+				t.Errorf("unexpected JSON output: %s", stdout)
+			}
+		})
+	}
+
+	t.Run("output with bom", func(t *testing.T) {
+		stdout, stderr, exitCode := runMorphWithStdin(t, "name\nJosé\n", "-in", "csv", "-out", "csv", "-output-encoding", "utf-8-bom")
+		if exitCode != 0 {
+			t.Fatalf("morph exited with code %d, stderr: %s", exitCode, stderr)
+		}
+		if stdout != "\xEF\xBB\xBFname\nJosé\n" {
+			t.Errorf("unexpected CSV output: %q", stdout)
+		}
+	})
+
+	t.Run("unencodable output", func(t *testing.T) {
+		_, stderr, exitCode := runMorphWithStdin(t, "price\n€5\n", "-in", "csv", "-out", "csv", "-output-encoding", "latin1")
+		if exitCode == 0 {
+			t.Fatal("expected morph to fail")
+		}
+		if !strings.Contains(stderr, "cannot encode output as latin1") {
+			t.Errorf("unexpected stderr: %s", stderr)
+		}
+	})
+}
+
+func TestIntegration_CSVDialect(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		args  []string
+		want  string
+	}{
+		{
+			name:  "multi-character delimiter with comments",
+			input: "# exported\nname::note\nAnn::a \\\"b\\\"\n",
+			args:  []string{"-delimiter", "::", "-comment", "#", "-escape", "backslash", "-out", "csv"},
+			want:  "name,note\nAnn,\"a \"\"b\"\"\"\n",
+		},
+		{
+			name:  "headerless with skipped lines",
+			input: "Report\n\nAnn,30\n",
+			args:  []string{"-skip-lines", "2", "-no-header", "-out", "csv"},
+			want:  "col1,col2\nAnn,30\n",
+		},
+		{
+			name:  "tsv without quoting",
+			input: "name,note\nAnn,\"two\nlines\"\n",
+			args:  []string{"-in", "csv", "-out", "csv", "-out-delimiter", "tab", "-quoting", "never", "-out-escape", "backslash"},
+			want:  "name\tnote\nAnn\ttwo\\nlines\n",
+		},
+		{
+			name:  "crlf output",
+			input: "name\nAnn\n",
+			args:  []string{"-in", "csv", "-out", "csv", "-crlf"},
+			want:  "name\r\nAnn\r\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stdout, stderr, exitCode := runMorphWithStdin(t, tt.input, tt.args...)
+			if exitCode != 0 {
+				t.Fatalf("morph exited with code %d, stderr: %s", exitCode, stderr)
+			}
+			if stdout != tt.want {
+				t.Errorf("output = %q, want %q", stdout, tt.want)
+			}
+		})
+	}
+}
+
+func TestIntegration_StrictMode(t *testing.T) {
+	tmpDir := t.TempDir()
+	inputFile := filepath.Join(tmpDir, "ragged.csv")
+	if err := os.WriteFile(inputFile, []byte("name,age\nAlice\nBob,25\n"), 0644); err != nil {
+		t.Fatalf("Failed to write input file: %v", err)
+	}
+
+	// By default the short row is padded and reported
+	stdout, stderr, exitCode := runMorph(t, "-out", "csv", inputFile)
+	if exitCode != 0 {
+		t.Fatalf("morph exited with code %d, stderr: %s", exitCode, stderr)
+	}
+	if stdout != "name,age\nAlice,\nBob,25\n" {
+		t.Errorf("unexpected output: %q", stdout)
+	}
+	if !strings.Contains(stderr, "Warning: "+inputFile+": line 2: row 1 has 1 field, expected 2; padded with nulls") {
+		t.Errorf("expected a padding warning, got stderr: %s", stderr)
+	}
+
+	// Strict mode rejects it
+	_, stderr, exitCode = runMorph(t, "-strict", "-out", "csv", inputFile)
+	if exitCode == 0 {
+		t.Fatal("expected morph to fail in strict mode")
+	}
+	if !strings.Contains(stderr, "Line 2: row 1 has 1 field, expected 2") {
+		t.Errorf("unexpected stderr: %s", stderr)
+	}
+}
+
+func TestIntegration_StrictModeBoxAndDocument(t *testing.T) {
+	tmpDir := t.TempDir()
+	boxFile := filepath.Join(tmpDir, "ragged.txt")
+	box := "+---+---+\n| a | b |\n+---+---+\n| 1 | 2 | 3 |\n+---+---+\n"
+	if err := os.WriteFile(boxFile, []byte(box), 0644); err != nil {
+		t.Fatalf("Failed to write input file: %v", err)
+	}
+
+	stdout, stderr, exitCode := runMorph(t, "-out", "csv", boxFile)
+	if exitCode != 0 || stdout != "a,b\n1,2\n" {
+		t.Errorf("got %q, exit %d, stderr: %s", stdout, exitCode, stderr)
+	}
+	if !strings.Contains(stderr, "line 4: row 1 has 3 fields, expected 2; extra fields dropped") {
+		t.Errorf("expected an extra fields warning, got stderr: %s", stderr)
+	}
+	if _, stderr, exitCode = runMorph(t, "-strict", "-out", "csv", boxFile); exitCode == 0 {
+		t.Error("expected morph to fail in strict mode")
+	} else if !strings.Contains(stderr, "Line 4: row 1 has 3 fields, expected 2") {
+		t.Errorf("unexpected stderr: %s", stderr)
+	}
+
+	// In a document, lines are counted from the top of the document
+	docFile := filepath.Join(tmpDir, "doc.md")
+	doc := "# Report\n\nSome text.\n\n| a | b |\n|---|---|\n| 1 |\n"
+	if err := os.WriteFile(docFile, []byte(doc), 0644); err != nil {
+		t.Fatalf("Failed to write input file: %v", err)
+	}
+	_, stderr, exitCode = runMorph(t, "-table", "1", "-out", "csv", docFile)
+	if exitCode != 0 || !strings.Contains(stderr, "line 7: row 1 has 1 field, expected 2; padded with nulls") {
+		t.Errorf("exit %d, expected a padding warning, got stderr: %s", exitCode, stderr)
+	}
+	if _, stderr, exitCode = runMorph(t, "-strict", "-table", "1", "-out", "csv", docFile); exitCode == 0 {
+		t.Error("expected morph -table to fail in strict mode")
+	} else if !strings.Contains(stderr, "Line 7: row 1 has 1 field, expected 2") {
+		t.Errorf("unexpected stderr: %s", stderr)
+	}
+}
+
+func TestIntegration_StrictModeRecordFormats(t *testing.T) {
+	input := `[{"a": 1, "a": 2}]`
+
+	// By default the last value is kept and the repeated key reported
+	stdout, stderr, exitCode := runMorphWithStdin(t, input, "-in", "json", "-out", "csv")
+	if exitCode != 0 || stdout != "a\n2\n" {
+		t.Errorf("got %q, exit %d, stderr: %s", stdout, exitCode, stderr)
+	}
+	if !strings.Contains(stderr, `Warning: row 1 has duplicate field "a"; last value kept`) {
+		t.Errorf("expected a duplicate field warning, got stderr: %s", stderr)
+	}
+
+	if _, stderr, exitCode = runMorphWithStdin(t, input, "-strict", "-in", "json", "-out", "csv"); exitCode == 0 {
+		t.Error("expected morph to fail in strict mode")
+	} else if !strings.Contains(stderr, `row 1 has duplicate field "a"`) {
+		t.Errorf("unexpected stderr: %s", stderr)
+	}
+}
+
+func TestIntegration_HeaderNames(t *testing.T) {
+	input := "Amount,Amount,\n1,2,3\n"
+
+	// JSON output renames repeated headers by default
+	stdout, stderr, exitCode := runMorphWithStdin(t, input, "-in", "csv", "-out", "json", "-empty-headers", "generate")
+	if exitCode != 0 {
+		t.Fatalf("morph exited with code %d, stderr: %s", exitCode, stderr)
+	}
+	for _, want := range []string{`"Amount": "1"`, `"Amount_2": "2"`, `"column_3": "3"`} {
+		if !strings.Contains(stdout, want) {
+			t.Errorf("expected %s in output: %s", want, stdout)
+		}
+	}
+
+	// CSV output keeps them unless asked
+	stdout, _, _ = runMorphWithStdin(t, input, "-in", "csv", "-out", "csv")
+	if stdout != "Amount,Amount,\n1,2,3\n" {
+		t.Errorf("unexpected output: %q", stdout)
+	}
+	stdout, _, _ = runMorphWithStdin(t, "Total Amount,userID\n1,2\n", "-in", "csv", "-out", "csv", "-header-case", "snake")
+	if stdout != "total_amount,user_id\n1,2\n" {
+		t.Errorf("unexpected snake_case output: %q", stdout)
+	}
+
+	_, stderr, exitCode = runMorphWithStdin(t, input, "-in", "csv", "-out", "json", "-duplicate-headers", "error")
+	if exitCode == 0 || !strings.Contains(stderr, `duplicate header "Amount" in column 2`) {
+		t.Errorf("expected a duplicate header error, got exit %d, stderr: %s", exitCode, stderr)
+	}
+}
+
+func TestIntegration_ColumnSelection(t *testing.T) {
+	input := "id,name,email,addr_city,addr_zip\n1,\"Smith, Alice\",a@example.com,Paris,75001\n"
+
+	stdout, stderr, exitCode := runMorphWithStdin(t, input, "-in", "csv", "-out", "csv",
+		"-select", "email,name,addr_*", "-exclude", "addr_zip", "-rename", "name:full_name")
+	if exitCode != 0 {
+		t.Fatalf("morph exited with code %d, stderr: %s", exitCode, stderr)
+	}
+	if want := "email,full_name,addr_city\na@example.com,\"Smith, Alice\",Paris\n"; stdout != want {
+		t.Errorf("output = %q, want %q", stdout, want)
+	}
+
+	_, stderr, exitCode = runMorphWithStdin(t, input, "-in", "csv", "-out", "json", "-select", "id,phone")
+	if exitCode == 0 || !strings.Contains(stderr, `column "phone" not found`) {
+		t.Errorf("expected a missing column error, got exit %d, stderr: %s", exitCode, stderr)
+	}
+}
+
+func TestIntegration_Where(t *testing.T) {
+	input := "id,status,amount\n1,active,150\n2,active,50\n3,closed,500\n4,active,\n"
+
+	stdout, stderr, exitCode := runMorphWithStdin(t, input, "-in", "csv", "-out", "csv",
+		"-where", `status == "active" && amount > 100 || id == 4`, "-select", "id")
+	if exitCode != 0 {
+		t.Fatalf("morph exited with code %d, stderr: %s", exitCode, stderr)
+	}
+	if want := "id\n1\n4\n"; stdout != want {
+		t.Errorf("output = %q, want %q", stdout, want)
+	}
+
+	_, stderr, exitCode = runMorphWithStdin(t, input, "-in", "csv", "-out", "csv", "-where", "status > 1")
+	if exitCode == 0 || !strings.Contains(stderr, `row 1: cannot compare with a number: column "status" ("active") is not a number`) {
+		t.Errorf("expected a column error, got exit %d, stderr: %s", exitCode, stderr)
+	}
+}
+
+func TestIntegration_Sort(t *testing.T) {
+	input := "name,amount\nbob,10\nalice,9\ncarol,\ndave,100\neve,10\n"
+
+	stdout, stderr, exitCode := runMorphWithStdin(t, input, "-in", "csv", "-out", "csv", "-sort", "amount:desc,name")
+	if exitCode != 0 {
+		t.Fatalf("morph exited with code %d, stderr: %s", exitCode, stderr)
+	}
+	if want := "name,amount\ndave,100\nbob,10\neve,10\nalice,9\ncarol,\n"; stdout != want {
+		t.Errorf("output = %q, want %q", stdout, want)
+	}
+}
+
+func TestIntegration_Query(t *testing.T) {
+	tmpDir := t.TempDir()
+	sales := filepath.Join(tmpDir, "sales.csv")
+	if err := os.WriteFile(sales, []byte("region,amount,customer\nnorth,100,alice\nsouth,250,bob\nnorth,75,carol\n"), 0644); err != nil {
+		t.Fatalf("Failed to write sales: %v", err)
+	}
+	people := filepath.Join(tmpDir, "people.json")
+	if err := os.WriteFile(people, []byte(`[{"name":"alice","city":"Oslo"},{"name":"bob","city":"Rome"}]`), 0644); err != nil {
+		t.Fatalf("Failed to write people: %v", err)
+	}
+
+	t.Run("group by", func(t *testing.T) {
+		stdout, stderr, exitCode := runMorph(t, "query",
+			"SELECT region, sum(amount) AS total FROM input GROUP BY region ORDER BY total DESC", sales, "-out", "csv")
+		if exitCode != 0 {
+			t.Fatalf("morph exited with code %d, stderr: %s", exitCode, stderr)
+		}
+		if want := "region,total\nsouth,250\nnorth,175\n"; stdout != want {
+			t.Errorf("output = %q, want %q", stdout, want)
+		}
+	})
+
+	t.Run("join across formats", func(t *testing.T) {
+		stdout, stderr, exitCode := runMorph(t, "query", "-out", "csv",
+			"SELECT s.customer, p.city FROM sales s LEFT JOIN p ON s.customer = p.name", sales, "p="+people)
+		if exitCode != 0 {
+			t.Fatalf("morph exited with code %d, stderr: %s", exitCode, stderr)
+		}
+		if want := "customer,city\nalice,Oslo\nbob,Rome\ncarol,\n"; stdout != want {
+			t.Errorf("output = %q, want %q", stdout, want)
+		}
+	})
+
+	t.Run("stdin", func(t *testing.T) {
+		stdout, stderr, exitCode := runMorphWithStdin(t, "a,b\n1,2\n3,4\n", "query", "SELECT b FROM input WHERE a > 1", "-out", "csv")
+		if exitCode != 0 {
+			t.Fatalf("morph exited with code %d, stderr: %s", exitCode, stderr)
+		}
+		if want := "b\n4\n"; stdout != want {
+			t.Errorf("output = %q, want %q", stdout, want)
+		}
+	})
+
+	t.Run("syntax error", func(t *testing.T) {
+		_, stderr, exitCode := runMorph(t, "query", "SELECT FROM sales", sales, "-out", "csv")
+		if exitCode != 2 {
+			t.Errorf("exit code = %d, want 2", exitCode)
+		}
+		if !strings.Contains(stderr, "Failed to run query") {
+			t.Errorf("stderr = %q, want a query error", stderr)
+		}
+	})
+}
+
+func TestIntegration_Join(t *testing.T) {
+	tmpDir := t.TempDir()
+	orders := filepath.Join(tmpDir, "orders.csv")
+	if err := os.WriteFile(orders, []byte("order,customer_id,name\no1,1,tea\no2,3,jam\no3,2,cake\n"), 0644); err != nil {
+		t.Fatalf("Failed to write orders: %v", err)
+	}
+	customers := filepath.Join(tmpDir, "customers.json")
+	if err := os.WriteFile(customers, []byte(`[{"id":1,"name":"Alice"},{"id":2,"name":"Bob"}]`), 0644); err != nil {
+		t.Fatalf("Failed to write customers: %v", err)
+	}
+
+	tests := []struct {
+		how  string
+		want string
+	}{
+		{"inner", "order,customer_id,name_left,id,name_right\no1,1,tea,1,Alice\no3,2,cake,2,Bob\n"},
+		{"left", "order,customer_id,name_left,id,name_right\no1,1,tea,1,Alice\no2,3,jam,,\no3,2,cake,2,Bob\n"},
+		{"anti", "order,customer_id,name\no2,3,jam\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.how, func(t *testing.T) {
+			stdout, stderr, exitCode := runMorph(t, "join", orders, customers, "-on", "customer_id=id", "-how", tt.how, "-out", "csv")
+			if exitCode != 0 {
+				t.Fatalf("morph exited with code %d, stderr: %s", exitCode, stderr)
+			}
+			if stdout != tt.want {
+				t.Errorf("output = %q, want %q", stdout, tt.want)
+			}
+		})
+	}
+
+	t.Run("output file", func(t *testing.T) {
+		out := filepath.Join(tmpDir, "joined.json")
+		if _, stderr, exitCode := runMorph(t, "join", "-on", "customer_id=id", orders, customers, out); exitCode != 0 {
+			t.Fatalf("morph exited with code %d, stderr: %s", exitCode, stderr)
+		}
+		data, err := os.ReadFile(out)
+		if err != nil {
+			t.Fatalf("Failed to read output: %v", err)
+		}
+		if !strings.Contains(string(data), `"name_right": "Alice"`) {
+			t.Errorf("output = %s, want the joined customer names", data)
+		}
+	})
+}
+
+func TestIntegration_Cat(t *testing.T) {
+	tmpDir := t.TempDir()
+	jan := filepath.Join(tmpDir, "jan.csv")
+	if err := os.WriteFile(jan, []byte("date,amount\n2024-01-02,10\n"), 0644); err != nil {
+		t.Fatalf("Failed to write jan: %v", err)
+	}
+	feb := filepath.Join(tmpDir, "feb.json")
+	if err := os.WriteFile(feb, []byte(`[{"amount":7,"region":"north"}]`), 0644); err != nil {
+		t.Fatalf("Failed to write feb: %v", err)
+	}
+	mar := filepath.Join(tmpDir, "mar.csv")
+	if err := os.WriteFile(mar, []byte("region,amount\nsouth,n/a\n"), 0644); err != nil {
+		t.Fatalf("Failed to write mar: %v", err)
+	}
+
+	stdout, stderr, exitCode := runMorph(t, "cat", jan, feb, mar, "-source-column", "source_file", "-out", "csv")
+	if exitCode != 0 {
+		t.Fatalf("morph exited with code %d, stderr: %s", exitCode, stderr)
+	}
+	want := "source_file,date,amount,region\n" + jan + ",2024-01-02,10,\n" + feb + ",,7,north\n" + mar + ",,n/a,south\n"
+	if stdout != want {
+		t.Errorf("output = %q, want %q", stdout, want)
+	}
+	if !strings.Contains(stderr, `Warning: column "amount" has mixed types: number in `+jan+", number in "+feb+", text in "+mar) {
+		t.Errorf("stderr = %q, want a type conflict warning", stderr)
+	}
+
+	if _, stderr, exitCode := runMorph(t, "cat", "-strict-types", jan, mar, "-out", "csv"); exitCode == 0 {
+		t.Errorf("morph -strict-types succeeded, stderr: %s", stderr)
+	}
+}
+
+func TestIntegration_Reshape(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		args  []string
+		want  string
+	}{
+		{
+			name:  "pivot",
+			input: "region,month,amount\nnorth,jan,10\nsouth,jan,5\nnorth,feb,7\nnorth,jan,3\n",
+			args:  []string{"-pivot", "index=region,columns=month,values=amount,agg=sum"},
+			want:  "region,jan,feb\nnorth,13,7\nsouth,5,\n",
+		},
+		{
+			name:  "unpivot",
+			input: "id,name,q1,q2\n1,tea,10,11\n",
+			args:  []string{"-unpivot", "id_cols=id,name"},
+			want:  "id,name,variable,value\n1,tea,q1,10\n1,tea,q2,11\n",
+		},
+		{
+			name:  "transpose",
+			input: "metric,jan,feb\nsales,10,12\ncosts,4,5\n",
+			args:  []string{"-transpose"},
+			want:  "metric,sales,costs\njan,10,4\nfeb,12,5\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args := append([]string{"-in", "csv", "-out", "csv"}, tt.args...)
+			stdout, stderr, exitCode := runMorphWithStdin(t, tt.input, args...)
+			if exitCode != 0 {
+				t.Fatalf("morph exited with code %d, stderr: %s", exitCode, stderr)
+			}
+			if stdout != tt.want {
+				t.Errorf("output = %q, want %q", stdout, tt.want)
+			}
+		})
+	}
+}
+
+func TestIntegration_GroupBy(t *testing.T) {
+	input := "region,customer,amount,date\nnorth,ann,100,2024-03-01\nsouth,bob,40,2024-01-15\nnorth,cid,,2024-02-10\nnorth,ann,20,2024-04-02\n"
+
+	stdout, stderr, exitCode := runMorphWithStdin(t, input, "-in", "csv", "-out", "csv",
+		"-group-by", "region", "-agg", "count(*),sum(amount) as total,min(date),count_distinct(customer)", "-sort", "total:desc")
+	if exitCode != 0 {
+		t.Fatalf("morph exited with code %d, stderr: %s", exitCode, stderr)
+	}
+	want := "region,count,total,min_date,count_distinct_customer\nnorth,3,120,2024-02-10,2\nsouth,1,40,2024-01-15,1\n"
+	if stdout != want {
+		t.Errorf("output = %q, want %q", stdout, want)
+	}
+
+	_, stderr, exitCode = runMorphWithStdin(t, "region,amount\nnorth,n/a\n", "-in", "csv", "-out", "csv", "-group-by", "region", "-agg", "avg(amount)")
+	if exitCode == 0 {
+		t.Fatal("morph exited with code 0 for a text value in avg")
+	}
+	if !strings.Contains(stderr, `avg(amount): column "amount" ("n/a") is not a number`) {
+		t.Errorf("stderr = %q, want the row and column of the bad value", stderr)
+	}
+}
+
+func TestIntegration_Compute(t *testing.T) {
+	input := "email,price,qty,ordered\n Ann@Example.com ,2.5,4,03/01/2024\nbob@example.com,3,0,12/24/2023\n"
+
+	stdout, stderr, exitCode := runMorphWithStdin(t, input, "-in", "csv", "-out", "csv",
+		"-add", "total = price * qty",
+		"-set", "email = lower(trim(email))",
+		"-set", `ordered = formatDate(parseDate(ordered, "%m/%d/%Y"), "%Y-%m-%d")`,
+		"-add", `size = if(total > 5, "large", "small")`,
+		"-where", "total > 0")
+	if exitCode != 0 {
+		t.Fatalf("morph exited with code %d, stderr: %s", exitCode, stderr)
+	}
+	want := "email,price,qty,ordered,total,size\nann@example.com,2.5,4,2024-03-01,10,large\n"
+	if stdout != want {
+		t.Errorf("output = %q, want %q", stdout, want)
+	}
+
+	_, stderr, exitCode = runMorphWithStdin(t, input, "-in", "csv", "-out", "csv", "-add", "price = 1")
+	if exitCode == 0 {
+		t.Fatal("morph exited with code 0 adding an existing column")
+	}
+	if !strings.Contains(stderr, `column "price" already exists`) {
+		t.Errorf("stderr = %q, want it to name the existing column", stderr)
+	}
+}
+
+func TestIntegration_Dedupe(t *testing.T) {
+	input := "email,amount\nann@example.com,1\nbob@example.com,2\nann@example.com,1.0\nBOB@example.com,3\n"
+
+	tests := []struct {
+		name       string
+		args       []string
+		want       string
+		wantStderr string
+	}{
+		{
+			name:       "distinct",
+			args:       []string{"-distinct"},
+			want:       "email,amount\nann@example.com,1\nbob@example.com,2\nBOB@example.com,3\n",
+			wantStderr: "Removed 1 duplicate row\n",
+		},
+		{
+			name:       "dedupe by key",
+			args:       []string{"-dedupe-by", "email", "-keep", "last", "-dedupe-ignore-case"},
+			want:       "email,amount\nann@example.com,1.0\nBOB@example.com,3\n",
+			wantStderr: "Removed 2 duplicate rows\n",
+		},
+		{
+			name: "report",
+			args: []string{"-dedupe-by", "email", "-report-duplicates"},
+			want: "email,duplicate_count,duplicate_rows\nann@example.com,2,\"1,3\"\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args := append([]string{"-in", "csv", "-out", "csv"}, tt.args...)
+			stdout, stderr, exitCode := runMorphWithStdin(t, input, args...)
+			if exitCode != 0 {
+				t.Fatalf("morph exited with code %d, stderr: %s", exitCode, stderr)
+			}
+			if stdout != tt.want {
+				t.Errorf("output = %q, want %q", stdout, tt.want)
+			}
+			if stderr != tt.wantStderr {
+				t.Errorf("stderr = %q, want %q", stderr, tt.wantStderr)
+			}
+		})
+	}
+}
+
+func TestIntegration_FramedSingleMultiLineRowRoundTrip(t *testing.T) {
+	input := "name,note\nAlice,\"one\ntwo\"\n"
+	for _, style := range []string{"box", "unicode", "rst-grid", "double", "rounded", "heavy"} {
+		t.Run(style, func(t *testing.T) {
+			table, stderr, exitCode := runMorphWithStdin(t, input, "-in", "csv", "-out", "ascii", "-f", style)
+			if exitCode != 0 {
+				t.Fatalf("morph exited with code %d, stderr: %s", exitCode, stderr)
+			}
+			stdout, stderr, exitCode := runMorphWithStdin(t, table, "-in", "ascii", "-out", "csv")
+			if exitCode != 0 {
+				t.Fatalf("morph exited with code %d, stderr: %s", exitCode, stderr)
+			}
+			if stdout != input {
+				t.Errorf("round trip = %q, want %q\ntable:\n%s", stdout, input, table)
+			}
+		})
+	}
+}
@@ -38,6 +38,9 @@ func registerFormats() {
 
 	// Markdown format (alias for ASCII with markdown style)
 	registry.Register(registry.FormatMarkdown, parser.NewUnifiedASCIIParser(), serializer.NewUnifiedASCIISerializer("md"))
+
+	// Fixed-width column text
+	registry.Register(registry.FormatFixed, parser.NewFixedWidthParser(), serializer.NewFixedWidthSerializer())
 }
 
 func main() {
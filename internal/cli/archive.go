@@ -0,0 +1,210 @@
+package cli
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// ArchiveKind identifies an archive whose members are converted one by one
+type ArchiveKind string
+
+const (
+	ArchiveNone ArchiveKind = ""
+	ArchiveZip  ArchiveKind = "zip"
+	ArchiveTar  ArchiveKind = "tar"
+)
+
+// DetectArchive determines from a file name whether it is a zip or tar
+// archive, including compressed tar archives such as .tar.gz and .tgz
+func DetectArchive(name string) ArchiveKind {
+	stem, _ := SplitCompressionSuffix(name)
+	switch strings.ToLower(filepath.Ext(stem)) {
+	case ".zip":
+		return ArchiveZip
+	case ".tar", ".tgz", ".tbz2", ".txz", ".tzst":
+		return ArchiveTar
+	}
+	return ArchiveNone
+}
+
+// ConvertArchive converts every file in the input archive. With an output
+// path, each member is written to that directory under its own name with
+// the output format's extension; otherwise the members are written to
+// stdout separated by blank lines. Warnings are written to stderr
+func ConvertArchive(config *Config, stdout, stderr io.Writer) error {
+	a := &archiveConverter{config: config, stdout: stdout, stderr: stderr}
+
+	var err error
+	switch config.Archive {
+	case ArchiveZip:
+		err = a.convertZip()
+	case ArchiveTar:
+		err = a.convertTar()
+	default:
+		return FormatUsageError(fmt.Sprintf("%q is not an archive", config.InputFile))
+	}
+	if err != nil {
+		return err
+	}
+
+	if a.converted == 0 {
+		return NewCLIError(fmt.Sprintf("Error: No files to convert in archive %q", config.InputFile), ExitError)
+	}
+	return nil
+}
+
+// archiveConverter converts the members of one archive
+type archiveConverter struct {
+	config    *Config
+	stdout    io.Writer
+	stderr    io.Writer
+	converted int
+}
+
+// convertZip converts the files of a zip archive
+func (a *archiveConverter) convertZip() error {
+	zr, err := zip.OpenReader(a.config.InputFile)
+	if err != nil {
+		return FormatFileReadError(a.config.InputFile, err)
+	}
+	defer zr.Close()
+
+	for _, f := range zr.File {
+		if f.FileInfo().IsDir() {
+			continue
+		}
+		member, err := f.Open()
+		if err != nil {
+			return FormatFileReadError(a.config.InputFile, fmt.Errorf("%s: %w", f.Name, err))
+		}
+		err = a.convertMember(f.Name, member)
+		member.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// convertTar converts the regular files of a tar archive, which may itself
+// be compressed
+func (a *archiveConverter) convertTar() error {
+	input, err := createInputReader(a.config.InputFile)
+	if err != nil {
+		return FormatFileReadError(a.config.InputFile, err)
+	}
+	defer input.Close()
+
+	tr := tar.NewReader(input)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return FormatFileReadError(a.config.InputFile, err)
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		if err := a.convertMember(hdr.Name, tr); err != nil {
+			return err
+		}
+	}
+}
+
+// convertMember converts one archive member. Its format comes from -in,
+// then from its name, then from its content; compressed members are
+// decompressed first
+func (a *archiveConverter) convertMember(name string, member io.Reader) error {
+	if isArchiveMetadata(name) {
+		return nil
+	}
+
+	input, err := decompressReader(io.NopCloser(member))
+	if err != nil {
+		return FormatFileReadError(a.config.InputFile, fmt.Errorf("%s: %w", name, err))
+	}
+	defer input.Close()
+
+	opts := a.config.convertOptions()
+	opts.Warnings = a.stderr
+	opts.Source = name
+	if opts.InputFormat == "" {
+		opts.InputFormat = FormatAuto
+		if format, err := DetectFormat(name); err == nil {
+			opts.InputFormat = format
+		}
+	}
+
+	if a.config.OutputFile == "" || a.config.OutputFile == "-" {
+		if a.converted > 0 {
+			if _, err := io.WriteString(a.stdout, "\n"); err != nil {
+				return FormatFileWriteError("stdout", err)
+			}
+		}
+		if err := Convert(input, a.stdout, opts); err != nil {
+			return memberError(name, err)
+		}
+		a.converted++
+		return nil
+	}
+
+	outPath, err := memberOutputPath(a.config.OutputFile, name, a.config.OutputFormat)
+	if err != nil {
+		return FormatUsageError(err.Error())
+	}
+	if err := os.MkdirAll(filepath.Dir(outPath), 0755); err != nil {
+		return FormatFileWriteError(outPath, err)
+	}
+	output, err := createOutputWriter(outPath)
+	if err != nil {
+		return FormatFileWriteError(outPath, err)
+	}
+	if err := Convert(input, output, opts); err != nil {
+		output.Close()
+		return memberError(name, err)
+	}
+	if err := output.Close(); err != nil {
+		return FormatFileWriteError(outPath, err)
+	}
+	a.converted++
+	return nil
+}
+
+// isArchiveMetadata reports whether a member is bookkeeping added by the
+// archiver, such as macOS resource forks, rather than data
+func isArchiveMetadata(name string) bool {
+	return strings.HasPrefix(name, "__MACOSX/") || strings.HasPrefix(path.Base(name), "._")
+}
+
+// memberOutputPath returns where a converted member is written: its path
+// inside the archive, under dir, with the output format's extension.
+// Members that would land outside dir are rejected
+func memberOutputPath(dir, name string, format Format) (string, error) {
+	rel := filepath.Clean(filepath.FromSlash(name))
+	if filepath.IsAbs(rel) || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("archive member %q has an unsafe path", name)
+	}
+
+	stem, _ := SplitCompressionSuffix(rel)
+	stem = strings.TrimSuffix(stem, filepath.Ext(stem))
+	return filepath.Join(dir, stem+format.Extension()), nil
+}
+
+// memberError adds the archive member name to a conversion error
+func memberError(name string, err error) error {
+	cliErr := FormatError(err)
+	return &CLIError{
+		Message:  fmt.Sprintf("%s\n  Archive member: %s", cliErr.Message, name),
+		ExitCode: cliErr.ExitCode,
+		Err:      err,
+	}
+}
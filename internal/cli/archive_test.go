@@ -0,0 +1,55 @@
+package cli
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDetectArchive(t *testing.T) {
+	tests := map[string]ArchiveKind{
+		"exports.zip":     ArchiveZip,
+		"exports.ZIP":     ArchiveZip,
+		"exports.tar":     ArchiveTar,
+		"exports.tar.gz":  ArchiveTar,
+		"exports.tgz":     ArchiveTar,
+		"exports.tar.zst": ArchiveTar,
+		"exports.csv.gz":  ArchiveNone,
+		"exports.xlsx":    ArchiveNone,
+	}
+	for name, want := range tests {
+		if got := DetectArchive(name); got != want {
+			t.Errorf("DetectArchive(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestMemberOutputPath(t *testing.T) {
+	got, err := memberOutputPath("out", "2024/sales.csv.gz", FormatJSON)
+	if err != nil {
+		t.Fatalf("memberOutputPath failed: %v", err)
+	}
+	if want := filepath.Join("out", "2024", "sales.json"); got != want {
+		t.Errorf("memberOutputPath = %q, want %q", got, want)
+	}
+
+	for _, name := range []string{"../escape.csv", "a/../../escape.csv", "/etc/passwd"} {
+		if _, err := memberOutputPath("out", name, FormatJSON); err == nil || !strings.Contains(err.Error(), "unsafe path") {
+			t.Errorf("memberOutputPath(%q) error = %v, want unsafe path", name, err)
+		}
+	}
+}
+
+func TestParseArgs_ArchiveInput(t *testing.T) {
+	config, err := ParseArgs([]string{"-out", "json", "exports.tar.gz", "outdir"})
+	if err != nil {
+		t.Fatalf("ParseArgs failed: %v", err)
+	}
+	if config.Archive != ArchiveTar || config.InputFormat != "" || config.OutputFormat != FormatJSON {
+		t.Errorf("unexpected config: archive %q, in %q, out %q", config.Archive, config.InputFormat, config.OutputFormat)
+	}
+
+	if _, err := ParseArgs([]string{"exports.zip", "outdir"}); err == nil || !strings.Contains(err.Error(), "archive input") {
+		t.Errorf("expected an error requiring -out, got %v", err)
+	}
+}
@@ -6,7 +6,13 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"path/filepath"
+	"strconv"
 	"strings"
+	"unicode/utf8"
+
+	"github.com/user/table-converter/internal/model"
+	"github.com/user/table-converter/internal/transform"
 )
 
 // Version is the application version
@@ -16,11 +22,40 @@ const Version = "1.0.0"
 type Config struct {
 	InputFile    string // Input file path (empty for stdin)
 	OutputFile   string // Output file path (empty for stdout)
-	InputFormat  Format // Input format
+	InputFormat  Format // Input format (FormatAuto = detect from the content)
 	OutputFormat Format // Output format
 	FormatStyle  string // Format style variant (e.g., "md", "psql", "box" for ASCII)
 	ShowHelp     bool   // Show help message
 	ShowVersion  bool   // Show version
+
+	MaxWidth        int            // Maximum ASCII table width (0 = unlimited)
+	MaxColumnWidths map[string]int // Maximum ASCII column widths, keyed by header
+	AutoWidth       bool           // Fit ASCII tables to the terminal width
+	Overflow        string         // How oversized cells are fitted ("truncate" or "wrap")
+	NumericAlign    string         // Alignment of numeric ASCII columns ("right", "left" or "decimal")
+
+	Columns []model.ColumnSpec // Declared fixed-width columns (nil = infer from the data)
+	PadChar rune               // Fixed-width padding character (0 = space)
+
+	TableIndex int  // 1-based table to extract from a document (0 = whole input is one table)
+	AllTables  bool // Extract every table from a document
+	ListTables bool // List the tables found in a document instead of converting
+
+	ShowDetection bool // Report how the input format was detected instead of converting
+
+	Archive ArchiveKind // Input is an archive whose members are converted one by one
+
+	InputEncoding  string // Character encoding of text input ("" or "auto" = detect)
+	OutputEncoding string // Character encoding of text output ("" = UTF-8)
+
+	CSVInput  model.CSVDialect // Delimiter, quoting, comments and header of CSV input
+	CSVOutput model.CSVDialect // Delimiter, quoting and line endings of CSV output
+
+	Strict bool // Reject ragged rows and duplicate or empty headers
+
+	Headers model.HeaderPolicy // How empty and duplicate headers are fixed and their case
+
+	Transforms transform.Pipeline // Row filtering, sorting and column selection applied before serialization
 }
 
 // ParseArgs parses command-line arguments and returns a Config
@@ -38,9 +73,79 @@ func ParseArgsWithOutput(args []string, output io.Writer) (*Config, error) {
 
 	// Define flags
 	var inFormat, outFormat, formatStyle string
-	fs.StringVar(&inFormat, "in", "", "Input format (csv|excel|yaml|json|html|xml|markdown|ascii)")
-	fs.StringVar(&outFormat, "out", "", "Output format (csv|excel|yaml|json|html|xml|markdown|ascii)")
-	fs.StringVar(&formatStyle, "f", "", "Format style variant (for ascii: md|psql|box|org|rst-grid|rst-simple)")
+	fs.StringVar(&inFormat, "in", "", "Input format (auto|csv|excel|yaml|json|html|xml|markdown|ascii|fixed)")
+	fs.StringVar(&outFormat, "out", "", "Output format (csv|excel|yaml|json|html|xml|markdown|ascii|fixed)")
+	fs.StringVar(&formatStyle, "f", "", "Format style variant (for ascii: md|psql|box|org|rst-grid|rst-simple|unicode|double|rounded|heavy|simple)")
+
+	// ASCII table width options
+	var maxWidth string
+	var wrap, truncate bool
+	fs.StringVar(&maxWidth, "max-width", "", "Maximum ASCII table width: N, column:N,... or auto")
+	fs.BoolVar(&wrap, "wrap", false, "Word-wrap cells that exceed the maximum width")
+	fs.BoolVar(&truncate, "truncate", false, "Truncate cells that exceed the maximum width")
+	fs.StringVar(&config.NumericAlign, "numeric-align", "", "Alignment of numeric ASCII columns (right|left|decimal)")
+
+	// Fixed-width options
+	var columns, columnsFile, padChar string
+	fs.StringVar(&columns, "columns", "", "Fixed-width columns: name:start-end[:align],...")
+	fs.StringVar(&columnsFile, "columns-file", "", "File declaring fixed-width columns, one name:start-end[:align] per line")
+	fs.StringVar(&padChar, "pad-char", "", "Fixed-width padding character (default: space)")
+
+	// CSV dialect options
+	var csvOpts csvFlags
+	fs.StringVar(&csvOpts.delimiter, "delimiter", "", "CSV input delimiter, one or more characters or comma|tab|semicolon|pipe|space (default: detect)")
+	fs.StringVar(&csvOpts.quote, "quote", "", "CSV input quote character, or none (default: \")")
+	fs.StringVar(&csvOpts.escape, "escape", "", "CSV input escape character such as backslash (default: quotes are doubled)")
+	fs.StringVar(&csvOpts.comment, "comment", "", "Skip CSV input lines starting with this prefix")
+	fs.IntVar(&csvOpts.skipLines, "skip-lines", 0, "Skip this many CSV input lines before the header")
+	fs.BoolVar(&csvOpts.noHeader, "no-header", false, "CSV input has no header line; name the columns col1..colN")
+	fs.StringVar(&csvOpts.outDelimiter, "out-delimiter", "", "CSV output delimiter, one or more characters or comma|tab|semicolon|pipe|space (default: comma)")
+	fs.StringVar(&csvOpts.outQuote, "out-quote", "", "CSV output quote character, or none (default: \")")
+	fs.StringVar(&csvOpts.outEscape, "out-escape", "", "CSV output escape character such as backslash (default: quotes are doubled)")
+	fs.StringVar(&csvOpts.quoting, "quoting", "", "Which CSV output fields are quoted (minimal|all|never)")
+	fs.BoolVar(&csvOpts.crlf, "crlf", false, "End CSV output lines with CRLF")
+
+	// Document table selection
+	var table string
+	fs.StringVar(&table, "table", "", "Table to extract from a document: N (1-based) or all")
+	fs.BoolVar(&config.ListTables, "list-tables", false, "List the tables found in a document")
+
+	// Character encodings
+	fs.StringVar(&config.InputEncoding, "input-encoding", EncodingAuto, "Character encoding of text input (auto|utf-8|utf-16|utf-16le|utf-16be|windows-1252|latin1|...)")
+	fs.StringVar(&config.OutputEncoding, "output-encoding", "utf-8", "Character encoding of text output (utf-8|utf-8-bom|utf-16|utf-16le|utf-16be|windows-1252|latin1|...)")
+
+	// Table shape checks
+	fs.BoolVar(&config.Strict, "strict", false, "Reject ragged rows and duplicate or empty headers instead of padding or truncating rows")
+
+	// Header normalization
+	var duplicateHeaders, emptyHeaders, headerCase string
+	fs.StringVar(&duplicateHeaders, "duplicate-headers", "", "Repeated headers: keep|rename|error (default: rename for json, yaml and xml output, else keep)")
+	fs.StringVar(&emptyHeaders, "empty-headers", "keep", "Empty headers: keep|generate|error")
+	fs.StringVar(&headerCase, "header-case", "keep", "Convert headers to keep|snake|camel|lower case")
+
+	// Row filtering and column selection
+	var transformOpts transformFlags
+	fs.Var(assignmentFlag{&transformOpts.assignments, false}, "add", "Add a column computed for each row, e.g. 'total = price * qty' (repeatable)")
+	fs.Var(assignmentFlag{&transformOpts.assignments, true}, "set", "Replace a column's values, e.g. 'email = lower(trim(email))' (repeatable)")
+	fs.StringVar(&transformOpts.where, "where", "", "Keep only rows where the condition is true, e.g. 'status == \"active\" && amount > 100'")
+	fs.StringVar(&transformOpts.sort, "sort", "", "Sort rows by columns: column[:asc|:desc],...")
+	fs.StringVar(&transformOpts.sortLocale, "sort-locale", "", "Order text by the rules of a language such as de or sv (default: by code point)")
+	fs.BoolVar(&transformOpts.distinct, "distinct", false, "Drop rows that repeat an earlier row")
+	fs.StringVar(&transformOpts.dedupeBy, "dedupe-by", "", "Drop rows whose key columns repeat another row's: column,...")
+	fs.StringVar(&transformOpts.keep, "keep", "", "Row to keep of each set of duplicates (first|last, default first)")
+	fs.BoolVar(&transformOpts.dedupeIgnoreCase, "dedupe-ignore-case", false, "Compare text without regard to case when finding duplicates")
+	fs.BoolVar(&transformOpts.reportDuplicates, "report-duplicates", false, "Write a report of the duplicate rows instead of dropping them")
+	fs.StringVar(&transformOpts.groupBy, "group-by", "", "Summarize rows by these columns: column,...")
+	fs.StringVar(&transformOpts.agg, "agg", "", "Summary columns, e.g. count(*),sum(amount) as total (default count(*))")
+	fs.StringVar(&transformOpts.selectColumns, "select", "", "Keep only these columns, in this order: name,glob*,/regexp/,...")
+	fs.StringVar(&transformOpts.exclude, "exclude", "", "Drop these columns: name,glob*,/regexp/,...")
+	fs.StringVar(&transformOpts.rename, "rename", "", "Rename columns: old:new,...")
+	fs.StringVar(&transformOpts.pivot, "pivot", "", "Turn a column's values into columns: index=col,...,columns=col,values=col[,agg=sum]")
+	fs.StringVar(&transformOpts.unpivot, "unpivot", "", "Turn columns into variable/value rows: id_cols=col,...[,value_cols=col,...]")
+	fs.BoolVar(&transformOpts.transpose, "transpose", false, "Swap rows and columns, using the first column as the new headers")
+
+	// Input format detection
+	fs.BoolVar(&config.ShowDetection, "detect", false, "Report the detected input format and why, without converting")
 
 	// Custom help and version flags
 	var showHelp, showVersion bool
@@ -72,6 +177,49 @@ func ParseArgsWithOutput(args []string, output io.Writer) (*Config, error) {
 		return config, nil
 	}
 
+	// Parse width limits for ASCII output
+	if maxWidth != "" {
+		if err := parseMaxWidth(maxWidth, config); err != nil {
+			return nil, err
+		}
+	}
+	if wrap && truncate {
+		return nil, errors.New("-wrap and -truncate cannot be used together")
+	}
+	if (wrap || truncate) && maxWidth == "" {
+		return nil, errors.New("-wrap and -truncate require -max-width")
+	}
+	if wrap {
+		config.Overflow = "wrap"
+	} else if truncate {
+		config.Overflow = "truncate"
+	}
+
+	// Parse fixed-width column declarations
+	if err := parseFixedWidth(columns, columnsFile, padChar, config); err != nil {
+		return nil, err
+	}
+
+	// Parse the CSV dialect
+	if err := parseCSVDialect(csvOpts, config); err != nil {
+		return nil, err
+	}
+
+	// Check character encodings
+	if err := validateEncoding(config.InputEncoding, true); err != nil {
+		return nil, fmt.Errorf("invalid -input-encoding: %w", err)
+	}
+	if err := validateEncoding(config.OutputEncoding, false); err != nil {
+		return nil, fmt.Errorf("invalid -output-encoding: %w", err)
+	}
+
+	// Parse document table selection
+	if table != "" {
+		if err := parseTableSelection(table, config); err != nil {
+			return nil, err
+		}
+	}
+
 	// Parse positional arguments (input and output files)
 	positionalArgs := fs.Args()
 	if len(positionalArgs) > 0 {
@@ -88,20 +236,40 @@ func ParseArgsWithOutput(args []string, output io.Writer) (*Config, error) {
 	isStdin := config.InputFile == "" || config.InputFile == "-"
 	isStdout := config.OutputFile == "" || config.OutputFile == "-"
 
+	// Archives are converted member by member, each with its own format
+	if !isStdin && !config.ShowDetection {
+		config.Archive = DetectArchive(config.InputFile)
+	}
+
 	// Parse and validate input format
-	if inFormat != "" {
+	switch {
+	case config.ShowDetection || strings.EqualFold(inFormat, string(FormatAuto)):
+		config.InputFormat = FormatAuto
+	case inFormat != "":
 		format, err := ParseFormat(inFormat)
 		if err != nil {
 			return nil, err
 		}
 		config.InputFormat = format
-	} else if !isStdin {
-		// Try to detect format from file extension
-		format, err := DetectFormat(config.InputFile)
-		if err != nil {
-			return nil, fmt.Errorf("cannot determine input format: %w (use -in flag to specify format)", err)
+	case config.Archive != ArchiveNone:
+		// Each member is detected from its own name or content
+	case config.IsDocumentMode():
+		// Documents are scanned as text whatever their extension, keeping
+		// Markdown apart for its code block and heading syntax
+		config.InputFormat = FormatASCII
+		if format, err := DetectFormat(config.InputFile); err == nil && format == FormatMarkdown {
+			config.InputFormat = FormatMarkdown
 		}
-		config.InputFormat = format
+	case !isStdin:
+		// Try to detect format from file extension, falling back to the content
+		if format, err := DetectFormat(config.InputFile); err == nil {
+			config.InputFormat = format
+		} else {
+			config.InputFormat = FormatAuto
+		}
+	default:
+		// Standard input has no name to go by
+		config.InputFormat = FormatAuto
 	}
 
 	// Parse and validate output format
@@ -111,8 +279,8 @@ func ParseArgsWithOutput(args []string, output io.Writer) (*Config, error) {
 			return nil, err
 		}
 		config.OutputFormat = format
-	} else if !isStdout {
-		// Try to detect format from file extension
+	} else if !isStdout && config.Archive == ArchiveNone {
+		// Try to detect format from file extension (archive output is a directory)
 		format, err := DetectFormat(config.OutputFile)
 		if err != nil {
 			return nil, fmt.Errorf("cannot determine output format: %w (use -out flag to specify format)", err)
@@ -120,6 +288,27 @@ func ParseArgsWithOutput(args []string, output io.Writer) (*Config, error) {
 		config.OutputFormat = format
 	}
 
+	// CSV dialect options imply CSV input, and .tsv files are tab-separated
+	if config.InputFormat == FormatAuto && !config.ShowDetection && csvOpts.hasInputOptions() {
+		config.InputFormat = FormatCSV
+	}
+	if config.CSVInput.Delimiter == "" && isTSVPath(config.InputFile) {
+		config.CSVInput.Delimiter = "\t"
+	}
+	if config.CSVOutput.Delimiter == "" && isTSVPath(config.OutputFile) {
+		config.CSVOutput.Delimiter = "\t"
+	}
+
+	// Parse header normalization policies
+	if err := parseHeaderPolicy(duplicateHeaders, emptyHeaders, headerCase, config); err != nil {
+		return nil, err
+	}
+
+	// Build the row and column transforms
+	if err := parseTransforms(transformOpts, config); err != nil {
+		return nil, err
+	}
+
 	// Validate configuration
 	if err := validateConfig(config); err != nil {
 		return nil, err
@@ -128,14 +317,380 @@ func ParseArgsWithOutput(args []string, output io.Writer) (*Config, error) {
 	return config, nil
 }
 
+// parseMaxWidth parses a -max-width value into the config. The value is a
+// total table width ("80"), per-column widths ("name:20,notes:40"), a mix of
+// both, or "auto" to fit the terminal
+func parseMaxWidth(value string, config *Config) error {
+	if strings.EqualFold(value, "auto") {
+		config.AutoWidth = true
+		return nil
+	}
+
+	for _, part := range strings.Split(value, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		// The column name may itself contain ':', so split on the last one
+		if idx := strings.LastIndex(part, ":"); idx >= 0 {
+			name := strings.TrimSpace(part[:idx])
+			width, err := strconv.Atoi(strings.TrimSpace(part[idx+1:]))
+			if err != nil || width < 1 || name == "" {
+				return fmt.Errorf("invalid -max-width column limit %q (expected column:N)", part)
+			}
+			if config.MaxColumnWidths == nil {
+				config.MaxColumnWidths = make(map[string]int)
+			}
+			config.MaxColumnWidths[name] = width
+			continue
+		}
+
+		width, err := strconv.Atoi(part)
+		if err != nil || width < 1 {
+			return fmt.Errorf("invalid -max-width value %q (expected N, column:N or auto)", part)
+		}
+		config.MaxWidth = width
+	}
+
+	return nil
+}
+
+// parseFixedWidth parses the fixed-width column declarations, given either
+// inline or in a spec file, and the padding character
+func parseFixedWidth(columns, columnsFile, padChar string, config *Config) error {
+	if columns != "" && columnsFile != "" {
+		return errors.New("-columns and -columns-file cannot be used together")
+	}
+
+	var err error
+	if columns != "" {
+		if config.Columns, err = model.ParseColumnSpecs(columns); err != nil {
+			return fmt.Errorf("invalid -columns value: %w", err)
+		}
+	}
+	if columnsFile != "" {
+		file, err := os.Open(columnsFile)
+		if err != nil {
+			return fmt.Errorf("cannot read -columns-file: %w", err)
+		}
+		defer file.Close()
+		if config.Columns, err = model.ReadColumnSpecs(file); err != nil {
+			return fmt.Errorf("invalid -columns-file %q: %w", columnsFile, err)
+		}
+	}
+
+	if padChar != "" {
+		runes := []rune(padChar)
+		if len(runes) != 1 {
+			return fmt.Errorf("invalid -pad-char %q (expected a single character)", padChar)
+		}
+		config.PadChar = runes[0]
+		if model.PadInValues(config.PadChar) && len(config.Columns) == 0 {
+			return fmt.Errorf("-pad-char %q can appear in values, so it needs -columns or -columns-file to mark the right-aligned fields it pads", padChar)
+		}
+	}
+
+	return nil
+}
+
+// csvFlags holds the raw values of the CSV dialect flags
+type csvFlags struct {
+	delimiter, quote, escape          string
+	comment                           string
+	skipLines                         int
+	noHeader                          bool
+	outDelimiter, outQuote, outEscape string
+	quoting                           string
+	crlf                              bool
+}
+
+// hasInputOptions reports whether any flag that applies to CSV input was given
+func (f csvFlags) hasInputOptions() bool {
+	return f.delimiter != "" || f.quote != "" || f.escape != "" || f.comment != "" ||
+		f.skipLines != 0 || f.noHeader
+}
+
+// parseCSVDialect parses the CSV dialect flags into the input and output
+// dialects of the config
+func parseCSVDialect(f csvFlags, config *Config) error {
+	in, out := &config.CSVInput, &config.CSVOutput
+	var err error
+
+	if err := parseDelimiterQuoteEscape(in, f.delimiter, f.quote, f.escape, ""); err != nil {
+		return err
+	}
+	if f.skipLines < 0 {
+		return fmt.Errorf("invalid -skip-lines %d (expected a number of lines)", f.skipLines)
+	}
+	in.Comment = f.comment
+	in.SkipLines = f.skipLines
+	in.NoHeader = f.noHeader
+
+	if err := parseDelimiterQuoteEscape(out, f.outDelimiter, f.outQuote, f.outEscape, "out-"); err != nil {
+		return err
+	}
+	if out.Quoting, err = model.ParseQuoteStyle(f.quoting); err != nil {
+		return fmt.Errorf("invalid -quoting: %w", err)
+	}
+	if f.crlf {
+		out.LineTerminator = "\r\n"
+	}
+
+	if err := in.Validate(); err != nil {
+		return fmt.Errorf("invalid CSV input dialect: %w", err)
+	}
+	if err := out.Validate(); err != nil {
+		return fmt.Errorf("invalid CSV output dialect: %w", err)
+	}
+	return nil
+}
+
+// parseDelimiterQuoteEscape parses the delimiter, quote and escape flags of
+// one side of the conversion; prefix is "" for input and "out-" for output
+func parseDelimiterQuoteEscape(d *model.CSVDialect, delimiter, quote, escape, prefix string) error {
+	var err error
+	if delimiter != "" {
+		if d.Delimiter, err = model.ParseDelimiterName(delimiter); err != nil {
+			return fmt.Errorf("invalid -%sdelimiter: %w", prefix, err)
+		}
+	}
+
+	switch {
+	case quote == "":
+	case strings.EqualFold(quote, "none"):
+		d.Quote = model.NoQuote
+	case utf8.RuneCountInString(quote) == 1:
+		d.Quote, _ = utf8.DecodeRuneInString(quote)
+	default:
+		return fmt.Errorf("invalid -%squote %q (expected a single character or none)", prefix, quote)
+	}
+
+	switch {
+	case escape == "" || strings.EqualFold(escape, "none"):
+	case strings.EqualFold(escape, "backslash"):
+		d.Escape = '\\'
+	case utf8.RuneCountInString(escape) == 1:
+		d.Escape, _ = utf8.DecodeRuneInString(escape)
+	default:
+		return fmt.Errorf("invalid -%sescape %q (expected a single character or backslash)", prefix, escape)
+	}
+	return nil
+}
+
+// parseHeaderPolicy parses the header normalization flags. Unless told
+// otherwise, repeated headers are renamed for formats that key values by
+// header, where all but one of the columns would be lost
+func parseHeaderPolicy(duplicates, empty, headerCase string, config *Config) error {
+	var err error
+	if duplicates != "" {
+		if config.Headers.Duplicates, err = model.ParseDuplicatePolicy(duplicates); err != nil {
+			return fmt.Errorf("invalid -duplicate-headers: %w", err)
+		}
+	} else if config.OutputFormat.KeysByHeader() {
+		config.Headers.Duplicates = model.DuplicateRename
+	}
+	if config.Headers.Empty, err = model.ParseEmptyPolicy(empty); err != nil {
+		return fmt.Errorf("invalid -empty-headers: %w", err)
+	}
+	if config.Headers.Case, err = model.ParseHeaderCase(headerCase); err != nil {
+		return fmt.Errorf("invalid -header-case: %w", err)
+	}
+	return nil
+}
+
+// transformFlags holds the raw row filtering and column selection flags
+type transformFlags struct {
+	assignments                    []assignment
+	where                          string
+	distinct                       bool
+	dedupeBy, keep                 string
+	dedupeIgnoreCase               bool
+	reportDuplicates               bool
+	groupBy, agg                   string
+	sort, sortLocale               string
+	selectColumns, exclude, rename string
+	pivot, unpivot                 string
+	transpose                      bool
+}
+
+// assignment is the value of an -add or -set flag
+type assignment struct {
+	spec    string
+	replace bool // -set
+}
+
+// assignmentFlag collects the -add and -set flags into one list, so that
+// they are applied in command-line order
+type assignmentFlag struct {
+	list    *[]assignment
+	replace bool
+}
+
+func (f assignmentFlag) String() string {
+	return ""
+}
+
+func (f assignmentFlag) Set(s string) error {
+	*f.list = append(*f.list, assignment{spec: s, replace: f.replace})
+	return nil
+}
+
+// parseTransforms builds the transform pipeline from the flags. Computed
+// columns come first, in command-line order, so that every later step can
+// use them. Rows are then filtered, deduplicated, grouped and sorted.
+// Columns are selected and dropped by their original names before they
+// are renamed. The table is reshaped last, using the final column names
+func parseTransforms(opts transformFlags, config *Config) error {
+	for _, a := range opts.assignments {
+		c, err := transform.NewCompute(a.spec, a.replace)
+		if err != nil {
+			flagName := "-add"
+			if a.replace {
+				flagName = "-set"
+			}
+			return fmt.Errorf("invalid %s: %w", flagName, err)
+		}
+		config.Transforms = append(config.Transforms, c)
+	}
+	if opts.where != "" {
+		w, err := transform.NewWhere(opts.where)
+		if err != nil {
+			return fmt.Errorf("invalid -where: %w", err)
+		}
+		config.Transforms = append(config.Transforms, w)
+	}
+	if opts.distinct || opts.dedupeBy != "" {
+		if opts.distinct && opts.dedupeBy != "" {
+			return fmt.Errorf("-distinct and -dedupe-by cannot be combined")
+		}
+		d, err := transform.NewDedupe(opts.dedupeBy)
+		if err != nil {
+			return fmt.Errorf("invalid -dedupe-by: %w", err)
+		}
+		if d.KeepLast, err = transform.ParseKeep(opts.keep); err != nil {
+			return fmt.Errorf("invalid -keep: %w", err)
+		}
+		d.IgnoreCase = opts.dedupeIgnoreCase
+		d.Report = opts.reportDuplicates
+		config.Transforms = append(config.Transforms, d)
+	} else if opts.keep != "" || opts.dedupeIgnoreCase || opts.reportDuplicates {
+		return fmt.Errorf("-keep, -dedupe-ignore-case and -report-duplicates require -distinct or -dedupe-by")
+	}
+	if opts.groupBy != "" || opts.agg != "" {
+		aggs := opts.agg
+		if aggs == "" {
+			aggs = "count(*)"
+		}
+		g, err := transform.NewGroupBy(opts.groupBy, aggs)
+		if err != nil {
+			return fmt.Errorf("invalid -agg: %w", err)
+		}
+		config.Transforms = append(config.Transforms, g)
+	}
+	if opts.sort != "" {
+		sorter, err := transform.NewSort(opts.sort, opts.sortLocale)
+		if err != nil {
+			return fmt.Errorf("invalid -sort: %w", err)
+		}
+		config.Transforms = append(config.Transforms, sorter)
+	} else if opts.sortLocale != "" {
+		return fmt.Errorf("-sort-locale requires -sort")
+	}
+	if opts.selectColumns != "" {
+		s, err := transform.NewSelect(opts.selectColumns)
+		if err != nil {
+			return fmt.Errorf("invalid -select: %w", err)
+		}
+		config.Transforms = append(config.Transforms, s)
+	}
+	if opts.exclude != "" {
+		e, err := transform.NewExclude(opts.exclude)
+		if err != nil {
+			return fmt.Errorf("invalid -exclude: %w", err)
+		}
+		config.Transforms = append(config.Transforms, e)
+	}
+	if opts.rename != "" {
+		r, err := transform.NewRename(opts.rename)
+		if err != nil {
+			return fmt.Errorf("invalid -rename: %w", err)
+		}
+		config.Transforms = append(config.Transforms, r)
+	}
+
+	reshapes := 0
+	for _, set := range []bool{opts.pivot != "", opts.unpivot != "", opts.transpose} {
+		if set {
+			reshapes++
+		}
+	}
+	if reshapes > 1 {
+		return fmt.Errorf("only one of -pivot, -unpivot and -transpose can be used")
+	}
+	if opts.pivot != "" {
+		p, err := transform.NewPivot(opts.pivot)
+		if err != nil {
+			return fmt.Errorf("invalid -pivot: %w", err)
+		}
+		config.Transforms = append(config.Transforms, p)
+	}
+	if opts.unpivot != "" {
+		u, err := transform.NewUnpivot(opts.unpivot)
+		if err != nil {
+			return fmt.Errorf("invalid -unpivot: %w", err)
+		}
+		config.Transforms = append(config.Transforms, u)
+	}
+	if opts.transpose {
+		config.Transforms = append(config.Transforms, transform.Transpose{})
+	}
+	return nil
+}
+
+// isTSVPath reports whether a file is named as tab-separated values
+func isTSVPath(path string) bool {
+	stem, _ := SplitCompressionSuffix(path)
+	return strings.EqualFold(filepath.Ext(stem), ".tsv")
+}
+
+// IsDocumentMode reports whether the input is scanned as a document
+// containing tables rather than read as a single table
+func (c *Config) IsDocumentMode() bool {
+	return c.TableIndex > 0 || c.AllTables || c.ListTables
+}
+
+// parseTableSelection parses a -table value: a 1-based table number or "all"
+func parseTableSelection(value string, config *Config) error {
+	if strings.EqualFold(value, "all") {
+		config.AllTables = true
+		return nil
+	}
+
+	index, err := strconv.Atoi(value)
+	if err != nil || index < 1 {
+		return fmt.Errorf("invalid -table value %q (expected a table number starting at 1, or all)", value)
+	}
+	config.TableIndex = index
+	return nil
+}
+
 // validateConfig validates the parsed configuration
 func validateConfig(config *Config) error {
-	isStdin := config.InputFile == "" || config.InputFile == "-"
 	isStdout := config.OutputFile == "" || config.OutputFile == "-"
 
-	// If reading from stdin, input format must be specified
-	if isStdin && config.InputFormat == "" {
-		return errors.New("input format required when reading from stdin (use -in flag)")
+	if config.Archive != ArchiveNone {
+		if config.IsDocumentMode() {
+			return errors.New("-table and -list-tables cannot be used with archive input")
+		}
+		if config.OutputFormat == "" {
+			return errors.New("output format required for archive input (use -out flag)")
+		}
+	}
+
+	// Listing tables and reporting detection produce no converted output
+	if config.ListTables || config.ShowDetection {
+		return nil
 	}
 
 	// If writing to stdout, output format must be specified
@@ -143,6 +698,18 @@ func validateConfig(config *Config) error {
 		return errors.New("output format required when writing to stdout (use -out flag)")
 	}
 
+	// Several JSON, XML, YAML or Excel tables written one after another do
+	// not form a single valid document
+	if isStdout && (config.AllTables || config.Archive != ArchiveNone) {
+		switch config.OutputFormat {
+		case FormatJSON, FormatXML, FormatYAML, FormatExcel:
+			if config.AllTables {
+				return fmt.Errorf("-table all cannot write %s to stdout; give an output file to write each table to its own file", config.OutputFormat)
+			}
+			return fmt.Errorf("archive input cannot be written as %s to stdout; give an output directory to write each member to its own file", config.OutputFormat)
+		}
+	}
+
 	return nil
 }
 
@@ -152,10 +719,16 @@ func printUsage(w io.Writer) {
 
 Usage:
   morph [OPTIONS] [INPUT_FILE] [OUTPUT_FILE]
+  morph fmt [-check] [FILE...]    Reformat tables inside documents (see morph fmt -h)
+  morph query SQL [FILE...]       Run a SQL SELECT over tables (see morph query -h)
+  morph join LEFT RIGHT [OUTPUT]  Join two tables on key columns (see morph join -h)
+  morph cat FILE...               Stack the rows of several tables (see morph cat -h)
 
 Options:
-  -in <format>      Input format (csv|excel|yaml|json|html|xml|markdown|ascii)
-  -out <format>     Output format (csv|excel|yaml|json|html|xml|markdown|ascii)
+  -in <format>      Input format (auto|csv|excel|yaml|json|html|xml|markdown|ascii|fixed)
+                    Without -in, the format comes from the file extension, or
+                    from the content for stdin and unknown extensions
+  -out <format>     Output format (csv|excel|yaml|json|html|xml|markdown|ascii|fixed)
   -f <style>        Format style variant (for ascii output)
                       md         - Markdown table style
                       psql       - PostgreSQL aligned format
@@ -163,14 +736,130 @@ Options:
                       org        - Emacs org-mode style
                       rst-grid   - reStructuredText grid table
                       rst-simple - reStructuredText simple table
+                      unicode    - Unicode single-line box
+                      double     - Unicode double-line box
+                      rounded    - Unicode box with rounded corners
+                      heavy      - Unicode heavy-line box
+                      simple     - Borderless columns with a dashed header rule
+                      psql-expanded  - PostgreSQL expanded display (\x)
+                      mysql-vertical - MySQL vertical output (\G)
+                      sqlite-line    - SQLite .mode line
+  -max-width <spec> Limit ASCII table width (for ascii output)
+                      N          - Total table width, shrinking the widest columns first
+                      col:N,...  - Width of individual columns
+                      auto       - Fit the table to the terminal width
+  -wrap             Word-wrap cells that exceed the width limit
+  -truncate         Truncate cells that exceed the width limit (default)
+  -numeric-align <mode>
+                    Alignment of numeric columns (for ascii output)
+                      right      - Right-align numbers (default)
+                      left       - Left-align numbers like text
+                      decimal    - Right-align with decimal points lined up
+  -columns <spec>   Fixed-width columns as name:start-end[:align],... with
+                    1-based inclusive positions and optional left|center|right
+                    alignment. Without it, columns are inferred from the data
+  -columns-file <path>
+                    Read fixed-width columns from a file, one spec per line
+  -pad-char <c>     Fixed-width padding character (default: space)
+  -table <N|all>    Extract table N (starting at 1) or all tables from a
+                    Markdown, Org-mode, reStructuredText or plain text document.
+                    With an output file, "all" writes one numbered file per table
+  -list-tables      List the tables found in a document with their line numbers
+  -detect           Report the detected input format and why, without converting
+  -delimiter <d>    CSV input delimiter: one or more characters, or comma, tab,
+                    semicolon, pipe or space (default: detected)
+  -quote <c>        CSV input quote character, or none (default: ")
+  -escape <c>       CSV input escape character, e.g. backslash (default: quotes
+                    are doubled)
+  -comment <prefix> Skip CSV input lines starting with the prefix
+  -skip-lines <N>   Skip N CSV input lines before the header
+  -no-header        CSV input has no header line; columns are named col1..colN
+  -out-delimiter <d>, -out-quote <c>, -out-escape <c>
+                    Delimiter, quote and escape character of CSV output
+  -quoting <mode>   Which CSV output fields are quoted: minimal (default), all,
+                    or never (escaping delimiters and line breaks instead)
+  -crlf             End CSV output lines with CRLF
+  -strict           Reject ragged rows and duplicate or empty headers. Without
+                    it, ragged rows are padded or truncated with a warning
+  -duplicate-headers <policy>
+                    Repeated headers: keep, rename (Amount_2) or error
+                    (default: rename for json, yaml and xml output, else keep)
+  -empty-headers <policy>
+                    Empty headers: keep (default), generate (column_3) or error
+  -header-case <case>
+                    Convert headers to keep (default), snake, camel or lower case
+  -add <column = expression>
+                    Add a column computed for each row, e.g. 'total = price * qty'.
+                    Expressions use the functions of -where; repeat the flag to
+                    add several columns
+  -set <column = expression>
+                    Replace the values of a column, e.g. 'email = lower(trim(email))'
+  -where <condition>
+                    Keep only rows where the condition is true, for example
+                    'status == "active" && amount > 100'
+  -distinct         Drop rows that repeat an earlier row
+  -dedupe-by <columns>
+                    Drop rows whose key columns repeat another row's. Values
+                    compare as -where compares them, so 1 equals 1.0. The
+                    number of rows removed is reported on stderr
+  -keep <row>       Row to keep of each set of duplicates: first (default) or last
+  -dedupe-ignore-case
+                    Compare text without regard to case when finding duplicates
+  -report-duplicates
+                    Write the duplicate groups instead of dropping rows: their
+                    key columns, duplicate_count and duplicate_rows
+  -group-by <columns>
+                    Summarize the rows sharing these columns into one row each
+  -agg <list>       Summary columns of -group-by, e.g.
+                    'count(*),sum(amount) as total,count_distinct(customer)'
+                    (count, count_distinct, sum, avg, min or max; default
+                    count(*)). Without -group-by the whole table is summarized
+  -sort <keys>      Sort rows by columns, e.g. amount:desc,name. Numbers, dates
+                    and booleans are compared by value; empty cells sort last
+  -sort-locale <tag>
+                    Order text by the rules of a language such as de or sv
+  -select <columns> Keep only these columns, in this order. Columns are names,
+                    globs such as addr_* or /regexps/, separated by commas
+  -exclude <columns>
+                    Drop these columns
+  -rename <old:new,...>
+                    Rename columns, after -select and -exclude
+  -pivot <spec>     Turn the values of a column into columns, e.g.
+                    index=region,columns=month,values=amount,agg=sum
+                    (agg: count, count_distinct, sum, avg, min or max)
+  -unpivot <spec>   Turn columns into rows of variable and value, e.g.
+                    id_cols=id,name (also value_cols, var_name, value_name)
+  -transpose        Swap rows and columns; the first column becomes the headers
+  -input-encoding <name>
+                    Character encoding of text input (default: auto, from a
+                    byte order mark or the content). For example utf-8,
+                    utf-16, utf-16le, utf-16be, windows-1252, latin1
+  -output-encoding <name>
+                    Character encoding of text output (default: utf-8).
+                    utf-8-bom adds a byte order mark so Excel reads CSV as UTF-8
   -h, --help        Show help message
   -v, --version     Show version
 
+Compressed files and archives:
+  Input compressed with gzip, zstd, bzip2 or xz is decompressed, and output
+  named *.gz, *.zst, *.bz2 or *.xz is compressed. Zip and tar archives are
+  converted member by member into the OUTPUT_FILE directory, or to stdout.
+
 Examples:
   morph data.csv output.json
   morph data.csv -out ascii -f md
   morph data.psql -out ascii -f rst-grid
+  morph -out ascii -max-width auto -wrap data.csv
+  morph -in fixed -columns id:1-6,name:7-26,amount:27-38:right accounts.dat accounts.csv
+  morph -list-tables README.md
+  morph -table 2 README.md table.csv
   morph -in json -out yaml < input.json > output.yaml
+  morph -detect < unknown-data
+  morph sales.csv.gz sales.json.zst
+  morph -output-encoding utf-8-bom data.json excel-friendly.csv
+  morph -delimiter '::' -escape backslash -comment '#' export.dat export.csv
+  morph -out-delimiter tab -quoting never -out-escape backslash data.csv data.tsv
+  morph -out json exports.zip json-dir
   echo '[{"a":1}]' | morph -in json -out csv
 
 Supported formats:
@@ -181,8 +870,9 @@ Supported formats:
   html      - HTML table                     [aliases: htm]
   xml       - XML dataset
   markdown  - GitHub-flavored markdown table [aliases: md]
-  ascii     - ASCII table (auto-detects md, psql, box, org, rst formats)
+  ascii     - ASCII table (auto-detects md, psql, box, org, rst, unicode formats)
                                              [aliases: txt, table]
+  fixed     - Fixed-width column text        [aliases: fixed-width, fw]
 `
 	fmt.Fprint(w, usage)
 }
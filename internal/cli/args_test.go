@@ -4,6 +4,9 @@ import (
 	"bytes"
 	"strings"
 	"testing"
+
+	"github.com/user/table-converter/internal/model"
+	"github.com/user/table-converter/internal/transform"
 )
 
 func TestParseArgs_ValidFlagCombinations(t *testing.T) {
@@ -131,11 +134,6 @@ func TestParseArgs_InvalidFlagCombinations(t *testing.T) {
 		args        []string
 		wantErrMsg  string
 	}{
-		{
-			name:       "stdin without input format",
-			args:       []string{"-out", "csv"},
-			wantErrMsg: "input format required when reading from stdin",
-		},
 		{
 			name:       "stdout without output format",
 			args:       []string{"-in", "csv"},
@@ -151,11 +149,6 @@ func TestParseArgs_InvalidFlagCombinations(t *testing.T) {
 			args:       []string{"-in", "csv", "-out", "invalid"},
 			wantErrMsg: "unsupported format",
 		},
-		{
-			name:       "unknown file extension for input",
-			args:       []string{"input.xyz", "output.csv"},
-			wantErrMsg: "cannot determine input format",
-		},
 		{
 			name:       "unknown file extension for output",
 			args:       []string{"input.csv", "output.xyz"},
@@ -283,3 +276,518 @@ func TestParseArgsWithOutput(t *testing.T) {
 		t.Error("ShowHelp = false, want true")
 	}
 }
+
+func TestParseArgs_MaxWidth(t *testing.T) {
+	tests := []struct {
+		name         string
+		args         []string
+		wantMaxWidth int
+		wantColumns  map[string]int
+		wantAuto     bool
+		wantOverflow string
+		wantErr      bool
+	}{
+		{
+			name:         "total width",
+			args:         []string{"-out", "ascii", "-max-width", "80", "in.csv"},
+			wantMaxWidth: 80,
+		},
+		{
+			name:        "per column widths",
+			args:        []string{"-out", "ascii", "-max-width", "name:10,notes:40", "in.csv"},
+			wantColumns: map[string]int{"name": 10, "notes": 40},
+		},
+		{
+			name:         "total and column widths with wrap",
+			args:         []string{"-out", "ascii", "-max-width", "100,notes:40", "-wrap", "in.csv"},
+			wantMaxWidth: 100,
+			wantColumns:  map[string]int{"notes": 40},
+			wantOverflow: "wrap",
+		},
+		{
+			name:         "auto width with truncate",
+			args:         []string{"-out", "ascii", "--max-width", "auto", "--truncate", "in.csv"},
+			wantAuto:     true,
+			wantOverflow: "truncate",
+		},
+		{
+			name:    "invalid width",
+			args:    []string{"-out", "ascii", "-max-width", "wide", "in.csv"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid column width",
+			args:    []string{"-out", "ascii", "-max-width", "name:0", "in.csv"},
+			wantErr: true,
+		},
+		{
+			name:    "wrap and truncate together",
+			args:    []string{"-out", "ascii", "-max-width", "80", "-wrap", "-truncate", "in.csv"},
+			wantErr: true,
+		},
+		{
+			name:    "wrap without a width limit",
+			args:    []string{"-out", "ascii", "-wrap", "in.csv"},
+			wantErr: true,
+		},
+		{
+			name:    "truncate without a width limit",
+			args:    []string{"-out", "ascii", "-truncate", "in.csv"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config, err := ParseArgs(tt.args)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseArgs() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			if config.MaxWidth != tt.wantMaxWidth {
+				t.Errorf("MaxWidth = %d, want %d", config.MaxWidth, tt.wantMaxWidth)
+			}
+			if config.AutoWidth != tt.wantAuto {
+				t.Errorf("AutoWidth = %v, want %v", config.AutoWidth, tt.wantAuto)
+			}
+			if config.Overflow != tt.wantOverflow {
+				t.Errorf("Overflow = %q, want %q", config.Overflow, tt.wantOverflow)
+			}
+			if len(config.MaxColumnWidths) != len(tt.wantColumns) {
+				t.Fatalf("MaxColumnWidths = %v, want %v", config.MaxColumnWidths, tt.wantColumns)
+			}
+			for name, width := range tt.wantColumns {
+				if config.MaxColumnWidths[name] != width {
+					t.Errorf("MaxColumnWidths[%q] = %d, want %d", name, config.MaxColumnWidths[name], width)
+				}
+			}
+		})
+	}
+}
+
+func TestTerminalWidth_ColumnsEnv(t *testing.T) {
+	t.Setenv("COLUMNS", "132")
+	if got := TerminalWidth(); got != 132 {
+		t.Errorf("TerminalWidth() = %d, want 132", got)
+	}
+}
+
+func TestParseArgs_TableSelection(t *testing.T) {
+	tests := []struct {
+		name       string
+		args       []string
+		wantIndex  int
+		wantAll    bool
+		wantList   bool
+		wantFormat Format
+		wantErr    bool
+	}{
+		{
+			name:       "table number",
+			args:       []string{"-table", "2", "-out", "csv", "README"},
+			wantIndex:  2,
+			wantFormat: FormatASCII,
+		},
+		{
+			name:       "all tables",
+			args:       []string{"-table", "all", "notes.org", "out.csv"},
+			wantAll:    true,
+			wantFormat: FormatASCII,
+		},
+		{
+			name:       "explicit input format",
+			args:       []string{"-in", "md", "-table", "1", "-out", "json"},
+			wantIndex:  1,
+			wantFormat: FormatMarkdown,
+		},
+		{
+			name:       "list tables without output format",
+			args:       []string{"-list-tables", "doc.rst"},
+			wantList:   true,
+			wantFormat: FormatASCII,
+		},
+		{
+			name:    "zero table number",
+			args:    []string{"-table", "0", "-out", "csv", "doc.md"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid table number",
+			args:    []string{"-table", "first", "-out", "csv", "doc.md"},
+			wantErr: true,
+		},
+		{
+			name:       "all tables to stdout as csv",
+			args:       []string{"-table", "all", "-out", "csv", "doc.md"},
+			wantAll:    true,
+			wantFormat: FormatMarkdown,
+		},
+		{
+			name:    "all tables to stdout as json",
+			args:    []string{"-table", "all", "-out", "json", "doc.md"},
+			wantErr: true,
+		},
+		{
+			name:       "all tables to json files",
+			args:       []string{"-table", "all", "doc.md", "tables.json"},
+			wantAll:    true,
+			wantFormat: FormatMarkdown,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config, err := ParseArgs(tt.args)
+			if tt.wantErr {
+				if err == nil {
+					t.Error("expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if config.TableIndex != tt.wantIndex {
+				t.Errorf("TableIndex = %d, want %d", config.TableIndex, tt.wantIndex)
+			}
+			if config.AllTables != tt.wantAll {
+				t.Errorf("AllTables = %v, want %v", config.AllTables, tt.wantAll)
+			}
+			if config.ListTables != tt.wantList {
+				t.Errorf("ListTables = %v, want %v", config.ListTables, tt.wantList)
+			}
+			if config.InputFormat != tt.wantFormat {
+				t.Errorf("InputFormat = %q, want %q", config.InputFormat, tt.wantFormat)
+			}
+		})
+	}
+}
+
+func TestParseArgs_FixedWidth(t *testing.T) {
+	config, err := ParseArgs([]string{"-in", "fw", "-columns", "id:1-4:right,name:5-14", "-pad-char", "0", "-out", "csv", "in.dat"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if config.InputFormat != FormatFixed {
+		t.Errorf("InputFormat = %q, want %q", config.InputFormat, FormatFixed)
+	}
+	if len(config.Columns) != 2 || config.Columns[1].Name != "name" || config.Columns[1].End != 14 {
+		t.Errorf("unexpected columns: %+v", config.Columns)
+	}
+	if config.PadChar != '0' {
+		t.Errorf("PadChar = %q, want '0'", config.PadChar)
+	}
+
+	invalid := [][]string{
+		{"-in", "fixed", "-columns", "id:4-1", "-out", "csv", "in.dat"},
+		{"-in", "fixed", "-pad-char", "ab", "-out", "csv", "in.dat"},
+		{"-in", "csv", "-out", "fixed", "-pad-char", "0", "in.csv"},
+		{"-in", "fixed", "-columns", "id:1-4", "-columns-file", "spec.txt", "-out", "csv", "in.dat"},
+		{"-in", "fixed", "-columns-file", "/nonexistent/spec.txt", "-out", "csv", "in.dat"},
+	}
+	for _, args := range invalid {
+		if _, err := ParseArgs(args); err == nil {
+			t.Errorf("expected error for %v", args)
+		}
+	}
+}
+
+func TestParseArgs_AutoInputFormat(t *testing.T) {
+	tests := []struct {
+		name       string
+		args       []string
+		wantFormat Format
+	}{
+		{"stdin without input format", []string{"-out", "csv"}, FormatAuto},
+		{"explicit auto", []string{"-in", "auto", "data.csv", "out.json"}, FormatAuto},
+		{"unknown extension", []string{"input.xyz", "output.csv"}, FormatAuto},
+		{"no extension", []string{"export", "output.csv"}, FormatAuto},
+		{"known extension", []string{"input.json", "output.csv"}, FormatJSON},
+		{"detect report", []string{"-detect", "input.json"}, FormatAuto},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config, err := ParseArgs(tt.args)
+			if err != nil {
+				t.Fatalf("ParseArgs() error = %v", err)
+			}
+			if config.InputFormat != tt.wantFormat {
+				t.Errorf("InputFormat = %q, want %q", config.InputFormat, tt.wantFormat)
+			}
+		})
+	}
+}
+
+func TestParseArgs_Encodings(t *testing.T) {
+	config, err := ParseArgs([]string{"-input-encoding", "utf-16le", "-output-encoding", "utf-8-bom", "in.csv", "out.csv"})
+	if err != nil {
+		t.Fatalf("ParseArgs() error = %v", err)
+	}
+	if config.InputEncoding != "utf-16le" || config.OutputEncoding != "utf-8-bom" {
+		t.Errorf("encodings = %q, %q", config.InputEncoding, config.OutputEncoding)
+	}
+
+	config, err = ParseArgs([]string{"in.csv", "out.csv"})
+	if err != nil {
+		t.Fatalf("ParseArgs() error = %v", err)
+	}
+	if config.InputEncoding != EncodingAuto || config.OutputEncoding != "utf-8" {
+		t.Errorf("default encodings = %q, %q", config.InputEncoding, config.OutputEncoding)
+	}
+
+	for _, args := range [][]string{
+		{"-input-encoding", "ebcdic-ish", "in.csv", "out.csv"},
+		{"-output-encoding", "auto", "in.csv", "out.csv"},
+	} {
+		if _, err := ParseArgs(args); err == nil || !strings.Contains(err.Error(), "encoding") {
+			t.Errorf("ParseArgs(%v) error = %v, want an encoding error", args, err)
+		}
+	}
+}
+
+func TestParseArgs_CSVDialect(t *testing.T) {
+	config, err := ParseArgs([]string{
+		"-delimiter", "::", "-quote", "'", "-escape", "backslash", "-comment", "#", "-skip-lines", "2", "-no-header",
+		"-out", "csv", "-out-delimiter", "tab", "-out-quote", "none", "-out-escape", "\\", "-quoting", "never", "-crlf",
+	})
+	if err != nil {
+		t.Fatalf("ParseArgs() error = %v", err)
+	}
+	wantIn := model.CSVDialect{Delimiter: "::", Quote: '\'', Escape: '\\', Comment: "#", SkipLines: 2, NoHeader: true}
+	if config.CSVInput != wantIn {
+		t.Errorf("CSVInput = %+v, want %+v", config.CSVInput, wantIn)
+	}
+	wantOut := model.CSVDialect{Delimiter: "\t", Quote: model.NoQuote, Escape: '\\', LineTerminator: "\r\n", Quoting: model.QuoteNever}
+	if config.CSVOutput != wantOut {
+		t.Errorf("CSVOutput = %+v, want %+v", config.CSVOutput, wantOut)
+	}
+	if config.InputFormat != FormatCSV {
+		t.Errorf("InputFormat = %q, want csv for stdin with CSV options", config.InputFormat)
+	}
+
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{"quote of two characters", []string{"-quote", "''", "-out", "json"}},
+		{"unknown quoting", []string{"-quoting", "sometimes", "-out", "json"}},
+		{"escape equals quote", []string{"-quote", "'", "-escape", "'", "-out", "json"}},
+		{"output delimiter with line break", []string{"-out-delimiter", "\n", "-out", "csv"}},
+		{"negative skip", []string{"-skip-lines", "-1", "-out", "json"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseArgs(tt.args); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
+
+func TestParseArgs_TSVFiles(t *testing.T) {
+	config, err := ParseArgs([]string{"data.tsv", "out.tsv.gz"})
+	if err != nil {
+		t.Fatalf("ParseArgs() error = %v", err)
+	}
+	if config.InputFormat != FormatCSV || config.OutputFormat != FormatCSV {
+		t.Errorf("formats = %q, %q, want csv", config.InputFormat, config.OutputFormat)
+	}
+	if config.CSVInput.Delimiter != "\t" || config.CSVOutput.Delimiter != "\t" {
+		t.Errorf("delimiters = %q, %q, want tabs", config.CSVInput.Delimiter, config.CSVOutput.Delimiter)
+	}
+}
+
+func TestParseArgs_HeaderPolicy(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want model.HeaderPolicy
+	}{
+		{"json renames duplicates", []string{"in.csv", "out.json"}, model.HeaderPolicy{Duplicates: model.DuplicateRename}},
+		{"csv keeps duplicates", []string{"in.xlsx", "out.csv"}, model.HeaderPolicy{}},
+		{"explicit keep", []string{"-duplicate-headers", "keep", "in.csv", "out.yaml"}, model.HeaderPolicy{}},
+		{"all options", []string{"-duplicate-headers", "error", "-empty-headers", "generate", "-header-case", "snake", "in.csv", "out.csv"},
+			model.HeaderPolicy{Duplicates: model.DuplicateError, Empty: model.EmptyGenerate, Case: model.CaseSnake}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config, err := ParseArgs(tt.args)
+			if err != nil {
+				t.Fatalf("ParseArgs() error = %v", err)
+			}
+			if config.Headers != tt.want {
+				t.Errorf("Headers = %+v, want %+v", config.Headers, tt.want)
+			}
+		})
+	}
+
+	for _, args := range [][]string{
+		{"-duplicate-headers", "merge", "in.csv", "out.json"},
+		{"-empty-headers", "drop", "in.csv", "out.json"},
+		{"-header-case", "kebab", "in.csv", "out.json"},
+	} {
+		if _, err := ParseArgs(args); err == nil || !strings.Contains(err.Error(), "-") {
+			t.Errorf("ParseArgs(%v) error = %v, want a header policy error", args, err)
+		}
+	}
+}
+
+func TestParseArgs_ColumnTransforms(t *testing.T) {
+	config, err := ParseArgs([]string{"-rename", "id:ID", "-select", "id,addr_*", "-exclude", "addr_zip", "-where", "id > 1", "-sort", "id:desc", "in.csv", "out.json"})
+	if err != nil {
+		t.Fatalf("ParseArgs() error = %v", err)
+	}
+	if len(config.Transforms) != 5 {
+		t.Fatalf("len(Transforms) = %d, want 5", len(config.Transforms))
+	}
+	if _, ok := config.Transforms[0].(*transform.Where); !ok {
+		t.Errorf("first transform = %T, want *transform.Where", config.Transforms[0])
+	}
+	if _, ok := config.Transforms[1].(*transform.Sort); !ok {
+		t.Errorf("second transform = %T, want *transform.Sort", config.Transforms[1])
+	}
+	if _, ok := config.Transforms[4].(*transform.Rename); !ok {
+		t.Errorf("last transform = %T, want *transform.Rename", config.Transforms[4])
+	}
+
+	for _, args := range [][]string{
+		{"-select", "/[/", "in.csv", "out.json"},
+		{"-exclude", ",", "in.csv", "out.json"},
+		{"-rename", "a", "in.csv", "out.json"},
+		{"-where", "a >", "in.csv", "out.json"},
+		{"-sort", "a:sideways", "in.csv", "out.json"},
+		{"-sort-locale", "de", "in.csv", "out.json"},
+	} {
+		if _, err := ParseArgs(args); err == nil {
+			t.Errorf("ParseArgs(%v) error = nil", args)
+		}
+	}
+}
+
+func TestParseArgs_Reshape(t *testing.T) {
+	config, err := ParseArgs([]string{"-rename", "amt:amount", "-pivot", "index=region,columns=month,values=amount,agg=sum", "in.csv", "out.json"})
+	if err != nil {
+		t.Fatalf("ParseArgs() error = %v", err)
+	}
+	if len(config.Transforms) != 2 {
+		t.Fatalf("len(Transforms) = %d, want 2", len(config.Transforms))
+	}
+	if _, ok := config.Transforms[1].(*transform.Pivot); !ok {
+		t.Errorf("last transform = %T, want *transform.Pivot", config.Transforms[1])
+	}
+
+	for _, args := range [][]string{
+		{"-pivot", "index=region", "in.csv", "out.json"},
+		{"-unpivot", "var_name=x", "in.csv", "out.json"},
+		{"-transpose", "-unpivot", "id_cols=id", "in.csv", "out.json"},
+	} {
+		if _, err := ParseArgs(args); err == nil {
+			t.Errorf("ParseArgs(%v) error = nil", args)
+		}
+	}
+}
+
+func TestParseArgs_GroupBy(t *testing.T) {
+	config, err := ParseArgs([]string{"-sort", "total:desc", "-where", "amount > 0", "-group-by", "region", "-agg", "sum(amount) as total", "in.csv", "out.json"})
+	if err != nil {
+		t.Fatalf("ParseArgs() error = %v", err)
+	}
+	if len(config.Transforms) != 3 {
+		t.Fatalf("len(Transforms) = %d, want 3", len(config.Transforms))
+	}
+	g, ok := config.Transforms[1].(*transform.GroupBy)
+	if !ok {
+		t.Fatalf("second transform = %T, want *transform.GroupBy", config.Transforms[1])
+	}
+	if len(g.Aggregations) != 1 || g.Aggregations[0].Name != "total" {
+		t.Errorf("Aggregations = %+v, want sum(amount) as total", g.Aggregations)
+	}
+
+	config, err = ParseArgs([]string{"-group-by", "region", "in.csv", "out.json"})
+	if err != nil {
+		t.Fatalf("ParseArgs() error = %v", err)
+	}
+	if g := config.Transforms[0].(*transform.GroupBy); g.Aggregations[0].String() != "count(*)" {
+		t.Errorf("default aggregation = %s, want count(*)", g.Aggregations[0])
+	}
+
+	if _, err := ParseArgs([]string{"-agg", "median(amount)", "in.csv", "out.json"}); err == nil {
+		t.Error("ParseArgs() with an unknown aggregate error = nil")
+	}
+}
+
+func TestParseArgs_Compute(t *testing.T) {
+	config, err := ParseArgs([]string{"-where", "total > 1", "-add", "total = price * qty", "-set", "email = lower(email)", "-add", "tax = total * 0.2", "in.csv", "out.json"})
+	if err != nil {
+		t.Fatalf("ParseArgs() error = %v", err)
+	}
+	if len(config.Transforms) != 4 {
+		t.Fatalf("len(Transforms) = %d, want 4", len(config.Transforms))
+	}
+	want := []struct {
+		column  string
+		replace bool
+	}{{"total", false}, {"email", true}, {"tax", false}}
+	for i, w := range want {
+		c, ok := config.Transforms[i].(*transform.Compute)
+		if !ok {
+			t.Fatalf("transform %d = %T, want *transform.Compute", i, config.Transforms[i])
+		}
+		if c.Column != w.column || c.Replace != w.replace {
+			t.Errorf("transform %d = %s (replace %v), want %s (replace %v)", i, c.Column, c.Replace, w.column, w.replace)
+		}
+	}
+
+	for _, args := range [][]string{
+		{"-add", "total", "in.csv", "out.json"},
+		{"-set", "email = lower(", "in.csv", "out.json"},
+	} {
+		if _, err := ParseArgs(args); err == nil {
+			t.Errorf("ParseArgs(%v) error = nil", args)
+		}
+	}
+}
+
+func TestParseArgs_Dedupe(t *testing.T) {
+	config, err := ParseArgs([]string{"-dedupe-by", "email", "-keep", "last", "-dedupe-ignore-case", "-where", "amount > 0", "in.csv", "out.json"})
+	if err != nil {
+		t.Fatalf("ParseArgs() error = %v", err)
+	}
+	if len(config.Transforms) != 2 {
+		t.Fatalf("len(Transforms) = %d, want 2", len(config.Transforms))
+	}
+	d, ok := config.Transforms[1].(*transform.Dedupe)
+	if !ok {
+		t.Fatalf("second transform = %T, want *transform.Dedupe", config.Transforms[1])
+	}
+	if len(d.Columns) != 1 || d.Columns[0] != "email" || !d.KeepLast || !d.IgnoreCase || d.Report {
+		t.Errorf("Dedupe = %+v", d)
+	}
+
+	config, err = ParseArgs([]string{"-distinct", "-report-duplicates", "in.csv", "out.json"})
+	if err != nil {
+		t.Fatalf("ParseArgs() error = %v", err)
+	}
+	if d := config.Transforms[0].(*transform.Dedupe); d.Columns != nil || !d.Report {
+		t.Errorf("Dedupe = %+v, want a whole-row report", d)
+	}
+
+	for _, tt := range []struct {
+		args    []string
+		wantErr string
+	}{
+		{[]string{"-distinct", "-dedupe-by", "email", "in.csv", "out.json"}, "cannot be combined"},
+		{[]string{"-dedupe-by", "email", "-keep", "middle", "in.csv", "out.json"}, "invalid -keep"},
+		{[]string{"-dedupe-by", ",", "in.csv", "out.json"}, "invalid -dedupe-by"},
+		{[]string{"-keep", "last", "in.csv", "out.json"}, "require -distinct or -dedupe-by"},
+		{[]string{"-report-duplicates", "in.csv", "out.json"}, "require -distinct or -dedupe-by"},
+	} {
+		if _, err := ParseArgs(tt.args); err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("ParseArgs(%v) error = %v, want %q", tt.args, err, tt.wantErr)
+		}
+	}
+}
@@ -0,0 +1,182 @@
+package cli
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/user/table-converter/internal/transform"
+)
+
+// CatConfig holds the parsed configuration of the cat subcommand
+type CatConfig struct {
+	Files          []string // Input files ("-" for stdin)
+	OutputFile     string   // Output file path (empty for stdout)
+	SourceColumn   string   // Column naming each row's input file ("" = none)
+	StrictTypes    bool     // Fail instead of warning when a column's type differs between files
+	InputFormat    Format   // Format of every input ("" = detect per input)
+	OutputFormat   Format   // Format of the result
+	FormatStyle    string   // Style variant of the output format
+	InputEncoding  string   // Character encoding of text input
+	OutputEncoding string   // Character encoding of text output
+	ShowHelp       bool     // Show help message
+}
+
+// ParseCatArgs parses the arguments of the cat subcommand. Options may
+// follow the file names
+func ParseCatArgs(args []string, output io.Writer) (*CatConfig, error) {
+	fs := flag.NewFlagSet("morph cat", flag.ContinueOnError)
+	fs.SetOutput(output)
+
+	config := &CatConfig{}
+	var inFormat, outFormat string
+	fs.StringVar(&config.OutputFile, "o", "", "Output file (default stdout)")
+	fs.StringVar(&config.SourceColumn, "source-column", "", "Add a first column with this name holding each row's input file")
+	fs.BoolVar(&config.StrictTypes, "strict-types", false, "Fail when a column holds different types in different files")
+	fs.StringVar(&inFormat, "in", "", "Input format of every file (auto|csv|excel|yaml|json|html|xml|markdown|ascii|fixed)")
+	fs.StringVar(&outFormat, "out", "", "Output format (csv|excel|yaml|json|html|xml|markdown|ascii|fixed)")
+	fs.StringVar(&config.FormatStyle, "f", "", "Format style variant (for ascii output)")
+	fs.StringVar(&config.InputEncoding, "input-encoding", EncodingAuto, "Character encoding of text input")
+	fs.StringVar(&config.OutputEncoding, "output-encoding", "utf-8", "Character encoding of text output")
+	fs.BoolVar(&config.ShowHelp, "h", false, "Show help message")
+	fs.BoolVar(&config.ShowHelp, "help", false, "Show help message")
+
+	fs.Usage = func() {
+		printCatUsage(output)
+	}
+
+	positional, err := parseInterspersed(fs, args)
+	if err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			config.ShowHelp = true
+			return config, nil
+		}
+		return nil, err
+	}
+	if config.ShowHelp {
+		return config, nil
+	}
+
+	config.Files = positional
+	if len(config.Files) == 0 {
+		config.Files = []string{"-"}
+	}
+	stdin := 0
+	for _, path := range config.Files {
+		if path == "-" {
+			stdin++
+		}
+	}
+	if stdin > 1 {
+		return nil, errors.New("only one input can be read from stdin")
+	}
+
+	if err := validateEncoding(config.InputEncoding, true); err != nil {
+		return nil, fmt.Errorf("invalid -input-encoding: %w", err)
+	}
+	if err := validateEncoding(config.OutputEncoding, false); err != nil {
+		return nil, fmt.Errorf("invalid -output-encoding: %w", err)
+	}
+	if config.InputFormat, err = inputFormatFlag(inFormat); err != nil {
+		return nil, err
+	}
+	if config.OutputFormat, err = outputTableFormat(outFormat, config.OutputFile); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// RunCat executes the cat subcommand
+func RunCat(args []string, stdout, stderr io.Writer) ExitCode {
+	config, err := ParseCatArgs(args, stderr)
+	if err != nil {
+		cliErr := FormatUsageError(err.Error())
+		fmt.Fprintln(stderr, cliErr.Message)
+		return cliErr.ExitCode
+	}
+
+	if config.ShowHelp {
+		printCatUsage(stdout)
+		return ExitSuccess
+	}
+
+	if err := CatFiles(config, stdout, stderr); err != nil {
+		cliErr := FormatError(err)
+		fmt.Fprintln(stderr, cliErr.Message)
+		return cliErr.ExitCode
+	}
+	return ExitSuccess
+}
+
+// CatFiles reads every input, stacks their rows and writes the result to
+// the output file or stdout. Columns whose type differs between files are
+// reported on stderr, or fail the command with -strict-types
+func CatFiles(config *CatConfig, stdout, stderr io.Writer) error {
+	inputs := make([]transform.UnionInput, len(config.Files))
+	for i, path := range config.Files {
+		td, err := readInputTable(path, config.InputFormat, config.InputEncoding, stderr)
+		if err != nil {
+			return err
+		}
+		source := path
+		if source == "-" {
+			source = "<stdin>"
+		}
+		inputs[i] = transform.UnionInput{Source: source, Data: td}
+	}
+
+	u := &transform.Union{SourceColumn: config.SourceColumn}
+	result, conflicts, err := u.Union(inputs)
+	if err != nil {
+		return FormatTransformError(err)
+	}
+	for _, c := range conflicts {
+		if config.StrictTypes {
+			return FormatTransformError(transform.NewTransformError(c.String()))
+		}
+		fmt.Fprintf(stderr, "Warning: %s\n", c)
+	}
+
+	return writeOutputTable(result, config.OutputFile, stdout, ConvertOptions{
+		OutputFormat:   config.OutputFormat,
+		FormatStyle:    config.FormatStyle,
+		OutputEncoding: config.OutputEncoding,
+	})
+}
+
+// printCatUsage prints the usage information of the cat subcommand
+func printCatUsage(w io.Writer) {
+	usage := `morph cat - Stack the rows of several tables into one
+
+Usage:
+  morph cat [OPTIONS] FILE...
+
+Each file is parsed in the format of its extension (or detected from its
+content), so the inputs may be in different formats. Columns are matched
+by name: the result has every column of every file, in the order they
+first appear, and empty cells where a file lacks a column. A column whose
+values have different types in different files (numbers in one, text in
+another) is reported on stderr. Without files, stdin is read.
+
+Options:
+  -o <file>         Write the result to a file; its extension sets the format
+  -source-column <name>
+                    Add a first column with this name holding each row's file
+  -strict-types     Fail instead of warning when a column's type differs
+                    between files
+  -in <format>      Input format of every file (default: from the extension)
+  -out <format>     Output format (csv|excel|yaml|json|html|xml|markdown|ascii|fixed)
+  -f <style>        Format style variant (for ascii output)
+  -input-encoding <name>
+                    Character encoding of text input (default: auto)
+  -output-encoding <name>
+                    Character encoding of text output (default: utf-8)
+  -h, --help        Show help message
+
+Examples:
+  morph cat jan.csv feb.xlsx mar.json -o year.xlsx
+  morph cat -source-column source_file reports/*.csv -out csv > all.csv
+`
+	fmt.Fprint(w, usage)
+}
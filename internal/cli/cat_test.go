@@ -0,0 +1,34 @@
+package cli
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestParseCatArgs(t *testing.T) {
+	config, err := ParseCatArgs([]string{"jan.csv", "feb.xlsx", "-source-column", "source_file", "mar.json", "-o", "year.xlsx"}, &bytes.Buffer{})
+	if err != nil {
+		t.Fatalf("ParseCatArgs() error: %v", err)
+	}
+	if want := []string{"jan.csv", "feb.xlsx", "mar.json"}; !reflect.DeepEqual(config.Files, want) {
+		t.Errorf("Files = %q, want %q", config.Files, want)
+	}
+	if config.SourceColumn != "source_file" || config.OutputFormat != FormatExcel {
+		t.Errorf("SourceColumn = %q, OutputFormat = %q", config.SourceColumn, config.OutputFormat)
+	}
+
+	config, err = ParseCatArgs([]string{"-out", "csv"}, &bytes.Buffer{})
+	if err != nil {
+		t.Fatalf("ParseCatArgs() error: %v", err)
+	}
+	if want := []string{"-"}; !reflect.DeepEqual(config.Files, want) {
+		t.Errorf("Files = %q, want stdin", config.Files)
+	}
+
+	for _, args := range [][]string{{"a.csv", "-", "-", "-out", "csv"}, {"a.csv"}} {
+		if _, err := ParseCatArgs(args, &bytes.Buffer{}); err == nil {
+			t.Errorf("ParseCatArgs(%q) succeeded, want error", args)
+		}
+	}
+}
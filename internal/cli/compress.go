@@ -0,0 +1,177 @@
+package cli
+
+import (
+	"bufio"
+	"bytes"
+	"compress/bzip2"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	dsbzip2 "github.com/dsnet/compress/bzip2"
+	"github.com/klauspost/compress/zstd"
+	"github.com/ulikunitz/xz"
+)
+
+// Compression identifies a stream compression format
+type Compression string
+
+const (
+	CompressionNone  Compression = ""
+	CompressionGzip  Compression = "gzip"
+	CompressionZstd  Compression = "zstd"
+	CompressionBzip2 Compression = "bzip2"
+	CompressionXz    Compression = "xz"
+)
+
+// compressionSuffixes maps file name suffixes to the compression they denote
+var compressionSuffixes = map[string]Compression{
+	".gz":   CompressionGzip,
+	".gzip": CompressionGzip,
+	".zst":  CompressionZstd,
+	".zstd": CompressionZstd,
+	".bz2":  CompressionBzip2,
+	".xz":   CompressionXz,
+}
+
+// compressionMagic holds the leading bytes of each compressed stream
+var compressionMagic = []struct {
+	magic       []byte
+	compression Compression
+}{
+	{[]byte{0x1f, 0x8b}, CompressionGzip},
+	{[]byte{0x28, 0xb5, 0x2f, 0xfd}, CompressionZstd},
+	{[]byte{0xfd, '7', 'z', 'X', 'Z', 0x00}, CompressionXz},
+	{[]byte("BZh"), CompressionBzip2}, // followed by a block size digit and the block magic
+}
+
+// bzip2BlockMagic follows the "BZh" and block size digit of a bzip2 stream
+var bzip2BlockMagic = []byte{0x31, 0x41, 0x59, 0x26, 0x53, 0x59}
+
+// SplitCompressionSuffix splits a compression suffix off a path, so that
+// "data.csv.gz" gives "data.csv" and gzip. Paths without one are returned
+// unchanged with CompressionNone
+func SplitCompressionSuffix(path string) (string, Compression) {
+	ext := strings.ToLower(filepath.Ext(path))
+	if c, ok := compressionSuffixes[ext]; ok {
+		return path[:len(path)-len(ext)], c
+	}
+	return path, CompressionNone
+}
+
+// detectCompression identifies a compressed stream from its first bytes
+func detectCompression(head []byte) Compression {
+	for _, m := range compressionMagic {
+		if !bytes.HasPrefix(head, m.magic) {
+			continue
+		}
+		if m.compression == CompressionBzip2 {
+			// "BZh" alone could start a line of text
+			if len(head) < 10 || head[3] < '1' || head[3] > '9' || !bytes.Equal(head[4:10], bzip2BlockMagic) {
+				continue
+			}
+		}
+		return m.compression
+	}
+	return CompressionNone
+}
+
+// decompressReader returns a reader that decompresses the input if it
+// starts with the signature of a supported compression, and otherwise
+// yields it unchanged. Closing it closes the input
+func decompressReader(input io.ReadCloser) (io.ReadCloser, error) {
+	br := bufio.NewReader(input)
+	head, _ := br.Peek(10)
+
+	var r io.Reader
+	var closer func() error
+	switch compression := detectCompression(head); compression {
+	case CompressionGzip:
+		zr, err := gzip.NewReader(br)
+		if err != nil {
+			return nil, err
+		}
+		r, closer = zr, zr.Close
+	case CompressionZstd:
+		zr, err := zstd.NewReader(br)
+		if err != nil {
+			return nil, err
+		}
+		r, closer = zr, func() error { zr.Close(); return nil }
+	case CompressionBzip2:
+		r = bzip2.NewReader(br)
+	case CompressionXz:
+		zr, err := xz.NewReader(br)
+		if err != nil {
+			return nil, err
+		}
+		r = zr
+	default:
+		r = br
+	}
+
+	return &stackedReadCloser{Reader: r, closers: []func() error{closer, input.Close}}, nil
+}
+
+// compressWriter wraps output so that what is written to it is compressed.
+// Closing it flushes the compressed stream and closes output
+func compressWriter(output io.WriteCloser, compression Compression) (io.WriteCloser, error) {
+	var w io.WriteCloser
+	var err error
+	switch compression {
+	case CompressionNone:
+		return output, nil
+	case CompressionGzip:
+		w = gzip.NewWriter(output)
+	case CompressionZstd:
+		w, err = zstd.NewWriter(output)
+	case CompressionBzip2:
+		w, err = dsbzip2.NewWriter(output, nil)
+	case CompressionXz:
+		w, err = xz.NewWriter(output)
+	default:
+		return nil, fmt.Errorf("unsupported compression %q", compression)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &stackedWriteCloser{Writer: w, closers: []func() error{w.Close, output.Close}}, nil
+}
+
+// stackedReadCloser is a reader layered over other streams, closing each of
+// them in order
+type stackedReadCloser struct {
+	io.Reader
+	closers []func() error
+}
+
+func (s *stackedReadCloser) Close() error {
+	return closeAll(s.closers)
+}
+
+// stackedWriteCloser is a writer layered over other streams, closing each
+// of them in order so that buffered data reaches the innermost one
+type stackedWriteCloser struct {
+	io.Writer
+	closers []func() error
+}
+
+func (s *stackedWriteCloser) Close() error {
+	return closeAll(s.closers)
+}
+
+// closeAll calls every non-nil close function and returns the first error
+func closeAll(closers []func() error) error {
+	var firstErr error
+	for _, c := range closers {
+		if c == nil {
+			continue
+		}
+		if err := c(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
@@ -0,0 +1,104 @@
+package cli
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSplitCompressionSuffix(t *testing.T) {
+	tests := []struct {
+		path        string
+		wantStem    string
+		compression Compression
+	}{
+		{"data.csv.gz", "data.csv", CompressionGzip},
+		{"data.json.zst", "data.json", CompressionZstd},
+		{"DATA.CSV.BZ2", "DATA.CSV", CompressionBzip2},
+		{"dir/data.yaml.xz", "dir/data.yaml", CompressionXz},
+		{"data.csv", "data.csv", CompressionNone},
+		{"data", "data", CompressionNone},
+	}
+
+	for _, tt := range tests {
+		stem, compression := SplitCompressionSuffix(tt.path)
+		if stem != tt.wantStem || compression != tt.compression {
+			t.Errorf("SplitCompressionSuffix(%q) = %q, %q, want %q, %q",
+				tt.path, stem, compression, tt.wantStem, tt.compression)
+		}
+	}
+}
+
+func TestDetectFormat_CompressedExtension(t *testing.T) {
+	for path, want := range map[string]Format{
+		"export.csv.gz":   FormatCSV,
+		"export.json.zst": FormatJSON,
+		"export.xlsx.bz2": FormatExcel,
+	} {
+		got, err := DetectFormat(path)
+		if err != nil || got != want {
+			t.Errorf("DetectFormat(%q) = %q, %v, want %q", path, got, err, want)
+		}
+	}
+
+	if _, err := DetectFormat("export.gz"); err == nil {
+		t.Error("expected an error for a compressed file without a format extension")
+	}
+}
+
+func TestCompressionRoundTrip(t *testing.T) {
+	content := "name,age\nAlice,30\nBob,25\n"
+
+	for _, suffix := range []string{".gz", ".zst", ".bz2", ".xz", ""} {
+		t.Run("suffix"+suffix, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "data.csv"+suffix)
+
+			w, err := createOutputWriter(path)
+			if err != nil {
+				t.Fatalf("createOutputWriter failed: %v", err)
+			}
+			if _, err := io.WriteString(w, content); err != nil {
+				t.Fatalf("write failed: %v", err)
+			}
+			if err := w.Close(); err != nil {
+				t.Fatalf("close failed: %v", err)
+			}
+
+			raw, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf("ReadFile failed: %v", err)
+			}
+			_, compression := SplitCompressionSuffix(path)
+			if got := detectCompression(raw); got != compression {
+				t.Errorf("file written as %q, want %q", got, compression)
+			}
+
+			// Input is decompressed by signature, whatever the file name
+			renamed := filepath.Join(t.TempDir(), "data")
+			if err := os.Rename(path, renamed); err != nil {
+				t.Fatalf("Rename failed: %v", err)
+			}
+			r, err := createInputReader(renamed)
+			if err != nil {
+				t.Fatalf("createInputReader failed: %v", err)
+			}
+			defer r.Close()
+			data, err := io.ReadAll(r)
+			if err != nil {
+				t.Fatalf("read failed: %v", err)
+			}
+			if string(data) != content {
+				t.Errorf("round trip = %q, want %q", data, content)
+			}
+		})
+	}
+}
+
+func TestDetectCompression_PlainText(t *testing.T) {
+	for _, text := range []string{"BZh,name\n1,2\n", "name\n", ""} {
+		if got := detectCompression([]byte(text)); got != CompressionNone {
+			t.Errorf("detectCompression(%q) = %q, want none", text, got)
+		}
+	}
+}
@@ -4,7 +4,11 @@ import (
 	"fmt"
 	"io"
 
+	"github.com/user/table-converter/internal/model"
+	"github.com/user/table-converter/internal/parser"
 	"github.com/user/table-converter/internal/registry"
+	"github.com/user/table-converter/internal/serializer"
+	"github.com/user/table-converter/internal/transform"
 )
 
 // ConvertOptions holds options for the conversion process
@@ -15,6 +19,43 @@ type ConvertOptions struct {
 	OutputFormat Format
 	// FormatStyle is the style variant for formats that support it (e.g., ASCII)
 	FormatStyle string
+	// MaxWidth limits the total output width for formats that support it (0 = unlimited)
+	MaxWidth int
+	// MaxColumnWidths limits the width of individual columns, keyed by header
+	MaxColumnWidths map[string]int
+	// Overflow selects how cells exceeding a width limit are fitted ("truncate" or "wrap")
+	Overflow string
+	// NumericAlign selects how numeric columns are aligned ("right", "left" or "decimal")
+	NumericAlign string
+	// Columns declares fixed-width column positions (nil = infer them)
+	Columns []model.ColumnSpec
+	// PadChar is the fixed-width padding character (0 = space)
+	PadChar rune
+	// TableIndex selects a table from a document by 1-based position (0 = whole input)
+	TableIndex int
+	// AllTables converts every table in a document, separated by blank lines
+	AllTables bool
+	// InputEncoding is the character encoding of text input ("" or "auto" = detect)
+	InputEncoding string
+	// OutputEncoding is the character encoding of text output ("" = UTF-8)
+	OutputEncoding string
+	// CSVInput is the dialect of CSV input: delimiter, quoting, comments and header
+	CSVInput model.CSVDialect
+	// CSVOutput is the dialect of CSV output: delimiter, quoting and line endings
+	CSVOutput model.CSVDialect
+	// Strict rejects ragged rows and duplicate or empty headers instead of
+	// padding or truncating the rows
+	Strict bool
+	// Warnings receives a line for each row that was padded or truncated (nil = discard)
+	Warnings io.Writer
+	// Source names the input in warnings ("" = not named)
+	Source string
+	// Headers fixes empty and duplicate headers and converts their case
+	// before the table is serialized
+	Headers model.HeaderPolicy
+	// Transforms filters and sorts rows and selects, drops and renames
+	// columns after the headers are normalized (nil = none)
+	Transforms transform.Pipeline
 }
 
 // Convert performs the conversion from input to output using the specified formats
@@ -29,49 +70,329 @@ func Convert(input io.Reader, output io.Writer, opts ConvertOptions) error {
 		return NewCLIError("output format is required", ExitUsageError)
 	}
 
-	// Get parser
+	// Text input is read as UTF-8 and text output written in the requested encoding
+	input, err := decodeInput(input, opts.InputEncoding, opts.InputFormat)
+	if err != nil {
+		return NewCLIError(fmt.Sprintf("invalid input encoding: %v", err), ExitUsageError)
+	}
+	output, flush, err := encodeOutput(output, opts.OutputEncoding, opts.OutputFormat)
+	if err != nil {
+		return NewCLIError(fmt.Sprintf("invalid output encoding: %v", err), ExitUsageError)
+	}
+	if err := convert(input, output, opts); err != nil {
+		return err
+	}
+	if err := flush(); err != nil {
+		return FormatSerializeError(string(opts.OutputFormat), err)
+	}
+	return nil
+}
+
+// convert parses the UTF-8 input and serializes it to the output
+func convert(input io.Reader, output io.Writer, opts ConvertOptions) error {
+	// Detect the input format from the content when it is not known
+	if opts.InputFormat == FormatAuto {
+		format, buffered, err := resolveAutoFormat(input)
+		if err != nil {
+			return err
+		}
+		input = buffered
+		opts.InputFormat = format
+	}
+
+	// Documents are scanned for tables instead of parsed as one table
+	if opts.TableIndex > 0 || opts.AllTables {
+		return convertDocument(input, output, opts)
+	}
+
+	p, err := newParser(opts)
+	if err != nil {
+		return err
+	}
+
+	s, err := newSerializer(opts)
+	if err != nil {
+		return err
+	}
+
+	// Parse input to TableData
+	tableData, err := p.Parse(input)
+	if err != nil {
+		return FormatParseError(string(opts.InputFormat), err)
+	}
+
+	tableData, err = transformTable(tableData, opts)
+	if err != nil {
+		return err
+	}
+
+	// Serialize TableData to output
+	if err := s.Serialize(tableData, output); err != nil {
+		return FormatSerializeError(string(opts.OutputFormat), err)
+	}
+
+	return nil
+}
+
+// readTable decodes and parses one table from the input, detecting the
+// format from the content when it is auto. The header policy and
+// transforms are not applied
+func readTable(input io.Reader, opts ConvertOptions) (*model.TableData, error) {
+	input, err := decodeInput(input, opts.InputEncoding, opts.InputFormat)
+	if err != nil {
+		return nil, NewCLIError(fmt.Sprintf("invalid input encoding: %v", err), ExitUsageError)
+	}
+	if opts.InputFormat == FormatAuto {
+		format, buffered, err := resolveAutoFormat(input)
+		if err != nil {
+			return nil, err
+		}
+		input = buffered
+		opts.InputFormat = format
+	}
+
+	p, err := newParser(opts)
+	if err != nil {
+		return nil, err
+	}
+	tableData, err := p.Parse(input)
+	if err != nil {
+		return nil, FormatParseError(string(opts.InputFormat), err)
+	}
+	return tableData, nil
+}
+
+// writeTable serializes a table to the output in the output format and
+// encoding
+func writeTable(tableData *model.TableData, output io.Writer, opts ConvertOptions) error {
+	s, err := newSerializer(opts)
+	if err != nil {
+		return err
+	}
+	output, flush, err := encodeOutput(output, opts.OutputEncoding, opts.OutputFormat)
+	if err != nil {
+		return NewCLIError(fmt.Sprintf("invalid output encoding: %v", err), ExitUsageError)
+	}
+	if err := s.Serialize(tableData, output); err != nil {
+		return FormatSerializeError(string(opts.OutputFormat), err)
+	}
+	if err := flush(); err != nil {
+		return FormatSerializeError(string(opts.OutputFormat), err)
+	}
+	return nil
+}
+
+// transformTable applies the header policy and then the transforms to a
+// parsed table. Headers the transforms create, such as renamed, pivoted or
+// transposed ones, get the empty and duplicate header policies again
+func transformTable(td *model.TableData, opts ConvertOptions) (*model.TableData, error) {
+	if !opts.Headers.IsZero() {
+		headers, err := model.NormalizeHeaders(td.Headers, opts.Headers)
+		if err != nil {
+			return nil, FormatParseError(string(opts.InputFormat), parser.NewParseError(err.Error()))
+		}
+		td.Headers = headers
+	}
+
+	td, err := opts.Transforms.Transform(td)
+	if err != nil {
+		return nil, FormatTransformError(err)
+	}
+	reportRemovedDuplicates(opts)
+
+	if policy := (model.HeaderPolicy{Duplicates: opts.Headers.Duplicates, Empty: opts.Headers.Empty}); len(opts.Transforms) > 0 && !policy.IsZero() {
+		headers, err := model.NormalizeHeaders(td.Headers, policy)
+		if err != nil {
+			return nil, FormatTransformError(transform.NewTransformError(err.Error()))
+		}
+		td.Headers = headers
+	}
+	return td, nil
+}
+
+// reportRemovedDuplicates writes the number of rows each deduplication
+// removed to the warnings writer
+func reportRemovedDuplicates(opts ConvertOptions) {
+	if opts.Warnings == nil {
+		return
+	}
+	for _, t := range opts.Transforms {
+		d, ok := t.(*transform.Dedupe)
+		if !ok || d.Report {
+			continue
+		}
+		noun := "rows"
+		if d.Removed == 1 {
+			noun = "row"
+		}
+		if opts.Source != "" {
+			fmt.Fprintf(opts.Warnings, "%s: removed %d duplicate %s\n", opts.Source, d.Removed, noun)
+		} else {
+			fmt.Fprintf(opts.Warnings, "Removed %d duplicate %s\n", d.Removed, noun)
+		}
+	}
+}
+
+// newParser looks up the parser for the input format and applies the
+// fixed-width, CSV dialect and strict mode options
+func newParser(opts ConvertOptions) (parser.Parser, error) {
 	p, err := registry.GetParser(registry.Format(opts.InputFormat))
 	if err != nil {
-		return FormatUnsupportedFormatError(string(opts.InputFormat)).WithErr(err)
+		return nil, FormatUnsupportedFormatError(string(opts.InputFormat)).WithErr(err)
+	}
+
+	if err := configureFixedWidth(p, opts); err != nil {
+		return nil, err
 	}
+	if err := configureCSVDialect(p, opts.CSVInput); err != nil {
+		return nil, err
+	}
+	configureChecks(p, opts)
+	return p, nil
+}
 
-	// Get serializer
+// newSerializer looks up the serializer for the output format and applies
+// the style, width and alignment options it supports
+func newSerializer(opts ConvertOptions) (serializer.Serializer, error) {
 	s, err := registry.GetSerializer(registry.Format(opts.OutputFormat))
 	if err != nil {
-		return FormatUnsupportedFormatError(string(opts.OutputFormat)).WithErr(err)
+		return nil, FormatUnsupportedFormatError(string(opts.OutputFormat)).WithErr(err)
 	}
 
 	// If format style is specified and serializer supports it, configure it
 	if opts.FormatStyle != "" {
 		if configurable, ok := s.(interface{ SetStyle(string) error }); ok {
 			if err := configurable.SetStyle(opts.FormatStyle); err != nil {
-				return NewCLIError(fmt.Sprintf("invalid format style: %v", err), ExitUsageError)
+				return nil, NewCLIError(fmt.Sprintf("invalid format style: %v", err), ExitUsageError)
 			}
 		}
 	}
 
-	// Parse input to TableData
-	tableData, err := p.Parse(input)
-	if err != nil {
-		return FormatParseError(string(opts.InputFormat), err)
+	// If width limits are specified and serializer supports them, configure it
+	if opts.MaxWidth > 0 || len(opts.MaxColumnWidths) > 0 {
+		if limited, ok := s.(interface {
+			SetWidthLimits(int, map[string]int, string) error
+		}); ok {
+			if err := limited.SetWidthLimits(opts.MaxWidth, opts.MaxColumnWidths, opts.Overflow); err != nil {
+				return nil, NewCLIError(fmt.Sprintf("invalid width limits: %v", err), ExitUsageError)
+			}
+		}
 	}
 
-	// Serialize TableData to output
-	if err := s.Serialize(tableData, output); err != nil {
-		return FormatSerializeError(string(opts.OutputFormat), err)
+	// If numeric alignment is specified and serializer supports it, configure it
+	if opts.NumericAlign != "" {
+		if aligned, ok := s.(interface{ SetNumericAlignment(string) error }); ok {
+			if err := aligned.SetNumericAlignment(opts.NumericAlign); err != nil {
+				return nil, NewCLIError(fmt.Sprintf("invalid numeric alignment: %v", err), ExitUsageError)
+			}
+		}
+	}
+
+	if err := configureFixedWidth(s, opts); err != nil {
+		return nil, err
+	}
+	if err := configureCSVDialect(s, opts.CSVOutput); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// configureFixedWidth applies the fixed-width column and padding options to
+// a parser or serializer that supports them. They are always set so that
+// options from an earlier conversion do not carry over
+func configureFixedWidth(target interface{}, opts ConvertOptions) error {
+	if fixed, ok := target.(interface{ SetColumns([]model.ColumnSpec) }); ok {
+		fixed.SetColumns(opts.Columns)
+	}
+	if padded, ok := target.(interface{ SetPadChar(rune) error }); ok {
+		if err := padded.SetPadChar(opts.PadChar); err != nil {
+			return NewCLIError(fmt.Sprintf("invalid padding character: %v", err), ExitUsageError)
+		}
 	}
+	return nil
+}
 
+// configureCSVDialect applies the CSV dialect to a parser or serializer that
+// supports it. It is always set so that an earlier dialect does not carry over
+func configureCSVDialect(target interface{}, dialect model.CSVDialect) error {
+	if csv, ok := target.(interface{ SetDialect(model.CSVDialect) error }); ok {
+		if err := csv.SetDialect(dialect); err != nil {
+			return NewCLIError(fmt.Sprintf("invalid CSV dialect: %v", err), ExitUsageError)
+		}
+	}
 	return nil
 }
 
+// configureChecks applies strict mode and the warning destination to a
+// parser that checks the shape of its tables
+func configureChecks(target interface{}, opts ConvertOptions) {
+	if strict, ok := target.(interface{ SetStrict(bool) }); ok {
+		strict.SetStrict(opts.Strict)
+	}
+	if warner, ok := target.(interface{ SetWarningHandler(func(parser.Warning)) }); ok {
+		warner.SetWarningHandler(warningHandler(opts))
+	}
+}
+
+// rowChecker returns the table shape checks selected by opts
+func rowChecker(opts ConvertOptions) parser.RowChecker {
+	return parser.RowChecker{Strict: opts.Strict, Warn: warningHandler(opts)}
+}
+
+// warningHandler returns a function that writes parser warnings to
+// opts.Warnings, or nil if warnings are discarded
+func warningHandler(opts ConvertOptions) func(parser.Warning) {
+	if opts.Warnings == nil {
+		return nil
+	}
+	return func(w parser.Warning) {
+		if opts.Source != "" {
+			fmt.Fprintf(opts.Warnings, "Warning: %s: %s\n", opts.Source, w)
+		} else {
+			fmt.Fprintf(opts.Warnings, "Warning: %s\n", w)
+		}
+	}
+}
+
 // ConvertWithConfig performs conversion using a Config struct
 // This is a convenience wrapper around Convert that extracts options from Config
 func ConvertWithConfig(input io.Reader, output io.Writer, config *Config) error {
-	return Convert(input, output, ConvertOptions{
-		InputFormat:  config.InputFormat,
-		OutputFormat: config.OutputFormat,
-		FormatStyle:  config.FormatStyle,
-	})
+	return Convert(input, output, config.convertOptions())
+}
+
+// convertOptions extracts the conversion options from the config
+func (c *Config) convertOptions() ConvertOptions {
+	maxWidth := c.MaxWidth
+	if c.AutoWidth {
+		maxWidth = TerminalWidth()
+	}
+	source := c.InputFile
+	if source == "-" {
+		source = ""
+	}
+
+	return ConvertOptions{
+		InputFormat:     c.InputFormat,
+		OutputFormat:    c.OutputFormat,
+		FormatStyle:     c.FormatStyle,
+		MaxWidth:        maxWidth,
+		MaxColumnWidths: c.MaxColumnWidths,
+		Overflow:        c.Overflow,
+		NumericAlign:    c.NumericAlign,
+		Columns:         c.Columns,
+		PadChar:         c.PadChar,
+		TableIndex:      c.TableIndex,
+		AllTables:       c.AllTables,
+		InputEncoding:   c.InputEncoding,
+		OutputEncoding:  c.OutputEncoding,
+		CSVInput:        c.CSVInput,
+		CSVOutput:       c.CSVOutput,
+		Strict:          c.Strict,
+		Source:          source,
+		Headers:         c.Headers,
+		Transforms:      c.Transforms,
+	}
 }
 
 // Run executes the full CLI workflow:
@@ -80,6 +401,20 @@ func ConvertWithConfig(input io.Reader, output io.Writer, config *Config) error
 // 3. Call conversion function
 // 4. Handle errors and return exit code
 func Run(args []string, stdout, stderr io.Writer) ExitCode {
+	// Subcommands
+	if len(args) > 0 {
+		switch args[0] {
+		case "fmt":
+			return RunFmt(args[1:], stdout, stderr)
+		case "query":
+			return RunQuery(args[1:], stdout, stderr)
+		case "join":
+			return RunJoin(args[1:], stdout, stderr)
+		case "cat":
+			return RunCat(args[1:], stdout, stderr)
+		}
+	}
+
 	// Parse CLI arguments
 	config, err := ParseArgsWithOutput(args, stderr)
 	if err != nil {
@@ -100,6 +435,26 @@ func Run(args []string, stdout, stderr io.Writer) ExitCode {
 		return ExitSuccess
 	}
 
+	// Modes that do not write a single output stream
+	if config.ListTables || config.ShowDetection || config.Archive != ArchiveNone ||
+		(config.AllTables && config.OutputFile != "" && config.OutputFile != "-") {
+		run := func(config *Config) error { return ExportTables(config, stderr) }
+		switch {
+		case config.ShowDetection:
+			run = func(config *Config) error { return ReportDetection(config, stdout) }
+		case config.Archive != ArchiveNone:
+			run = func(config *Config) error { return ConvertArchive(config, stdout, stderr) }
+		case config.ListTables:
+			run = func(config *Config) error { return ListTables(config, stdout) }
+		}
+		if err := run(config); err != nil {
+			cliErr := FormatError(err)
+			fmt.Fprintln(stderr, cliErr.Message)
+			return cliErr.ExitCode
+		}
+		return ExitSuccess
+	}
+
 	// Set up I/O handler
 	ioHandler, err := NewIOHandler(config)
 	if err != nil {
@@ -107,14 +462,24 @@ func Run(args []string, stdout, stderr io.Writer) ExitCode {
 		fmt.Fprintln(stderr, cliErr.Message)
 		return cliErr.ExitCode
 	}
-	defer ioHandler.Close()
 
-	// Perform conversion
-	if err := ConvertWithConfig(ioHandler.InputReader(), ioHandler.OutputWriter(), config); err != nil {
+	// Perform conversion, reporting repaired rows on stderr
+	opts := config.convertOptions()
+	opts.Warnings = stderr
+	if err := Convert(ioHandler.InputReader(), ioHandler.OutputWriter(), opts); err != nil {
+		ioHandler.Close()
 		cliErr := FormatError(err)
 		fmt.Fprintln(stderr, cliErr.Message)
 		return cliErr.ExitCode
 	}
 
+	// Closing the output finishes a compressed stream, so a failure here
+	// leaves the file truncated
+	if err := ioHandler.Close(); err != nil {
+		cliErr := FormatFileWriteError(config.OutputFile, err)
+		fmt.Fprintln(stderr, cliErr.Message)
+		return cliErr.ExitCode
+	}
+
 	return ExitSuccess
 }
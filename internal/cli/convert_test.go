@@ -0,0 +1,42 @@
+package cli
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/user/table-converter/internal/model"
+	"github.com/user/table-converter/internal/transform"
+)
+
+// setHeaders is a transform that replaces the headers of a table
+type setHeaders []string
+
+func (h setHeaders) Transform(data *model.TableData) (*model.TableData, error) {
+	data.Headers = h
+	return data, nil
+}
+
+func TestTransformTable_HeaderPolicyAfterTransforms(t *testing.T) {
+	newData := func() *model.TableData {
+		return model.NewTableData([]string{"a", "b", "c"}, [][]model.Value{
+			{model.NewStringValue("1"), model.NewStringValue("2"), model.NewStringValue("3")},
+		})
+	}
+	opts := ConvertOptions{
+		Headers:    model.HeaderPolicy{Duplicates: model.DuplicateRename, Empty: model.EmptyGenerate, Case: model.CaseSnake},
+		Transforms: transform.Pipeline{setHeaders{"x", "x", ""}},
+	}
+
+	got, err := transformTable(newData(), opts)
+	if err != nil {
+		t.Fatalf("transformTable() error = %v", err)
+	}
+	if want := []string{"x", "x_2", "column_3"}; !reflect.DeepEqual(got.Headers, want) {
+		t.Errorf("headers = %q, want %q", got.Headers, want)
+	}
+
+	opts.Headers.Duplicates = model.DuplicateError
+	if _, err := transformTable(newData(), opts); err == nil {
+		t.Error("transformTable() with duplicate transformed headers error = nil")
+	}
+}
@@ -0,0 +1,206 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/user/table-converter/internal/parser"
+)
+
+// scanTables reads a document and returns the tables it contains. Only text
+// formats can hold tables among prose, so detection treats the input as text
+func scanTables(input io.Reader, format Format) ([]parser.TableBlock, error) {
+	if format != FormatASCII && format != FormatMarkdown && format != FormatAuto {
+		return nil, FormatUsageError(fmt.Sprintf("tables can only be extracted from text documents, not %s input", format))
+	}
+
+	blocks, err := parser.ScanDocument(input, format == FormatMarkdown)
+	if err != nil {
+		return nil, FormatParseError(string(format), err)
+	}
+	return blocks, nil
+}
+
+// checkTables applies the strict mode and warnings of opts to the tables.
+// Tables are found in a document with lenient parsing, so each block is
+// checked again once it is selected
+func checkTables(blocks []parser.TableBlock, opts ConvertOptions) error {
+	checker := rowChecker(opts)
+	for _, block := range blocks {
+		if err := block.Check(checker); err != nil {
+			return FormatParseError(string(opts.InputFormat), err)
+		}
+	}
+	return nil
+}
+
+// selectTables returns the table at the 1-based index, or every table if
+// index is 0
+func selectTables(blocks []parser.TableBlock, index int) ([]parser.TableBlock, error) {
+	if len(blocks) == 0 {
+		return nil, FormatParseError("document", parser.NewParseError("no tables found in document"))
+	}
+	if index == 0 {
+		return blocks, nil
+	}
+	if index > len(blocks) {
+		return nil, FormatUsageError(fmt.Sprintf("table %d requested but the document has %d table(s)", index, len(blocks)))
+	}
+	return blocks[index-1 : index], nil
+}
+
+// convertDocument converts the selected tables of a document, separating
+// multiple tables with a blank line
+func convertDocument(input io.Reader, output io.Writer, opts ConvertOptions) error {
+	blocks, err := scanTables(input, opts.InputFormat)
+	if err != nil {
+		return err
+	}
+
+	index := opts.TableIndex
+	if opts.AllTables {
+		index = 0
+	}
+	blocks, err = selectTables(blocks, index)
+	if err != nil {
+		return err
+	}
+	if err := checkTables(blocks, opts); err != nil {
+		return err
+	}
+
+	s, err := newSerializer(opts)
+	if err != nil {
+		return err
+	}
+
+	for i, block := range blocks {
+		if i > 0 {
+			if _, err := io.WriteString(output, "\n"); err != nil {
+				return FormatSerializeError(string(opts.OutputFormat), err)
+			}
+		}
+		table, err := transformTable(block.Table, opts)
+		if err != nil {
+			return err
+		}
+		block.Table = table
+		if err := s.Serialize(block.Table, output); err != nil {
+			return FormatSerializeError(string(opts.OutputFormat), err)
+		}
+	}
+
+	return nil
+}
+
+// ListTables prints the position, style and size of every table found in
+// the input document
+func ListTables(config *Config, output io.Writer) error {
+	input, err := createInputReader(config.InputFile)
+	if err != nil {
+		return FormatFileReadError(config.InputFile, err)
+	}
+	defer input.Close()
+
+	decoded, err := decodeInput(input, config.InputEncoding, config.InputFormat)
+	if err != nil {
+		return NewCLIError(fmt.Sprintf("invalid input encoding: %v", err), ExitUsageError)
+	}
+	blocks, err := scanTables(decoded, config.InputFormat)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(output, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "TABLE\tLINES\tSTYLE\tCOLUMNS\tROWS")
+	for i, block := range blocks {
+		fmt.Fprintf(w, "%d\t%d-%d\t%s\t%d\t%d\n",
+			i+1, block.StartLine, block.EndLine, block.Style, len(block.Table.Headers), len(block.Table.Rows))
+	}
+	return w.Flush()
+}
+
+// ExportTables writes every table of the input document to its own file,
+// numbering the output file name: tables.csv becomes tables-1.csv,
+// tables-2.csv and so on. Warnings are written to stderr
+func ExportTables(config *Config, stderr io.Writer) error {
+	input, err := createInputReader(config.InputFile)
+	if err != nil {
+		return FormatFileReadError(config.InputFile, err)
+	}
+	defer input.Close()
+
+	decoded, err := decodeInput(input, config.InputEncoding, config.InputFormat)
+	if err != nil {
+		return NewCLIError(fmt.Sprintf("invalid input encoding: %v", err), ExitUsageError)
+	}
+	blocks, err := scanTables(decoded, config.InputFormat)
+	if err != nil {
+		return err
+	}
+	if blocks, err = selectTables(blocks, 0); err != nil {
+		return err
+	}
+	opts := config.convertOptions()
+	opts.Warnings = stderr
+	if err := checkTables(blocks, opts); err != nil {
+		return err
+	}
+
+	// The number goes before the extension and any compression suffix:
+	// tables.csv.gz becomes tables-1.csv.gz
+	stem, _ := SplitCompressionSuffix(config.OutputFile)
+	suffix := config.OutputFile[len(stem):]
+	ext := filepath.Ext(stem)
+	base := strings.TrimSuffix(stem, ext)
+
+	for i, block := range blocks {
+		path := fmt.Sprintf("%s-%d%s%s", base, i+1, ext, suffix)
+		if err := exportTable(block, path, opts); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// exportTable writes one table to a file using the output options of opts
+func exportTable(block parser.TableBlock, path string, opts ConvertOptions) error {
+	output, err := createOutputWriter(path)
+	if err != nil {
+		return FormatFileWriteError(path, err)
+	}
+
+	s, err := newSerializer(opts)
+	if err != nil {
+		output.Close()
+		return err
+	}
+
+	encoded, flush, err := encodeOutput(output, opts.OutputEncoding, opts.OutputFormat)
+	if err != nil {
+		output.Close()
+		return NewCLIError(fmt.Sprintf("invalid output encoding: %v", err), ExitUsageError)
+	}
+	table, err := transformTable(block.Table, opts)
+	if err != nil {
+		output.Close()
+		return err
+	}
+	if err := s.Serialize(table, encoded); err != nil {
+		output.Close()
+		return FormatSerializeError(string(opts.OutputFormat), err)
+	}
+	if err := flush(); err != nil {
+		output.Close()
+		return FormatSerializeError(string(opts.OutputFormat), err)
+	}
+
+	if err := output.Close(); err != nil {
+		return FormatFileWriteError(path, err)
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testDocument = `# Inventory
+
+Some prose.
+
+| name | qty |
+|------|-----|
+| tea  | 3   |
+
+More prose.
+
+ id | owner
+----+-------
+  1 | Ann
+(1 row)
+`
+
+func TestConvert_TableSelectionErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		opts     ConvertOptions
+		exitCode ExitCode
+	}{
+		{
+			name:     "out of range",
+			input:    testDocument,
+			opts:     ConvertOptions{InputFormat: FormatASCII, OutputFormat: FormatCSV, TableIndex: 3},
+			exitCode: ExitUsageError,
+		},
+		{
+			name:     "non-text input",
+			input:    testDocument,
+			opts:     ConvertOptions{InputFormat: FormatCSV, OutputFormat: FormatJSON, TableIndex: 1},
+			exitCode: ExitUsageError,
+		},
+		{
+			name:     "no tables",
+			input:    "Just prose.\n",
+			opts:     ConvertOptions{InputFormat: FormatASCII, OutputFormat: FormatCSV, AllTables: true},
+			exitCode: ExitParseError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var output bytes.Buffer
+			err := Convert(strings.NewReader(tt.input), &output, tt.opts)
+			if GetExitCode(err) != tt.exitCode {
+				t.Errorf("expected exit code %d, got %d (%v)", tt.exitCode, GetExitCode(err), err)
+			}
+		})
+	}
+}
+
+func TestListTables(t *testing.T) {
+	tmpDir := t.TempDir()
+	path := filepath.Join(tmpDir, "notes.txt")
+	if err := os.WriteFile(path, []byte(testDocument), 0644); err != nil {
+		t.Fatalf("failed to create test file: %v", err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	if code := Run([]string{"-list-tables", path}, &stdout, &stderr); code != ExitSuccess {
+		t.Fatalf("exit code = %d, stderr: %s", code, stderr.String())
+	}
+
+	expected := `TABLE  LINES  STYLE  COLUMNS  ROWS
+1      5-7    md     2        1
+2      11-13  psql   2        1
+`
+	if stdout.String() != expected {
+		t.Errorf("output:\n%s\nwant:\n%s", stdout.String(), expected)
+	}
+}
@@ -0,0 +1,190 @@
+package cli
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/encoding/htmlindex"
+	"golang.org/x/text/encoding/unicode"
+	"golang.org/x/text/transform"
+)
+
+// EncodingAuto detects the input encoding from byte order marks and the content
+const EncodingAuto = "auto"
+
+// namedEncodings maps the encoding names most used for table exports to
+// their encodings. Other names are looked up in the WHATWG encoding index
+var namedEncodings = map[string]encoding.Encoding{
+	"utf-8":        unicode.UTF8,
+	"utf8":         unicode.UTF8,
+	"utf-8-bom":    unicode.UTF8BOM,
+	"utf8-bom":     unicode.UTF8BOM,
+	"utf-16":       unicode.UTF16(unicode.LittleEndian, unicode.UseBOM),
+	"utf-16le":     unicode.UTF16(unicode.LittleEndian, unicode.IgnoreBOM),
+	"utf-16be":     unicode.UTF16(unicode.BigEndian, unicode.IgnoreBOM),
+	"windows-1252": charmap.Windows1252,
+	"cp1252":       charmap.Windows1252,
+	"latin1":       charmap.ISO8859_1,
+	"latin-1":      charmap.ISO8859_1,
+	"iso-8859-1":   charmap.ISO8859_1,
+}
+
+// lookupEncoding resolves an encoding name such as utf-16le or windows-1252
+func lookupEncoding(name string) (encoding.Encoding, error) {
+	key := strings.ToLower(strings.TrimSpace(name))
+	if enc, ok := namedEncodings[key]; ok {
+		return enc, nil
+	}
+	if enc, err := htmlindex.Get(key); err == nil {
+		return enc, nil
+	}
+	return nil, fmt.Errorf("unsupported encoding %q, common encodings: utf-8, utf-8-bom, utf-16, utf-16le, utf-16be, windows-1252, latin1", name)
+}
+
+// validateEncoding checks an -input-encoding or -output-encoding value
+func validateEncoding(name string, allowAuto bool) error {
+	if name == "" || (allowAuto && strings.EqualFold(name, EncodingAuto)) {
+		return nil
+	}
+	_, err := lookupEncoding(name)
+	return err
+}
+
+// detectEncoding guesses the encoding of the start of the input and says
+// why. complete reports whether the sample holds the whole input. Binary
+// workbooks give an empty name, as they are not text
+func detectEncoding(sample []byte, complete bool) (name, reason string) {
+	switch {
+	case bytes.HasPrefix(sample, zipMagic) || bytes.HasPrefix(sample, oleMagic):
+		return "", "binary workbook"
+	case bytes.HasPrefix(sample, utf8BOM):
+		return "utf-8", "UTF-8 byte order mark"
+	case bytes.HasPrefix(sample, []byte{0xFF, 0xFE}):
+		return "utf-16le", "UTF-16LE byte order mark"
+	case bytes.HasPrefix(sample, []byte{0xFE, 0xFF}):
+		return "utf-16be", "UTF-16BE byte order mark"
+	}
+
+	if order := utf16ByteOrder(sample); order != "" {
+		return order, "NUL bytes in every other position"
+	}
+
+	// A partial sample may end in the middle of a character
+	if i := lastRuneStart(sample); !complete && i >= 0 && !utf8.FullRune(sample[i:]) {
+		sample = sample[:i]
+	}
+	if utf8.Valid(sample) {
+		return "utf-8", "valid UTF-8"
+	}
+	return "windows-1252", "not valid UTF-8"
+}
+
+// lastRuneStart returns the index where the last, possibly partial, UTF-8
+// sequence of b starts, or -1 if b is empty
+func lastRuneStart(b []byte) int {
+	for i := len(b) - 1; i >= 0 && i >= len(b)-utf8.UTFMax; i-- {
+		if utf8.RuneStart(b[i]) {
+			return i
+		}
+	}
+	return len(b) - 1
+}
+
+// utf16ByteOrder recognises UTF-16 text without a byte order mark from the
+// NUL high bytes of ASCII characters, returning utf-16le, utf-16be or ""
+func utf16ByteOrder(sample []byte) string {
+	if len(sample) > 4096 {
+		sample = sample[:4096]
+	}
+	pairs := len(sample) / 2
+	if pairs < 2 {
+		return ""
+	}
+
+	evenNUL, oddNUL := 0, 0
+	for i := 0; i+1 < len(sample); i += 2 {
+		if sample[i] == 0 {
+			evenNUL++
+		}
+		if sample[i+1] == 0 {
+			oddNUL++
+		}
+	}
+
+	switch {
+	case oddNUL*10 >= pairs*4 && evenNUL*20 < pairs:
+		return "utf-16le"
+	case evenNUL*10 >= pairs*4 && oddNUL*20 < pairs:
+		return "utf-16be"
+	}
+	return ""
+}
+
+// decodeInput returns a reader that yields the input as UTF-8 without a
+// byte order mark. An empty or "auto" name detects the encoding. Excel
+// input and other binary workbooks are passed through unchanged
+func decodeInput(input io.Reader, name string, format Format) (io.Reader, error) {
+	if format == FormatExcel {
+		return input, nil
+	}
+
+	br := bufio.NewReaderSize(input, sniffSize)
+	if name == "" || strings.EqualFold(name, EncodingAuto) {
+		sample, err := br.Peek(sniffSize)
+		if name, _ = detectEncoding(sample, errors.Is(err, io.EOF)); name == "" {
+			return br, nil
+		}
+	}
+
+	enc, err := lookupEncoding(name)
+	if err != nil {
+		return nil, err
+	}
+
+	// A byte order mark in the input takes precedence over the named encoding
+	return transform.NewReader(br, unicode.BOMOverride(enc.NewDecoder())), nil
+}
+
+// encodeOutput returns a writer that encodes UTF-8 text into the named
+// encoding, and a function that flushes it after the last write. Excel
+// output is binary and is written unchanged
+func encodeOutput(output io.Writer, name string, format Format) (io.Writer, func() error, error) {
+	key := strings.ToLower(name)
+	if format == FormatExcel || key == "" || key == "utf-8" || key == "utf8" {
+		return output, func() error { return nil }, nil
+	}
+
+	enc, err := lookupEncoding(name)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	w := transform.NewWriter(output, enc.NewEncoder())
+	ew := &encodingWriter{w: w, name: name}
+	return ew, func() error { return ew.wrap(w.Close()) }, nil
+}
+
+// encodingWriter explains errors from characters the encoding cannot represent
+type encodingWriter struct {
+	w    io.Writer
+	name string
+}
+
+func (e *encodingWriter) Write(p []byte) (int, error) {
+	n, err := e.w.Write(p)
+	return n, e.wrap(err)
+}
+
+func (e *encodingWriter) wrap(err error) error {
+	if err == nil {
+		return nil
+	}
+	return fmt.Errorf("cannot encode output as %s: %w", e.name, err)
+}
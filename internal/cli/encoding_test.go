@@ -0,0 +1,139 @@
+package cli
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestDetectEncoding(t *testing.T) {
+	tests := []struct {
+		name   string
+		sample []byte
+		want   string
+	}{
+		{"utf-8 bom", []byte("\xEF\xBB\xBFname\n"), "utf-8"},
+		{"utf-16le bom", []byte("\xFF\xFEn\x00a\x00"), "utf-16le"},
+		{"utf-16be bom", []byte("\xFE\xFF\x00n\x00a"), "utf-16be"},
+		{"utf-16le without bom", []byte("n\x00a\x00m\x00e\x00\n\x00"), "utf-16le"},
+		{"utf-16be without bom", []byte("\x00n\x00a\x00m\x00e\x00\n"), "utf-16be"},
+		{"ascii", []byte("name,city\n"), "utf-8"},
+		{"utf-8", []byte("name,city\nJosé,Málaga\n"), "utf-8"},
+		{"windows-1252", []byte("name,city\nJos\xe9,M\xe1laga\n"), "windows-1252"},
+		{"xlsx", append([]byte(nil), zipMagic...), ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, _ := detectEncoding(tt.sample, true); got != tt.want {
+				t.Errorf("detectEncoding = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDetectEncoding_PartialSample(t *testing.T) {
+	// A sample cut in the middle of "é" is still UTF-8
+	sample := []byte("name\nJos\xc3")
+	if got, _ := detectEncoding(sample, false); got != "utf-8" {
+		t.Errorf("detectEncoding = %q, want utf-8", got)
+	}
+	if got, _ := detectEncoding(sample, true); got != "windows-1252" {
+		t.Errorf("detectEncoding of complete input = %q, want windows-1252", got)
+	}
+}
+
+func TestValidateEncoding(t *testing.T) {
+	for _, name := range []string{"", "utf-8", "UTF-16LE", "cp1252", "latin1", "shift_jis"} {
+		if err := validateEncoding(name, false); err != nil {
+			t.Errorf("validateEncoding(%q) failed: %v", name, err)
+		}
+	}
+	if err := validateEncoding("auto", true); err != nil {
+		t.Errorf("validateEncoding(auto) failed: %v", err)
+	}
+	if err := validateEncoding("auto", false); err == nil {
+		t.Error("expected an error for auto output encoding")
+	}
+	if err := validateEncoding("klingon", false); err == nil || !strings.Contains(err.Error(), "unsupported encoding") {
+		t.Errorf("expected an unsupported encoding error, got %v", err)
+	}
+}
+
+func TestDecodeInput(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		encoding string
+	}{
+		{"auto utf-8 bom", "\xEF\xBB\xBFJosé", EncodingAuto},
+		{"auto utf-16le bom", "\xFF\xFEJ\x00o\x00s\x00\xe9\x00", EncodingAuto},
+		{"auto windows-1252", "Jos\xe9", EncodingAuto},
+		{"named latin1", "Jos\xe9", "latin1"},
+		{"named utf-16be", "\x00J\x00o\x00s\x00\xe9", "utf-16be"},
+		{"bom overrides name", "\xEF\xBB\xBFJosé", "windows-1252"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := decodeInput(strings.NewReader(tt.input), tt.encoding, FormatCSV)
+			if err != nil {
+				t.Fatalf("decodeInput failed: %v", err)
+			}
+			got, err := io.ReadAll(r)
+			if err != nil {
+				t.Fatalf("read failed: %v", err)
+			}
+			if string(got) != "José" {
+				t.Errorf("decoded %q, want %q", got, "José")
+			}
+		})
+	}
+}
+
+func TestEncodeOutput(t *testing.T) {
+	tests := []struct {
+		encoding string
+		want     string
+	}{
+		{"utf-8", "José"},
+		{"utf-8-bom", "\xEF\xBB\xBFJosé"},
+		{"utf-16le", "J\x00o\x00s\x00\xe9\x00"},
+		{"windows-1252", "Jos\xe9"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.encoding, func(t *testing.T) {
+			var buf bytes.Buffer
+			w, flush, err := encodeOutput(&buf, tt.encoding, FormatCSV)
+			if err != nil {
+				t.Fatalf("encodeOutput failed: %v", err)
+			}
+			if _, err := io.WriteString(w, "José"); err != nil {
+				t.Fatalf("write failed: %v", err)
+			}
+			if err := flush(); err != nil {
+				t.Fatalf("flush failed: %v", err)
+			}
+			if buf.String() != tt.want {
+				t.Errorf("encoded %q, want %q", buf.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestEncodeOutput_Unencodable(t *testing.T) {
+	var buf bytes.Buffer
+	w, flush, err := encodeOutput(&buf, "latin1", FormatCSV)
+	if err != nil {
+		t.Fatalf("encodeOutput failed: %v", err)
+	}
+	_, err = io.WriteString(w, "price €5")
+	if err == nil {
+		err = flush()
+	}
+	if err == nil || !strings.Contains(err.Error(), "cannot encode output as latin1") {
+		t.Errorf("expected an encoding error, got %v", err)
+	}
+}
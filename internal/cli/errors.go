@@ -6,7 +6,9 @@ import (
 	"strings"
 
 	"github.com/user/table-converter/internal/parser"
+	"github.com/user/table-converter/internal/query"
 	"github.com/user/table-converter/internal/serializer"
+	"github.com/user/table-converter/internal/transform"
 )
 
 // ExitCode represents CLI exit codes
@@ -121,6 +123,9 @@ func FormatSerializeError(format string, err error) *CLIError {
 		if serializeErr.Context != "" {
 			msg += fmt.Sprintf("\n  Context: %s", serializeErr.Context)
 		}
+		if serializeErr.Err != nil {
+			msg += fmt.Sprintf("\n  Reason: %v", serializeErr.Err)
+		}
 	} else {
 		msg = fmt.Sprintf("Error: Failed to serialize to %s format\n  %v", format, err)
 	}
@@ -132,6 +137,43 @@ func FormatSerializeError(format string, err error) *CLIError {
 	}
 }
 
+// FormatTransformError formats an error from the transformation stage
+func FormatTransformError(err error) *CLIError {
+	var transformErr *transform.TransformError
+	var msg string
+
+	if errors.As(err, &transformErr) {
+		msg = fmt.Sprintf("Error: Failed to transform table\n  %s", transformErr.Message)
+		if transformErr.Err != nil {
+			msg += fmt.Sprintf("\n  Reason: %v", transformErr.Err)
+		}
+	} else {
+		msg = fmt.Sprintf("Error: Failed to transform table\n  %v", err)
+	}
+
+	return &CLIError{
+		Message:  msg,
+		ExitCode: ExitError,
+		Err:      err,
+	}
+}
+
+// FormatQueryError formats an error from parsing or running a query. A
+// statement that cannot be parsed is a usage error
+func FormatQueryError(err error) *CLIError {
+	exitCode := ExitError
+	var syntaxErr *query.SyntaxError
+	if errors.As(err, &syntaxErr) {
+		exitCode = ExitUsageError
+	}
+
+	return &CLIError{
+		Message:  fmt.Sprintf("Error: Failed to run query\n  %v", err),
+		ExitCode: exitCode,
+		Err:      err,
+	}
+}
+
 // FormatUnsupportedFormatError formats an unsupported format error with list of supported formats
 func FormatUnsupportedFormatError(format string) *CLIError {
 	formats := SupportedFormats()
@@ -181,6 +223,12 @@ func FormatError(err error) *CLIError {
 		return FormatSerializeError("output", err)
 	}
 
+	// Check for transform error
+	var transformErr *transform.TransformError
+	if errors.As(err, &transformErr) {
+		return FormatTransformError(err)
+	}
+
 	// Default to general error
 	return &CLIError{
 		Message:  fmt.Sprintf("Error: %v", err),
@@ -18,11 +18,17 @@ const (
 	FormatXML      Format = "xml"
 	FormatMarkdown Format = "markdown"
 	FormatASCII    Format = "ascii"
+	FormatFixed    Format = "fixed"
+
+	// FormatAuto is not a format of its own: it detects the input format
+	// from the content
+	FormatAuto Format = "auto"
 )
 
 // extensionMap maps file extensions to formats
 var extensionMap = map[string]Format{
 	".csv":  FormatCSV,
+	".tsv":  FormatCSV,
 	".xlsx": FormatExcel,
 	".xls":  FormatExcel,
 	".yaml": FormatYAML,
@@ -33,6 +39,32 @@ var extensionMap = map[string]Format{
 	".xml":  FormatXML,
 	".md":   FormatMarkdown,
 	".txt":  FormatASCII, // ASCII tables often in .txt files
+	".org":  FormatASCII,
+	".rst":  FormatASCII,
+}
+
+// outputExtensions maps formats to the extension used when naming output files
+var outputExtensions = map[Format]string{
+	FormatCSV:      ".csv",
+	FormatExcel:    ".xlsx",
+	FormatYAML:     ".yaml",
+	FormatJSON:     ".json",
+	FormatHTML:     ".html",
+	FormatXML:      ".xml",
+	FormatMarkdown: ".md",
+	FormatASCII:    ".txt",
+	FormatFixed:    ".txt",
+}
+
+// Extension returns the file extension used for output in the format
+func (f Format) Extension() string {
+	return outputExtensions[f]
+}
+
+// KeysByHeader reports whether the format writes each value under its
+// header name, so that columns with the same header overwrite each other
+func (f Format) KeysByHeader() bool {
+	return f == FormatJSON || f == FormatYAML || f == FormatXML
 }
 
 // aliasMap maps shorthand aliases to canonical format names
@@ -52,6 +84,9 @@ var aliasMap = map[string]Format{
 	"table": FormatASCII,
 	// JSON alias
 	"js": FormatJSON,
+	// Fixed-width aliases
+	"fixed-width": FormatFixed,
+	"fw":          FormatFixed,
 }
 
 // SupportedFormats returns a list of all supported format names
@@ -65,12 +100,15 @@ func SupportedFormats() []Format {
 		FormatXML,
 		FormatMarkdown,
 		FormatASCII,
+		FormatFixed,
 	}
 }
 
-// DetectFormat determines the format from a file path based on its extension
+// DetectFormat determines the format from a file path based on its extension,
+// looking through a compression suffix ("data.csv.gz" is CSV)
 // Returns an error if the extension is unknown
 func DetectFormat(filepath string) (Format, error) {
+	filepath, _ = SplitCompressionSuffix(filepath)
 	ext := strings.ToLower(getExtension(filepath))
 	if ext == "" {
 		return "", fmt.Errorf("cannot detect format: file has no extension")
@@ -124,9 +124,9 @@ func TestParseFormat(t *testing.T) {
 func TestSupportedFormats(t *testing.T) {
 	formats := SupportedFormats()
 
-	// Should have 8 formats
-	if len(formats) != 8 {
-		t.Errorf("SupportedFormats() returned %d formats, want 8", len(formats))
+	// Should have 9 formats
+	if len(formats) != 9 {
+		t.Errorf("SupportedFormats() returned %d formats, want 9", len(formats))
 	}
 
 	// Check all expected formats are present
@@ -139,6 +139,7 @@ func TestSupportedFormats(t *testing.T) {
 		FormatXML:      true,
 		FormatMarkdown: true,
 		FormatASCII:    true,
+		FormatFixed:    true,
 	}
 
 	for _, f := range formats {
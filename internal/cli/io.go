@@ -85,22 +85,32 @@ func (h *IOHandler) IsStdout() bool {
 // Otherwise, it opens the file for reading
 func createInputReader(filepath string) (io.ReadCloser, error) {
 	// Use stdin if no file specified or "-" is used
+	var input io.ReadCloser
 	if filepath == "" || filepath == "-" {
-		return io.NopCloser(os.Stdin), nil
+		input = io.NopCloser(os.Stdin)
+	} else {
+		// Open the file
+		file, err := os.Open(filepath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open input file %q: %w", filepath, err)
+		}
+		input = file
 	}
 
-	// Open the file
-	file, err := os.Open(filepath)
+	// Compressed input is recognised by its signature and decompressed
+	reader, err := decompressReader(input)
 	if err != nil {
-		return nil, fmt.Errorf("failed to open input file %q: %w", filepath, err)
+		input.Close()
+		return nil, fmt.Errorf("failed to decompress input: %w", err)
 	}
 
-	return file, nil
+	return reader, nil
 }
 
 // createOutputWriter creates an output writer based on the file path
 // If the path is empty or "-", it returns stdout
-// Otherwise, it creates/truncates the file for writing
+// Otherwise, it creates/truncates the file for writing, compressing the
+// output when the name ends in a compression suffix such as .gz
 func createOutputWriter(filepath string) (io.WriteCloser, error) {
 	// Use stdout if no file specified or "-" is used
 	if filepath == "" || filepath == "-" {
@@ -113,7 +123,14 @@ func createOutputWriter(filepath string) (io.WriteCloser, error) {
 		return nil, fmt.Errorf("failed to create output file %q: %w", filepath, err)
 	}
 
-	return file, nil
+	_, compression := SplitCompressionSuffix(filepath)
+	writer, err := compressWriter(file, compression)
+	if err != nil {
+		file.Close()
+		return nil, fmt.Errorf("failed to compress output file %q: %w", filepath, err)
+	}
+
+	return writer, nil
 }
 
 // nopWriteCloser wraps a Writer to provide a no-op Close method
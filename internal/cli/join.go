@@ -0,0 +1,202 @@
+package cli
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/user/table-converter/internal/transform"
+)
+
+// JoinConfig holds the parsed configuration of the join subcommand
+type JoinConfig struct {
+	LeftFile       string          // Left input file ("-" for stdin)
+	RightFile      string          // Right input file ("-" for stdin)
+	OutputFile     string          // Output file path (empty for stdout)
+	Join           *transform.Join // Key columns, join type and suffixes
+	InputFormat    Format          // Format of both inputs ("" = detect per input)
+	OutputFormat   Format          // Format of the result
+	FormatStyle    string          // Style variant of the output format
+	InputEncoding  string          // Character encoding of text input
+	OutputEncoding string          // Character encoding of text output
+	ShowHelp       bool            // Show help message
+}
+
+// ParseJoinArgs parses the arguments of the join subcommand. Options may
+// follow the file names
+func ParseJoinArgs(args []string, output io.Writer) (*JoinConfig, error) {
+	fs := flag.NewFlagSet("morph join", flag.ContinueOnError)
+	fs.SetOutput(output)
+
+	config := &JoinConfig{}
+	var on, how, suffixes, inFormat, outFormat string
+	fs.StringVar(&on, "on", "", "Key columns: name,... or left=right,...")
+	fs.StringVar(&how, "how", "inner", "Join type (inner|left|outer|anti)")
+	fs.StringVar(&suffixes, "suffixes", "_left,_right", "Suffixes for columns both inputs have: left,right")
+	fs.StringVar(&inFormat, "in", "", "Input format of both files (auto|csv|excel|yaml|json|html|xml|markdown|ascii|fixed)")
+	fs.StringVar(&outFormat, "out", "", "Output format (csv|excel|yaml|json|html|xml|markdown|ascii|fixed)")
+	fs.StringVar(&config.FormatStyle, "f", "", "Format style variant (for ascii output)")
+	fs.StringVar(&config.InputEncoding, "input-encoding", EncodingAuto, "Character encoding of text input")
+	fs.StringVar(&config.OutputEncoding, "output-encoding", "utf-8", "Character encoding of text output")
+	fs.BoolVar(&config.ShowHelp, "h", false, "Show help message")
+	fs.BoolVar(&config.ShowHelp, "help", false, "Show help message")
+
+	fs.Usage = func() {
+		printJoinUsage(output)
+	}
+
+	positional, err := parseInterspersed(fs, args)
+	if err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			config.ShowHelp = true
+			return config, nil
+		}
+		return nil, err
+	}
+	if config.ShowHelp {
+		return config, nil
+	}
+
+	switch {
+	case len(positional) < 2:
+		return nil, errors.New("join needs a left and a right input file")
+	case len(positional) > 3:
+		return nil, fmt.Errorf("too many arguments: expected LEFT RIGHT [OUTPUT], got %d", len(positional))
+	}
+	config.LeftFile, config.RightFile = positional[0], positional[1]
+	if len(positional) == 3 {
+		config.OutputFile = positional[2]
+	}
+	if config.LeftFile == "-" && config.RightFile == "-" {
+		return nil, errors.New("only one input can be read from stdin")
+	}
+
+	if on == "" {
+		return nil, errors.New("-on is required")
+	}
+	kind, err := transform.ParseJoinKind(how)
+	if err != nil {
+		return nil, err
+	}
+	if config.Join, err = transform.NewJoin(on, kind); err != nil {
+		return nil, fmt.Errorf("invalid -on: %w", err)
+	}
+	if config.Join.Suffixes, err = parseJoinSuffixes(suffixes); err != nil {
+		return nil, err
+	}
+
+	if err := validateEncoding(config.InputEncoding, true); err != nil {
+		return nil, fmt.Errorf("invalid -input-encoding: %w", err)
+	}
+	if err := validateEncoding(config.OutputEncoding, false); err != nil {
+		return nil, fmt.Errorf("invalid -output-encoding: %w", err)
+	}
+	if config.InputFormat, err = inputFormatFlag(inFormat); err != nil {
+		return nil, err
+	}
+	if config.OutputFormat, err = outputTableFormat(outFormat, config.OutputFile); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// parseJoinSuffixes parses the -suffixes flag, two suffixes separated by a
+// comma. Either may be empty, but not both
+func parseJoinSuffixes(spec string) ([2]string, error) {
+	left, right, ok := strings.Cut(spec, ",")
+	if !ok || left == right {
+		return [2]string{}, fmt.Errorf("invalid -suffixes %q (expected two different suffixes, such as _left,_right)", spec)
+	}
+	return [2]string{left, right}, nil
+}
+
+// RunJoin executes the join subcommand
+func RunJoin(args []string, stdout, stderr io.Writer) ExitCode {
+	config, err := ParseJoinArgs(args, stderr)
+	if err != nil {
+		cliErr := FormatUsageError(err.Error())
+		fmt.Fprintln(stderr, cliErr.Message)
+		return cliErr.ExitCode
+	}
+
+	if config.ShowHelp {
+		printJoinUsage(stdout)
+		return ExitSuccess
+	}
+
+	if err := JoinFiles(config, stdout, stderr); err != nil {
+		cliErr := FormatError(err)
+		fmt.Fprintln(stderr, cliErr.Message)
+		return cliErr.ExitCode
+	}
+	return ExitSuccess
+}
+
+// JoinFiles reads both inputs, joins them and writes the result to the
+// output file or stdout
+func JoinFiles(config *JoinConfig, stdout, stderr io.Writer) error {
+	left, err := readInputTable(config.LeftFile, config.InputFormat, config.InputEncoding, stderr)
+	if err != nil {
+		return err
+	}
+	right, err := readInputTable(config.RightFile, config.InputFormat, config.InputEncoding, stderr)
+	if err != nil {
+		return err
+	}
+
+	result, err := config.Join.Join(left, right)
+	if err != nil {
+		return FormatTransformError(err)
+	}
+
+	return writeOutputTable(result, config.OutputFile, stdout, ConvertOptions{
+		OutputFormat:   config.OutputFormat,
+		FormatStyle:    config.FormatStyle,
+		OutputEncoding: config.OutputEncoding,
+	})
+}
+
+// printJoinUsage prints the usage information of the join subcommand
+func printJoinUsage(w io.Writer) {
+	usage := `morph join - Join two tables on key columns
+
+Usage:
+  morph join [OPTIONS] LEFT RIGHT [OUTPUT]
+
+Each input is parsed in the format of its extension (or detected from its
+content), so a CSV export can be joined with an Excel sheet. Rows whose key
+columns are equal are combined; keys compare by value, so 42 matches 42.0,
+and an empty key matches nothing. Either input may be - for stdin.
+
+Options:
+  -on <keys>        Key columns, comma-separated. Use left=right for a key
+                    named differently in the right input: customer_id=id
+  -how <type>       Join type (default: inner)
+                      inner  - rows with a match in both inputs
+                      left   - every left row; empty cells where the right has no match
+                      outer  - every row of both inputs
+                      anti   - left rows without a match, with only the left columns
+  -suffixes <l,r>   Added to the names of columns both inputs have
+                    (default: _left,_right)
+  -in <format>      Input format of both files (default: from the extension)
+  -out <format>     Output format (csv|excel|yaml|json|html|xml|markdown|ascii|fixed)
+  -f <style>        Format style variant (for ascii output)
+  -input-encoding <name>
+                    Character encoding of text input (default: auto)
+  -output-encoding <name>
+                    Character encoding of text output (default: utf-8)
+  -h, --help        Show help message
+
+The result has the left columns followed by the right ones. A key with
+the same name in both inputs appears once. Rows keep the left input's
+order; an outer join adds the unmatched right rows at the end.
+
+Examples:
+  morph join orders.csv customers.xlsx -on customer_id -how left enriched.json
+  morph join sales.csv targets.csv -on region=area,year -out md
+  morph join -how anti -on email subscribers.csv bounced.csv -out csv
+`
+	fmt.Fprint(w, usage)
+}
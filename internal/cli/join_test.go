@@ -0,0 +1,51 @@
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/user/table-converter/internal/transform"
+)
+
+func TestParseJoinArgs(t *testing.T) {
+	config, err := ParseJoinArgs([]string{"orders.csv", "customers.xlsx", "-on", "customer_id=id", "-how", "left", "out.json"}, &bytes.Buffer{})
+	if err != nil {
+		t.Fatalf("ParseJoinArgs() error: %v", err)
+	}
+	if config.LeftFile != "orders.csv" || config.RightFile != "customers.xlsx" || config.OutputFile != "out.json" {
+		t.Errorf("files = %q, %q, %q", config.LeftFile, config.RightFile, config.OutputFile)
+	}
+	if config.OutputFormat != FormatJSON {
+		t.Errorf("OutputFormat = %q, want json", config.OutputFormat)
+	}
+	if config.Join.Kind != transform.JoinLeft {
+		t.Errorf("Kind = %v, want left", config.Join.Kind)
+	}
+	if want := (transform.JoinKey{Left: "customer_id", Right: "id"}); len(config.Join.Keys) != 1 || config.Join.Keys[0] != want {
+		t.Errorf("Keys = %v, want [%v]", config.Join.Keys, want)
+	}
+	if config.Join.Suffixes != transform.DefaultJoinSuffixes {
+		t.Errorf("Suffixes = %q, want the defaults", config.Join.Suffixes)
+	}
+}
+
+func TestParseJoinArgs_Errors(t *testing.T) {
+	tests := []struct {
+		args []string
+		want string
+	}{
+		{[]string{"a.csv", "-on", "id", "-out", "csv"}, "left and a right input"},
+		{[]string{"a.csv", "b.csv", "-out", "csv"}, "-on is required"},
+		{[]string{"a.csv", "b.csv", "-on", "id", "-how", "cross", "-out", "csv"}, "invalid join type"},
+		{[]string{"a.csv", "b.csv", "-on", "id", "-suffixes", "_x", "-out", "csv"}, "invalid -suffixes"},
+		{[]string{"-", "-", "-on", "id", "-out", "csv"}, "only one input"},
+		{[]string{"a.csv", "b.csv", "-on", "id"}, "output format required"},
+	}
+	for _, tt := range tests {
+		_, err := ParseJoinArgs(tt.args, &bytes.Buffer{})
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("ParseJoinArgs(%q) error = %v, want %q", tt.args, err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,232 @@
+package cli
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/user/table-converter/internal/query"
+)
+
+// QueryConfig holds the parsed configuration of the query subcommand
+type QueryConfig struct {
+	SQL            string       // The SELECT statement
+	Inputs         []QueryInput // Tables to query (empty for stdin)
+	InputFormat    Format       // Format of every input ("" = detect per input)
+	OutputFormat   Format       // Format of the result
+	FormatStyle    string       // Style variant of the output format
+	OutputFile     string       // Output file path (empty for stdout)
+	InputEncoding  string       // Character encoding of text input
+	OutputEncoding string       // Character encoding of text output
+	ShowHelp       bool         // Show help message
+}
+
+// QueryInput is an input file and the table name it is queried by
+type QueryInput struct {
+	Name string
+	Path string // "-" for stdin
+}
+
+// queryTableName matches the name= prefix that names an input explicitly
+var queryTableName = regexp.MustCompile(`^([A-Za-z_][A-Za-z0-9_]*)=(.+)$`)
+
+// ParseQueryArgs parses the arguments of the query subcommand. Options may
+// follow the statement and files
+func ParseQueryArgs(args []string, output io.Writer) (*QueryConfig, error) {
+	fs := flag.NewFlagSet("morph query", flag.ContinueOnError)
+	fs.SetOutput(output)
+
+	config := &QueryConfig{}
+	var inFormat, outFormat string
+	fs.StringVar(&inFormat, "in", "", "Input format of every file (auto|csv|excel|yaml|json|html|xml|markdown|ascii|fixed)")
+	fs.StringVar(&outFormat, "out", "", "Output format (csv|excel|yaml|json|html|xml|markdown|ascii|fixed)")
+	fs.StringVar(&config.FormatStyle, "f", "", "Format style variant (for ascii output)")
+	fs.StringVar(&config.OutputFile, "o", "", "Output file (default stdout)")
+	fs.StringVar(&config.InputEncoding, "input-encoding", EncodingAuto, "Character encoding of text input")
+	fs.StringVar(&config.OutputEncoding, "output-encoding", "utf-8", "Character encoding of text output")
+	fs.BoolVar(&config.ShowHelp, "h", false, "Show help message")
+	fs.BoolVar(&config.ShowHelp, "help", false, "Show help message")
+
+	fs.Usage = func() {
+		printQueryUsage(output)
+	}
+
+	positional, err := parseInterspersed(fs, args)
+	if err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			config.ShowHelp = true
+			return config, nil
+		}
+		return nil, err
+	}
+	if config.ShowHelp {
+		return config, nil
+	}
+
+	if len(positional) == 0 {
+		return nil, errors.New("missing SQL statement")
+	}
+	config.SQL = positional[0]
+
+	if err := validateEncoding(config.InputEncoding, true); err != nil {
+		return nil, fmt.Errorf("invalid -input-encoding: %w", err)
+	}
+	if err := validateEncoding(config.OutputEncoding, false); err != nil {
+		return nil, fmt.Errorf("invalid -output-encoding: %w", err)
+	}
+
+	if err := parseQueryInputs(positional[1:], config); err != nil {
+		return nil, err
+	}
+
+	if config.InputFormat, err = inputFormatFlag(inFormat); err != nil {
+		return nil, err
+	}
+	if config.OutputFormat, err = outputTableFormat(outFormat, config.OutputFile); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// parseQueryInputs names the input files. A file is named name=path or
+// after its base name without extensions; the first one can also be
+// queried as "input"
+func parseQueryInputs(args []string, config *QueryConfig) error {
+	if len(args) == 0 {
+		args = []string{"-"}
+	}
+
+	stdin := false
+	seen := make(map[string]string)
+	for _, arg := range args {
+		name, path := "", arg
+		if m := queryTableName.FindStringSubmatch(arg); m != nil {
+			name, path = m[1], m[2]
+		}
+		if path == "-" {
+			if stdin {
+				return errors.New("standard input can only be queried once")
+			}
+			stdin = true
+		}
+		if name == "" {
+			name = queryInputName(path)
+		}
+
+		key := strings.ToLower(name)
+		if other, ok := seen[key]; ok {
+			return fmt.Errorf("table name %q is used by both %s and %s (name them with name=FILE)", name, other, arg)
+		}
+		seen[key] = arg
+		config.Inputs = append(config.Inputs, QueryInput{Name: name, Path: path})
+	}
+	return nil
+}
+
+// queryInputName returns the table name of an input path: "sales" for
+// data/sales.csv.gz and "input" for stdin
+func queryInputName(path string) string {
+	if path == "-" {
+		return "input"
+	}
+	name, _ := SplitCompressionSuffix(filepath.Base(path))
+	return strings.TrimSuffix(name, filepath.Ext(name))
+}
+
+// RunQuery executes the query subcommand: it parses each input, runs the
+// statement over them and writes the result
+func RunQuery(args []string, stdout, stderr io.Writer) ExitCode {
+	config, err := ParseQueryArgs(args, stderr)
+	if err != nil {
+		cliErr := FormatUsageError(err.Error())
+		fmt.Fprintln(stderr, cliErr.Message)
+		return cliErr.ExitCode
+	}
+
+	if config.ShowHelp {
+		printQueryUsage(stdout)
+		return ExitSuccess
+	}
+
+	if err := Query(config, stdout, stderr); err != nil {
+		cliErr := FormatError(err)
+		fmt.Fprintln(stderr, cliErr.Message)
+		return cliErr.ExitCode
+	}
+	return ExitSuccess
+}
+
+// Query reads the inputs of a query, runs it and writes the result to the
+// output file or stdout
+func Query(config *QueryConfig, stdout, stderr io.Writer) error {
+	// Check the statement before reading any input
+	stmt, err := query.Parse(config.SQL)
+	if err != nil {
+		return FormatQueryError(err)
+	}
+
+	tables := make(query.Tables)
+	for i, in := range config.Inputs {
+		td, err := readInputTable(in.Path, config.InputFormat, config.InputEncoding, stderr)
+		if err != nil {
+			return err
+		}
+		tables[in.Name] = td
+		if i == 0 {
+			if _, taken := tables["input"]; !taken {
+				tables["input"] = td
+			}
+		}
+	}
+
+	result, err := stmt.Execute(tables)
+	if err != nil {
+		return FormatQueryError(err)
+	}
+
+	return writeOutputTable(result, config.OutputFile, stdout, ConvertOptions{
+		OutputFormat:   config.OutputFormat,
+		FormatStyle:    config.FormatStyle,
+		OutputEncoding: config.OutputEncoding,
+	})
+}
+
+// printQueryUsage prints the usage information of the query subcommand
+func printQueryUsage(w io.Writer) {
+	usage := `morph query - Run a SQL SELECT statement over tables in any input format
+
+Usage:
+  morph query [OPTIONS] SQL [FILE...]
+
+Each file is parsed in the format of its extension (or detected from its
+content) and queried as a table named after the file: sales.csv.gz is
+"sales". Name a file explicitly with name=FILE. The first file, or stdin
+when there are no files, is also the table "input".
+
+Supported: SELECT [DISTINCT], FROM, [INNER|LEFT|RIGHT|FULL|CROSS] JOIN ... ON,
+WHERE, GROUP BY, HAVING, ORDER BY ... [ASC|DESC] [NULLS FIRST|LAST],
+LIMIT ... OFFSET, CASE, CAST, IN, BETWEEN, LIKE, ILIKE, IS NULL and the
+aggregates count, sum, avg, min and max.
+
+Options:
+  -in <format>      Input format of every file (default: from the extension)
+  -out <format>     Output format (csv|excel|yaml|json|html|xml|markdown|ascii|fixed)
+  -f <style>        Format style variant (for ascii output)
+  -o <file>         Write the result to a file; its extension sets the format
+  -input-encoding <name>
+                    Character encoding of text input (default: auto)
+  -output-encoding <name>
+                    Character encoding of text output (default: utf-8)
+  -h, --help        Show help message
+
+Examples:
+  morph query 'SELECT region, sum(amount) FROM input GROUP BY region' sales.xlsx -out md
+  morph query 'SELECT * FROM input WHERE amount > 100 ORDER BY amount DESC LIMIT 10' -out csv < sales.csv
+  morph query 'SELECT o.id, c.name FROM o JOIN c ON o.customer_id = c.id' o=orders.csv c=customers.json -o out.json
+`
+	fmt.Fprint(w, usage)
+}
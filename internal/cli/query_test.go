@@ -0,0 +1,73 @@
+package cli
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseQueryArgs(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		inputs  []QueryInput
+		output  Format
+		wantErr string
+	}{
+		{
+			name:   "stdin",
+			args:   []string{"-out", "csv", "SELECT * FROM input"},
+			inputs: []QueryInput{{Name: "input", Path: "-"}},
+			output: FormatCSV,
+		},
+		{
+			name:   "flags after files",
+			args:   []string{"SELECT * FROM sales", "data/sales.csv.gz", "-out", "json"},
+			inputs: []QueryInput{{Name: "sales", Path: "data/sales.csv.gz"}},
+			output: FormatJSON,
+		},
+		{
+			name:   "named inputs and output file",
+			args:   []string{"SELECT 1", "o=orders.csv", "people.json", "-o", "out.md"},
+			inputs: []QueryInput{{Name: "o", Path: "orders.csv"}, {Name: "people", Path: "people.json"}},
+			output: FormatMarkdown,
+		},
+		{
+			name:    "missing statement",
+			args:    []string{"-out", "csv"},
+			wantErr: "missing SQL statement",
+		},
+		{
+			name:    "missing output format",
+			args:    []string{"SELECT 1"},
+			wantErr: "output format required",
+		},
+		{
+			name:    "clashing names",
+			args:    []string{"SELECT 1", "a/sales.csv", "b/Sales.json", "-out", "csv"},
+			wantErr: `table name "Sales" is used by both`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config, err := ParseQueryArgs(tt.args, &bytes.Buffer{})
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("ParseQueryArgs() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseQueryArgs() error: %v", err)
+			}
+			if !reflect.DeepEqual(config.Inputs, tt.inputs) {
+				t.Errorf("Inputs = %v, want %v", config.Inputs, tt.inputs)
+			}
+			if config.OutputFormat != tt.output {
+				t.Errorf("OutputFormat = %q, want %q", config.OutputFormat, tt.output)
+			}
+		})
+	}
+}
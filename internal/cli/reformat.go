@@ -0,0 +1,333 @@
+package cli
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/user/table-converter/internal/model"
+	"github.com/user/table-converter/internal/parser"
+	"github.com/user/table-converter/internal/serializer"
+)
+
+// FmtConfig holds the parsed configuration of the fmt subcommand
+type FmtConfig struct {
+	Files    []string // Documents to reformat (empty for stdin)
+	Check    bool     // Report documents that would change instead of rewriting them
+	ShowHelp bool     // Show help message
+}
+
+// FormatResult describes the outcome of reformatting a document
+type FormatResult struct {
+	Output  string // The reformatted document
+	Changed int    // Number of tables whose text changed
+	Skipped []int  // Start lines of tables left as-is because their layout cannot be reproduced
+}
+
+// ParseFmtArgs parses the arguments of the fmt subcommand
+func ParseFmtArgs(args []string, output io.Writer) (*FmtConfig, error) {
+	fs := flag.NewFlagSet("morph fmt", flag.ContinueOnError)
+	fs.SetOutput(output)
+
+	config := &FmtConfig{}
+	fs.BoolVar(&config.Check, "check", false, "Exit non-zero if any table would be reformatted")
+	fs.BoolVar(&config.ShowHelp, "h", false, "Show help message")
+	fs.BoolVar(&config.ShowHelp, "help", false, "Show help message")
+
+	fs.Usage = func() {
+		printFmtUsage(output)
+	}
+
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			config.ShowHelp = true
+			return config, nil
+		}
+		return nil, err
+	}
+
+	config.Files = fs.Args()
+	return config, nil
+}
+
+// FormatDocument reformats every table in a Markdown, Org-mode or
+// reStructuredText document in its detected style. All text outside the
+// tables is left byte-identical. markdown selects the Markdown rules of
+// parser.FindTables
+func FormatDocument(doc string, markdown bool) (*FormatResult, error) {
+	lines := strings.Split(doc, "\n")
+	clean := make([]string, len(lines))
+	for i, line := range lines {
+		clean[i] = strings.TrimSuffix(line, "\r")
+	}
+
+	result := &FormatResult{}
+	out := make([]string, 0, len(lines))
+	next := 0
+
+	for _, block := range parser.FindTables(clean, markdown) {
+		out = append(out, lines[next:block.StartLine-1]...)
+		original := lines[block.StartLine-1 : block.EndLine]
+		next = block.EndLine
+
+		if !reproducibleLayout(block) {
+			result.Skipped = append(result.Skipped, block.StartLine)
+			out = append(out, original...)
+			continue
+		}
+
+		formatted, err := formatTableBlock(block)
+		if err != nil {
+			return nil, err
+		}
+
+		// Keep the block's indentation and line endings
+		eol := ""
+		if strings.HasSuffix(original[len(original)-1], "\r") {
+			eol = "\r"
+		}
+		for i, line := range formatted {
+			formatted[i] = block.Indent + line + eol
+		}
+
+		if strings.Join(formatted, "\n") != strings.Join(original, "\n") {
+			result.Changed++
+		}
+		out = append(out, formatted...)
+	}
+
+	out = append(out, lines[next:]...)
+	result.Output = strings.Join(out, "\n")
+	return result, nil
+}
+
+// ruleLayout inspects the separator lines of a Markdown or Org-mode table.
+// The serializer only draws the rule below the header, plus the opening and
+// closing rules Org-mode tables often have; ok is false for other layouts
+func ruleLayout(block parser.TableBlock) (top, bottom, ok bool) {
+	separators := block.SeparatorLines()
+	if block.Style == parser.StyleOrgMode {
+		if len(separators) > 1 && separators[0] == 0 {
+			top, separators = true, separators[1:]
+		}
+		if len(separators) > 1 && separators[len(separators)-1] == len(block.Lines)-1 {
+			bottom, separators = true, separators[:len(separators)-1]
+		}
+	}
+
+	header := 1
+	if top {
+		header = 2
+	}
+	return top, bottom, len(separators) == 1 && separators[0] == header
+}
+
+// reproducibleLayout reports whether the serializer can redraw the table
+// without losing separator lines or spanned cells
+func reproducibleLayout(block parser.TableBlock) bool {
+	if block.Style != parser.StyleMarkdown && block.Style != parser.StyleOrgMode {
+		return block.HasRegularColumns()
+	}
+	_, _, ok := ruleLayout(block)
+	return ok
+}
+
+// formatTableBlock redraws a table block in its detected style
+func formatTableBlock(block parser.TableBlock) ([]string, error) {
+	s := serializer.NewUnifiedASCIISerializer(serializer.TableStyle(block.Style))
+
+	// Markdown alignment markers change how the table renders, so only keep
+	// the ones already present instead of adding them to numeric columns
+	if block.Style == parser.StyleMarkdown {
+		s.NumericAlign = serializer.NumericLeft
+	}
+
+	// Framed tables with a rule between every row keep their row rules
+	s.RowSeparators = len(block.SeparatorLines()) > 3
+
+	var buf bytes.Buffer
+	if err := s.Serialize(preserveRawValues(block.Table), &buf); err != nil {
+		return nil, err
+	}
+
+	formatted := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	for i, line := range formatted {
+		formatted[i] = strings.TrimRight(line, " ")
+	}
+
+	// Org-mode tables keep their opening and closing rules
+	if block.Style == parser.StyleOrgMode {
+		rule := formatted[1]
+		top, bottom, _ := ruleLayout(block)
+		if top {
+			formatted = append([]string{rule}, formatted...)
+		}
+		if bottom {
+			formatted = append(formatted, rule)
+		}
+	}
+	return formatted, nil
+}
+
+// preserveRawValues returns a copy of the table whose boolean cells keep
+// their original text. The serializer would otherwise print "1" or "yes"
+// as "true"; cells that read as numbers stay numeric for alignment
+func preserveRawValues(td *model.TableData) *model.TableData {
+	rows := make([][]model.Value, len(td.Rows))
+	for i, row := range td.Rows {
+		rows[i] = make([]model.Value, len(row))
+		for j, val := range row {
+			rows[i][j] = val
+			if val.Type != model.TypeBoolean {
+				continue
+			}
+			if num, err := strconv.ParseFloat(strings.TrimSpace(val.Raw), 64); err == nil {
+				rows[i][j] = model.Value{Type: model.TypeNumber, Raw: val.Raw, Parsed: num}
+			} else {
+				rows[i][j] = model.NewStringValue(val.Raw)
+			}
+		}
+	}
+
+	out := model.NewTableData(td.Headers, rows)
+	out.Alignments = td.Alignments
+	return out
+}
+
+// RunFmt executes the fmt subcommand, rewriting each document in place or,
+// with -check, listing the documents that would change
+func RunFmt(args []string, stdout, stderr io.Writer) ExitCode {
+	config, err := ParseFmtArgs(args, stderr)
+	if err != nil {
+		cliErr := FormatUsageError(err.Error())
+		fmt.Fprintln(stderr, cliErr.Message)
+		return cliErr.ExitCode
+	}
+
+	if config.ShowHelp {
+		printFmtUsage(stdout)
+		return ExitSuccess
+	}
+
+	// Without files, filter stdin to stdout
+	if len(config.Files) == 0 {
+		return formatStream(config, stdout, stderr)
+	}
+
+	exitCode := ExitSuccess
+	for _, path := range config.Files {
+		if code := formatFile(path, config, stdout, stderr); code != ExitSuccess {
+			exitCode = code
+		}
+	}
+	return exitCode
+}
+
+// formatStream reformats the document on stdin
+func formatStream(config *FmtConfig, stdout, stderr io.Writer) ExitCode {
+	input, err := io.ReadAll(os.Stdin)
+	if err != nil {
+		cliErr := FormatFileReadError("<stdin>", err)
+		fmt.Fprintln(stderr, cliErr.Message)
+		return cliErr.ExitCode
+	}
+
+	// Stdin has no name to tell its markup by. The Markdown rules are the
+	// cautious choice: they only leave more text alone
+	result, err := FormatDocument(string(input), true)
+	if err != nil {
+		cliErr := FormatSerializeError("table", err)
+		fmt.Fprintln(stderr, cliErr.Message)
+		return cliErr.ExitCode
+	}
+	reportSkipped("<stdin>", result, stderr)
+
+	if config.Check {
+		if result.Changed > 0 {
+			fmt.Fprintln(stdout, "<stdin>")
+			return ExitError
+		}
+		return ExitSuccess
+	}
+
+	fmt.Fprint(stdout, result.Output)
+	return ExitSuccess
+}
+
+// formatFile reformats a document in place
+func formatFile(path string, config *FmtConfig, stdout, stderr io.Writer) ExitCode {
+	info, err := os.Stat(path)
+	if err != nil {
+		cliErr := FormatFileReadError(path, err)
+		fmt.Fprintln(stderr, cliErr.Message)
+		return cliErr.ExitCode
+	}
+
+	input, err := os.ReadFile(path)
+	if err != nil {
+		cliErr := FormatFileReadError(path, err)
+		fmt.Fprintln(stderr, cliErr.Message)
+		return cliErr.ExitCode
+	}
+
+	format, _ := DetectFormat(path)
+	result, err := FormatDocument(string(input), format == FormatMarkdown)
+	if err != nil {
+		cliErr := FormatSerializeError("table", err)
+		fmt.Fprintln(stderr, cliErr.Message)
+		return cliErr.ExitCode
+	}
+	reportSkipped(path, result, stderr)
+
+	if result.Changed == 0 {
+		return ExitSuccess
+	}
+
+	if config.Check {
+		fmt.Fprintln(stdout, path)
+		return ExitError
+	}
+
+	if err := os.WriteFile(path, []byte(result.Output), info.Mode().Perm()); err != nil {
+		cliErr := FormatFileWriteError(path, err)
+		fmt.Fprintln(stderr, cliErr.Message)
+		return cliErr.ExitCode
+	}
+	return ExitSuccess
+}
+
+// reportSkipped warns about tables that were left unformatted
+func reportSkipped(name string, result *FormatResult, stderr io.Writer) {
+	for _, line := range result.Skipped {
+		fmt.Fprintf(stderr, "Warning: %s:%d: table has separator lines or spanned cells that cannot be preserved, left unchanged\n", name, line)
+	}
+}
+
+// printFmtUsage prints the usage information of the fmt subcommand
+func printFmtUsage(w io.Writer) {
+	usage := `morph fmt - Reformat tables inside Markdown, Org-mode and reStructuredText documents
+
+Usage:
+  morph fmt [OPTIONS] [FILE...]
+
+Every table in each file is redrawn in its detected style and the file is
+rewritten in place. All other text is left untouched. Without files, the
+document is read from stdin and written to stdout.
+
+Options:
+  -check            Do not rewrite files; list those that would change and
+                    exit with status 1 if there are any
+  -h, --help        Show help message
+
+Examples:
+  morph fmt README.md
+  morph fmt -check docs/*.md notes.org
+  morph fmt < notes.org > formatted.org
+`
+	fmt.Fprint(w, usage)
+}
@@ -0,0 +1,199 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestFormatDocument(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+		changed  int
+	}{
+		{
+			name: "markdown with prose",
+			input: `# Title
+
+Intro | text.
+
+| Name | Age |
+|---|:-:|
+| Alice | 30 |
+|Bob|4|
+
+Outro.
+`,
+			expected: `# Title
+
+Intro | text.
+
+| Name  | Age |
+| ----- | :-: |
+| Alice | 30  |
+| Bob   |  4  |
+
+Outro.
+`,
+			changed: 1,
+		},
+		{
+			name: "org with rules and formula",
+			input: `* Heading
+|---+---|
+| item | qty |
+|---+---|
+| tea | 1.50 |
+|---+---|
+#+TBLFM: $2=$1
+`,
+			expected: `* Heading
+|------+------|
+| item |  qty |
+|------+------|
+| tea  | 1.50 |
+|------+------|
+#+TBLFM: $2=$1
+`,
+			changed: 1,
+		},
+		{
+			name:     "indented table with CRLF",
+			input:    "text\r\n  | a | b |\r\n  |-|-|\r\n  | x | yes |\r\n",
+			expected: "text\r\n  | a   | b   |\r\n  | --- | --- |\r\n  | x   | yes |\r\n",
+			changed:  1,
+		},
+		{
+			name:     "already formatted",
+			input:    "| a   | b   |\n| --- | --- |\n| x   | y   |",
+			expected: "| a   | b   |\n| --- | --- |\n| x   | y   |",
+			changed:  0,
+		},
+		{
+			name:     "code block untouched",
+			input:    "```\n| a | b |\n|-|-|\n```\n",
+			expected: "```\n| a | b |\n|-|-|\n```\n",
+			changed:  0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := FormatDocument(tt.input, false)
+			if err != nil {
+				t.Fatalf("FormatDocument() error = %v", err)
+			}
+			if result.Output != tt.expected {
+				t.Errorf("unexpected output:\n%s\nwant:\n%s", result.Output, tt.expected)
+			}
+			if result.Changed != tt.changed {
+				t.Errorf("Changed = %d, want %d", result.Changed, tt.changed)
+			}
+
+			// Formatting is idempotent
+			again, err := FormatDocument(result.Output, false)
+			if err != nil {
+				t.Fatalf("FormatDocument() error = %v", err)
+			}
+			if again.Output != result.Output || again.Changed != 0 {
+				t.Errorf("second pass changed the document:\n%s", again.Output)
+			}
+		})
+	}
+}
+
+func TestFormatDocument_SkipsIrregularRules(t *testing.T) {
+	input := "| a | b |\n|---|---|\n| 1 | 2 |\n|---|---|\n| 3 | 4 |\n"
+
+	result, err := FormatDocument(input, false)
+	if err != nil {
+		t.Fatalf("FormatDocument() error = %v", err)
+	}
+	if result.Output != input {
+		t.Errorf("expected table to be left unchanged, got:\n%s", result.Output)
+	}
+	if len(result.Skipped) != 1 || result.Skipped[0] != 1 {
+		t.Errorf("Skipped = %v, want [1]", result.Skipped)
+	}
+}
+
+func TestFormatDocument_SkipsSpannedGridCells(t *testing.T) {
+	inputs := []string{
+		"+-------+-------+\n| a     | b     |\n+=======+=======+\n| spans both    |\n+-------+-------+\n| 1     | 2     |\n+-------+-------+\n",
+		"+-------+-------+\n| a     | b     |\n+=======+=======+\n| spans | 1     |\n|       +-------+\n| rows  | 2     |\n+-------+-------+\n",
+		"┌───────┬───────┐\n│ a     │ b     │\n├───────┴───────┤\n│ spans both    │\n└───────────────┘\n",
+	}
+
+	for _, input := range inputs {
+		doc := "Text\n\n" + input
+		result, err := FormatDocument(doc, false)
+		if err != nil {
+			t.Fatalf("FormatDocument() error = %v", err)
+		}
+		if result.Output != doc {
+			t.Errorf("expected table to be left unchanged, got:\n%s", result.Output)
+		}
+		if len(result.Skipped) != 1 || result.Skipped[0] != 3 {
+			t.Errorf("Skipped = %v, want [3]", result.Skipped)
+		}
+	}
+}
+
+func TestFormatDocument_MarkdownProse(t *testing.T) {
+	doc := "Key   Value\n----  -----\nfoo   bar\n\n    |a|b|\n    |-|-|\n"
+
+	result, err := FormatDocument(doc, true)
+	if err != nil {
+		t.Fatalf("FormatDocument() error = %v", err)
+	}
+	if result.Output != doc || result.Changed != 0 {
+		t.Errorf("expected prose and code to be left unchanged, got:\n%s", result.Output)
+	}
+}
+
+func TestRunFmt(t *testing.T) {
+	tmpDir := t.TempDir()
+	path := filepath.Join(tmpDir, "doc.md")
+	original := "Text\n\n| a | b |\n|-|-|\n| x | y |\n"
+	if err := os.WriteFile(path, []byte(original), 0644); err != nil {
+		t.Fatalf("failed to create test file: %v", err)
+	}
+
+	// -check reports the file without rewriting it
+	var stdout, stderr bytes.Buffer
+	if code := Run([]string{"fmt", "-check", path}, &stdout, &stderr); code != ExitError {
+		t.Errorf("fmt -check exit code = %d, want %d", code, ExitError)
+	}
+	if strings.TrimSpace(stdout.String()) != path {
+		t.Errorf("fmt -check output = %q, want %q", stdout.String(), path)
+	}
+	content, _ := os.ReadFile(path)
+	if string(content) != original {
+		t.Errorf("fmt -check modified the file")
+	}
+
+	// fmt rewrites the file in place
+	stdout.Reset()
+	if code := Run([]string{"fmt", path}, &stdout, &stderr); code != ExitSuccess {
+		t.Fatalf("fmt exit code = %d, stderr: %s", code, stderr.String())
+	}
+	content, _ = os.ReadFile(path)
+	expected := "Text\n\n| a   | b   |\n| --- | --- |\n| x   | y   |\n"
+	if string(content) != expected {
+		t.Errorf("formatted file = %q, want %q", string(content), expected)
+	}
+
+	// A formatted file passes the check
+	if code := Run([]string{"fmt", "--check", path}, &stdout, &stderr); code != ExitSuccess {
+		t.Errorf("fmt --check exit code = %d, want %d", code, ExitSuccess)
+	}
+
+	// Missing files are read errors
+	if code := Run([]string{"fmt", filepath.Join(tmpDir, "missing.md")}, &stdout, &stderr); code != ExitFileReadError {
+		t.Errorf("fmt missing file exit code = %d, want %d", code, ExitFileReadError)
+	}
+}
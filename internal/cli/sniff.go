@@ -0,0 +1,429 @@
+package cli
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/user/table-converter/internal/parser"
+)
+
+// sniffSize is how many bytes of input are inspected to detect its format
+const sniffSize = 64 * 1024
+
+// minSniffScore is the confidence a format needs to be picked
+const minSniffScore = 50
+
+// sniffLines is how many lines of text input are inspected
+const sniffLines = 20
+
+var (
+	zipMagic = []byte("PK\x03\x04")
+	oleMagic = []byte("\xD0\xCF\x11\xE0\xA1\xB1\x1A\xE1")
+	utf8BOM  = []byte("\xEF\xBB\xBF")
+
+	// recordOutputHeader matches the record headers of psql expanded and MySQL vertical output
+	recordOutputHeader = regexp.MustCompile(`^(-\[ RECORD \d+ \]|\*+ \d+\. row \*+$)`)
+	// yamlMapping matches a YAML key: value line
+	yamlMapping = regexp.MustCompile(`^(- )?[\w"' -]+:( |$)`)
+)
+
+// FormatGuess is the confidence that input is in a format, with the
+// evidence found for it
+type FormatGuess struct {
+	Format  Format
+	Score   int      // Confidence from 0 to 100
+	Reasons []string // What in the content points to the format
+}
+
+// Detection holds the guesses for every format that matched the content,
+// most confident first
+type Detection struct {
+	Guesses []FormatGuess
+}
+
+// Best returns the most confident guess, and false if no format is
+// confident enough to be picked
+func (d Detection) Best() (FormatGuess, bool) {
+	if len(d.Guesses) == 0 || d.Guesses[0].Score < minSniffScore {
+		return FormatGuess{}, false
+	}
+	return d.Guesses[0], true
+}
+
+// Report writes the guesses and their reasons as a table
+func (d Detection) Report(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "FORMAT\tSCORE\tREASONS")
+	for _, guess := range d.Guesses {
+		fmt.Fprintf(tw, "%s\t%d\t%s\n", guess.Format, guess.Score, strings.Join(guess.Reasons, "; "))
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	if best, ok := d.Best(); ok {
+		_, err := fmt.Fprintf(w, "\ndetected: %s\n", best.Format)
+		return err
+	}
+	_, err := fmt.Fprintln(w, "\ndetected: none (use -in to specify the format)")
+	return err
+}
+
+// sniffer scores how likely a sample is to be in its format. complete
+// reports whether the sample holds the whole input
+type sniffer func(sample []byte, complete bool) []FormatGuess
+
+// sniffers are tried in order; on equal scores the earlier format wins
+var sniffers = []sniffer{
+	sniffSpreadsheet,
+	sniffJSON,
+	sniffMarkup,
+	sniffYAML,
+	sniffTextTable,
+	sniffCSV,
+}
+
+// SniffFormat scores every format against the first bytes of the input
+func SniffFormat(sample []byte, complete bool) Detection {
+	sample = bytes.TrimPrefix(sample, utf8BOM)
+
+	best := make(map[Format]int)
+	var guesses []FormatGuess
+	for _, sniff := range sniffers {
+		for _, guess := range sniff(sample, complete) {
+			if guess.Score <= 0 {
+				continue
+			}
+			// Keep the strongest evidence found for each format
+			if i, ok := best[guess.Format]; ok {
+				if guess.Score > guesses[i].Score {
+					guesses[i] = guess
+				}
+				continue
+			}
+			best[guess.Format] = len(guesses)
+			guesses = append(guesses, guess)
+		}
+	}
+
+	sort.SliceStable(guesses, func(i, j int) bool {
+		return guesses[i].Score > guesses[j].Score
+	})
+	return Detection{Guesses: guesses}
+}
+
+// DetectInputFormat inspects the start of the input and returns the
+// detection along with a reader that still yields the whole input
+func DetectInputFormat(input io.Reader) (Detection, io.Reader, error) {
+	br := bufio.NewReaderSize(input, sniffSize)
+	sample, err := br.Peek(sniffSize)
+	complete := errors.Is(err, io.EOF)
+	if err != nil && !complete && !errors.Is(err, bufio.ErrBufferFull) {
+		return Detection{}, nil, NewCLIError(fmt.Sprintf("Error: Failed to read input\n  Reason: %v", err), ExitFileReadError).WithErr(err)
+	}
+	return SniffFormat(sample, complete), br, nil
+}
+
+// ReportDetection prints how the encoding and format of the input were detected
+func ReportDetection(config *Config, output io.Writer) error {
+	input, err := createInputReader(config.InputFile)
+	if err != nil {
+		return FormatFileReadError(config.InputFile, err)
+	}
+	defer input.Close()
+
+	br := bufio.NewReaderSize(input, sniffSize)
+	encoding, reason := config.InputEncoding, "set with -input-encoding"
+	if encoding == "" || strings.EqualFold(encoding, EncodingAuto) {
+		sample, err := br.Peek(sniffSize)
+		encoding, reason = detectEncoding(sample, errors.Is(err, io.EOF))
+	}
+
+	decoded, err := decodeInput(br, encoding, "")
+	if err != nil {
+		return NewCLIError(fmt.Sprintf("invalid input encoding: %v", err), ExitUsageError)
+	}
+	detection, _, err := DetectInputFormat(decoded)
+	if err != nil {
+		return err
+	}
+	if err := detection.Report(output); err != nil {
+		return err
+	}
+
+	if encoding == "" {
+		encoding = "none"
+	}
+	_, err = fmt.Fprintf(output, "encoding: %s (%s)\n", encoding, reason)
+	return err
+}
+
+// resolveAutoFormat detects the input format and returns it with a reader
+// that still yields the whole input
+func resolveAutoFormat(input io.Reader) (Format, io.Reader, error) {
+	detection, input, err := DetectInputFormat(input)
+	if err != nil {
+		return "", nil, err
+	}
+	best, ok := detection.Best()
+	if !ok {
+		return "", nil, NewCLIError("Error: cannot detect input format from its content\n  Use -in to specify the format, or -detect to see what was considered", ExitUsageError)
+	}
+	return best.Format, input, nil
+}
+
+// sniffSpreadsheet checks for the binary signatures of workbook files
+func sniffSpreadsheet(sample []byte, complete bool) []FormatGuess {
+	switch {
+	case bytes.HasPrefix(sample, oleMagic):
+		return []FormatGuess{{FormatExcel, 90, []string{"OLE2 compound document signature (legacy .xls)"}}}
+	case !bytes.HasPrefix(sample, zipMagic):
+		return nil
+	case bytes.Contains(sample, []byte("application/vnd.oasis.opendocument.spreadsheet")):
+		return []FormatGuess{{FormatExcel, 60, []string{"ZIP signature with an OpenDocument spreadsheet mimetype (ODS files may not be readable)"}}}
+	case bytes.Contains(sample, []byte("[Content_Types].xml")) || bytes.Contains(sample, []byte("xl/")):
+		return []FormatGuess{{FormatExcel, 100, []string{"ZIP signature with OOXML workbook parts"}}}
+	default:
+		return []FormatGuess{{FormatExcel, 50, []string{"ZIP signature"}}}
+	}
+}
+
+// sniffJSON checks for a JSON array or object
+func sniffJSON(sample []byte, complete bool) []FormatGuess {
+	trimmed := bytes.TrimSpace(sample)
+	if len(trimmed) == 0 || (trimmed[0] != '[' && trimmed[0] != '{') {
+		return nil
+	}
+
+	guess := FormatGuess{Format: FormatJSON, Score: 60, Reasons: []string{fmt.Sprintf("starts with %q", trimmed[0])}}
+	if complete {
+		if json.Valid(trimmed) {
+			guess.Score = 100
+			guess.Reasons = append(guess.Reasons, "parses as JSON")
+		} else {
+			guess.Score = 30
+			guess.Reasons = append(guess.Reasons, "does not parse as JSON")
+		}
+		return []FormatGuess{guess}
+	}
+
+	// A truncated sample cannot be validated, but its first tokens can
+	dec := json.NewDecoder(bytes.NewReader(trimmed))
+	if _, err := dec.Token(); err == nil {
+		if tok, err := dec.Token(); err == nil {
+			switch tok.(type) {
+			case json.Delim, string:
+				guess.Score = 85
+				guess.Reasons = append(guess.Reasons, "followed by JSON tokens")
+			}
+		}
+	}
+	return []FormatGuess{guess}
+}
+
+// sniffMarkup checks for XML declarations, the XML dataset root and HTML tables
+func sniffMarkup(sample []byte, complete bool) []FormatGuess {
+	trimmed := bytes.TrimSpace(sample)
+	if len(trimmed) == 0 || trimmed[0] != '<' {
+		if bytes.Contains(bytes.ToLower(sample), []byte("<table")) {
+			return []FormatGuess{{FormatHTML, 40, []string{"contains a <table> element after other text"}}}
+		}
+		return nil
+	}
+	lower := bytes.ToLower(trimmed)
+
+	var guesses []FormatGuess
+	switch {
+	case bytes.HasPrefix(lower, []byte("<!doctype html")) || bytes.HasPrefix(lower, []byte("<html")):
+		guesses = append(guesses, FormatGuess{FormatHTML, 95, []string{"HTML document"}})
+	case bytes.HasPrefix(lower, []byte("<table")):
+		guesses = append(guesses, FormatGuess{FormatHTML, 95, []string{"starts with a <table> element"}})
+	case bytes.Contains(lower, []byte("<table")):
+		guesses = append(guesses, FormatGuess{FormatHTML, 85, []string{"markup containing a <table> element"}})
+	}
+
+	xmlGuess := FormatGuess{Format: FormatXML}
+	if bytes.HasPrefix(lower, []byte("<?xml")) {
+		xmlGuess.Score = 80
+		xmlGuess.Reasons = append(xmlGuess.Reasons, "XML declaration")
+	}
+	if bytes.Contains(trimmed, []byte("<dataset")) {
+		xmlGuess.Score = 95
+		xmlGuess.Reasons = append(xmlGuess.Reasons, "<dataset> root element")
+	}
+	if xmlGuess.Score == 0 && len(guesses) == 0 {
+		xmlGuess.Score = 55
+		xmlGuess.Reasons = append(xmlGuess.Reasons, "starts with an element")
+	}
+	return append(guesses, xmlGuess)
+}
+
+// sniffYAML checks for YAML directives, document markers and block mappings
+func sniffYAML(sample []byte, complete bool) []FormatGuess {
+	lines := sampleLines(sample, complete)
+	if len(lines) == 0 {
+		return nil
+	}
+
+	first := strings.TrimRight(lines[0], " ")
+	switch {
+	case strings.HasPrefix(first, "%YAML"):
+		return []FormatGuess{{FormatYAML, 95, []string{"%YAML directive"}}}
+	case first == "---":
+		return []FormatGuess{{FormatYAML, 85, []string{"--- document marker"}}}
+	}
+
+	// A sequence of mappings: every line is "- key: value", "  key: value" or a comment
+	mappings := 0
+	for _, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		if !yamlMapping.MatchString(trimmed) {
+			return nil
+		}
+		mappings++
+	}
+	if strings.HasPrefix(strings.TrimSpace(lines[0]), "- ") {
+		return []FormatGuess{{FormatYAML, 75, []string{fmt.Sprintf("sequence of mappings (%d key: value lines)", mappings)}}}
+	}
+	return []FormatGuess{{FormatYAML, 55, []string{fmt.Sprintf("%d key: value lines", mappings)}}}
+}
+
+// sniffTextTable checks for tables drawn with borders or rules, using the
+// same scanner that finds tables in documents
+func sniffTextTable(sample []byte, complete bool) []FormatGuess {
+	lines := sampleLines(sample, complete)
+	if len(lines) == 0 {
+		return nil
+	}
+
+	if recordOutputHeader.MatchString(strings.TrimSpace(lines[0])) {
+		return []FormatGuess{{FormatASCII, 95, []string{"database client record header"}}}
+	}
+
+	blocks := parser.FindTables(lines, false)
+	if len(blocks) == 0 {
+		return nil
+	}
+
+	block := blocks[0]
+	if block.StartLine > 1 {
+		return []FormatGuess{{FormatASCII, 40, []string{fmt.Sprintf("%s table at line %d after other text", block.Style, block.StartLine)}}}
+	}
+
+	reason := fmt.Sprintf("%s table starting on line 1", block.Style)
+	guesses := []FormatGuess{{FormatASCII, 90, []string{reason}}}
+	if block.Style == parser.StyleMarkdown {
+		// Markdown tables read the same with either parser; the markdown
+		// parser also insists on the header separator row
+		guesses = append([]FormatGuess{{FormatMarkdown, 92, []string{reason, "header separator row"}}}, guesses...)
+	}
+	return guesses
+}
+
+// delimiterNames describes the delimiters the CSV parser detects
+var delimiterNames = map[rune]string{
+	',':  "comma",
+	'\t': "tab",
+	';':  "semicolon",
+	'|':  "pipe",
+}
+
+// sniffCSV checks that the lines split into the same number of fields on
+// one of the delimiters the CSV parser detects
+func sniffCSV(sample []byte, complete bool) []FormatGuess {
+	lines := sampleLines(sample, complete)
+	if len(lines) == 0 {
+		return nil
+	}
+
+	var best *FormatGuess
+	for _, delim := range parser.CommonDelimiters {
+		guess := sniffDelimiter(lines, delim)
+		if guess != nil && (best == nil || guess.Score > best.Score) {
+			best = guess
+		}
+	}
+	if best == nil {
+		return nil
+	}
+	return []FormatGuess{*best}
+}
+
+// sniffDelimiter scores the lines as CSV separated by delim. Delimiters
+// other than commas score a little lower, so that tables drawn with pipes
+// are still read as text tables
+func sniffDelimiter(lines []string, delim rune) *FormatGuess {
+	r := csv.NewReader(strings.NewReader(strings.Join(lines, "\n")))
+	r.Comma = delim
+	r.FieldsPerRecord = -1
+	records, err := r.ReadAll()
+	if err != nil || len(records) == 0 {
+		return nil
+	}
+
+	fields := len(records[0])
+	consistent := true
+	for _, record := range records[1:] {
+		if len(record) != fields {
+			consistent = false
+		}
+	}
+
+	penalty := 0
+	if delim != ',' {
+		penalty = 5
+	}
+	name := delimiterNames[delim]
+	switch {
+	case fields > 1 && consistent:
+		return &FormatGuess{FormatCSV, 80 - penalty, []string{fmt.Sprintf("%d line(s) of %d %s-separated fields", len(records), fields, name)}}
+	case fields > 1:
+		return &FormatGuess{FormatCSV, 45 - penalty, []string{name + "-separated fields with varying counts"}}
+	}
+	return nil
+}
+
+// sampleLines returns the lines of the sample from the first non-blank line
+// up to sniffLines non-blank lines. Blank lines in between are kept, as they
+// end tables. A line cut off by the end of a partial sample is dropped
+func sampleLines(sample []byte, complete bool) []string {
+	if bytes.IndexByte(sample, 0) >= 0 {
+		return nil // Binary data
+	}
+
+	text := string(sample)
+	if !complete {
+		if i := strings.LastIndexByte(text, '\n'); i >= 0 {
+			text = text[:i]
+		}
+	}
+
+	var lines []string
+	nonBlank := 0
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimRight(line, "\r")
+		if strings.TrimSpace(line) == "" {
+			if nonBlank > 0 {
+				lines = append(lines, line)
+			}
+			continue
+		}
+		lines = append(lines, line)
+		if nonBlank++; nonBlank == sniffLines {
+			break
+		}
+	}
+	return lines
+}
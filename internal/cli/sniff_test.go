@@ -0,0 +1,106 @@
+package cli
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestSniffFormat(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  string
+		want   Format
+		partly bool // Sample is only the start of the input
+	}{
+		{"xlsx", "PK\x03\x04\x14\x00\x06\x00[Content_Types].xml", FormatExcel, true},
+		{"xls", "\xD0\xCF\x11\xE0\xA1\xB1\x1A\xE1\x00\x00", FormatExcel, true},
+		{"json array", `[{"name": "Alice", "age": 30}]`, FormatJSON, false},
+		{"truncated json", `[{"name": "Alice", "age": 30}, {"na`, FormatJSON, true},
+		{"json with BOM", "\xEF\xBB\xBF{\"rows\": []}", FormatJSON, false},
+		{"xml declaration", `<?xml version="1.0"?><dataset><record><a>1</a></record></dataset>`, FormatXML, false},
+		{"xml dataset", `<dataset><record><a>1</a></record></dataset>`, FormatXML, false},
+		{"html document", "<!DOCTYPE html><html><body><table><tr><td>1</td></tr></table></body></html>", FormatHTML, false},
+		{"html table", "<table>\n<tr><th>a</th></tr>\n</table>", FormatHTML, false},
+		{"yaml marker", "---\n- name: Alice\n", FormatYAML, false},
+		{"yaml sequence", "- name: Alice\n  age: 30\n- name: Bob\n  age: 25\n", FormatYAML, false},
+		{"markdown", "| a | b |\n|---|---|\n| 1 | 2 |\n", FormatMarkdown, false},
+		{"box", "+---+---+\n| a | b |\n+---+---+\n| 1 | 2 |\n+---+---+\n", FormatASCII, false},
+		{"unicode box", "┌───┬───┐\n│ a │ b │\n├───┼───┤\n│ 1 │ 2 │\n└───┴───┘\n", FormatASCII, false},
+		{"psql", " a | b\n---+---\n 1 | 2\n(1 row)\n", FormatASCII, false},
+		{"psql expanded", "-[ RECORD 1 ]\na | 1\nb | 2\n", FormatASCII, false},
+		{"mysql vertical", "*************************** 1. row ***************************\na: 1\n", FormatASCII, false},
+		{"csv", "name,age\nAlice,30\n\"Smith, Bob\",25\n", FormatCSV, false},
+		{"csv with CRLF", "name,age\r\nAlice,30\r\n", FormatCSV, false},
+		{"tab-separated", "name\tage\nAlice\t30\nBob\t25\n", FormatCSV, false},
+		{"semicolon-separated", "name;amount\nAlice;1,50\nBob;2,00\n", FormatCSV, false},
+		{"pipe-separated", "name|age\nAlice|30\nBob|25\n", FormatCSV, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			detection := SniffFormat([]byte(tt.input), !tt.partly)
+			best, ok := detection.Best()
+			if !ok {
+				t.Fatalf("no format detected, guesses: %+v", detection.Guesses)
+			}
+			if best.Format != tt.want {
+				t.Errorf("detected %s, want %s (guesses: %+v)", best.Format, tt.want, detection.Guesses)
+			}
+			if len(best.Reasons) == 0 {
+				t.Error("detection should give a reason")
+			}
+		})
+	}
+}
+
+func TestSniffFormat_DelimiterReason(t *testing.T) {
+	best, ok := SniffFormat([]byte("name;amount\nAlice;1,50\nBob;2,00\n"), true).Best()
+	if !ok || !strings.Contains(strings.Join(best.Reasons, "; "), "2 semicolon-separated fields") {
+		t.Errorf("best guess = %+v, want semicolon-separated fields", best)
+	}
+}
+
+func TestSniffFormat_Undetectable(t *testing.T) {
+	for _, input := range []string{"", "hello world\n", "{not json at all", "\x00\x01\x02binary"} {
+		detection := SniffFormat([]byte(input), true)
+		if best, ok := detection.Best(); ok {
+			t.Errorf("SniffFormat(%q) detected %s, want none", input, best.Format)
+		}
+	}
+}
+
+func TestDetectInputFormat_KeepsInput(t *testing.T) {
+	input := "name,age\n" + strings.Repeat("Alice,30\n", sniffSize/8)
+
+	detection, r, err := DetectInputFormat(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("DetectInputFormat failed: %v", err)
+	}
+	if best, ok := detection.Best(); !ok || best.Format != FormatCSV {
+		t.Errorf("detected %+v, want csv", detection.Guesses)
+	}
+
+	data, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatalf("reading input failed: %v", err)
+	}
+	if string(data) != input {
+		t.Errorf("input changed by detection: got %d bytes, want %d", len(data), len(input))
+	}
+}
+
+func TestDetection_Report(t *testing.T) {
+	var buf bytes.Buffer
+	if err := SniffFormat([]byte("a,b\n1,2\n"), true).Report(&buf); err != nil {
+		t.Fatalf("Report failed: %v", err)
+	}
+
+	out := buf.String()
+	for _, want := range []string{"FORMAT", "csv", "comma-separated", "detected: csv"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("report missing %q:\n%s", want, out)
+		}
+	}
+}
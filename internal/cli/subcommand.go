@@ -0,0 +1,137 @@
+package cli
+
+import (
+	"errors"
+	"flag"
+	"io"
+	"strings"
+
+	"github.com/user/table-converter/internal/model"
+)
+
+// parseInterspersed parses flags that may appear before, between or after
+// the positional arguments, which it returns in order
+func parseInterspersed(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		args = fs.Args()
+		if len(args) == 0 {
+			return positional, nil
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+}
+
+// inputFormatFlag parses the -in flag of a subcommand, which may be auto
+// ("" = by extension)
+func inputFormatFlag(name string) (Format, error) {
+	switch {
+	case name == "":
+		return "", nil
+	case strings.EqualFold(name, string(FormatAuto)):
+		return FormatAuto, nil
+	}
+	return ParseFormat(name)
+}
+
+// inputTableFormat returns the format an input file is read in: the given
+// format, else the one of its extension, else detected from its content
+func inputTableFormat(path string, format Format) Format {
+	if format != "" {
+		return format
+	}
+	if path != "-" {
+		if detected, err := DetectFormat(path); err == nil {
+			return detected
+		}
+	}
+	return FormatAuto
+}
+
+// readInputTable parses a whole input file ("-" for stdin) as one table.
+// Empty headers are named and duplicates renamed so that every column can
+// be referred to by name
+func readInputTable(path string, format Format, encoding string, stderr io.Writer) (*model.TableData, error) {
+	format = inputTableFormat(path, format)
+
+	reader, err := CreateInputReader(path)
+	if err != nil {
+		return nil, FormatFileReadError(path, err)
+	}
+	defer reader.Close()
+
+	source := path
+	if source == "-" {
+		source = "<stdin>"
+	}
+	td, err := readTable(reader, ConvertOptions{
+		InputFormat:   format,
+		InputEncoding: encoding,
+		CSVInput:      model.CSVDialect{Delimiter: tsvDelimiter(path)},
+		Warnings:      stderr,
+		Source:        source,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	td.Headers, err = model.NormalizeHeaders(td.Headers, model.HeaderPolicy{
+		Duplicates: model.DuplicateRename,
+		Empty:      model.EmptyGenerate,
+	})
+	if err != nil {
+		return nil, FormatParseError(string(format), err)
+	}
+	return td, nil
+}
+
+// writeOutputTable writes a table to the output file, or to stdout when
+// the path is empty or "-"
+func writeOutputTable(td *model.TableData, path string, stdout io.Writer, opts ConvertOptions) error {
+	opts.CSVOutput.Delimiter = tsvDelimiter(path)
+	if path == "" || path == "-" {
+		return writeTable(td, stdout, opts)
+	}
+
+	writer, err := CreateOutputWriter(path)
+	if err != nil {
+		return FormatFileWriteError(path, err)
+	}
+	if err := writeTable(td, writer, opts); err != nil {
+		writer.Close()
+		return err
+	}
+	// Closing the writer finishes a compressed stream
+	if err := writer.Close(); err != nil {
+		return FormatFileWriteError(path, err)
+	}
+	return nil
+}
+
+// outputTableFormat returns the output format given with -out, or the one
+// of the output file's extension
+func outputTableFormat(outFormat, path string) (Format, error) {
+	if outFormat != "" {
+		return ParseFormat(outFormat)
+	}
+	if path == "" || path == "-" {
+		return "", errors.New("output format required when writing to stdout (use -out flag)")
+	}
+	format, err := DetectFormat(path)
+	if err != nil {
+		return "", errors.New("cannot determine output format: " + err.Error() + " (use -out flag to specify format)")
+	}
+	return format, nil
+}
+
+// tsvDelimiter returns a tab for .tsv paths and "" (the default) otherwise
+func tsvDelimiter(path string) string {
+	if isTSVPath(path) {
+		return "\t"
+	}
+	return ""
+}
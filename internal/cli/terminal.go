@@ -0,0 +1,16 @@
+package cli
+
+import (
+	"os"
+	"strconv"
+)
+
+// TerminalWidth returns the width of the terminal attached to stdout.
+// The COLUMNS environment variable takes precedence; 0 means the width
+// could not be determined
+func TerminalWidth() int {
+	if cols, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && cols > 0 {
+		return cols
+	}
+	return stdoutWidth()
+}
@@ -0,0 +1,8 @@
+//go:build !(linux || darwin || freebsd || netbsd || openbsd)
+
+package cli
+
+// stdoutWidth is not supported on this platform
+func stdoutWidth() int {
+	return 0
+}
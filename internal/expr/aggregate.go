@@ -0,0 +1,145 @@
+package expr
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Aggregator accumulates the values of a column into a summary such as a
+// sum. Null values are skipped
+type Aggregator interface {
+	// Add adds a value to the summary
+	Add(v Value) error
+	// Result returns the summary of the values added so far
+	Result() Value
+}
+
+// aggregates creates the aggregators, keyed by lower-case name
+var aggregates = map[string]func() Aggregator{
+	"count":          func() Aggregator { return &countAggregator{} },
+	"count_distinct": func() Aggregator { return &countAggregator{seen: make(map[string]bool)} },
+	"sum":            func() Aggregator { return &sumAggregator{} },
+	"avg":            func() Aggregator { return &sumAggregator{average: true} },
+	"min":            func() Aggregator { return &extremeAggregator{want: -1} },
+	"max":            func() Aggregator { return &extremeAggregator{want: 1} },
+}
+
+// IsAggregate reports whether name is an aggregate function: count,
+// count_distinct, sum, avg, min or max
+func IsAggregate(name string) bool {
+	_, ok := aggregates[strings.ToLower(name)]
+	return ok
+}
+
+// NewAggregator creates the aggregator for an aggregate function
+func NewAggregator(name string) (Aggregator, error) {
+	create, ok := aggregates[strings.ToLower(name)]
+	if !ok {
+		return nil, fmt.Errorf("unknown aggregate function %s", name)
+	}
+	return create(), nil
+}
+
+// DistinctKey returns a key that is equal for values that compare equal,
+// so that 1, 1.0 and "1" are one value
+func DistinctKey(v Value) string {
+	switch v.Kind {
+	case KindNull:
+		return "null"
+	case KindBool:
+		return "b:" + strconv.FormatBool(v.Bool)
+	case KindDate:
+		return "d:" + v.Time.UTC().Format("2006-01-02T15:04:05.999999999")
+	}
+	if n, err := v.AsNumber(); err == nil {
+		return "n:" + strconv.FormatFloat(n, 'g', -1, 64)
+	}
+	return "s:" + v.String()
+}
+
+// countAggregator counts non-null values, or distinct non-null values
+// when seen is set
+type countAggregator struct {
+	n    int
+	seen map[string]bool
+}
+
+func (a *countAggregator) Add(v Value) error {
+	if v.Kind == KindNull {
+		return nil
+	}
+	if a.seen != nil {
+		key := DistinctKey(v)
+		if a.seen[key] {
+			return nil
+		}
+		a.seen[key] = true
+	}
+	a.n++
+	return nil
+}
+
+func (a *countAggregator) Result() Value {
+	return Number(float64(a.n))
+}
+
+// sumAggregator sums numbers, or averages them. A value that is not a
+// number is an error
+type sumAggregator struct {
+	sum     float64
+	n       int
+	average bool
+}
+
+func (a *sumAggregator) Add(v Value) error {
+	if v.Kind == KindNull {
+		return nil
+	}
+	n, err := v.AsNumber()
+	if err != nil {
+		return err
+	}
+	a.sum += n
+	a.n++
+	return nil
+}
+
+func (a *sumAggregator) Result() Value {
+	switch {
+	case a.n == 0:
+		return Null()
+	case a.average:
+		return Number(a.sum / float64(a.n))
+	}
+	return Number(a.sum)
+}
+
+// extremeAggregator keeps the least (want -1) or greatest (want 1) value,
+// compared as Compare does
+type extremeAggregator struct {
+	best Value
+	want int
+}
+
+func (a *extremeAggregator) Add(v Value) error {
+	if v.Kind == KindNull {
+		return nil
+	}
+	if a.best.Kind == KindNull {
+		a.best = v
+		return nil
+	}
+	c, err := Compare(v, a.best)
+	if err != nil {
+		return err
+	}
+	if c == a.want {
+		a.best = v
+	}
+	return nil
+}
+
+func (a *extremeAggregator) Result() Value {
+	return a.best
+}
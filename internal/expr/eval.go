@@ -0,0 +1,371 @@
+package expr
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strings"
+
+	"github.com/user/table-converter/internal/model"
+)
+
+// Bound is an expression whose columns have been resolved against the
+// headers of a table
+type Bound struct {
+	expr  *Expr
+	index map[string]int
+}
+
+// Bind resolves the columns of the expression against table headers. A
+// column that is not in the headers is an error naming it
+func (e *Expr) Bind(headers []string) (*Bound, error) {
+	index := make(map[string]int, len(headers))
+	for i := len(headers) - 1; i >= 0; i-- {
+		index[headers[i]] = i
+	}
+	for _, column := range e.columns {
+		if _, ok := index[column]; !ok {
+			return nil, fmt.Errorf("unknown column %q (columns: %s)", column, strings.Join(headers, ", "))
+		}
+	}
+	return &Bound{expr: e, index: index}, nil
+}
+
+// Eval evaluates the expression against a row
+func (b *Bound) Eval(row []model.Value) (Value, error) {
+	return b.expr.root.eval(&env{row: row, index: b.index})
+}
+
+// Test evaluates the expression as a condition. Null counts as false, and
+// a result that is not true or false is an error
+func (b *Bound) Test(row []model.Value) (bool, error) {
+	v, err := b.Eval(row)
+	if err != nil {
+		return false, err
+	}
+	ok, err := v.AsBool()
+	if err != nil {
+		return false, fmt.Errorf("condition must be true or false: %w", err)
+	}
+	return ok, nil
+}
+
+// env is the row an expression is evaluated against
+type env struct {
+	row   []model.Value
+	index map[string]int
+}
+
+// node is a node of the expression tree
+type node interface {
+	eval(e *env) (Value, error)
+}
+
+// literalNode is a constant
+type literalNode struct {
+	value Value
+}
+
+func (n *literalNode) eval(*env) (Value, error) {
+	return n.value, nil
+}
+
+// columnNode reads a column of the row
+type columnNode struct {
+	name string
+}
+
+func (n *columnNode) eval(e *env) (Value, error) {
+	i, ok := e.index[n.name]
+	if !ok || i >= len(e.row) {
+		return Value{Kind: KindNull, Column: n.name}, nil
+	}
+	return FromModel(e.row[i], n.name), nil
+}
+
+// logicalNode is a && b or a || b, evaluated left to right with short
+// circuiting
+type logicalNode struct {
+	and         bool
+	left, right node
+}
+
+func (n *logicalNode) eval(e *env) (Value, error) {
+	left, err := evalBool(n.left, e)
+	if err != nil || left != n.and {
+		return Bool(left), err
+	}
+	right, err := evalBool(n.right, e)
+	return Bool(right), err
+}
+
+// notNode is !a
+type notNode struct {
+	operand node
+}
+
+func (n *notNode) eval(e *env) (Value, error) {
+	v, err := evalBool(n.operand, e)
+	return Bool(!v), err
+}
+
+// evalBool evaluates a node as a condition
+func evalBool(n node, e *env) (bool, error) {
+	v, err := n.eval(e)
+	if err != nil {
+		return false, err
+	}
+	return v.AsBool()
+}
+
+// compareNode compares two values with ==, !=, <, <=, > or >=
+type compareNode struct {
+	op          string
+	left, right node
+}
+
+func (n *compareNode) eval(e *env) (Value, error) {
+	left, err := n.left.eval(e)
+	if err != nil {
+		return Value{}, err
+	}
+	right, err := n.right.eval(e)
+	if err != nil {
+		return Value{}, err
+	}
+
+	return Comparison(n.op, left, right)
+}
+
+// Comparison applies a comparison operator (==, !=, <, <=, > or >=) to two
+// values. Null equals only null, and is neither less nor greater than
+// anything
+func Comparison(op string, left, right Value) (Value, error) {
+	if left.Kind == KindNull || right.Kind == KindNull {
+		both := left.Kind == right.Kind
+		switch op {
+		case "==":
+			return Bool(both), nil
+		case "!=":
+			return Bool(!both), nil
+		}
+		return Bool(false), nil
+	}
+
+	c, err := Compare(left, right)
+	if err != nil {
+		return Value{}, err
+	}
+
+	switch op {
+	case "==":
+		return Bool(c == 0), nil
+	case "!=":
+		return Bool(c != 0), nil
+	case "<":
+		return Bool(c < 0), nil
+	case "<=":
+		return Bool(c <= 0), nil
+	case ">":
+		return Bool(c > 0), nil
+	case ">=":
+		return Bool(c >= 0), nil
+	}
+	return Value{}, fmt.Errorf("unknown comparison %s", op)
+}
+
+// Compare orders two non-null values, returning -1, 0 or 1. A number or
+// date on either side makes the other side a number or date, and a string
+// that cannot be converted is an error. Two strings are compared as
+// numbers if both hold numbers, as dates if both hold dates, and as text
+// otherwise. False is less than true
+func Compare(a, b Value) (int, error) {
+	switch {
+	case a.Kind == KindDate || b.Kind == KindDate:
+		x, err := a.AsDate()
+		if err != nil {
+			return 0, fmt.Errorf("cannot compare with a date: %w", err)
+		}
+		y, err := b.AsDate()
+		if err != nil {
+			return 0, fmt.Errorf("cannot compare with a date: %w", err)
+		}
+		return x.Compare(y), nil
+
+	case a.Kind == KindNumber || b.Kind == KindNumber:
+		x, err := a.AsNumber()
+		if err != nil {
+			return 0, fmt.Errorf("cannot compare with a number: %w", err)
+		}
+		y, err := b.AsNumber()
+		if err != nil {
+			return 0, fmt.Errorf("cannot compare with a number: %w", err)
+		}
+		return compareNumbers(x, y), nil
+
+	case a.Kind == KindBool || b.Kind == KindBool:
+		x, err := a.AsBool()
+		if err != nil {
+			return 0, fmt.Errorf("cannot compare with a boolean: %w", err)
+		}
+		y, err := b.AsBool()
+		if err != nil {
+			return 0, fmt.Errorf("cannot compare with a boolean: %w", err)
+		}
+		switch {
+		case x == y:
+			return 0, nil
+		case y:
+			return -1, nil
+		}
+		return 1, nil
+	}
+
+	if x, err := a.AsNumber(); err == nil {
+		if y, err := b.AsNumber(); err == nil {
+			return compareNumbers(x, y), nil
+		}
+	}
+	if x, ok := ParseDate(a.Str); ok {
+		if y, ok := ParseDate(b.Str); ok {
+			return x.Compare(y), nil
+		}
+	}
+	return strings.Compare(a.Str, b.Str), nil
+}
+
+// compareNumbers orders two numbers
+func compareNumbers(x, y float64) int {
+	switch {
+	case x < y:
+		return -1
+	case x > y:
+		return 1
+	}
+	return 0
+}
+
+// matchNode is a =~ regexp or a !~ regexp
+type matchNode struct {
+	left, right node
+	re          *regexp.Regexp // compiled when the regexp is a literal
+	negate      bool
+}
+
+func (n *matchNode) eval(e *env) (Value, error) {
+	left, err := n.left.eval(e)
+	if err != nil {
+		return Value{}, err
+	}
+	re := n.re
+	if re == nil {
+		pattern, err := n.right.eval(e)
+		if err != nil {
+			return Value{}, err
+		}
+		if re, err = compileRegexp(pattern.String()); err != nil {
+			return Value{}, err
+		}
+	}
+	if left.Kind == KindNull {
+		return Bool(n.negate), nil
+	}
+	return Bool(re.MatchString(left.String()) != n.negate), nil
+}
+
+// arithNode is a + b, a - b, a * b, a / b or a % b. Null operands give null
+type arithNode struct {
+	op          string
+	left, right node
+}
+
+func (n *arithNode) eval(e *env) (Value, error) {
+	left, err := n.left.eval(e)
+	if err != nil {
+		return Value{}, err
+	}
+	right, err := n.right.eval(e)
+	if err != nil {
+		return Value{}, err
+	}
+	return Arithmetic(n.op, left, right)
+}
+
+// Arithmetic applies an arithmetic operator (+, -, *, / or %) to two
+// values, converting strings that hold numbers. Null operands give null
+func Arithmetic(op string, left, right Value) (Value, error) {
+	if left.Kind == KindNull || right.Kind == KindNull {
+		return Null(), nil
+	}
+
+	x, err := left.AsNumber()
+	if err != nil {
+		return Value{}, fmt.Errorf("cannot use %s: %w", op, err)
+	}
+	y, err := right.AsNumber()
+	if err != nil {
+		return Value{}, fmt.Errorf("cannot use %s: %w", op, err)
+	}
+
+	switch op {
+	case "+":
+		return Number(x + y), nil
+	case "-":
+		return Number(x - y), nil
+	case "*":
+		return Number(x * y), nil
+	case "/", "%":
+		if y == 0 {
+			return Value{}, fmt.Errorf("division by zero")
+		}
+		if op == "%" {
+			return Number(math.Mod(x, y)), nil
+		}
+		return Number(x / y), nil
+	}
+	return Value{}, fmt.Errorf("unknown operator %s", op)
+}
+
+// ifNode chooses between two values by a condition, evaluating only the
+// chosen one, so that if(qty == 0, 0, total / qty) never divides by zero
+type ifNode struct {
+	cond, then, els node // els is nil for null
+}
+
+func (n *ifNode) eval(e *env) (Value, error) {
+	ok, err := evalBool(n.cond, e)
+	if err != nil {
+		return Value{}, err
+	}
+	switch {
+	case ok:
+		return n.then.eval(e)
+	case n.els != nil:
+		return n.els.eval(e)
+	}
+	return Null(), nil
+}
+
+// callNode calls a function
+type callNode struct {
+	name string
+	fn   *function
+	args []node
+	re   *regexp.Regexp // compiled regexp argument, when it is a literal
+}
+
+func (n *callNode) eval(e *env) (Value, error) {
+	args := make([]Value, len(n.args))
+	for i, arg := range n.args {
+		v, err := arg.eval(e)
+		if err != nil {
+			return Value{}, err
+		}
+		args[i] = v
+	}
+	v, err := n.fn.call(callArgs{values: args, re: n.re})
+	if err != nil {
+		return Value{}, fmt.Errorf("%s: %w", n.name, err)
+	}
+	return v, nil
+}
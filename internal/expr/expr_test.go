@@ -0,0 +1,226 @@
+package expr
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/user/table-converter/internal/model"
+)
+
+var testHeaders = []string{"status", "amount", "name", "joined", "note", "Order Date", "active"}
+
+// testRow is a row as the CSV parser produces it, with string cells
+var testRow = []model.Value{
+	model.NewStringValue("active"),
+	model.NewStringValue("150"),
+	model.NewStringValue("Alice Smith"),
+	model.NewStringValue("2024-03-01"),
+	model.NewNullValue(),
+	model.NewStringValue("2024-01-15T10:00:00Z"),
+	model.NewStringValue("yes"),
+}
+
+func TestTest(t *testing.T) {
+	tests := []struct {
+		src  string
+		want bool
+	}{
+		{`status == "active" && amount > 100`, true},
+		{`status = 'active' and amount > 200`, false},
+		{`amount > 100 || status == "gone"`, true},
+		{`amount >= 150 && amount <= 150`, true},
+		{`amount == 150.0`, true},
+		{`amount != "150"`, false},
+		{`!(amount < 100)`, true},
+		{`not status == "active"`, false},
+		{`amount * 2 - 100 == 200`, true},
+		{`amount % 7 == 3`, true},
+		{`-amount < 0`, true},
+		{`contains(name, "Smith")`, true},
+		{`startsWith(lower(name), "alice")`, true},
+		{`endsWith(name, "Jones")`, false},
+		{`matches(name, "^A\w+ S")`, true},
+		{`name =~ "(?i)smith$"`, true},
+		{`name !~ "Bob"`, true},
+		{`note == null`, true},
+		{`isNull(note) && !isNull(name)`, true},
+		{`note != null`, false},
+		{`note > 5`, false},
+		{`contains(note, "x")`, false},
+		{`coalesce(note, name) == "Alice Smith"`, true},
+		{`joined > "2024-02-28"`, true},
+		{`joined < date("2024-03-01T12:00:00Z")`, true},
+		{"`Order Date` < joined", true},
+		{`len(name) == 11`, true},
+		{`active`, true},
+		{`active == true`, true},
+		{`upper(trim(" x ")) == "X"`, true},
+		{`"10" > "9"`, true},
+		{`"b" > "a"`, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.src, func(t *testing.T) {
+			e, err := Parse(tt.src)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			b, err := e.Bind(testHeaders)
+			if err != nil {
+				t.Fatalf("Bind() error = %v", err)
+			}
+			got, err := b.Test(testRow)
+			if err != nil {
+				t.Fatalf("Test() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Test() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		src  string
+		want string
+	}{
+		{`amount >`, "position 9: unexpected end of expression"},
+		{`amount > 1 1`, "position 12: unexpected 1"},
+		{`(amount > 1`, "expected )"},
+		{`status == "active`, "position 11: unterminated string"},
+		{"`Order Date", "unterminated column name"},
+		{`amount # 1`, `unexpected character '#'`},
+		{`sum(amount)`, "unknown function sum"},
+		{`contains(name)`, "contains takes 2 arguments"},
+		{`name =~ "["`, "invalid regexp"},
+		{`matches(name, "(")`, "invalid regexp"},
+	}
+	for _, tt := range tests {
+		_, err := Parse(tt.src)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("Parse(%q) error = %v, want %q", tt.src, err, tt.want)
+		}
+	}
+}
+
+func TestEvalErrors(t *testing.T) {
+	tests := []struct {
+		src  string
+		want string
+	}{
+		{`name > 100`, `column "name" ("Alice Smith") is not a number`},
+		{`name > date("2024-01-01")`, `column "name" ("Alice Smith") is not a date`},
+		{`amount + name`, `cannot use +: column "name"`},
+		{`amount / 0 > 1`, "division by zero"},
+		{`name`, `condition must be true or false: column "name"`},
+		{`number(status) > 1`, `number: column "status" ("active") is not a number`},
+		{`parseDate(name, "%Y-%m-%d") > joined`, `parseDate: column "name" ("Alice Smith") does not match the format "%Y-%m-%d"`},
+		{`formatDate(joined, "%Q") == ""`, `unknown directive %Q`},
+	}
+	for _, tt := range tests {
+		e, err := Parse(tt.src)
+		if err != nil {
+			t.Fatalf("Parse(%q) error = %v", tt.src, err)
+		}
+		b, err := e.Bind(testHeaders)
+		if err != nil {
+			t.Fatalf("Bind(%q) error = %v", tt.src, err)
+		}
+		if _, err := b.Test(testRow); err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("Test(%q) error = %v, want %q", tt.src, err, tt.want)
+		}
+	}
+}
+
+func TestBindUnknownColumn(t *testing.T) {
+	e, err := Parse(`amout > 100`)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if cols := e.Columns(); len(cols) != 1 || cols[0] != "amout" {
+		t.Errorf("Columns() = %q", cols)
+	}
+	if _, err := e.Bind(testHeaders); err == nil || !strings.Contains(err.Error(), `unknown column "amout"`) {
+		t.Errorf("Bind() error = %v", err)
+	}
+}
+
+func TestEvalValues(t *testing.T) {
+	tests := []struct {
+		src  string
+		want model.Value
+	}{
+		{`amount * 2`, model.NewNumberValue(300)},
+		{`upper(status)`, model.NewStringValue("ACTIVE")},
+		{`note + 1`, model.NewNullValue()},
+		{`amount > 1`, model.NewBooleanValue(true)},
+		{`date(joined)`, model.NewStringValue("2024-03-01")},
+		{`regexReplace(name, "(\\w+) (\\w+)", "$2, $1")`, model.NewStringValue("Smith, Alice")},
+		{`floor(amount / 7)`, model.NewNumberValue(21)},
+		{`ceil(amount / 7)`, model.NewNumberValue(22)},
+		{`if(amount > 100, "big", "small")`, model.NewStringValue("big")},
+		{`if(note == null, 0, amount / 0)`, model.NewNumberValue(0)},
+		{`if(note, 1)`, model.NewNullValue()},
+		{`formatDate(joined, "%d.%m.%Y")`, model.NewStringValue("01.03.2024")},
+		{`formatDate(parseDate("3/7/24", "%m/%d/%y"), "%a %e %b %Y")`, model.NewStringValue("Thu  7 Mar 2024")},
+		{`parseDate(note, "%Y")`, model.NewNullValue()},
+		{`md5(status)`, model.NewStringValue("c76a5e84e4bdee527e274ea30c680d79")},
+		{`sha256(lower(trim("  A ")))`, model.NewStringValue("ca978112ca1bbdcafac231b39a23dc4da786eff8147c4e72b9807785afee48bb")},
+		{`len(ltrim("  a "))`, model.NewNumberValue(2)},
+		{`len(rtrim("  a "))`, model.NewNumberValue(3)},
+	}
+	for _, tt := range tests {
+		e, err := Parse(tt.src)
+		if err != nil {
+			t.Fatalf("Parse(%q) error = %v", tt.src, err)
+		}
+		b, _ := e.Bind(testHeaders)
+		v, err := b.Eval(testRow)
+		if err != nil {
+			t.Fatalf("Eval(%q) error = %v", tt.src, err)
+		}
+		if got := v.ToModel(); got != tt.want {
+			t.Errorf("Eval(%q) = %+v, want %+v", tt.src, got, tt.want)
+		}
+	}
+}
+
+func TestAggregators(t *testing.T) {
+	values := []Value{String("10"), Number(2.5), Null(), String("10.0"), Number(-1)}
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"count", "4"},
+		{"count_distinct", "3"},
+		{"sum", "21.5"},
+		{"avg", "5.375"},
+		{"min", "-1"},
+		{"max", "10"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			agg, err := NewAggregator(tt.name)
+			if err != nil {
+				t.Fatalf("NewAggregator() error: %v", err)
+			}
+			for _, v := range values {
+				if err := agg.Add(v); err != nil {
+					t.Fatalf("Add(%v) error: %v", v, err)
+				}
+			}
+			if got := agg.Result().String(); got != tt.want {
+				t.Errorf("Result() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	sum, _ := NewAggregator("sum")
+	if got := sum.Result(); got.Kind != KindNull {
+		t.Errorf("sum of no values = %v, want null", got)
+	}
+	if err := sum.Add(String("n/a")); err == nil {
+		t.Error("sum.Add(\"n/a\") succeeded, want error")
+	}
+}
@@ -0,0 +1,454 @@
+package expr
+
+import (
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"math"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// function is a built-in function
+type function struct {
+	minArgs, maxArgs int // maxArgs < 0 = any number
+	// regexpArg is the 1-based argument that is a regexp (0 = none)
+	regexpArg int
+	call      func(args callArgs) (Value, error)
+}
+
+// callArgs holds the evaluated arguments of a function call
+type callArgs struct {
+	values []Value
+	re     *regexp.Regexp // the regexp argument, if compiled in advance
+}
+
+// arity describes the number of arguments the function takes
+func (f *function) arity() string {
+	switch {
+	case f.maxArgs < 0:
+		return fmt.Sprintf("at least %d arguments", f.minArgs)
+	case f.minArgs == f.maxArgs && f.minArgs == 1:
+		return "1 argument"
+	case f.minArgs == f.maxArgs:
+		return fmt.Sprintf("%d arguments", f.minArgs)
+	}
+	return fmt.Sprintf("%d to %d arguments", f.minArgs, f.maxArgs)
+}
+
+// functions are the built-in functions, keyed by lower-case name
+var functions = map[string]*function{
+	"contains":       {minArgs: 2, maxArgs: 2, call: stringTest(strings.Contains)},
+	"startswith":     {minArgs: 2, maxArgs: 2, call: stringTest(strings.HasPrefix)},
+	"endswith":       {minArgs: 2, maxArgs: 2, call: stringTest(strings.HasSuffix)},
+	"matches":        {minArgs: 2, maxArgs: 2, regexpArg: 2, call: matches},
+	"lower":          {minArgs: 1, maxArgs: 1, call: stringMap(strings.ToLower)},
+	"upper":          {minArgs: 1, maxArgs: 1, call: stringMap(strings.ToUpper)},
+	"trim":           {minArgs: 1, maxArgs: 1, call: stringMap(strings.TrimSpace)},
+	"ltrim":          {minArgs: 1, maxArgs: 1, call: stringMap(func(s string) string { return strings.TrimLeft(s, " \t\r\n") })},
+	"rtrim":          {minArgs: 1, maxArgs: 1, call: stringMap(func(s string) string { return strings.TrimRight(s, " \t\r\n") })},
+	"len":            {minArgs: 1, maxArgs: 1, call: length},
+	"length":         {minArgs: 1, maxArgs: 1, call: length},
+	"substr":         {minArgs: 2, maxArgs: 3, call: substr},
+	"replace":        {minArgs: 3, maxArgs: 3, call: replace},
+	"regexreplace":   {minArgs: 3, maxArgs: 3, regexpArg: 2, call: regexReplace},
+	"regexp_replace": {minArgs: 3, maxArgs: 3, regexpArg: 2, call: regexReplace},
+	"concat":         {minArgs: 1, maxArgs: -1, call: concat},
+	"abs":            {minArgs: 1, maxArgs: 1, call: abs},
+	"round":          {minArgs: 1, maxArgs: 2, call: round},
+	"floor":          {minArgs: 1, maxArgs: 1, call: numberMap(math.Floor)},
+	"ceil":           {minArgs: 1, maxArgs: 1, call: numberMap(math.Ceil)},
+	"isnull":         {minArgs: 1, maxArgs: 1, call: isNull},
+	"coalesce":       {minArgs: 1, maxArgs: -1, call: coalesce},
+	"ifnull":         {minArgs: 2, maxArgs: 2, call: coalesce},
+	"nullif":         {minArgs: 2, maxArgs: 2, call: nullIf},
+	"if":             {minArgs: 2, maxArgs: 3, call: ifElse},
+	"number":         {minArgs: 1, maxArgs: 1, call: toNumber},
+	"date":           {minArgs: 1, maxArgs: 1, call: toDate},
+	"string":         {minArgs: 1, maxArgs: 1, call: toString},
+	"parsedate":      {minArgs: 2, maxArgs: 2, call: parseDate},
+	"formatdate":     {minArgs: 2, maxArgs: 2, call: formatDate},
+	"md5":            {minArgs: 1, maxArgs: 1, call: hashHex(md5.New)},
+	"sha1":           {minArgs: 1, maxArgs: 1, call: hashHex(sha1.New)},
+	"sha256":         {minArgs: 1, maxArgs: 1, call: hashHex(sha256.New)},
+}
+
+// CheckFunction returns an error if there is no built-in function with the
+// name, or if it does not take n arguments
+func CheckFunction(name string, n int) error {
+	fn, ok := functions[strings.ToLower(name)]
+	if !ok {
+		return fmt.Errorf("unknown function %s", name)
+	}
+	if n < fn.minArgs || (fn.maxArgs >= 0 && n > fn.maxArgs) {
+		return fmt.Errorf("%s takes %s", name, fn.arity())
+	}
+	return nil
+}
+
+// CallFunction calls a built-in function by name
+func CallFunction(name string, args []Value) (Value, error) {
+	if err := CheckFunction(name, len(args)); err != nil {
+		return Value{}, err
+	}
+	v, err := functions[strings.ToLower(name)].call(callArgs{values: args})
+	if err != nil {
+		return Value{}, fmt.Errorf("%s: %w", name, err)
+	}
+	return v, nil
+}
+
+// regexpCache holds the regexps compiled while evaluating, keyed by pattern
+var regexpCache sync.Map
+
+// compileRegexp compiles a regexp computed while evaluating, reusing
+// earlier compilations of the same pattern
+func compileRegexp(pattern string) (*regexp.Regexp, error) {
+	if re, ok := regexpCache.Load(pattern); ok {
+		return re.(*regexp.Regexp), nil
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid regexp %q: %v", pattern, err)
+	}
+	regexpCache.Store(pattern, re)
+	return re, nil
+}
+
+// stringTest makes a function testing a string against another, such as
+// contains. Null is never matched
+func stringTest(test func(s, sub string) bool) func(callArgs) (Value, error) {
+	return func(args callArgs) (Value, error) {
+		s, sub := args.values[0], args.values[1]
+		if s.Kind == KindNull || sub.Kind == KindNull {
+			return Bool(false), nil
+		}
+		return Bool(test(s.String(), sub.String())), nil
+	}
+}
+
+// stringMap makes a function converting a string, such as lower. Null
+// stays null
+func stringMap(convert func(string) string) func(callArgs) (Value, error) {
+	return func(args callArgs) (Value, error) {
+		if args.values[0].Kind == KindNull {
+			return Null(), nil
+		}
+		return String(convert(args.values[0].String())), nil
+	}
+}
+
+// matches reports whether a string matches a regexp
+func matches(args callArgs) (Value, error) {
+	re := args.re
+	if re == nil {
+		var err error
+		if re, err = compileRegexp(args.values[1].String()); err != nil {
+			return Value{}, err
+		}
+	}
+	if args.values[0].Kind == KindNull {
+		return Bool(false), nil
+	}
+	return Bool(re.MatchString(args.values[0].String())), nil
+}
+
+// length returns the number of characters in a string
+func length(args callArgs) (Value, error) {
+	if args.values[0].Kind == KindNull {
+		return Null(), nil
+	}
+	return Number(float64(utf8.RuneCountInString(args.values[0].String()))), nil
+}
+
+// substr returns the characters of a string from a 1-based position,
+// optionally limited to a number of characters
+func substr(args callArgs) (Value, error) {
+	s := args.values[0]
+	if s.Kind == KindNull {
+		return Null(), nil
+	}
+	runes := []rune(s.String())
+	start, err := args.values[1].AsNumber()
+	if err != nil {
+		return Value{}, err
+	}
+	from := int(start) - 1
+	if from < 0 {
+		from = 0
+	}
+	if from > len(runes) {
+		from = len(runes)
+	}
+	to := len(runes)
+	if len(args.values) > 2 {
+		n, err := args.values[2].AsNumber()
+		if err != nil {
+			return Value{}, err
+		}
+		if n < 0 {
+			n = 0
+		}
+		to = min(from+int(n), len(runes))
+	}
+	return String(string(runes[from:to])), nil
+}
+
+// replace replaces every occurrence of a substring
+func replace(args callArgs) (Value, error) {
+	s := args.values[0]
+	if s.Kind == KindNull {
+		return Null(), nil
+	}
+	return String(strings.ReplaceAll(s.String(), args.values[1].String(), args.values[2].String())), nil
+}
+
+// regexReplace replaces every match of a regexp. The replacement may refer
+// to submatches as $1 or ${name}
+func regexReplace(args callArgs) (Value, error) {
+	re := args.re
+	if re == nil {
+		var err error
+		if re, err = compileRegexp(args.values[1].String()); err != nil {
+			return Value{}, err
+		}
+	}
+	s := args.values[0]
+	if s.Kind == KindNull {
+		return Null(), nil
+	}
+	return String(re.ReplaceAllString(s.String(), args.values[2].String())), nil
+}
+
+// concat joins its arguments as text, skipping nulls
+func concat(args callArgs) (Value, error) {
+	var b strings.Builder
+	for _, v := range args.values {
+		if v.Kind != KindNull {
+			b.WriteString(v.String())
+		}
+	}
+	return String(b.String()), nil
+}
+
+// abs returns the absolute value of a number
+func abs(args callArgs) (Value, error) {
+	v := args.values[0]
+	if v.Kind == KindNull {
+		return Null(), nil
+	}
+	n, err := v.AsNumber()
+	if err != nil {
+		return Value{}, err
+	}
+	return Number(math.Abs(n)), nil
+}
+
+// numberMap makes a function converting a number, such as floor. Null
+// stays null
+func numberMap(convert func(float64) float64) func(callArgs) (Value, error) {
+	return func(args callArgs) (Value, error) {
+		v := args.values[0]
+		if v.Kind == KindNull {
+			return Null(), nil
+		}
+		n, err := v.AsNumber()
+		if err != nil {
+			return Value{}, err
+		}
+		return Number(convert(n)), nil
+	}
+}
+
+// round rounds a number half away from zero, to a number of decimal
+// places (default 0)
+func round(args callArgs) (Value, error) {
+	v := args.values[0]
+	if v.Kind == KindNull {
+		return Null(), nil
+	}
+	n, err := v.AsNumber()
+	if err != nil {
+		return Value{}, err
+	}
+	places := 0.0
+	if len(args.values) > 1 {
+		if places, err = args.values[1].AsNumber(); err != nil {
+			return Value{}, err
+		}
+	}
+	scale := math.Pow(10, math.Trunc(places))
+	return Number(math.Round(n*scale) / scale), nil
+}
+
+// nullIf returns null if its arguments are equal, and the first otherwise
+func nullIf(args callArgs) (Value, error) {
+	eq, err := Comparison("==", args.values[0], args.values[1])
+	if err != nil {
+		return Value{}, err
+	}
+	if eq.Bool {
+		return Null(), nil
+	}
+	return args.values[0], nil
+}
+
+// ifElse returns its second argument if the first is true, and its third
+// (default null) otherwise. Null counts as false. Expressions evaluate only
+// the chosen argument (see ifNode); this is the form other callers use
+func ifElse(args callArgs) (Value, error) {
+	ok, err := args.values[0].AsBool()
+	if err != nil {
+		return Value{}, err
+	}
+	if ok {
+		return args.values[1], nil
+	}
+	if len(args.values) > 2 {
+		return args.values[2], nil
+	}
+	return Null(), nil
+}
+
+// isNull reports whether a value is null
+func isNull(args callArgs) (Value, error) {
+	return Bool(args.values[0].Kind == KindNull), nil
+}
+
+// coalesce returns the first argument that is not null
+func coalesce(args callArgs) (Value, error) {
+	for _, v := range args.values {
+		if v.Kind != KindNull {
+			return v, nil
+		}
+	}
+	return Null(), nil
+}
+
+// toNumber converts a value to a number
+func toNumber(args callArgs) (Value, error) {
+	v := args.values[0]
+	if v.Kind == KindNull {
+		return Null(), nil
+	}
+	n, err := v.AsNumber()
+	if err != nil {
+		return Value{}, err
+	}
+	return Number(n), nil
+}
+
+// toDate converts a value to a date
+func toDate(args callArgs) (Value, error) {
+	v := args.values[0]
+	if v.Kind == KindNull {
+		return Null(), nil
+	}
+	t, err := v.AsDate()
+	if err != nil {
+		return Value{}, err
+	}
+	return Date(t), nil
+}
+
+// toString converts a value to a string
+func toString(args callArgs) (Value, error) {
+	v := args.values[0]
+	if v.Kind == KindNull {
+		return Null(), nil
+	}
+	return String(v.String()), nil
+}
+
+// parseDate parses a date written in a strftime format such as %d/%m/%Y
+func parseDate(args callArgs) (Value, error) {
+	v := args.values[0]
+	layout, err := dateLayout(args.values[1].String(), true)
+	if err != nil {
+		return Value{}, err
+	}
+	if v.Kind == KindNull {
+		return Null(), nil
+	}
+	t, err := time.Parse(layout, strings.TrimSpace(v.String()))
+	if err != nil {
+		return Value{}, fmt.Errorf("%s does not match the format %q", v.describe(), args.values[1].String())
+	}
+	return Date(t), nil
+}
+
+// formatDate writes a date in a strftime format such as %d.%m.%Y
+func formatDate(args callArgs) (Value, error) {
+	v := args.values[0]
+	layout, err := dateLayout(args.values[1].String(), false)
+	if err != nil {
+		return Value{}, err
+	}
+	if v.Kind == KindNull {
+		return Null(), nil
+	}
+	t, err := v.AsDate()
+	if err != nil {
+		return Value{}, err
+	}
+	return String(t.Format(layout)), nil
+}
+
+// dateDirectives maps strftime directives to the layout elements of the
+// time package
+var dateDirectives = map[byte]string{
+	'Y': "2006", 'y': "06", 'm': "01", 'd': "02", 'e': "_2", 'j': "002",
+	'H': "15", 'I': "03", 'M': "04", 'S': "05", 'p': "PM",
+	'b': "Jan", 'B': "January", 'a': "Mon", 'A': "Monday",
+	'z': "-0700", 'Z': "MST", 'F': "2006-01-02", 'T': "15:04:05", '%': "%",
+}
+
+// parseDirectives replaces the zero-padded elements of dateDirectives when
+// parsing, so that %m/%d/%Y also reads 3/7/2024
+var parseDirectives = map[byte]string{
+	'm': "1", 'd': "2", 'e': "_2", 'I': "3", 'M': "4", 'S': "5",
+}
+
+// dateLayout converts a strftime format to a time package layout
+func dateLayout(format string, parsing bool) (string, error) {
+	var b strings.Builder
+	for i := 0; i < len(format); i++ {
+		if format[i] != '%' {
+			b.WriteByte(format[i])
+			continue
+		}
+		if i+1 == len(format) {
+			return "", fmt.Errorf("date format %q ends with %%", format)
+		}
+		i++
+		elem, ok := dateDirectives[format[i]]
+		if p, found := parseDirectives[format[i]]; found && parsing {
+			elem = p
+		}
+		if !ok {
+			return "", fmt.Errorf("unknown directive %%%c in date format %q", format[i], format)
+		}
+		b.WriteString(elem)
+	}
+	return b.String(), nil
+}
+
+// hashHex makes a function returning the hexadecimal hash of a value's
+// text, such as md5. Null stays null
+func hashHex(newHash func() hash.Hash) func(callArgs) (Value, error) {
+	return func(args callArgs) (Value, error) {
+		v := args.values[0]
+		if v.Kind == KindNull {
+			return Null(), nil
+		}
+		h := newHash()
+		h.Write([]byte(v.String()))
+		return String(hex.EncodeToString(h.Sum(nil))), nil
+	}
+}
@@ -0,0 +1,162 @@
+package expr
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// tokenKind is the type of a lexical token
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokNumber
+	tokString
+	tokIdent  // column name, keyword or function name
+	tokColumn // `quoted column name`
+	tokOp     // operator or punctuation
+)
+
+// token is a lexical token and its byte offset in the source
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+// operators lists the operators, longest first so that <= wins over <
+var operators = []string{
+	"==", "!=", "<=", ">=", "=~", "!~", "&&", "||",
+	"<", ">", "=", "!", "+", "-", "*", "/", "%", "(", ")", ",",
+}
+
+// SyntaxError reports an expression that cannot be parsed
+type SyntaxError struct {
+	// Message describes the problem
+	Message string
+	// Pos is the 1-based character position of the problem
+	Pos int
+}
+
+// Error implements the error interface
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("syntax error at position %d: %s", e.Pos, e.Message)
+}
+
+// syntaxError creates a SyntaxError at a byte offset of the source
+func syntaxError(src string, offset int, format string, args ...interface{}) *SyntaxError {
+	return &SyntaxError{
+		Message: fmt.Sprintf(format, args...),
+		Pos:     utf8.RuneCountInString(src[:offset]) + 1,
+	}
+}
+
+// lex splits the source into tokens
+func lex(src string) ([]token, error) {
+	var tokens []token
+	i := 0
+	for i < len(src) {
+		r, size := utf8.DecodeRuneInString(src[i:])
+		switch {
+		case unicode.IsSpace(r):
+			i += size
+
+		case r >= '0' && r <= '9' || r == '.' && i+1 < len(src) && src[i+1] >= '0' && src[i+1] <= '9':
+			start := i
+			for i < len(src) && (isDigit(src[i]) || src[i] == '.') {
+				i++
+			}
+			if i < len(src) && (src[i] == 'e' || src[i] == 'E') {
+				j := i + 1
+				if j < len(src) && (src[j] == '+' || src[j] == '-') {
+					j++
+				}
+				if j < len(src) && isDigit(src[j]) {
+					for i = j; i < len(src) && isDigit(src[i]); i++ {
+					}
+				}
+			}
+			tokens = append(tokens, token{tokNumber, src[start:i], start})
+
+		case r == '"' || r == '\'':
+			text, end, err := lexString(src, i)
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, token{tokString, text, i})
+			i = end
+
+		case r == '`':
+			end := strings.IndexByte(src[i+1:], '`')
+			if end < 0 {
+				return nil, syntaxError(src, i, "unterminated column name")
+			}
+			tokens = append(tokens, token{tokColumn, src[i+1 : i+1+end], i})
+			i += end + 2
+
+		case r == '_' || unicode.IsLetter(r):
+			start := i
+			for i < len(src) {
+				r, size := utf8.DecodeRuneInString(src[i:])
+				if r != '_' && !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+					break
+				}
+				i += size
+			}
+			tokens = append(tokens, token{tokIdent, src[start:i], start})
+
+		default:
+			op := ""
+			for _, candidate := range operators {
+				if strings.HasPrefix(src[i:], candidate) {
+					op = candidate
+					break
+				}
+			}
+			if op == "" {
+				return nil, syntaxError(src, i, "unexpected character %q", r)
+			}
+			tokens = append(tokens, token{tokOp, op, i})
+			i += len(op)
+		}
+	}
+	return append(tokens, token{tokEOF, "", len(src)}), nil
+}
+
+// lexString reads a quoted string starting at src[start], returning its
+// text and the offset after the closing quote. A backslash escapes the
+// quote or a backslash, and \n and \t stand for a newline and a tab. Other
+// backslashes are kept, so that regexps such as "\d+" need no doubling
+func lexString(src string, start int) (string, int, error) {
+	quote := src[start]
+	var b strings.Builder
+	for i := start + 1; i < len(src); i++ {
+		switch c := src[i]; {
+		case c == quote:
+			return b.String(), i + 1, nil
+		case c == '\\' && i+1 < len(src):
+			i++
+			switch src[i] {
+			case 'n':
+				b.WriteByte('\n')
+			case 't':
+				b.WriteByte('\t')
+			case quote, '\\':
+				b.WriteByte(src[i])
+			default:
+				b.WriteByte('\\')
+				b.WriteByte(src[i])
+			}
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return "", 0, syntaxError(src, start, "unterminated string")
+}
+
+// isDigit reports whether c is an ASCII digit
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
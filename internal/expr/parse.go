@@ -0,0 +1,354 @@
+package expr
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Expr is a parsed expression over the columns of a row
+type Expr struct {
+	src     string
+	root    node
+	columns []string
+}
+
+// Parse parses an expression. Columns are referred to by name, or
+// between backticks when the name is not a plain identifier:
+//
+//	status == "active" && amount > 100
+//	`Order Date` >= "2024-01-01" || contains(lower(name), "smith")
+func Parse(src string) (*Expr, error) {
+	tokens, err := lex(src)
+	if err != nil {
+		return nil, err
+	}
+	p := &exprParser{src: src, tokens: tokens}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != tokEOF {
+		return nil, p.unexpected(tok)
+	}
+	return &Expr{src: src, root: root, columns: p.columns}, nil
+}
+
+// String returns the source of the expression
+func (e *Expr) String() string {
+	return e.src
+}
+
+// Columns returns the names of the columns the expression refers to, in
+// order of first use
+func (e *Expr) Columns() []string {
+	return e.columns
+}
+
+// exprParser is a recursive descent parser over the tokens of an expression
+type exprParser struct {
+	src     string
+	tokens  []token
+	pos     int
+	columns []string
+}
+
+// peek returns the next token without consuming it
+func (p *exprParser) peek() token {
+	return p.tokens[p.pos]
+}
+
+// next consumes and returns the next token
+func (p *exprParser) next() token {
+	tok := p.tokens[p.pos]
+	if tok.kind != tokEOF {
+		p.pos++
+	}
+	return tok
+}
+
+// acceptOp consumes the next token if it is one of the operators or
+// keywords, returning it and whether it matched
+func (p *exprParser) acceptOp(ops ...string) (string, bool) {
+	tok := p.peek()
+	for _, op := range ops {
+		if (tok.kind == tokOp && tok.text == op) || (tok.kind == tokIdent && strings.EqualFold(tok.text, op)) {
+			p.next()
+			return op, true
+		}
+	}
+	return "", false
+}
+
+// expectOp consumes the operator op or fails
+func (p *exprParser) expectOp(op string) error {
+	if _, ok := p.acceptOp(op); !ok {
+		tok := p.peek()
+		if tok.kind == tokEOF {
+			return syntaxError(p.src, tok.pos, "expected %s at end of expression", op)
+		}
+		return syntaxError(p.src, tok.pos, "expected %s, found %s", op, describeToken(tok))
+	}
+	return nil
+}
+
+// unexpected creates the error for a token that cannot appear where it is
+func (p *exprParser) unexpected(tok token) *SyntaxError {
+	if tok.kind == tokEOF {
+		return syntaxError(p.src, tok.pos, "unexpected end of expression")
+	}
+	return syntaxError(p.src, tok.pos, "unexpected %s", describeToken(tok))
+}
+
+// describeToken returns a token as written, for error messages
+func describeToken(tok token) string {
+	switch tok.kind {
+	case tokString:
+		return strconv.Quote(tok.text)
+	case tokColumn:
+		return "`" + tok.text + "`"
+	}
+	return tok.text
+}
+
+// parseOr parses a || b, also written a or b
+func (p *exprParser) parseOr() (node, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		if _, ok := p.acceptOp("||", "or"); !ok {
+			return left, nil
+		}
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &logicalNode{and: false, left: left, right: right}
+	}
+}
+
+// parseAnd parses a && b, also written a and b
+func (p *exprParser) parseAnd() (node, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		if _, ok := p.acceptOp("&&", "and"); !ok {
+			return left, nil
+		}
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		left = &logicalNode{and: true, left: left, right: right}
+	}
+}
+
+// parseNot parses !a, also written not a
+func (p *exprParser) parseNot() (node, error) {
+	if _, ok := p.acceptOp("!", "not"); ok {
+		operand, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return &notNode{operand: operand}, nil
+	}
+	return p.parseComparison()
+}
+
+// parseComparison parses a comparison or regexp match of two sums
+func (p *exprParser) parseComparison() (node, error) {
+	left, err := p.parseSum()
+	if err != nil {
+		return nil, err
+	}
+	op, ok := p.acceptOp("==", "!=", "<=", ">=", "<", ">", "=~", "!~", "=")
+	if !ok {
+		return left, nil
+	}
+	right, err := p.parseSum()
+	if err != nil {
+		return nil, err
+	}
+
+	switch op {
+	case "=~", "!~":
+		m := &matchNode{left: left, right: right, negate: op == "!~"}
+		if lit, ok := right.(*literalNode); ok && lit.value.Kind == KindString {
+			re, err := regexp.Compile(lit.value.Str)
+			if err != nil {
+				return nil, syntaxError(p.src, p.tokens[p.pos-1].pos, "invalid regexp: %v", err)
+			}
+			m.re = re
+		}
+		return m, nil
+	case "=":
+		op = "=="
+	}
+	return &compareNode{op: op, left: left, right: right}, nil
+}
+
+// parseSum parses a + b and a - b
+func (p *exprParser) parseSum() (node, error) {
+	left, err := p.parseProduct()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op, ok := p.acceptOp("+", "-")
+		if !ok {
+			return left, nil
+		}
+		right, err := p.parseProduct()
+		if err != nil {
+			return nil, err
+		}
+		left = &arithNode{op: op, left: left, right: right}
+	}
+}
+
+// parseProduct parses a * b, a / b and a % b
+func (p *exprParser) parseProduct() (node, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op, ok := p.acceptOp("*", "/", "%")
+		if !ok {
+			return left, nil
+		}
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = &arithNode{op: op, left: left, right: right}
+	}
+}
+
+// parseUnary parses -a
+func (p *exprParser) parseUnary() (node, error) {
+	if _, ok := p.acceptOp("-"); ok {
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &arithNode{op: "-", left: &literalNode{value: Number(0)}, right: operand}, nil
+	}
+	return p.parsePrimary()
+}
+
+// parsePrimary parses a literal, column, function call or parenthesized
+// expression
+func (p *exprParser) parsePrimary() (node, error) {
+	tok := p.next()
+	switch tok.kind {
+	case tokNumber:
+		n, err := strconv.ParseFloat(tok.text, 64)
+		if err != nil {
+			return nil, syntaxError(p.src, tok.pos, "invalid number %q", tok.text)
+		}
+		return &literalNode{value: Number(n)}, nil
+
+	case tokString:
+		return &literalNode{value: String(tok.text)}, nil
+
+	case tokColumn:
+		return p.column(tok.text), nil
+
+	case tokIdent:
+		switch strings.ToLower(tok.text) {
+		case "true":
+			return &literalNode{value: Bool(true)}, nil
+		case "false":
+			return &literalNode{value: Bool(false)}, nil
+		case "null":
+			return &literalNode{value: Null()}, nil
+		case "and", "or", "not":
+			return nil, p.unexpected(tok)
+		}
+		if _, ok := p.acceptOp("("); ok {
+			return p.parseCall(tok)
+		}
+		return p.column(tok.text), nil
+
+	case tokOp:
+		if tok.text == "(" {
+			inner, err := p.parseOr()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expectOp(")"); err != nil {
+				return nil, err
+			}
+			return inner, nil
+		}
+	}
+	return nil, p.unexpected(tok)
+}
+
+// parseCall parses the arguments of a function call after the opening
+// parenthesis
+func (p *exprParser) parseCall(name token) (node, error) {
+	fn, ok := functions[strings.ToLower(name.text)]
+	if !ok {
+		return nil, syntaxError(p.src, name.pos, "unknown function %s", name.text)
+	}
+
+	var args []node
+	if _, ok := p.acceptOp(")"); !ok {
+		for {
+			arg, err := p.parseOr()
+			if err != nil {
+				return nil, err
+			}
+			args = append(args, arg)
+			if _, ok := p.acceptOp(","); !ok {
+				break
+			}
+		}
+		if err := p.expectOp(")"); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := CheckFunction(name.text, len(args)); err != nil {
+		return nil, syntaxError(p.src, name.pos, "%v", err)
+	}
+	if strings.EqualFold(name.text, "if") {
+		n := &ifNode{cond: args[0], then: args[1]}
+		if len(args) > 2 {
+			n.els = args[2]
+		}
+		return n, nil
+	}
+	call := &callNode{name: name.text, fn: fn, args: args}
+	if fn.regexpArg > 0 && len(args) >= fn.regexpArg {
+		if lit, ok := args[fn.regexpArg-1].(*literalNode); ok && lit.value.Kind == KindString {
+			re, err := regexp.Compile(lit.value.Str)
+			if err != nil {
+				return nil, syntaxError(p.src, name.pos, "invalid regexp in %s: %v", name.text, err)
+			}
+			call.re = re
+		}
+	}
+	return call, nil
+}
+
+// column creates a column reference and records the column
+func (p *exprParser) column(name string) node {
+	found := false
+	for _, c := range p.columns {
+		if c == name {
+			found = true
+			break
+		}
+	}
+	if !found {
+		p.columns = append(p.columns, name)
+	}
+	return &columnNode{name: name}
+}
@@ -0,0 +1,210 @@
+package expr
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/user/table-converter/internal/model"
+)
+
+// Kind is the type of an expression value
+type Kind int
+
+const (
+	KindNull Kind = iota
+	KindString
+	KindNumber
+	KindBool
+	KindDate
+)
+
+// String returns the name of the kind as used in error messages
+func (k Kind) String() string {
+	switch k {
+	case KindString:
+		return "string"
+	case KindNumber:
+		return "number"
+	case KindBool:
+		return "boolean"
+	case KindDate:
+		return "date"
+	default:
+		return "null"
+	}
+}
+
+// Value is the result of evaluating an expression
+type Value struct {
+	Kind Kind
+	Str  string
+	Num  float64
+	Bool bool
+	Time time.Time
+	// Column names the column the value was read from, for error messages
+	// ("" for literals and computed values)
+	Column string
+
+	cell    model.Value // the table cell the value was read from
+	hasCell bool
+}
+
+// Null returns the null value
+func Null() Value {
+	return Value{Kind: KindNull}
+}
+
+// String returns a string value
+func String(s string) Value {
+	return Value{Kind: KindString, Str: s}
+}
+
+// Number returns a number value
+func Number(n float64) Value {
+	return Value{Kind: KindNumber, Num: n}
+}
+
+// Bool returns a boolean value
+func Bool(b bool) Value {
+	return Value{Kind: KindBool, Bool: b}
+}
+
+// Date returns a date value
+func Date(t time.Time) Value {
+	return Value{Kind: KindDate, Time: t}
+}
+
+// FromModel converts a table cell to a value. Cells typed as numbers stay
+// numbers; other cells are strings that comparisons convert as needed, since
+// most text formats do not type their values
+func FromModel(v model.Value, column string) Value {
+	out := Value{Kind: KindString, Str: v.Raw, Column: column, cell: v, hasCell: true}
+	switch v.Type {
+	case model.TypeNull:
+		out.Kind, out.Str = KindNull, ""
+	case model.TypeNumber:
+		if n, ok := v.Parsed.(float64); ok {
+			out.Kind, out.Str, out.Num = KindNumber, "", n
+		}
+	}
+	return out
+}
+
+// ToModel converts the value to a table cell. A value read unchanged from
+// a cell gives back that cell, keeping its type and text
+func (v Value) ToModel() model.Value {
+	if v.hasCell {
+		return v.cell
+	}
+	switch v.Kind {
+	case KindNull:
+		return model.NewNullValue()
+	case KindNumber:
+		return model.NewNumberValue(v.Num)
+	case KindBool:
+		return model.NewBooleanValue(v.Bool)
+	default:
+		return model.NewStringValue(v.String())
+	}
+}
+
+// String returns the value as text. Dates without a time of day are
+// written as 2006-01-02
+func (v Value) String() string {
+	switch v.Kind {
+	case KindString:
+		return v.Str
+	case KindNumber:
+		return strconv.FormatFloat(v.Num, 'f', -1, 64)
+	case KindBool:
+		return strconv.FormatBool(v.Bool)
+	case KindDate:
+		if v.Time.Equal(v.Time.Truncate(24*time.Hour)) && v.Time.Location() == time.UTC {
+			return v.Time.Format("2006-01-02")
+		}
+		return v.Time.Format(time.RFC3339)
+	}
+	return ""
+}
+
+// describe returns the value for an error message, naming its column
+func (v Value) describe() string {
+	if v.Column != "" {
+		return fmt.Sprintf("column %q (%q)", v.Column, v.String())
+	}
+	if v.Kind == KindString {
+		return strconv.Quote(v.Str)
+	}
+	return v.String()
+}
+
+// AsNumber converts the value to a number. Strings are converted if they
+// hold a number
+func (v Value) AsNumber() (float64, error) {
+	switch v.Kind {
+	case KindNumber:
+		return v.Num, nil
+	case KindString:
+		if n, err := strconv.ParseFloat(strings.TrimSpace(v.Str), 64); err == nil {
+			return n, nil
+		}
+	}
+	return 0, fmt.Errorf("%s is not a number", v.describe())
+}
+
+// AsBool converts the value to a boolean. Strings such as true, no and 1
+// are converted, and null counts as false
+func (v Value) AsBool() (bool, error) {
+	switch v.Kind {
+	case KindNull:
+		return false, nil
+	case KindBool:
+		return v.Bool, nil
+	case KindString:
+		switch strings.ToLower(strings.TrimSpace(v.Str)) {
+		case "true", "yes", "1":
+			return true, nil
+		case "false", "no", "0":
+			return false, nil
+		}
+	}
+	return false, fmt.Errorf("%s is not true or false", v.describe())
+}
+
+// dateLayouts are the layouts strings are parsed as dates with
+var dateLayouts = []string{
+	"2006-01-02",
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006/01/02",
+}
+
+// ParseDate parses an ISO 8601 style date, or date and time, such as
+// 2024-03-01 or 2024-03-01T12:00:00Z
+func ParseDate(s string) (time.Time, bool) {
+	s = strings.TrimSpace(s)
+	for _, layout := range dateLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// AsDate converts the value to a date. Strings are converted if they hold
+// an ISO 8601 date such as 2024-03-01 or 2024-03-01T12:00:00Z
+func (v Value) AsDate() (time.Time, error) {
+	switch v.Kind {
+	case KindDate:
+		return v.Time, nil
+	case KindString:
+		if t, ok := ParseDate(v.Str); ok {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("%s is not a date", v.describe())
+}
@@ -0,0 +1,133 @@
+package model
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// ColumnSpec declares the position of a column in fixed-width text
+type ColumnSpec struct {
+	Name  string    // Column header
+	Start int       // First display column, 1-based
+	End   int       // Last display column, inclusive
+	Align Alignment // Alignment within the field (AlignDefault picks by type)
+}
+
+// Width returns the number of display columns the field occupies
+func (c ColumnSpec) Width() int {
+	return c.End - c.Start + 1
+}
+
+// PadInValues reports whether a padding character could also be part of a
+// value, as the digit 0 or a letter can. Such a character only pads the
+// left of right-aligned fields, where it cannot be confused with the value,
+// and other fields are padded with spaces
+func PadInValues(pad rune) bool {
+	return unicode.IsLetter(pad) || unicode.IsDigit(pad)
+}
+
+// ParseColumnSpecs parses a comma-separated list of column specs of the form
+// name:start-end or name:start-end:align, e.g. "name:1-10,amount:11-20:right"
+func ParseColumnSpecs(spec string) ([]ColumnSpec, error) {
+	var columns []ColumnSpec
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		column, err := parseColumnSpec(part)
+		if err != nil {
+			return nil, err
+		}
+		columns = append(columns, column)
+	}
+	return columns, validateColumnSpecs(columns)
+}
+
+// ReadColumnSpecs reads column specs from a spec file with one column per
+// line. Blank lines and lines starting with # are ignored
+func ReadColumnSpecs(r io.Reader) ([]ColumnSpec, error) {
+	var columns []ColumnSpec
+	scanner := bufio.NewScanner(r)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		column, err := parseColumnSpec(line)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNum, err)
+		}
+		columns = append(columns, column)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return columns, validateColumnSpecs(columns)
+}
+
+// parseColumnSpec parses a single name:start-end[:align] column spec
+func parseColumnSpec(spec string) (ColumnSpec, error) {
+	parts := strings.Split(spec, ":")
+	if len(parts) < 2 || len(parts) > 3 {
+		return ColumnSpec{}, fmt.Errorf("invalid column spec %q (expected name:start-end[:align])", spec)
+	}
+
+	column := ColumnSpec{Name: strings.TrimSpace(parts[0])}
+	if column.Name == "" {
+		return ColumnSpec{}, fmt.Errorf("invalid column spec %q: missing column name", spec)
+	}
+
+	start, end, found := strings.Cut(strings.TrimSpace(parts[1]), "-")
+	var err error
+	if column.Start, err = strconv.Atoi(strings.TrimSpace(start)); err != nil || column.Start < 1 {
+		return ColumnSpec{}, fmt.Errorf("invalid column spec %q: start must be a position starting at 1", spec)
+	}
+	column.End = column.Start
+	if found {
+		if column.End, err = strconv.Atoi(strings.TrimSpace(end)); err != nil || column.End < column.Start {
+			return ColumnSpec{}, fmt.Errorf("invalid column spec %q: end must be a position no less than start", spec)
+		}
+	}
+
+	if len(parts) == 3 {
+		if column.Align, err = ParseAlignment(parts[2]); err != nil {
+			return ColumnSpec{}, fmt.Errorf("invalid column spec %q: %w", spec, err)
+		}
+	}
+
+	return column, nil
+}
+
+// validateColumnSpecs checks that columns are declared and do not overlap
+func validateColumnSpecs(columns []ColumnSpec) error {
+	if len(columns) == 0 {
+		return fmt.Errorf("no columns declared")
+	}
+	for i := 1; i < len(columns); i++ {
+		if columns[i].Start <= columns[i-1].End {
+			return fmt.Errorf("column %q must start after column %q ends", columns[i].Name, columns[i-1].Name)
+		}
+	}
+	return nil
+}
+
+// ParseAlignment converts an alignment name (left, center or right) to an Alignment
+func ParseAlignment(name string) (Alignment, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "left", "l":
+		return AlignLeft, nil
+	case "center", "centre", "c":
+		return AlignCenter, nil
+	case "right", "r":
+		return AlignRight, nil
+	default:
+		return AlignDefault, fmt.Errorf("unknown alignment %q (expected left, center or right)", name)
+	}
+}
@@ -0,0 +1,74 @@
+package model
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseColumnSpecs(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    string
+		want    []ColumnSpec
+		wantErr bool
+	}{
+		{
+			name: "ranges and alignment",
+			spec: "name:1-10, amount:11-20:right,flag:22",
+			want: []ColumnSpec{
+				{Name: "name", Start: 1, End: 10},
+				{Name: "amount", Start: 11, End: 20, Align: AlignRight},
+				{Name: "flag", Start: 22, End: 22},
+			},
+		},
+		{name: "empty", spec: "", wantErr: true},
+		{name: "missing range", spec: "name", wantErr: true},
+		{name: "zero start", spec: "name:0-5", wantErr: true},
+		{name: "end before start", spec: "name:5-2", wantErr: true},
+		{name: "overlap", spec: "a:1-5,b:5-8", wantErr: true},
+		{name: "unknown alignment", spec: "a:1-5:middle", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseColumnSpecs(tt.spec)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("expected error, got %v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %d columns, want %d", len(got), len(tt.want))
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("column %d = %+v, want %+v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestReadColumnSpecs(t *testing.T) {
+	input := `# account export layout
+id:1-6:right
+
+name:7-26
+`
+	got, err := ReadColumnSpecs(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got) != 2 || got[0].Name != "id" || got[0].Align != AlignRight || got[1].Width() != 20 {
+		t.Errorf("unexpected columns: %+v", got)
+	}
+
+	_, err = ReadColumnSpecs(strings.NewReader("id:1-6\nbroken\n"))
+	if err == nil || !strings.Contains(err.Error(), "line 2") {
+		t.Errorf("expected error on line 2, got %v", err)
+	}
+}
@@ -0,0 +1,103 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+)
+
+// NoQuote is the Quote of a CSV dialect without quoting
+const NoQuote rune = -1
+
+// QuoteStyle selects which CSV output fields are quoted
+type QuoteStyle int
+
+const (
+	QuoteMinimal QuoteStyle = iota // Quote fields containing delimiters, quotes or line breaks
+	QuoteAll                       // Quote every field
+	QuoteNever                     // Never quote; special characters are escaped instead
+)
+
+// CSVDialect describes the delimiters, quoting and escaping of a CSV
+// variant. The zero value is RFC 4180 CSV, with the delimiter detected on
+// input. Some fields only apply to input or to output
+type CSVDialect struct {
+	Delimiter      string     // Field delimiter, one or more characters ("" = detect on input, comma on output)
+	Quote          rune       // Quote character (0 = '"', NoQuote = none)
+	Escape         rune       // Escape character such as '\\' (0 = quotes are doubled)
+	Comment        string     // Input lines starting with this prefix are skipped ("" = none)
+	SkipLines      int        // Input lines skipped before the header
+	NoHeader       bool       // Input has no header line; columns are named col1..colN
+	LineTerminator string     // Output line ending ("" = "\n")
+	Quoting        QuoteStyle // Which output fields are quoted
+}
+
+// QuoteChar returns the quote character, or 0 if quoting is disabled
+func (d CSVDialect) QuoteChar() rune {
+	switch d.Quote {
+	case 0:
+		return '"'
+	case NoQuote:
+		return 0
+	}
+	return d.Quote
+}
+
+// Validate checks that the delimiter, quote and escape characters can be
+// told apart
+func (d CSVDialect) Validate() error {
+	quote := d.QuoteChar()
+	if strings.ContainsAny(d.Delimiter, "\r\n") {
+		return fmt.Errorf("delimiter %q must not contain a line break", d.Delimiter)
+	}
+	if quote != 0 && strings.ContainsRune(d.Delimiter, quote) {
+		return fmt.Errorf("delimiter %q must not contain the quote character %q", d.Delimiter, quote)
+	}
+	if d.Escape != 0 && strings.ContainsRune(d.Delimiter, d.Escape) {
+		return fmt.Errorf("delimiter %q must not contain the escape character %q", d.Delimiter, d.Escape)
+	}
+	if d.Escape != 0 && d.Escape == quote {
+		return fmt.Errorf("escape character %q must differ from the quote character; quotes are doubled by default", d.Escape)
+	}
+	if d.Escape == '\r' || d.Escape == '\n' || quote == '\r' || quote == '\n' {
+		return fmt.Errorf("quote and escape characters must not be line breaks")
+	}
+	if d.SkipLines < 0 {
+		return fmt.Errorf("number of lines to skip must not be negative")
+	}
+	return nil
+}
+
+// ParseQuoteStyle converts a quoting name (minimal, all or never) to a QuoteStyle
+func ParseQuoteStyle(name string) (QuoteStyle, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "", "minimal":
+		return QuoteMinimal, nil
+	case "all", "always":
+		return QuoteAll, nil
+	case "never", "none":
+		return QuoteNever, nil
+	default:
+		return QuoteMinimal, fmt.Errorf("unknown quoting %q (expected minimal, all or never)", name)
+	}
+}
+
+// ParseDelimiterName converts a delimiter name such as tab or a literal
+// delimiter of one or more characters to the delimiter text. \t stands
+// for a tab
+func ParseDelimiterName(name string) (string, error) {
+	switch strings.ToLower(name) {
+	case "":
+		return "", fmt.Errorf("delimiter must not be empty")
+	case "comma":
+		return ",", nil
+	case "tab", `\t`:
+		return "\t", nil
+	case "semicolon":
+		return ";", nil
+	case "pipe":
+		return "|", nil
+	case "space":
+		return " ", nil
+	}
+	return strings.ReplaceAll(name, `\t`, "\t"), nil
+}
@@ -0,0 +1,38 @@
+package model
+
+import "testing"
+
+func TestCSVDialect_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		dialect CSVDialect
+		wantErr bool
+	}{
+		{name: "default", dialect: CSVDialect{}},
+		{name: "multi-character delimiter", dialect: CSVDialect{Delimiter: "::", Escape: '\\'}},
+		{name: "no quoting", dialect: CSVDialect{Delimiter: "\t", Quote: NoQuote, Escape: '\\'}},
+		{name: "delimiter with line break", dialect: CSVDialect{Delimiter: "\n"}, wantErr: true},
+		{name: "delimiter with quote", dialect: CSVDialect{Delimiter: `"`}, wantErr: true},
+		{name: "escape equals quote", dialect: CSVDialect{Quote: '\'', Escape: '\''}, wantErr: true},
+		{name: "negative skip", dialect: CSVDialect{SkipLines: -1}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.dialect.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestParseDelimiterName(t *testing.T) {
+	for name, want := range map[string]string{"tab": "\t", `\t`: "\t", "semicolon": ";", "::": "::", "|": "|"} {
+		if got, err := ParseDelimiterName(name); err != nil || got != want {
+			t.Errorf("ParseDelimiterName(%q) = %q, %v, want %q", name, got, err, want)
+		}
+	}
+	if _, err := ParseDelimiterName(""); err == nil {
+		t.Error("expected an error for an empty delimiter")
+	}
+}
@@ -0,0 +1,200 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// DuplicatePolicy says what happens to a header that repeats an earlier one
+type DuplicatePolicy int
+
+const (
+	DuplicateKeep   DuplicatePolicy = iota // Leave repeated headers as they are
+	DuplicateRename                        // Add a suffix: Amount, Amount_2, Amount_3
+	DuplicateError                         // Reject the table
+)
+
+// EmptyPolicy says what happens to an empty header
+type EmptyPolicy int
+
+const (
+	EmptyKeep     EmptyPolicy = iota // Leave empty headers as they are
+	EmptyGenerate                    // Name the column by position: column_3
+	EmptyError                       // Reject the table
+)
+
+// HeaderCase is a naming convention headers are converted to
+type HeaderCase int
+
+const (
+	CaseKeep  HeaderCase = iota // Leave the case as it is
+	CaseSnake                   // total_amount_usd
+	CaseCamel                   // totalAmountUsd
+	CaseLower                   // total amount (usd)
+)
+
+// HeaderPolicy says how NormalizeHeaders treats empty and duplicate
+// headers and which case it converts them to. The zero value leaves
+// headers unchanged
+type HeaderPolicy struct {
+	Duplicates DuplicatePolicy
+	Empty      EmptyPolicy
+	Case       HeaderCase
+}
+
+// IsZero reports whether the policy leaves headers unchanged
+func (p HeaderPolicy) IsZero() bool {
+	return p == HeaderPolicy{}
+}
+
+// NormalizeHeaders applies the policy to a copy of the headers. Empty
+// headers are named first, then the case is converted, and duplicates are
+// checked last so that names which only differ in case are caught
+func NormalizeHeaders(headers []string, policy HeaderPolicy) ([]string, error) {
+	out := make([]string, len(headers))
+	for i, header := range headers {
+		if strings.TrimSpace(header) == "" {
+			switch policy.Empty {
+			case EmptyError:
+				return nil, fmt.Errorf("empty header in column %d", i+1)
+			case EmptyGenerate:
+				header = fmt.Sprintf("column_%d", i+1)
+			}
+		}
+		out[i] = convertCase(header, policy.Case)
+	}
+
+	if policy.Duplicates == DuplicateKeep {
+		return out, nil
+	}
+
+	taken := make(map[string]bool, len(out))
+	for _, name := range out {
+		taken[name] = true
+	}
+	seen := make(map[string]int, len(out))
+	for i, name := range out {
+		seen[name]++
+		if seen[name] == 1 {
+			continue
+		}
+		if policy.Duplicates == DuplicateError {
+			return nil, fmt.Errorf("duplicate header %q in column %d", name, i+1)
+		}
+
+		// Skip suffixes already used by other headers
+		n := seen[name]
+		renamed := fmt.Sprintf("%s_%d", name, n)
+		for taken[renamed] {
+			n++
+			renamed = fmt.Sprintf("%s_%d", name, n)
+		}
+		seen[name] = n
+		taken[renamed] = true
+		out[i] = renamed
+	}
+	return out, nil
+}
+
+// convertCase converts a header to a naming convention. Headers without
+// letters or digits are left as they are
+func convertCase(header string, c HeaderCase) string {
+	if c == CaseLower {
+		return strings.ToLower(header)
+	}
+	words := headerWords(header)
+	if c == CaseKeep || len(words) == 0 {
+		return header
+	}
+
+	switch c {
+	case CaseSnake:
+		return strings.Join(words, "_")
+	case CaseCamel:
+		for i := 1; i < len(words); i++ {
+			runes := []rune(words[i])
+			runes[0] = unicode.ToUpper(runes[0])
+			words[i] = string(runes)
+		}
+		return strings.Join(words, "")
+	}
+	return header
+}
+
+// headerWords splits a header into lower-case words at spaces,
+// punctuation and case changes, so that "HTTPStatus code" gives
+// http, status and code
+func headerWords(header string) []string {
+	var words []string
+	var word []rune
+	flush := func() {
+		if len(word) > 0 {
+			words = append(words, strings.ToLower(string(word)))
+			word = word[:0]
+		}
+	}
+
+	runes := []rune(header)
+	for i, r := range runes {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			flush()
+			continue
+		}
+		if i > 0 && unicode.IsUpper(r) {
+			prev := runes[i-1]
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			// A word starts at aB, and at the last capital of an acronym in ABc
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextLower) {
+				flush()
+			}
+		}
+		word = append(word, r)
+	}
+	flush()
+	return words
+}
+
+// ParseDuplicatePolicy converts keep, rename or error to a DuplicatePolicy
+func ParseDuplicatePolicy(name string) (DuplicatePolicy, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "keep":
+		return DuplicateKeep, nil
+	case "rename":
+		return DuplicateRename, nil
+	case "error":
+		return DuplicateError, nil
+	default:
+		return DuplicateKeep, fmt.Errorf("unknown duplicate header policy %q (expected keep, rename or error)", name)
+	}
+}
+
+// ParseEmptyPolicy converts keep, generate or error to an EmptyPolicy
+func ParseEmptyPolicy(name string) (EmptyPolicy, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "keep":
+		return EmptyKeep, nil
+	case "generate":
+		return EmptyGenerate, nil
+	case "error":
+		return EmptyError, nil
+	default:
+		return EmptyKeep, fmt.Errorf("unknown empty header policy %q (expected keep, generate or error)", name)
+	}
+}
+
+// ParseHeaderCase converts keep, snake, camel or lower to a HeaderCase
+func ParseHeaderCase(name string) (HeaderCase, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "keep":
+		return CaseKeep, nil
+	case "snake", "snake_case":
+		return CaseSnake, nil
+	case "camel", "camelcase":
+		return CaseCamel, nil
+	case "lower", "lowercase":
+		return CaseLower, nil
+	default:
+		return CaseKeep, fmt.Errorf("unknown header case %q (expected keep, snake, camel or lower)", name)
+	}
+}
@@ -0,0 +1,57 @@
+package model
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNormalizeHeaders(t *testing.T) {
+	tests := []struct {
+		name    string
+		headers []string
+		policy  HeaderPolicy
+		want    []string
+		wantErr bool
+	}{
+		{"zero policy", []string{"A", "A", ""}, HeaderPolicy{}, []string{"A", "A", ""}, false},
+		{"rename", []string{"Amount", "Amount", "Amount"}, HeaderPolicy{Duplicates: DuplicateRename}, []string{"Amount", "Amount_2", "Amount_3"}, false},
+		{"rename skips taken names", []string{"a", "a_2", "a"}, HeaderPolicy{Duplicates: DuplicateRename}, []string{"a", "a_2", "a_3"}, false},
+		{"duplicate error", []string{"a", "b", "a"}, HeaderPolicy{Duplicates: DuplicateError}, nil, true},
+		{"generate", []string{"a", "b", " "}, HeaderPolicy{Empty: EmptyGenerate}, []string{"a", "b", "column_3"}, false},
+		{"empty error", []string{"a", ""}, HeaderPolicy{Empty: EmptyError}, nil, true},
+		{"snake", []string{"Total Amount (USD)", "HTTPStatus", "userID", "already_snake"}, HeaderPolicy{Case: CaseSnake},
+			[]string{"total_amount_usd", "http_status", "user_id", "already_snake"}, false},
+		{"camel", []string{"Total Amount (USD)", "first_name", "ID"}, HeaderPolicy{Case: CaseCamel},
+			[]string{"totalAmountUsd", "firstName", "id"}, false},
+		{"lower", []string{"Total Amount (USD)"}, HeaderPolicy{Case: CaseLower}, []string{"total amount (usd)"}, false},
+		{"case then duplicates", []string{"Name", "name"}, HeaderPolicy{Duplicates: DuplicateRename, Case: CaseLower},
+			[]string{"name", "name_2"}, false},
+		{"symbols kept", []string{"#"}, HeaderPolicy{Case: CaseSnake}, []string{"#"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NormalizeHeaders(tt.headers, tt.policy)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NormalizeHeaders() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("NormalizeHeaders() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseHeaderPolicies(t *testing.T) {
+	if p, err := ParseDuplicatePolicy("Rename"); err != nil || p != DuplicateRename {
+		t.Errorf("ParseDuplicatePolicy() = %v, %v", p, err)
+	}
+	if p, err := ParseEmptyPolicy("generate"); err != nil || p != EmptyGenerate {
+		t.Errorf("ParseEmptyPolicy() = %v, %v", p, err)
+	}
+	if c, err := ParseHeaderCase("camelCase"); err != nil || c != CaseCamel {
+		t.Errorf("ParseHeaderCase() = %v, %v", c, err)
+	}
+	if _, err := ParseHeaderCase("kebab"); err == nil {
+		t.Error("ParseHeaderCase(kebab) error = nil")
+	}
+}
@@ -116,10 +116,22 @@ func (v Value) String() string {
 	return v.Raw
 }
 
+// Alignment represents the horizontal alignment of a column
+type Alignment int
+
+const (
+	AlignDefault Alignment = iota // No explicit alignment
+	AlignLeft
+	AlignCenter
+	AlignRight
+)
+
 // TableData represents structured tabular data with headers and rows
 type TableData struct {
 	Headers []string
 	Rows    [][]Value
+	// Alignments optionally holds the alignment of each column (nil when unspecified)
+	Alignments []Alignment
 }
 
 // NewTableData creates a new TableData with the given headers and rows
@@ -198,6 +210,14 @@ func (td *TableData) NumCols() int {
 	return len(td.Headers)
 }
 
+// ColumnAlignment returns the alignment of a column, or AlignDefault if none is set
+func (td *TableData) ColumnAlignment(col int) Alignment {
+	if td == nil || col < 0 || col >= len(td.Alignments) {
+		return AlignDefault
+	}
+	return td.Alignments[col]
+}
+
 // IsEmpty returns true if the table has no rows
 func (td *TableData) IsEmpty() bool {
 	return td == nil || len(td.Rows) == 0
@@ -3,9 +3,12 @@ package parser
 import (
 	"bufio"
 	"io"
+	"math"
+	"regexp"
 	"strings"
 
 	"github.com/user/table-converter/internal/model"
+	"github.com/user/table-converter/internal/textwidth"
 )
 
 // TableStyle represents the detected or desired table style
@@ -18,12 +21,31 @@ const (
 	StyleOrgMode   TableStyle = "org"        // Emacs org-mode
 	StyleRSTGrid   TableStyle = "rst-grid"   // reStructuredText grid table
 	StyleRSTSimple TableStyle = "rst-simple" // reStructuredText simple table
+	StyleUnicode   TableStyle = "unicode"    // Unicode single-line box drawing
+	StyleDouble    TableStyle = "double"     // Unicode double-line box drawing
+	StyleRounded   TableStyle = "rounded"    // Unicode box drawing with rounded corners
+	StyleHeavy     TableStyle = "heavy"      // Unicode heavy-line box drawing
+	StyleSimple    TableStyle = "simple"     // Borderless columns with a dashed header rule
+
+	StylePsqlExpanded  TableStyle = "psql-expanded"  // PostgreSQL expanded display (\x), one field per line
+	StyleMySQLVertical TableStyle = "mysql-vertical" // MySQL vertical output (\G), one field per line
+	StyleSQLiteLine    TableStyle = "sqlite-line"    // SQLite .mode line, one field per line
 )
 
+// markdownLineBreak matches the HTML line breaks used for multi-line Markdown cells
+var markdownLineBreak = regexp.MustCompile(`(?i)<br\s*/?>`)
+
 // UnifiedASCIIParser implements the Parser interface for all ASCII-style table formats
-// Supports: ASCII box, psql, Markdown, Org-mode, reStructuredText (grid and simple)
+// Supports: ASCII box, psql, Markdown, Org-mode, reStructuredText (grid and simple),
+// Unicode box drawing (single, double, rounded, heavy), borderless simple tables
+// and the one-field-per-line record output of psql, MySQL and SQLite
 type UnifiedASCIIParser struct {
-	DetectedStyle TableStyle // The style that was detected during parsing
+	DetectedStyle    TableStyle // The style that was detected during parsing
+	RequireSeparator bool       // Reject tables that have no header separator line
+
+	RowChecker
+
+	lineNumbers []int // Input line of each non-empty line being parsed
 }
 
 // NewUnifiedASCIIParser creates a new unified ASCII table parser
@@ -31,16 +53,34 @@ func NewUnifiedASCIIParser() *UnifiedASCIIParser {
 	return &UnifiedASCIIParser{}
 }
 
+// NewASCIIParser creates a new ASCII table parser (alias for NewUnifiedASCIIParser)
+func NewASCIIParser() *UnifiedASCIIParser {
+	return NewUnifiedASCIIParser()
+}
+
+// NewMarkdownParser creates a parser for Markdown tables, which must have a separator row
+func NewMarkdownParser() *UnifiedASCIIParser {
+	return &UnifiedASCIIParser{
+		RequireSeparator: true,
+	}
+}
+
 // Parse reads an ASCII-style table and auto-detects the format
 func (p *UnifiedASCIIParser) Parse(input io.Reader) (*model.TableData, error) {
 	scanner := bufio.NewScanner(input)
 	var lines []string
-
-	// Read all non-empty lines
-	for scanner.Scan() {
-		line := scanner.Text()
+	p.lineNumbers = nil
+
+	// Read all non-empty lines, dropping terminal color codes and a UTF-8
+	// byte order mark
+	for number := 1; scanner.Scan(); number++ {
+		line := textwidth.StripANSI(scanner.Text())
+		if len(lines) == 0 {
+			line = strings.TrimPrefix(line, string(utf8BOM))
+		}
 		if strings.TrimSpace(line) != "" {
 			lines = append(lines, line)
+			p.lineNumbers = append(p.lineNumbers, number)
 		}
 	}
 
@@ -48,31 +88,97 @@ func (p *UnifiedASCIIParser) Parse(input io.Reader) (*model.TableData, error) {
 		return nil, NewParseError("failed to read input").WithErr(err)
 	}
 
+	// Database clients print a row count below the result
+	lines = stripQueryFooter(lines)
+
 	if len(lines) == 0 {
 		return model.NewTableData([]string{}, [][]model.Value{}), nil
 	}
 
+	// Unicode box-drawing tables are parsed as their ASCII equivalents
+	boxStyle := detectBoxDrawingStyle(lines)
+	if boxStyle != "" {
+		for i, line := range lines {
+			lines[i] = normalizeBoxDrawing(line)
+		}
+	}
+
+	if p.RequireSeparator && !p.hasSeparator(lines) {
+		return nil, NewParseError("invalid table: missing header separator line")
+	}
+
 	// Detect the table style
 	style := p.detectStyle(lines)
+	if boxStyle != "" && (style == StyleBox || style == StyleRSTGrid) {
+		style = boxStyle
+	}
 	p.DetectedStyle = style
 
 	// Parse based on detected style
+	var td *model.TableData
+	var err error
 	switch style {
+	case StylePsqlExpanded:
+		td, err = p.parsePsqlExpanded(lines)
+	case StyleMySQLVertical:
+		td, err = p.parseMySQLVertical(lines)
+	case StyleSQLiteLine:
+		td, err = p.parseSQLiteLine(lines)
 	case StyleRSTSimple:
-		return p.parseRSTSimple(lines)
+		td, err = p.parseRSTSimple(lines)
+	case StyleSimple:
+		td, err = p.parseSimple(lines)
 	default:
 		// All pipe-based formats use similar parsing
-		return p.parsePipeBased(lines, style)
+		td, err = p.parsePipeBased(lines, style)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	headerLine := 0
+	for i, line := range lines {
+		if !p.isSeparatorLine(line) {
+			headerLine = p.sourceLine(i)
+			break
+		}
 	}
+	if err := p.checkHeaders(td.Headers, headerLine); err != nil {
+		return nil, err
+	}
+	return td, nil
+}
+
+// sourceLine returns the 1-based input line of lines[i], or 0 if unknown
+func (p *UnifiedASCIIParser) sourceLine(i int) int {
+	if i < 0 || i >= len(p.lineNumbers) {
+		return 0
+	}
+	return p.lineNumbers[i]
 }
 
 // detectStyle determines which table format is being used
 func (p *UnifiedASCIIParser) detectStyle(lines []string) TableStyle {
+	// Check for record-per-block client output before any table layout
+	switch {
+	case p.isPsqlExpanded(lines):
+		return StylePsqlExpanded
+	case p.isMySQLVertical(lines):
+		return StyleMySQLVertical
+	case p.isSQLiteLine(lines):
+		return StyleSQLiteLine
+	}
+
 	// Check for RST Simple (uses = only, no pipes)
 	if p.isRSTSimple(lines) {
 		return StyleRSTSimple
 	}
 
+	// Check for borderless simple tables (dashed rule, no pipes)
+	if p.isSimple(lines) {
+		return StyleSimple
+	}
+
 	// Find separator lines for pipe-based formats
 	var sepLines []string
 	for _, line := range lines {
@@ -119,9 +225,14 @@ func (p *UnifiedASCIIParser) detectStyle(lines []string) TableStyle {
 	// Org-mode uses + at intersections, Markdown uses only -
 	if strings.Contains(sepLine, "+") {
 		// Could be org-mode or box
-		// Check if there's a data line to distinguish
-		if sepIndex > 0 {
-			dataLine := lines[sepIndex-1]
+		// Check the data line next to the separator to distinguish. Org-mode
+		// tables may open with a rule, so fall back to the line below it
+		dataIndex := sepIndex - 1
+		if dataIndex < 0 {
+			dataIndex = sepIndex + 1
+		}
+		if dataIndex < len(lines) {
+			dataLine := lines[dataIndex]
 			if strings.HasPrefix(strings.TrimSpace(dataLine), "|") {
 				// Has leading pipe, check for + in separator
 				if p.hasIntersectionPlus(sepLine) {
@@ -155,6 +266,33 @@ func (p *UnifiedASCIIParser) isRSTSimple(lines []string) bool {
 	return hasEquals && !hasPipes
 }
 
+// isSimple checks if the table is a borderless table with a dashed header rule
+func (p *UnifiedASCIIParser) isSimple(lines []string) bool {
+	hasRule := false
+
+	for _, line := range lines {
+		if strings.ContainsAny(line, "|+") {
+			return false
+		}
+		trimmed := strings.TrimSpace(line)
+		if len(trimmed) > 0 && strings.Trim(trimmed, "- \t") == "" {
+			hasRule = true
+		}
+	}
+
+	return hasRule
+}
+
+// hasSeparator checks if any line is a separator line
+func (p *UnifiedASCIIParser) hasSeparator(lines []string) bool {
+	for _, line := range lines {
+		if p.isSeparatorLine(line) {
+			return true
+		}
+	}
+	return false
+}
+
 // hasIntersectionPlus checks if + appears at column intersections (org-mode style)
 func (p *UnifiedASCIIParser) hasIntersectionPlus(sepLine string) bool {
 	// In org-mode, the separator looks like: |---------+-----+---------|
@@ -189,43 +327,335 @@ func (p *UnifiedASCIIParser) parsePipeBased(lines []string, style TableStyle) (*
 		return nil, NewParseError("invalid table: cannot detect column boundaries")
 	}
 
-	// Parse data rows (skip separator lines)
-	var headers []string
+	// Group data lines into logical rows (skip separator lines)
+	blocks, starts := p.splitBlocks(lines)
+	numCols := len(colBoundaries) - 1
+	var logicalRows [][]string
+
+	// checkLines checks the width of each input line of a data row. row is
+	// the logical row the lines belong to, where row 0 is the header
+	checkLines := func(row int, block []string, start int) error {
+		if row == 0 {
+			return nil
+		}
+		for i, line := range block {
+			fields := countRowFields(line, colBoundaries, style)
+			if err := p.checkRow(row, fields, numCols, p.sourceLine(start+i)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	switch {
+	case style == StylePsql:
+		lastMarker := p.psqlLastMarkerColumn(lines)
+		for b, block := range blocks {
+			rows, rowStarts := p.mergePsqlContinuations(block, colBoundaries, lastMarker)
+			for i := range rows {
+				end := len(block)
+				if i+1 < len(rowStarts) {
+					end = rowStarts[i+1]
+				}
+				if err := checkLines(len(logicalRows)+i, block[rowStarts[i]:end], starts[b]+rowStarts[i]); err != nil {
+					return nil, err
+				}
+			}
+			logicalRows = append(logicalRows, rows...)
+		}
+	case isFramedStyle(style) && len(blocks) > 1:
+		// Lines between two separators form one row, except in tables
+		// without row separators where every data line is its own row. A
+		// lone row closed by a row rule before the bottom border is one
+		// multi-line row
+		logicalRows = append(logicalRows, p.mergeLines(blocks[0], colBoundaries, style))
+		if len(blocks) > 2 || p.endsWithRowRule(lines) {
+			for b, block := range blocks[1:] {
+				if err := checkLines(len(logicalRows), block, starts[b+1]); err != nil {
+					return nil, err
+				}
+				logicalRows = append(logicalRows, p.mergeLines(block, colBoundaries, style))
+			}
+		} else {
+			for i, line := range blocks[1] {
+				if err := checkLines(len(logicalRows), blocks[1][i:i+1], starts[1]+i); err != nil {
+					return nil, err
+				}
+				logicalRows = append(logicalRows, p.parseDataRow(line, colBoundaries, style))
+			}
+		}
+	case style == StyleMarkdown || style == StyleOrgMode:
+		// Hand-edited tables drift out of alignment, so split each line on
+		// its own pipes rather than on the boundaries of the first row
+		for b, block := range blocks {
+			for i, line := range block {
+				cells, fields := splitPipeRow(line, numCols, style == StyleMarkdown)
+				if len(logicalRows) > 0 {
+					if err := p.checkRow(len(logicalRows), fields, numCols, p.sourceLine(starts[b]+i)); err != nil {
+						return nil, err
+					}
+				}
+				if style == StyleMarkdown {
+					for i, cell := range cells {
+						cells[i] = markdownLineBreak.ReplaceAllString(cell, "\n")
+					}
+				}
+				logicalRows = append(logicalRows, cells)
+			}
+		}
+	default:
+		for b, block := range blocks {
+			for i, line := range block {
+				if err := checkLines(len(logicalRows), block[i:i+1], starts[b]+i); err != nil {
+					return nil, err
+				}
+				logicalRows = append(logicalRows, p.parseDataRow(line, colBoundaries, style))
+			}
+		}
+	}
+
+	if len(logicalRows) == 0 {
+		return model.NewTableData([]string{}, [][]model.Value{}), nil
+	}
+
+	// First logical row is the header
+	headers := logicalRows[0]
 	var rows [][]model.Value
-	headerFound := false
+	for _, cells := range logicalRows[1:] {
+		values := make([]model.Value, len(cells))
+		for i, cell := range cells {
+			values[i] = model.NewValue(cell)
+		}
+		rows = append(rows, values)
+	}
+
+	td := model.NewTableData(headers, rows)
+	if style == StyleMarkdown {
+		td.Alignments = p.parseMarkdownAlignments(lines, len(headers))
+	}
+	return td, nil
+}
+
+// splitPipeRow splits a Markdown or Org-mode row on its pipes and pads or
+// trims the result to numCols cells, also returning the number of cells
+// the row had. Markdown allows pipes escaped as \|
+func splitPipeRow(line string, numCols int, escapes bool) ([]string, int) {
+	line = strings.TrimSpace(line)
+	line = strings.TrimPrefix(line, "|")
+	if strings.HasSuffix(line, "|") && !(escapes && strings.HasSuffix(line, "\\|")) {
+		line = line[:len(line)-1]
+	}
+
+	cells := make([]string, 0, numCols)
+	start := 0
+	for i := 0; i < len(line); i++ {
+		switch line[i] {
+		case '\\':
+			if escapes {
+				i++ // Skip the escaped character
+			}
+		case '|':
+			cells = append(cells, strings.TrimSpace(line[start:i]))
+			start = i + 1
+		}
+	}
+	cells = append(cells, strings.TrimSpace(line[start:]))
 
+	fields := len(cells)
+	for len(cells) < numCols {
+		cells = append(cells, "")
+	}
+	return cells[:numCols], fields
+}
+
+// parseMarkdownAlignments reads the GFM alignment markers (:---, :---:, ---:)
+// from the header separator line. It returns nil if no column is aligned
+func (p *UnifiedASCIIParser) parseMarkdownAlignments(lines []string, numCols int) []model.Alignment {
+	var sepLine string
 	for _, line := range lines {
 		if p.isSeparatorLine(line) {
+			sepLine = strings.TrimSpace(line)
+			break
+		}
+	}
+
+	sepLine = strings.TrimPrefix(sepLine, "|")
+	sepLine = strings.TrimSuffix(sepLine, "|")
+
+	alignments := make([]model.Alignment, numCols)
+	found := false
+	for i, cell := range strings.Split(sepLine, "|") {
+		if i >= numCols {
+			break
+		}
+		cell = strings.TrimSpace(cell)
+		left := strings.HasPrefix(cell, ":")
+		right := strings.HasSuffix(cell, ":") && len(cell) > 1
+
+		switch {
+		case left && right:
+			alignments[i] = model.AlignCenter
+		case left:
+			alignments[i] = model.AlignLeft
+		case right:
+			alignments[i] = model.AlignRight
+		default:
 			continue
 		}
+		found = true
+	}
 
-		cells := p.parseDataRow(line, colBoundaries, style)
+	if !found {
+		return nil
+	}
+	return alignments
+}
 
-		if !headerFound {
-			headers = cells
-			headerFound = true
-		} else {
-			values := make([]model.Value, len(cells))
-			for i, cell := range cells {
-				values[i] = model.NewValue(cell)
+// splitBlocks groups consecutive data lines, using separator lines as
+// dividers. It also returns the index into lines of each block's first line
+func (p *UnifiedASCIIParser) splitBlocks(lines []string) ([][]string, []int) {
+	var blocks [][]string
+	var starts []int
+	var current []string
+
+	for i, line := range lines {
+		if p.isSeparatorLine(line) {
+			if len(current) > 0 {
+				blocks = append(blocks, current)
+				current = nil
 			}
-			rows = append(rows, values)
+			continue
+		}
+		if len(current) == 0 {
+			starts = append(starts, i)
 		}
+		current = append(current, line)
 	}
 
-	if len(headers) == 0 {
-		return model.NewTableData([]string{}, [][]model.Value{}), nil
+	if len(current) > 0 {
+		blocks = append(blocks, current)
 	}
 
-	return model.NewTableData(headers, rows), nil
+	return blocks, starts
 }
 
-// findColumnBoundaries finds column separator positions
+// endsWithRowRule reports whether the table ends with two separator lines,
+// a row rule followed by the bottom border
+func (p *UnifiedASCIIParser) endsWithRowRule(lines []string) bool {
+	var last []string
+	for i := len(lines) - 1; i >= 0 && len(last) < 2; i-- {
+		if strings.TrimSpace(lines[i]) != "" {
+			last = append(last, lines[i])
+		}
+	}
+	return len(last) == 2 && p.isSeparatorLine(last[0]) && p.isSeparatorLine(last[1])
+}
+
+// mergeLines joins the physical lines of a multi-line row into one logical row
+func (p *UnifiedASCIIParser) mergeLines(block []string, boundaries []int, style TableStyle) []string {
+	parts := make([][]string, len(boundaries)-1)
+	for _, line := range block {
+		for i, cell := range p.parseDataRow(line, boundaries, style) {
+			parts[i] = append(parts[i], cell)
+		}
+	}
+	return joinCellLines(parts)
+}
+
+// psqlLastMarkerColumn returns the display column where psql places the
+// continuation marker of the last column. psql itself pads cells with a space
+// on both sides and puts the marker in the right padding; unpadded tables put
+// it one space after the end of the separator line
+func (p *UnifiedASCIIParser) psqlLastMarkerColumn(lines []string) int {
+	for _, line := range lines {
+		if p.isSeparatorLine(line) {
+			sepWidth := textwidth.String(strings.TrimRight(line, " \t"))
+			if strings.HasPrefix(lines[0], " ") {
+				return sepWidth - 1
+			}
+			return sepWidth + 1
+		}
+	}
+	return -1
+}
+
+// mergePsqlContinuations joins psql rows whose cells continue onto the next line.
+// psql marks a continued cell with a + just before the following |, or for the
+// last column at lastMarker. It also returns the index into block of each
+// row's first line
+func (p *UnifiedASCIIParser) mergePsqlContinuations(block []string, boundaries []int, lastMarker int) ([][]string, []int) {
+	var rows [][]string
+	var starts []int
+	numCols := len(boundaries) - 1
+	parts := make([][]string, numCols)
+
+	for n, line := range block {
+		if len(parts[0]) == 0 {
+			starts = append(starts, n)
+		}
+		cells := p.parseDataRow(line, boundaries, StylePsql)
+		continued := false
+
+		for i, cell := range cells {
+			marker := lastMarker
+			if i < numCols-1 {
+				marker = boundaries[i+1] - 1
+			}
+
+			if marker >= 0 && textwidth.Slice(line, marker, marker+1) == "+" {
+				cell = strings.TrimSpace(strings.TrimSuffix(cell, "+"))
+				continued = true
+			}
+			parts[i] = append(parts[i], cell)
+		}
+
+		if !continued {
+			rows = append(rows, joinCellLines(parts))
+			parts = make([][]string, numCols)
+		}
+	}
+
+	// A trailing continuation marker with no following line still ends the row
+	if len(parts[0]) > 0 {
+		rows = append(rows, joinCellLines(parts))
+	}
+
+	return rows, starts
+}
+
+// joinCellLines joins the lines collected for each cell with newlines,
+// dropping blank lines at the start and end of each cell
+func joinCellLines(parts [][]string) []string {
+	cells := make([]string, len(parts))
+	for i, lines := range parts {
+		start, end := 0, len(lines)
+		for start < end && lines[start] == "" {
+			start++
+		}
+		for end > start && lines[end-1] == "" {
+			end--
+		}
+		cells[i] = strings.Join(lines[start:end], "\n")
+	}
+	return cells
+}
+
+// isFramedStyle reports whether a style draws separator lines between rows
+// that can hold multi-line cells
+func isFramedStyle(style TableStyle) bool {
+	switch style {
+	case StyleBox, StyleRSTGrid, StyleUnicode, StyleDouble, StyleRounded, StyleHeavy:
+		return true
+	}
+	return false
+}
+
+// findColumnBoundaries finds column separator positions as display columns
 func (p *UnifiedASCIIParser) findColumnBoundaries(lines []string, style TableStyle) []int {
 	var maxLen int
 	for _, line := range lines {
-		if len(line) > maxLen {
-			maxLen = len(line)
+		if w := textwidth.String(line); w > maxLen {
+			maxLen = w
 		}
 	}
 
@@ -234,11 +664,7 @@ func (p *UnifiedASCIIParser) findColumnBoundaries(lines []string, style TableSty
 		for _, line := range lines {
 			if p.isSeparatorLine(line) && strings.Contains(line, "+") {
 				boundaries := []int{0}
-				for i, ch := range line {
-					if ch == '+' {
-						boundaries = append(boundaries, i)
-					}
-				}
+				boundaries = append(boundaries, textwidth.Index(line, '+')...)
 				boundaries = append(boundaries, maxLen)
 				return boundaries
 			}
@@ -248,12 +674,7 @@ func (p *UnifiedASCIIParser) findColumnBoundaries(lines []string, style TableSty
 	// For other formats, find | in data rows
 	for _, line := range lines {
 		if !p.isSeparatorLine(line) && strings.Contains(line, "|") {
-			var boundaries []int
-			for i, ch := range line {
-				if ch == '|' {
-					boundaries = append(boundaries, i)
-				}
-			}
+			boundaries := textwidth.Index(line, '|')
 			if len(boundaries) >= 2 {
 				return boundaries
 			}
@@ -263,10 +684,53 @@ func (p *UnifiedASCIIParser) findColumnBoundaries(lines []string, style TableSty
 	return nil
 }
 
+// countRowFields counts the cells of a data line in a framed or psql table.
+// Columns the line stops short of are missing, and every column separator
+// past the last boundary starts an extra cell that parseDataRow would drop
+// or fold into the last column
+func countRowFields(line string, boundaries []int, style TableStyle) int {
+	width := textwidth.String(strings.TrimRight(line, " \t"))
+	isPsqlFormat := style == StylePsql
+
+	fields := 0
+	for i := 0; i < len(boundaries)-1; i++ {
+		start := boundaries[i]
+		if !isPsqlFormat {
+			start++ // Skip the | character
+		}
+		if start < width {
+			fields++
+		}
+	}
+
+	// psql boundaries end at the widest line, so its last separator is the
+	// last +; framed rows end with a right border
+	last := boundaries[len(boundaries)-1]
+	if isPsqlFormat {
+		if len(boundaries) < 3 {
+			return fields
+		}
+		last = boundaries[len(boundaries)-2]
+	}
+	tail := last + 1
+	for _, pos := range textwidth.Index(line, '|') {
+		if pos > last {
+			fields++
+			tail = pos + 1
+		}
+	}
+	// Text after the last border of a framed row is one more cell
+	if !isPsqlFormat && tail < width && strings.TrimSpace(textwidth.Slice(line, tail, width)) != "" {
+		fields++
+	}
+	return fields
+}
+
 // parseDataRow extracts cell values from a data row
 func (p *UnifiedASCIIParser) parseDataRow(line string, boundaries []int, style TableStyle) []string {
 	var cells []string
 	isPsqlFormat := style == StylePsql
+	lineWidth := textwidth.String(line)
 
 	for i := 0; i < len(boundaries)-1; i++ {
 		start := boundaries[i]
@@ -274,12 +738,8 @@ func (p *UnifiedASCIIParser) parseDataRow(line string, boundaries []int, style T
 
 		if isPsqlFormat {
 			// psql format: boundaries mark + positions, extract between them
-			if start < len(line) && end <= len(line) {
-				cell := line[start:end]
-				cell = strings.Trim(cell, "|")
-				cells = append(cells, strings.TrimSpace(cell))
-			} else if start < len(line) {
-				cell := line[start:]
+			if start < lineWidth {
+				cell := textwidth.Slice(line, start, end)
 				cell = strings.Trim(cell, "|")
 				cells = append(cells, strings.TrimSpace(cell))
 			} else {
@@ -288,11 +748,8 @@ func (p *UnifiedASCIIParser) parseDataRow(line string, boundaries []int, style T
 		} else {
 			// Other formats: boundaries mark | positions
 			start++ // Skip the | character
-			if start < len(line) && end <= len(line) {
-				cell := line[start:end]
-				cells = append(cells, strings.TrimSpace(cell))
-			} else if start < len(line) {
-				cell := line[start:]
+			if start < lineWidth {
+				cell := textwidth.Slice(line, start, end)
 				cells = append(cells, strings.TrimSpace(cell))
 			} else {
 				cells = append(cells, "")
@@ -342,6 +799,10 @@ func (p *UnifiedASCIIParser) parseRSTSimple(lines []string) (*model.TableData, e
 	}
 
 	for i := startRow; i < endRow; i++ {
+		fields := countRuleFields(lines[i], colBoundaries)
+		if err := p.checkRow(len(rows)+1, fields, len(colBoundaries), p.sourceLine(i)); err != nil {
+			return nil, err
+		}
 		cells := p.parseRSTSimpleRow(lines[i], colBoundaries)
 		values := make([]model.Value, len(cells))
 		for j, cell := range cells {
@@ -353,45 +814,114 @@ func (p *UnifiedASCIIParser) parseRSTSimple(lines []string) (*model.TableData, e
 	return model.NewTableData(headers, rows), nil
 }
 
+// parseSimple parses a borderless table whose header is underlined with dashes
+func (p *UnifiedASCIIParser) parseSimple(lines []string) (*model.TableData, error) {
+	ruleIndex := -1
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if strings.Trim(trimmed, "- \t") == "" {
+			ruleIndex = i
+			break
+		}
+	}
+
+	if ruleIndex < 1 {
+		return nil, NewParseError("invalid simple table: missing header line above the rule")
+	}
+
+	colBoundaries := p.findRuleColumns(lines[ruleIndex], '-')
+	if len(colBoundaries) == 0 {
+		return nil, NewParseError("invalid simple table: cannot detect columns")
+	}
+	// The last column may hold text wider than its rule
+	colBoundaries[len(colBoundaries)-1][1] = math.MaxInt
+
+	headers := p.parseRSTSimpleRow(lines[ruleIndex-1], colBoundaries)
+
+	var rows [][]model.Value
+	for i, line := range lines[ruleIndex+1:] {
+		trimmed := strings.TrimSpace(line)
+		if strings.Trim(trimmed, "- \t") == "" {
+			continue
+		}
+		fields := countRuleFields(line, colBoundaries)
+		if err := p.checkRow(len(rows)+1, fields, len(colBoundaries), p.sourceLine(ruleIndex+1+i)); err != nil {
+			return nil, err
+		}
+		cells := p.parseRSTSimpleRow(line, colBoundaries)
+		values := make([]model.Value, len(cells))
+		for j, cell := range cells {
+			values[j] = model.NewValue(cell)
+		}
+		rows = append(rows, values)
+	}
+
+	return model.NewTableData(headers, rows), nil
+}
+
 // findRSTSimpleColumns finds column boundaries from = separator line
 func (p *UnifiedASCIIParser) findRSTSimpleColumns(sepLine string) [][]int {
-	// Find sequences of = characters
+	return p.findRuleColumns(sepLine, '=')
+}
+
+// findRuleColumns finds column boundaries from runs of fill characters in a rule line
+func (p *UnifiedASCIIParser) findRuleColumns(sepLine string, fill rune) [][]int {
+	// Find sequences of fill characters, measured in display columns
 	var columns [][]int
 	inColumn := false
 	start := 0
+	col := 0
 
-	for i, ch := range sepLine {
-		if ch == '=' {
+	for _, ch := range sepLine {
+		if ch == fill {
 			if !inColumn {
-				start = i
+				start = col
 				inColumn = true
 			}
 		} else {
 			if inColumn {
-				columns = append(columns, []int{start, i})
+				columns = append(columns, []int{start, col})
 				inColumn = false
 			}
 		}
+		col += textwidth.Rune(ch)
 	}
 
 	if inColumn {
-		columns = append(columns, []int{start, len(sepLine)})
+		columns = append(columns, []int{start, col})
 	}
 
 	return columns
 }
 
+// countRuleFields counts the cells of a line in a table whose columns are
+// marked by a rule: one per column, plus one for each gap between or after
+// the columns that holds text parseRSTSimpleRow would drop
+func countRuleFields(line string, colBoundaries [][]int) int {
+	fields := len(colBoundaries)
+	width := textwidth.String(line)
+	for i, bounds := range colBoundaries {
+		end := width
+		if i+1 < len(colBoundaries) {
+			end = colBoundaries[i+1][0]
+		}
+		if bounds[1] < end && strings.TrimSpace(textwidth.Slice(line, bounds[1], end)) != "" {
+			fields++
+		}
+	}
+	return fields
+}
+
 // parseRSTSimpleRow extracts cells from an RST simple table row
 func (p *UnifiedASCIIParser) parseRSTSimpleRow(line string, colBoundaries [][]int) []string {
 	var cells []string
 
+	lineWidth := textwidth.String(line)
+
 	for _, bounds := range colBoundaries {
 		start, end := bounds[0], bounds[1]
-		if start < len(line) {
-			if end > len(line) {
-				end = len(line)
-			}
-			cell := line[start:end]
+		if start < lineWidth {
+			cell := textwidth.Slice(line, start, end)
 			cells = append(cells, strings.TrimSpace(cell))
 		} else {
 			cells = append(cells, "")
@@ -400,3 +930,63 @@ func (p *UnifiedASCIIParser) parseRSTSimpleRow(line string, colBoundaries [][]in
 
 	return cells
 }
+
+// detectBoxDrawingStyle returns the Unicode box style used by the table,
+// or an empty style if the table only uses ASCII borders
+func detectBoxDrawingStyle(lines []string) TableStyle {
+	var found, rounded, double, heavy bool
+	for _, line := range lines {
+		for _, ch := range line {
+			if !isBoxDrawing(ch) {
+				continue
+			}
+			found = true
+			switch ch {
+			case '╭', '╮', '╰', '╯':
+				rounded = true
+			case '═', '║', '╔', '╗', '╚', '╝', '╠', '╣', '╦', '╩', '╬':
+				double = true
+			case '━', '┃', '┏', '┓', '┗', '┛', '┣', '┫', '┳', '┻', '╋':
+				heavy = true
+			}
+		}
+	}
+
+	switch {
+	case !found:
+		return ""
+	case rounded:
+		return StyleRounded
+	case double:
+		return StyleDouble
+	case heavy:
+		return StyleHeavy
+	default:
+		return StyleUnicode
+	}
+}
+
+// isBoxDrawing reports whether ch is in the Unicode box-drawing block
+func isBoxDrawing(ch rune) bool {
+	return ch >= 0x2500 && ch <= 0x257F
+}
+
+// normalizeBoxDrawing replaces Unicode box-drawing characters with their
+// ASCII equivalents so the table can be parsed by the pipe-based parser
+func normalizeBoxDrawing(line string) string {
+	return strings.Map(func(ch rune) rune {
+		if !isBoxDrawing(ch) {
+			return ch
+		}
+		switch ch {
+		case '─', '━', '┄', '┅', '┈', '┉', '╌', '╍':
+			return '-'
+		case '═':
+			return '='
+		case '│', '┃', '║', '┆', '┇', '┊', '┋', '╎', '╏':
+			return '|'
+		default:
+			return '+'
+		}
+	}, line)
+}
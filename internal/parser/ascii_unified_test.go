@@ -3,6 +3,8 @@ package parser
 import (
 	"strings"
 	"testing"
+
+	"github.com/user/table-converter/internal/model"
 )
 
 // TestUnifiedASCIIParser_AllFormats tests parsing of all supported ASCII table formats
@@ -80,6 +82,74 @@ Bob    25   London
 			expectedRows:  2,
 			expectedCols:  3,
 		},
+		{
+			name: "unicode single-line format",
+			input: `┌───────┬─────┬──────────┐
+│ Name  │ Age │ City     │
+├───────┼─────┼──────────┤
+│ Alice │ 30  │ New York │
+│ Bob   │ 25  │ London   │
+└───────┴─────┴──────────┘`,
+			expectedStyle: StyleUnicode,
+			expectedRows:  2,
+			expectedCols:  3,
+		},
+		{
+			name: "unicode double-line format",
+			input: `╔═══════╦═════╦══════════╗
+║ Name  ║ Age ║ City     ║
+╠═══════╬═════╬══════════╣
+║ Alice ║ 30  ║ New York ║
+║ Bob   ║ 25  ║ London   ║
+╚═══════╩═════╩══════════╝`,
+			expectedStyle: StyleDouble,
+			expectedRows:  2,
+			expectedCols:  3,
+		},
+		{
+			name: "unicode rounded format",
+			input: `╭───────┬─────┬──────────╮
+│ Name  │ Age │ City     │
+├───────┼─────┼──────────┤
+│ Alice │ 30  │ New York │
+│ Bob   │ 25  │ London   │
+╰───────┴─────┴──────────╯`,
+			expectedStyle: StyleRounded,
+			expectedRows:  2,
+			expectedCols:  3,
+		},
+		{
+			name: "unicode heavy format",
+			input: `┏━━━━━━━┳━━━━━┳━━━━━━━━━━┓
+┃ Name  ┃ Age ┃ City     ┃
+┣━━━━━━━╋━━━━━╋━━━━━━━━━━┫
+┃ Alice ┃ 30  ┃ New York ┃
+┃ Bob   ┃ 25  ┃ London   ┃
+┗━━━━━━━┻━━━━━┻━━━━━━━━━━┛`,
+			expectedStyle: StyleHeavy,
+			expectedRows:  2,
+			expectedCols:  3,
+		},
+		{
+			name: "psql unicode linestyle",
+			input: ` Name  │ Age │ City
+───────┼─────┼──────────
+ Alice │ 30  │ New York
+ Bob   │ 25  │ London`,
+			expectedStyle: StylePsql,
+			expectedRows:  2,
+			expectedCols:  3,
+		},
+		{
+			name: "simple borderless format",
+			input: `Name   Age  City
+-----  ---  --------
+Alice  30   New York
+Bob    25   London`,
+			expectedStyle: StyleSimple,
+			expectedRows:  2,
+			expectedCols:  3,
+		},
 	}
 
 	for _, tt := range tests {
@@ -149,3 +219,164 @@ func TestUnifiedASCIIParser_EmptyTable(t *testing.T) {
 		t.Errorf("expected 0 rows, got %d", len(td.Rows))
 	}
 }
+
+// TestUnifiedASCIIParser_MultiLineCells tests merging continuation lines into logical rows
+func TestUnifiedASCIIParser_MultiLineCells(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected [][]string
+	}{
+		{
+			name: "RST grid with multi-line cells",
+			input: `+-------+-------------+
+| Name  | Note        |
++=======+=============+
+| Alice | first line  |
+|       | second line |
++-------+-------------+
+| Bob   | single      |
++-------+-------------+`,
+			expected: [][]string{{"Alice", "first line\nsecond line"}, {"Bob", "single"}},
+		},
+		{
+			name: "box with multi-line header and cells",
+			input: `+-------+--------+
+| Name  | Home   |
+|       | town   |
++-------+--------+
+| Alice | Paris  |
+| Smith |        |
++-------+--------+
+| Bob   | Rome   |
++-------+--------+`,
+			expected: [][]string{{"Alice\nSmith", "Paris"}, {"Bob", "Rome"}},
+		},
+		{
+			name: "box without row separators keeps one row per line",
+			input: `+-------+-------+
+| Name  | Town  |
++-------+-------+
+| Alice | Paris |
+| Bob   | Rome  |
++-------+-------+`,
+			expected: [][]string{{"Alice", "Paris"}, {"Bob", "Rome"}},
+		},
+		{
+			name: "psql continuation markers",
+			input: ` name  | note
+-------+--------
+ Alice | one   +
+       | two
+ Bob   | three`,
+			expected: [][]string{{"Alice", "one\ntwo"}, {"Bob", "three"}},
+		},
+		{
+			name: "markdown line breaks",
+			input: `| Name  | Note           |
+|-------|----------------|
+| Alice | one<br>two     |
+| Bob   | three<br/>four |`,
+			expected: [][]string{{"Alice", "one\ntwo"}, {"Bob", "three\nfour"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			td, err := NewUnifiedASCIIParser().Parse(strings.NewReader(tt.input))
+			if err != nil {
+				t.Fatalf("failed to parse: %v", err)
+			}
+
+			if len(td.Rows) != len(tt.expected) {
+				t.Fatalf("expected %d rows, got %d", len(tt.expected), len(td.Rows))
+			}
+			for i, row := range tt.expected {
+				for j, want := range row {
+					if got := td.Rows[i][j].Raw; got != want {
+						t.Errorf("row %d, col %d: expected %q, got %q", i, j, want, got)
+					}
+				}
+			}
+		})
+	}
+}
+
+// TestUnifiedASCIIParser_MarkdownAlignment tests reading GFM alignment markers
+func TestUnifiedASCIIParser_MarkdownAlignment(t *testing.T) {
+	input := `| Left | Center | Right | Plain |
+|:-----|:------:|------:|-------|
+| a    |   b    |     1 | d     |`
+
+	td, err := NewUnifiedASCIIParser().Parse(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("failed to parse: %v", err)
+	}
+
+	expected := []model.Alignment{model.AlignLeft, model.AlignCenter, model.AlignRight, model.AlignDefault}
+	if len(td.Alignments) != len(expected) {
+		t.Fatalf("expected %d alignments, got %v", len(expected), td.Alignments)
+	}
+	for i, want := range expected {
+		if td.Alignments[i] != want {
+			t.Errorf("column %d: expected alignment %v, got %v", i, want, td.Alignments[i])
+		}
+	}
+
+	// Tables without markers have no alignment metadata
+	plain, err := NewUnifiedASCIIParser().Parse(strings.NewReader("| a |\n|---|\n| b |"))
+	if err != nil {
+		t.Fatalf("failed to parse: %v", err)
+	}
+	if plain.Alignments != nil {
+		t.Errorf("expected nil alignments, got %v", plain.Alignments)
+	}
+}
+
+// TestUnifiedASCIIParser_MisalignedRows tests hand-edited tables whose pipes do not line up
+func TestUnifiedASCIIParser_MisalignedRows(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		style TableStyle
+	}{
+		{
+			name: "markdown",
+			input: `| Name | Notes |
+|---|---|
+| Alice Smith | likes \| pipes |
+|Bob|`,
+			style: StyleMarkdown,
+		},
+		{
+			name: "org",
+			input: `| Name | Notes |
+|---+---|
+| Alice Smith | likes \| pipes |
+|Bob|`,
+			style: StyleOrgMode,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewUnifiedASCIIParser()
+			td, err := p.Parse(strings.NewReader(tt.input))
+			if err != nil {
+				t.Fatalf("failed to parse: %v", err)
+			}
+			if p.DetectedStyle != tt.style {
+				t.Errorf("expected style %s, got %s", tt.style, p.DetectedStyle)
+			}
+			if len(td.Rows) != 2 {
+				t.Fatalf("expected 2 rows, got %d", len(td.Rows))
+			}
+			if td.Rows[0][0].Raw != "Alice Smith" {
+				t.Errorf("expected 'Alice Smith', got %q", td.Rows[0][0].Raw)
+			}
+			if td.Rows[1][0].Raw != "Bob" || td.Rows[1][1].Raw != "" {
+				t.Errorf("expected short row to be padded, got %v", td.Rows[1])
+			}
+		})
+	}
+}
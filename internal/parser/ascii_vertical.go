@@ -0,0 +1,244 @@
+package parser
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/user/table-converter/internal/model"
+	"github.com/user/table-converter/internal/textwidth"
+)
+
+var (
+	// psqlRecordHeader matches the record header of psql expanded display (\x)
+	psqlRecordHeader = regexp.MustCompile(`^[-+]*\[ RECORD \d+ \][-+]*$`)
+	// mysqlRowHeader matches the row header of MySQL vertical output (\G)
+	mysqlRowHeader = regexp.MustCompile(`^\*+ \d+\. row \*+$`)
+	// queryFooter matches the row counts database clients print below results
+	queryFooter = regexp.MustCompile(`^\(\d+ rows?\)$|^\d+ rows? in set\b|^Empty set\b`)
+)
+
+// verticalField is one name/value pair of a record shown one field per line
+type verticalField struct {
+	name  string
+	value string
+	null  bool // The client printed its NULL marker
+}
+
+// isPsqlExpanded checks for psql expanded display, which starts with a record header
+func (p *UnifiedASCIIParser) isPsqlExpanded(lines []string) bool {
+	return psqlRecordHeader.MatchString(strings.TrimSpace(lines[0]))
+}
+
+// isMySQLVertical checks for MySQL vertical output, which starts with a row header
+func (p *UnifiedASCIIParser) isMySQLVertical(lines []string) bool {
+	return mysqlRowHeader.MatchString(strings.TrimSpace(lines[0]))
+}
+
+// isSQLiteLine checks for SQLite .mode line output, where each field is a
+// right-aligned column name and its value separated by " = ". Values with
+// line breaks continue on lines of their own, so most lines must be fields
+func (p *UnifiedASCIIParser) isSQLiteLine(lines []string) bool {
+	sepCol := sqliteSeparatorColumn(lines[0])
+	if sepCol <= 0 {
+		return false
+	}
+	fields := 0
+	for _, line := range lines {
+		if sqliteSeparatorColumn(line) == sepCol {
+			fields++
+		}
+	}
+	return fields*2 > len(lines)
+}
+
+// sqliteSeparatorColumn returns the display column of the first " = " in a
+// line, or -1 if there is none
+func sqliteSeparatorColumn(line string) int {
+	idx := sqliteSeparatorIndex(line)
+	if idx < 0 {
+		return -1
+	}
+	return textwidth.String(line[:idx])
+}
+
+// sqliteSeparatorIndex returns the byte index of the " = " after a column
+// name, or -1 if there is none. An empty value leaves a bare " =" at the end
+func sqliteSeparatorIndex(line string) int {
+	idx := strings.Index(line, " = ")
+	if idx < 0 {
+		trimmed := strings.TrimRight(line, " ")
+		if !strings.HasSuffix(trimmed, " =") {
+			return -1
+		}
+		idx = len(trimmed) - 2
+	}
+	if strings.TrimSpace(line[:idx]) == "" || strings.ContainsAny(line[:idx], "|+") {
+		return -1
+	}
+	return idx
+}
+
+// stripQueryFooter drops the row count lines database clients print after a result
+func stripQueryFooter(lines []string) []string {
+	for len(lines) > 0 && queryFooter.MatchString(strings.TrimSpace(lines[len(lines)-1])) {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// parsePsqlExpanded parses psql expanded display:
+//
+//	-[ RECORD 1 ]-
+//	id   | 1
+//	name | Alice
+//
+// A value ending in + continues on the next line, which has an empty name
+func (p *UnifiedASCIIParser) parsePsqlExpanded(lines []string) (*model.TableData, error) {
+	var records [][]verticalField
+
+	for _, line := range lines {
+		if psqlRecordHeader.MatchString(strings.TrimSpace(line)) {
+			records = append(records, nil)
+			continue
+		}
+		if len(records) == 0 {
+			return nil, NewParseError("invalid psql expanded output: missing record header").WithContext(line)
+		}
+
+		idx := strings.Index(line, "|")
+		if idx < 0 {
+			return nil, NewParseError("invalid psql expanded output: missing | separator").WithContext(line)
+		}
+		name := strings.TrimSpace(line[:idx])
+		value := strings.TrimSpace(line[idx+1:])
+
+		record := records[len(records)-1]
+		if name == "" && len(record) > 0 && strings.HasSuffix(record[len(record)-1].value, "+") {
+			last := &record[len(record)-1]
+			last.value = strings.TrimSpace(strings.TrimSuffix(last.value, "+")) + "\n" + value
+			continue
+		}
+		records[len(records)-1] = append(record, verticalField{name: name, value: value})
+	}
+
+	return buildVerticalTable(records), nil
+}
+
+// parseMySQLVertical parses MySQL vertical output (\G):
+//
+//	*************************** 1. row ***************************
+//	  id: 1
+//	name: Alice
+//
+// Names are right-aligned, so lines without a colon at the record's colon
+// column continue the previous value. NULL is read as a null value
+func (p *UnifiedASCIIParser) parseMySQLVertical(lines []string) (*model.TableData, error) {
+	var records [][]verticalField
+	colonCol := -1
+
+	for _, line := range lines {
+		if mysqlRowHeader.MatchString(strings.TrimSpace(line)) {
+			records = append(records, nil)
+			colonCol = -1
+			continue
+		}
+		if len(records) == 0 {
+			return nil, NewParseError("invalid MySQL vertical output: missing row header").WithContext(line)
+		}
+
+		record := records[len(records)-1]
+		if colonCol < 0 {
+			colonCol = strings.Index(line, ":")
+			if colonCol < 0 {
+				return nil, NewParseError("invalid MySQL vertical output: missing : separator").WithContext(line)
+			}
+		}
+
+		if colonCol >= len(line) || line[colonCol] != ':' || strings.TrimSpace(line[:colonCol]) == "" {
+			if len(record) == 0 {
+				return nil, NewParseError("invalid MySQL vertical output: missing : separator").WithContext(line)
+			}
+			record[len(record)-1].value += "\n" + strings.TrimSpace(line)
+			continue
+		}
+
+		value := strings.TrimSpace(line[colonCol+1:])
+		records[len(records)-1] = append(record, verticalField{
+			name:  strings.TrimSpace(line[:colonCol]),
+			value: value,
+			null:  value == "NULL",
+		})
+	}
+
+	return buildVerticalTable(records), nil
+}
+
+// parseSQLiteLine parses SQLite .mode line output:
+//
+//	  id = 1
+//	name = Alice
+//
+// Records are separated by blank lines in the original output; as blank
+// lines are dropped on reading, a record ends when its first name repeats.
+// Lines that are not fields at the separator column continue the previous value
+func (p *UnifiedASCIIParser) parseSQLiteLine(lines []string) (*model.TableData, error) {
+	var records [][]verticalField
+	sepCol := sqliteSeparatorColumn(lines[0])
+	firstName := ""
+
+	for _, line := range lines {
+		if sqliteSeparatorColumn(line) != sepCol {
+			record := records[len(records)-1]
+			record[len(record)-1].value += "\n" + line
+			continue
+		}
+
+		idx := sqliteSeparatorIndex(line)
+		name := strings.TrimSpace(line[:idx])
+		value := strings.TrimSpace(line[idx+2:])
+
+		if len(records) == 0 || name == firstName {
+			if firstName == "" {
+				firstName = name
+			}
+			records = append(records, nil)
+		}
+		records[len(records)-1] = append(records[len(records)-1], verticalField{name: name, value: value})
+	}
+
+	return buildVerticalTable(records), nil
+}
+
+// buildVerticalTable turns records into a table whose columns are the field
+// names in order of first appearance. Fields missing from a record are null
+func buildVerticalTable(records [][]verticalField) *model.TableData {
+	var headers []string
+	index := make(map[string]int)
+	for _, record := range records {
+		for _, field := range record {
+			if _, ok := index[field.name]; !ok {
+				index[field.name] = len(headers)
+				headers = append(headers, field.name)
+			}
+		}
+	}
+
+	rows := make([][]model.Value, len(records))
+	for i, record := range records {
+		row := make([]model.Value, len(headers))
+		for j := range row {
+			row[j] = model.NewNullValue()
+		}
+		for _, field := range record {
+			if !field.null {
+				row[index[field.name]] = model.NewValue(field.value)
+			}
+		}
+		rows[i] = row
+	}
+
+	if headers == nil {
+		headers = []string{}
+	}
+	return model.NewTableData(headers, rows)
+}
@@ -0,0 +1,172 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/user/table-converter/internal/model"
+)
+
+func TestUnifiedASCIIParser_VerticalStyles(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		style    TableStyle
+		headers  []string
+		expected [][]string
+	}{
+		{
+			name: "psql expanded",
+			input: `-[ RECORD 1 ]-----
+id   | 1
+name | Alice
+note | first+
+     | second
+-[ RECORD 2 ]-----
+id   | 2
+name | Bob Smith
+note |
+`,
+			style:    StylePsqlExpanded,
+			headers:  []string{"id", "name", "note"},
+			expected: [][]string{{"1", "Alice", "first\nsecond"}, {"2", "Bob Smith", ""}},
+		},
+		{
+			name: "psql expanded with separator mark",
+			input: `-[ RECORD 1 ]----------+------
+id                     | 7
+a_rather_long_name     | x
+`,
+			style:    StylePsqlExpanded,
+			headers:  []string{"id", "a_rather_long_name"},
+			expected: [][]string{{"7", "x"}},
+		},
+		{
+			name: "mysql vertical",
+			input: `*************************** 1. row ***************************
+  id: 1
+name: Alice
+note: line one
+line two
+*************************** 2. row ***************************
+  id: 2
+name: Bob Smith
+note: NULL
+2 rows in set (0.00 sec)
+`,
+			style:    StyleMySQLVertical,
+			headers:  []string{"id", "name", "note"},
+			expected: [][]string{{"1", "Alice", "line one\nline two"}, {"2", "Bob Smith", ""}},
+		},
+		{
+			name: "sqlite line",
+			input: `  id = 1
+name = Alice
+note = a = b
+
+  id = 2
+name = Bob Smith
+note =
+`,
+			style:    StyleSQLiteLine,
+			headers:  []string{"id", "name", "note"},
+			expected: [][]string{{"1", "Alice", "a = b"}, {"2", "Bob Smith", ""}},
+		},
+		{
+			name: "sqlite column mode",
+			input: `id  name
+--  ---------
+1   Alice
+2   Bob Smith
+`,
+			style:    StyleSimple,
+			headers:  []string{"id", "name"},
+			expected: [][]string{{"1", "Alice"}, {"2", "Bob Smith"}},
+		},
+		{
+			name: "sqlite box mode",
+			input: `┌────┬───────────┐
+│ id │   name    │
+├────┼───────────┤
+│ 1  │ Alice     │
+│ 2  │ Bob Smith │
+└────┴───────────┘
+`,
+			style:    StyleUnicode,
+			headers:  []string{"id", "name"},
+			expected: [][]string{{"1", "Alice"}, {"2", "Bob Smith"}},
+		},
+		{
+			name: "psql footer",
+			input: ` id | name
+----+-------
+  1 | Alice
+(1 row)
+`,
+			style:    StylePsql,
+			headers:  []string{"id", "name"},
+			expected: [][]string{{"1", "Alice"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewUnifiedASCIIParser()
+			td, err := p.Parse(strings.NewReader(tt.input))
+			if err != nil {
+				t.Fatalf("Parse failed: %v", err)
+			}
+			if p.DetectedStyle != tt.style {
+				t.Errorf("detected style %q, want %q", p.DetectedStyle, tt.style)
+			}
+			if strings.Join(td.Headers, ",") != strings.Join(tt.headers, ",") {
+				t.Errorf("headers = %v, want %v", td.Headers, tt.headers)
+			}
+			if len(td.Rows) != len(tt.expected) {
+				t.Fatalf("expected %d rows, got %d", len(tt.expected), len(td.Rows))
+			}
+			for i, row := range tt.expected {
+				for j, want := range row {
+					if got := td.Rows[i][j].Raw; got != want {
+						t.Errorf("row %d col %d = %q, want %q", i, j, got, want)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestUnifiedASCIIParser_VerticalMissingFields(t *testing.T) {
+	input := `-[ RECORD 1 ]
+id   | 1
+name | Alice
+-[ RECORD 2 ]
+id   | 2
+city | Paris
+`
+	td, err := NewUnifiedASCIIParser().Parse(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	if strings.Join(td.Headers, ",") != "id,name,city" {
+		t.Fatalf("unexpected headers: %v", td.Headers)
+	}
+	if td.Rows[0][2].Type != model.TypeNull || td.Rows[1][1].Type != model.TypeNull {
+		t.Errorf("missing fields should be null: %v", td.Rows)
+	}
+}
+
+func TestUnifiedASCIIParser_VerticalErrors(t *testing.T) {
+	inputs := map[string]string{
+		"psql field without separator": "-[ RECORD 1 ]\nid   | 1\nname Alice\n",
+		"mysql field without colon":    "*************************** 1. row ***************************\nname Alice\n",
+	}
+	for name, input := range inputs {
+		t.Run(name, func(t *testing.T) {
+			if _, err := NewUnifiedASCIIParser().Parse(strings.NewReader(input)); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
@@ -0,0 +1,103 @@
+package parser
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/user/table-converter/internal/model"
+	"github.com/user/table-converter/internal/serializer"
+	"github.com/user/table-converter/internal/textwidth"
+	"pgregory.net/rapid"
+)
+
+// mixedScriptFragments mixes ASCII, accented Latin (precomposed and combining),
+// CJK, Hangul, fullwidth forms, Greek and emoji
+var mixedScriptFragments = []string{
+	"abc", "Zoë", "cafe\u0301", "日本語", "東京", "한국어", "ＡＢＣ", "Ωμέγα", "🎉", "👍🏽", "x1",
+}
+
+// Feature: table-converter, Property: Display-Width Alignment (ASCII)
+//
+// Property: For any table containing mixed-script text, every line of a
+// serialized ASCII table has the same display width, and parsing the output
+// back yields the original cell text.
+func TestProperty_ASCIIMixedScriptRoundTrip(t *testing.T) {
+	styles := []string{"box", "md", "org", "psql", "rst-grid", "rst-simple", "unicode", "double", "simple"}
+
+	rapid.Check(t, func(t *rapid.T) {
+		td := generateMixedScriptTable(t)
+		style := rapid.SampledFrom(styles).Draw(t, "style")
+
+		s := serializer.NewUnifiedASCIISerializer("")
+		if err := s.SetStyle(style); err != nil {
+			t.Fatalf("SetStyle(%q) failed: %v", style, err)
+		}
+
+		var buf bytes.Buffer
+		if err := s.Serialize(td, &buf); err != nil {
+			t.Fatalf("failed to serialize: %v", err)
+		}
+		output := buf.String()
+
+		// Property: all lines have the same display width
+		lines := strings.Split(strings.TrimRight(output, "\n"), "\n")
+		expectedWidth := textwidth.String(lines[0])
+		for i, line := range lines {
+			if w := textwidth.String(line); w != expectedWidth {
+				t.Fatalf("line %d has width %d, expected %d:\n%s", i, w, expectedWidth, output)
+			}
+		}
+
+		// Property: parsing back preserves headers and cell text
+		parsed, err := NewUnifiedASCIIParser().Parse(strings.NewReader(output))
+		if err != nil {
+			t.Fatalf("failed to parse %s output: %v\n%s", style, err, output)
+		}
+
+		for i, header := range td.Headers {
+			if parsed.Headers[i] != header {
+				t.Fatalf("header %d mismatch: expected %q, got %q\n%s", i, header, parsed.Headers[i], output)
+			}
+		}
+
+		if len(parsed.Rows) != len(td.Rows) {
+			t.Fatalf("row count mismatch: expected %d, got %d\n%s", len(td.Rows), len(parsed.Rows), output)
+		}
+		for i, row := range td.Rows {
+			for j, val := range row {
+				if parsed.Rows[i][j].Raw != val.Raw {
+					t.Fatalf("cell [%d][%d] mismatch: expected %q, got %q\n%s",
+						i, j, val.Raw, parsed.Rows[i][j].Raw, output)
+				}
+			}
+		}
+	})
+}
+
+// generateMixedScriptTable creates a table whose cells combine mixed-script fragments
+func generateMixedScriptTable(t *rapid.T) *model.TableData {
+	numCols := rapid.IntRange(1, 5).Draw(t, "numCols")
+	headers := make([]string, numCols)
+	for i := range headers {
+		headers[i] = generateMixedScriptText(t, "header")
+	}
+
+	numRows := rapid.IntRange(1, 10).Draw(t, "numRows")
+	rows := make([][]model.Value, numRows)
+	for i := range rows {
+		row := make([]model.Value, numCols)
+		for j := range row {
+			row[j] = model.NewStringValue(generateMixedScriptText(t, "cell"))
+		}
+		rows[i] = row
+	}
+
+	return model.NewTableData(headers, rows)
+}
+
+// generateMixedScriptText joins one to three fragments with single spaces
+func generateMixedScriptText(t *rapid.T, label string) string {
+	parts := rapid.SliceOfN(rapid.SampledFrom(mixedScriptFragments), 1, 3).Draw(t, label)
+	return strings.Join(parts, " ")
+}
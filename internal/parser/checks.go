@@ -0,0 +1,126 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Warning reports a problem in the input that the parser worked around,
+// such as a short row that was padded with nulls
+type Warning struct {
+	// Line is the 1-based input line of the problem (0 if unknown)
+	Line int
+	// Message describes the problem and what was done about it
+	Message string
+}
+
+// String returns the warning with its line number, if known
+func (w Warning) String() string {
+	if w.Line > 0 {
+		return fmt.Sprintf("line %d: %s", w.Line, w.Message)
+	}
+	return w.Message
+}
+
+// RowChecker checks the headers and row widths of a parsed table. In strict
+// mode ragged rows and duplicate or empty headers are parse errors;
+// otherwise every row that is padded or truncated to the header width is
+// reported to Warn. Parsers embed it to offer SetStrict and
+// SetWarningHandler
+type RowChecker struct {
+	// Strict rejects malformed tables instead of repairing them
+	Strict bool
+	// Warn receives a warning for each repaired row (nil = discard)
+	Warn func(Warning)
+}
+
+// SetStrict turns strict mode on or off
+func (c *RowChecker) SetStrict(strict bool) {
+	c.Strict = strict
+}
+
+// SetWarningHandler sets the function that receives warnings; nil discards them
+func (c *RowChecker) SetWarningHandler(warn func(Warning)) {
+	c.Warn = warn
+}
+
+// checkHeaders rejects empty and duplicate headers in strict mode. line is
+// the input line of the header row (0 if unknown)
+func (c *RowChecker) checkHeaders(headers []string, line int) error {
+	if !c.Strict {
+		return nil
+	}
+
+	seen := make(map[string]int, len(headers))
+	for i, header := range headers {
+		name := strings.TrimSpace(header)
+		if name == "" {
+			return lineError(fmt.Sprintf("empty header in column %d", i+1), line)
+		}
+		if first, ok := seen[name]; ok {
+			return lineError(fmt.Sprintf("duplicate header %q in columns %d and %d", name, first+1, i+1), line)
+		}
+		seen[name] = i
+	}
+	return nil
+}
+
+// checkRow compares the number of fields in a data row with the header
+// width. row is the 1-based data row and line its input line (0 if unknown)
+func (c *RowChecker) checkRow(row, fields, width, line int) error {
+	if fields == width {
+		return nil
+	}
+
+	noun := "fields"
+	if fields == 1 {
+		noun = "field"
+	}
+	message := fmt.Sprintf("row %d has %d %s, expected %d", row, fields, noun, width)
+	if c.Strict {
+		return lineError(message, line)
+	}
+	if c.Warn != nil {
+		if fields < width {
+			message += "; padded with nulls"
+		} else {
+			message += "; extra fields dropped"
+		}
+		c.Warn(Warning{Line: line, Message: message})
+	}
+	return nil
+}
+
+// checkDuplicateField reports a field repeated within one record, as in a
+// JSON object or XML record with the same key twice. The last value is kept
+func (c *RowChecker) checkDuplicateField(row int, name string, line int) error {
+	message := fmt.Sprintf("row %d has duplicate field %q", row, name)
+	if c.Strict {
+		return lineError(message, line)
+	}
+	if c.Warn != nil {
+		c.Warn(Warning{Line: line, Message: message + "; last value kept"})
+	}
+	return nil
+}
+
+// checkExtraText reports text past the last column of a fixed-width row,
+// which is dropped
+func (c *RowChecker) checkExtraText(row, line int) error {
+	message := fmt.Sprintf("row %d has text past the last column", row)
+	if c.Strict {
+		return lineError(message, line)
+	}
+	if c.Warn != nil {
+		c.Warn(Warning{Line: line, Message: message + "; dropped"})
+	}
+	return nil
+}
+
+// lineError creates a ParseError with the line number, if known
+func lineError(message string, line int) *ParseError {
+	if line > 0 {
+		return NewParseErrorWithLine(message, line)
+	}
+	return NewParseError(message)
+}
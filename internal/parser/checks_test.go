@@ -0,0 +1,171 @@
+package parser
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/user/table-converter/internal/model"
+	"github.com/xuri/excelize/v2"
+)
+
+func TestCSVParser_RaggedRowWarnings(t *testing.T) {
+	var warnings []Warning
+	p := NewCSVParser()
+	p.SetWarningHandler(func(w Warning) { warnings = append(warnings, w) })
+
+	td, err := p.Parse(strings.NewReader("a,b,c\n1,2\n\"x\ny\",4,5,6\n7,8,9\n"))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if len(td.Rows) != 3 {
+		t.Fatalf("got %d rows, want 3", len(td.Rows))
+	}
+
+	want := []Warning{
+		{Line: 2, Message: "row 1 has 2 fields, expected 3; padded with nulls"},
+		{Line: 3, Message: "row 2 has 4 fields, expected 3; extra fields dropped"},
+	}
+	if len(warnings) != len(want) {
+		t.Fatalf("got warnings %v, want %v", warnings, want)
+	}
+	for i := range want {
+		if warnings[i] != want[i] {
+			t.Errorf("warning %d = %v, want %v", i, warnings[i], want[i])
+		}
+	}
+}
+
+func TestStrictMode(t *testing.T) {
+	tests := []struct {
+		name     string
+		parser   interface{ SetStrict(bool) }
+		input    string
+		wantLine int
+		wantMsg  string
+	}{
+		{"csv short row", NewCSVParser(), "a,b\n1,2\n3\n", 3, "row 2 has 1 field, expected 2"},
+		{"csv long row after skipped lines", &CSVParser{Dialect: model.CSVDialect{SkipLines: 2}}, "x\ny\na,b\n1,2,3\n", 4, "row 1 has 3 fields, expected 2"},
+		{"csv duplicate header", NewCSVParser(), "id,name,id\n1,a,2\n", 1, `duplicate header "id" in columns 1 and 3`},
+		{"csv empty header", NewCSVParser(), "id, ,name\n1,2,3\n", 1, "empty header in column 2"},
+		{"markdown ragged row", NewUnifiedASCIIParser(), "| a | b |\n|---|---|\n| 1 |\n", 3, "row 1 has 1 field, expected 2"},
+		{"markdown row after blank line", NewUnifiedASCIIParser(), "| a | b |\n|---|---|\n| 1 | 2 |\n\n| 3 | 4 | 5 |\n", 5, "row 2 has 3 fields, expected 2"},
+		{"box extra cell", NewUnifiedASCIIParser(), "+---+---+\n| a | b |\n+---+---+\n| 1 | 2 | 3 |\n+---+---+\n", 4, "row 1 has 3 fields, expected 2"},
+		{"box short row", NewUnifiedASCIIParser(), "+---+---+\n| a | b |\n+---+---+\n| 1 |\n+---+---+\n", 4, "row 1 has 1 field, expected 2"},
+		{"box multi-line row", NewUnifiedASCIIParser(), "+---+---+\n| a | b |\n+===+===+\n| 1 | 2 |\n| x | y | z |\n+---+---+\n| 3 | 4 |\n+---+---+\n", 5, "row 1 has 3 fields, expected 2"},
+		{"unicode extra cell", NewUnifiedASCIIParser(), "┌───┬───┐\n│ a │ b │\n├───┼───┤\n│ 1 │ 2 │ 3 │\n└───┴───┘\n", 4, "row 1 has 3 fields, expected 2"},
+		{"psql extra cell", NewUnifiedASCIIParser(), " a | b \n---+---\n 1 | 2\n 3 | 4 | 5\n", 4, "row 2 has 3 fields, expected 2"},
+		{"rst simple text past the last column", NewUnifiedASCIIParser(), "===  ===\na    b\n===  ===\n1    2\n3    4    5\n===  ===\n", 5, "row 2 has 3 fields, expected 2"},
+		{"simple text between columns", NewUnifiedASCIIParser(), "name   age\n----   ---\nann  x  30\n", 3, "row 1 has 3 fields, expected 2"},
+		{"html duplicate header", NewHTMLParser(), "<table><tr><th>a</th><th>a</th></tr></table>", 1, `duplicate header "a"`},
+		{"html ragged row", NewHTMLParser(), "<table>\n<tr><th>a</th><th>b</th></tr>\n<tr><td>1</td><td>2</td></tr>\n<tr><td>3</td></tr>\n</table>", 4, "row 2 has 1 field, expected 2"},
+		{"excel duplicate header", NewExcelParser(), excelSheet(t, [][]string{{"a", "a", ""}, {"1", "2", "3"}}), 0, `duplicate header "a" in columns 1 and 2`},
+		{"excel empty header", NewExcelParser(), excelSheet(t, [][]string{{"", "b"}, {"1", "2"}}), 0, "empty header in column 1"},
+		{"excel row wider than the header", NewExcelParser(), excelSheet(t, [][]string{{"a", ""}, {"1", "2"}}), 0, "row 1 has 2 fields, expected 1"},
+		{"json empty key", NewJSONParser(), `[{"": 1, "a": 2}]`, 0, "empty header in column 1"},
+		{"json duplicate key", NewJSONParser(), `[{"a": 1}, {"a": 2, "b": {"a": 0}, "a": 3}]`, 0, `row 2 has duplicate field "a"`},
+		{"yaml empty key", NewYAMLParser(), "- \"\": 1\n  a: 2\n", 0, "empty header in column 1"},
+		{"xml duplicate field", NewXMLParser(), "<dataset><record><a>1</a></record><record><a>2</a><a>3</a></record></dataset>", 0, `row 2 has duplicate field "a"`},
+		{"fixed duplicate header", NewFixedWidthParser(), "\nname  name\nann   bob\n", 2, `duplicate header "name" in columns 1 and 2`},
+		{"fixed text past the last column", NewFixedWidthParserWithColumns([]model.ColumnSpec{{Name: "a", Start: 1, End: 3}, {Name: "b", Start: 5, End: 6}}), "abc de\n\nfgh ij  extra\n", 3, "row 2 has text past the last column"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.parser.SetStrict(true)
+			_, err := tt.parser.(Parser).Parse(strings.NewReader(tt.input))
+
+			var parseErr *ParseError
+			if !errors.As(err, &parseErr) {
+				t.Fatalf("expected a ParseError, got %v", err)
+			}
+			if !strings.Contains(parseErr.Message, tt.wantMsg) {
+				t.Errorf("message = %q, want %q", parseErr.Message, tt.wantMsg)
+			}
+			line := 0
+			if parseErr.Line != nil {
+				line = *parseErr.Line
+			}
+			if line != tt.wantLine {
+				t.Errorf("line = %d, want %d", line, tt.wantLine)
+			}
+		})
+	}
+}
+
+func TestStrictMode_WellFormedInput(t *testing.T) {
+	inputs := []struct {
+		parser interface{ SetStrict(bool) }
+		input  string
+	}{
+		{NewCSVParser(), "a,b\n1,2\n3,4\n"},
+		{NewUnifiedASCIIParser(), "+---+-------+\n| a | b     |\n+===+=======+\n| 1 | x | y |\n|   | z     |\n+---+-------+\n"},
+		{NewUnifiedASCIIParser(), " a | b \n---+---\n 1 | \n 2 | x+\n   | y\n(2 rows)\n"},
+		{NewUnifiedASCIIParser(), "=====  =====\na      b\n=====  =====\n1      2\n=====  =====\n"},
+		{NewExcelParser(), excelSheet(t, [][]string{{"a", "b"}, {"1"}, {"2", "3"}})},
+		{NewJSONParser(), `[{"a": 1}, {"b": {"b": 2}}]`},
+		{NewFixedWidthParserWithColumns([]model.ColumnSpec{{Name: "a", Start: 1, End: 3}, {Name: "b", Start: 5, End: 6}}), "abc de  \n"},
+	}
+	for _, tt := range inputs {
+		tt.parser.SetStrict(true)
+		if _, err := tt.parser.(Parser).Parse(strings.NewReader(tt.input)); err != nil {
+			t.Errorf("Parse failed on well-formed input %q: %v", tt.input, err)
+		}
+	}
+}
+
+func TestDuplicateFieldWarnings(t *testing.T) {
+	var warnings []Warning
+	p := NewXMLParser()
+	p.SetWarningHandler(func(w Warning) { warnings = append(warnings, w) })
+
+	td, err := p.Parse(strings.NewReader("<dataset><record><a>1</a><a>2</a></record></dataset>"))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if got := td.Rows[0][0].Raw; got != "2" {
+		t.Errorf("value = %q, want the last value 2", got)
+	}
+	want := Warning{Message: `row 1 has duplicate field "a"; last value kept`}
+	if len(warnings) != 1 || warnings[0] != want {
+		t.Errorf("warnings = %v, want [%v]", warnings, want)
+	}
+}
+
+// excelSheet returns an xlsx workbook whose first sheet holds the rows
+func excelSheet(t *testing.T, rows [][]string) string {
+	t.Helper()
+	f := excelize.NewFile()
+	defer f.Close()
+	for i, row := range rows {
+		for j, value := range row {
+			cell, _ := excelize.CoordinatesToCellName(j+1, i+1)
+			if value != "" {
+				f.SetCellValue("Sheet1", cell, value)
+			}
+		}
+	}
+	buf, err := f.WriteToBuffer()
+	if err != nil {
+		t.Fatalf("failed to write workbook: %v", err)
+	}
+	return buf.String()
+}
+
+func TestUnifiedASCIIParser_RaggedRowWarnings(t *testing.T) {
+	var warnings []Warning
+	p := NewUnifiedASCIIParser()
+	p.SetWarningHandler(func(w Warning) { warnings = append(warnings, w) })
+
+	td, err := p.Parse(strings.NewReader("+---+---+\n| a | b |\n+---+---+\n| 1 | 2 | 3 |\n+---+---+\n"))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if got := td.Rows[0]; len(got) != 2 || got[0].Raw != "1" || got[1].Raw != "2" {
+		t.Errorf("row = %v, want [1 2]", got)
+	}
+	want := Warning{Line: 4, Message: "row 1 has 3 fields, expected 2; extra fields dropped"}
+	if len(warnings) != 1 || warnings[0] != want {
+		t.Errorf("warnings = %v, want [%v]", warnings, want)
+	}
+}
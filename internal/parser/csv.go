@@ -4,19 +4,30 @@ import (
 	"bufio"
 	"bytes"
 	"encoding/csv"
+	"errors"
 	"io"
 	"strings"
+	"unicode/utf8"
 
 	"github.com/user/table-converter/internal/model"
 )
 
-// Common delimiters to try for auto-detection
-var commonDelimiters = []rune{',', '\t', ';', '|'}
+// CommonDelimiters are the delimiters tried by auto-detection, in order of preference
+var CommonDelimiters = []rune{',', '\t', ';', '|'}
+
+// utf8BOM is the byte order mark some editors put at the start of UTF-8 files
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
 
 // CSVParser implements the Parser interface for CSV format
 type CSVParser struct {
 	// Delimiter is the field delimiter. If zero, auto-detect.
 	Delimiter rune
+	// Dialect sets delimiters of several characters, the quote and escape
+	// characters, comments, skipped lines and headerless input. Its
+	// Delimiter takes precedence over Delimiter
+	Dialect model.CSVDialect
+
+	RowChecker
 }
 
 // NewCSVParser creates a new CSV parser with auto-detection
@@ -33,6 +44,15 @@ func NewCSVParserWithDelimiter(delimiter rune) *CSVParser {
 	}
 }
 
+// SetDialect sets the CSV dialect of the input
+func (p *CSVParser) SetDialect(dialect model.CSVDialect) error {
+	if err := dialect.Validate(); err != nil {
+		return err
+	}
+	p.Dialect = dialect
+	return nil
+}
+
 // Parse reads CSV data from the input reader and converts it to TableData
 func (p *CSVParser) Parse(input io.Reader) (*model.TableData, error) {
 	// Read all input first (needed for delimiter detection)
@@ -41,42 +61,63 @@ func (p *CSVParser) Parse(input io.Reader) (*model.TableData, error) {
 		return nil, NewParseError("failed to read CSV data").WithErr(err)
 	}
 
+	// A UTF-8 byte order mark would otherwise become part of the first header
+	data = bytes.TrimPrefix(data, utf8BOM)
+
+	data = skipLeadingLines(data, p.Dialect.SkipLines)
+
 	if len(data) == 0 {
 		return nil, NewParseError("CSV file is empty")
 	}
 
 	// Determine delimiter
-	delimiter := p.Delimiter
-	if delimiter == 0 {
-		delimiter = detectDelimiter(data)
+	delimiter := p.Dialect.Delimiter
+	if delimiter == "" {
+		delim := p.Delimiter
+		if delim == 0 {
+			delim = detectDelimiter(data, p.Dialect.Comment)
+		}
+		delimiter = string(delim)
 	}
 
-	// Parse with detected/specified delimiter
-	reader := csv.NewReader(bytes.NewReader(data))
-	reader.Comma = delimiter
-	reader.FieldsPerRecord = -1 // Allow variable number of fields
-
-	// Read all records at once
-	records, err := reader.ReadAll()
+	records, lines, err := p.readRecords(data, delimiter)
 	if err != nil {
 		return nil, NewParseError("failed to parse CSV data").WithErr(err)
 	}
+	// Line numbers count the skipped lines
+	for i := range lines {
+		lines[i] += p.Dialect.SkipLines
+	}
 
 	// Check if we have any data
 	if len(records) == 0 {
 		return nil, NewParseError("CSV file is empty")
 	}
 
-	// First row is headers
+	// First row is headers, unless the input has none
 	headers := records[0]
+	if p.Dialect.NoHeader {
+		width := 0
+		for _, record := range records {
+			width = max(width, len(record))
+		}
+		headers = generatedHeaders(width)
+	} else {
+		if err := p.checkHeaders(headers, lines[0]); err != nil {
+			return nil, err
+		}
+		records, lines = records[1:], lines[1:]
+	}
 	if len(headers) == 0 {
 		return nil, NewParseError("CSV file has no columns")
 	}
 
 	// Parse remaining rows as data
-	rows := make([][]model.Value, 0, len(records)-1)
-	for i := 1; i < len(records); i++ {
-		record := records[i]
+	rows := make([][]model.Value, 0, len(records))
+	for i, record := range records {
+		if err := p.checkRow(i+1, len(record), len(headers), lines[i]); err != nil {
+			return nil, err
+		}
 		row := make([]model.Value, len(record))
 
 		for j, field := range record {
@@ -96,13 +137,50 @@ func (p *CSVParser) Parse(input io.Reader) (*model.TableData, error) {
 	return model.NewTableData(headers, rows), nil
 }
 
+// readRecords splits the data into records and returns the line each
+// record starts on. encoding/csv reads the standard dialect; other dialects
+// use csvDialectReader
+func (p *CSVParser) readRecords(data []byte, delimiter string) ([][]string, []int, error) {
+	comma, size := utf8.DecodeRuneInString(delimiter)
+	comment, commentSize := utf8.DecodeRuneInString(p.Dialect.Comment)
+	if size != len(delimiter) || p.Dialect.QuoteChar() != '"' || p.Dialect.Escape != 0 ||
+		commentSize != len(p.Dialect.Comment) {
+		return readDialectRecords(string(data), delimiter, p.Dialect)
+	}
+
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.Comma = comma
+	reader.FieldsPerRecord = -1 // Allow variable number of fields
+	if p.Dialect.Comment != "" {
+		reader.Comment = comment
+	}
+
+	var records [][]string
+	var lines []int
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return records, lines, nil
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		line, _ := reader.FieldPos(0)
+		records = append(records, record)
+		lines = append(lines, line)
+	}
+}
+
 // detectDelimiter attempts to auto-detect the CSV delimiter
-// by analyzing the first few lines of the file
-func detectDelimiter(data []byte) rune {
+// by analyzing the first few lines of the file, skipping comment lines
+func detectDelimiter(data []byte, comment string) rune {
 	// Read first few lines for analysis
 	scanner := bufio.NewScanner(bytes.NewReader(data))
 	var lines []string
-	for i := 0; i < 5 && scanner.Scan(); i++ {
+	for len(lines) < 5 && scanner.Scan() {
+		if comment != "" && strings.HasPrefix(scanner.Text(), comment) {
+			continue
+		}
 		lines = append(lines, scanner.Text())
 	}
 
@@ -114,7 +192,7 @@ func detectDelimiter(data []byte) rune {
 	bestDelimiter := ','
 	bestScore := -1
 
-	for _, delim := range commonDelimiters {
+	for _, delim := range CommonDelimiters {
 		score := scoreDelimiter(lines, delim)
 		if score > bestScore {
 			bestScore = score
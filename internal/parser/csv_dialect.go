@@ -0,0 +1,216 @@
+package parser
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/user/table-converter/internal/model"
+)
+
+// csvDialectReader splits CSV text into records for dialects that
+// encoding/csv cannot read: delimiters of several characters, other quote
+// characters, no quoting, backslash escapes and comment prefixes longer
+// than one character
+type csvDialectReader struct {
+	data      string
+	pos       int
+	line      int
+	delimiter string
+	quote     rune // 0 = no quoting
+	escape    rune // 0 = no escaping
+	comment   string
+}
+
+// readDialectRecords splits data into records using the dialect's quote,
+// escape and comment settings and the given delimiter, and returns the
+// line each record starts on
+func readDialectRecords(data, delimiter string, dialect model.CSVDialect) ([][]string, []int, error) {
+	r := &csvDialectReader{
+		data:      data,
+		line:      1,
+		delimiter: delimiter,
+		quote:     dialect.QuoteChar(),
+		escape:    dialect.Escape,
+		comment:   dialect.Comment,
+	}
+
+	var records [][]string
+	var lines []int
+	for r.pos < len(r.data) {
+		if r.atLineEnd() {
+			// Blank lines are skipped, as encoding/csv does
+			r.skipLineEnd()
+			continue
+		}
+		if r.comment != "" && strings.HasPrefix(r.data[r.pos:], r.comment) {
+			r.skipLine()
+			continue
+		}
+		line := r.line
+		record, err := r.readRecord()
+		if err != nil {
+			return nil, nil, err
+		}
+		records = append(records, record)
+		lines = append(lines, line)
+	}
+	return records, lines, nil
+}
+
+// readRecord reads the fields of one record and the line break after it
+func (r *csvDialectReader) readRecord() ([]string, error) {
+	var fields []string
+	for {
+		field, err := r.readField()
+		if err != nil {
+			return nil, err
+		}
+		fields = append(fields, field)
+
+		if strings.HasPrefix(r.data[r.pos:], r.delimiter) {
+			r.pos += len(r.delimiter)
+			continue
+		}
+		r.skipLineEnd()
+		return fields, nil
+	}
+}
+
+// readField reads one quoted or unquoted field, stopping before the
+// delimiter or line break that ends it
+func (r *csvDialectReader) readField() (string, error) {
+	var b strings.Builder
+
+	if r.quote != 0 && r.peek() == r.quote {
+		startLine := r.line
+		r.pos += utf8.RuneLen(r.quote)
+		for {
+			if r.pos >= len(r.data) {
+				return "", fmt.Errorf("line %d: quoted field is not closed", startLine)
+			}
+			ch := r.next()
+			switch {
+			case r.escape != 0 && ch == r.escape:
+				r.unescape(&b)
+			case ch == r.quote:
+				if r.peek() == r.quote {
+					r.pos += utf8.RuneLen(r.quote)
+					b.WriteRune(r.quote)
+					continue
+				}
+				if r.pos < len(r.data) && !r.atLineEnd() && !strings.HasPrefix(r.data[r.pos:], r.delimiter) {
+					return "", fmt.Errorf("line %d: unexpected text after closing quote", r.line)
+				}
+				return b.String(), nil
+			default:
+				if ch == '\n' {
+					r.line++
+				}
+				b.WriteRune(ch)
+			}
+		}
+	}
+
+	for r.pos < len(r.data) && !r.atLineEnd() && !strings.HasPrefix(r.data[r.pos:], r.delimiter) {
+		ch := r.next()
+		if r.escape != 0 && ch == r.escape {
+			r.unescape(&b)
+			continue
+		}
+		b.WriteRune(ch)
+	}
+	return b.String(), nil
+}
+
+// unescape writes the character after an escape character. \n, \r and
+// \t stand for line breaks and tabs; an escaped line break continues the
+// field on the next line
+func (r *csvDialectReader) unescape(b *strings.Builder) {
+	if r.pos >= len(r.data) {
+		b.WriteRune(r.escape)
+		return
+	}
+	ch := r.next()
+	switch ch {
+	case 'n':
+		b.WriteByte('\n')
+	case 'r':
+		b.WriteByte('\r')
+	case 't':
+		b.WriteByte('\t')
+	case '\r':
+		if r.peek() == '\n' {
+			r.pos++
+		}
+		r.line++
+		b.WriteByte('\n')
+	case '\n':
+		r.line++
+		b.WriteByte('\n')
+	default:
+		b.WriteRune(ch)
+	}
+}
+
+// peek returns the next character without consuming it
+func (r *csvDialectReader) peek() rune {
+	if r.pos >= len(r.data) {
+		return 0
+	}
+	ch, _ := utf8.DecodeRuneInString(r.data[r.pos:])
+	return ch
+}
+
+// next consumes and returns the next character
+func (r *csvDialectReader) next() rune {
+	ch, size := utf8.DecodeRuneInString(r.data[r.pos:])
+	r.pos += size
+	return ch
+}
+
+// atLineEnd reports whether the reader is at a line break
+func (r *csvDialectReader) atLineEnd() bool {
+	return r.pos < len(r.data) && (r.data[r.pos] == '\n' || r.data[r.pos] == '\r')
+}
+
+// skipLineEnd consumes a \n, \r\n or \r line break
+func (r *csvDialectReader) skipLineEnd() {
+	if r.pos < len(r.data) && r.data[r.pos] == '\r' {
+		r.pos++
+	}
+	if r.pos < len(r.data) && r.data[r.pos] == '\n' {
+		r.pos++
+	}
+	r.line++
+}
+
+// skipLine consumes the rest of the line and its line break
+func (r *csvDialectReader) skipLine() {
+	for r.pos < len(r.data) && !r.atLineEnd() {
+		r.pos++
+	}
+	r.skipLineEnd()
+}
+
+// skipLeadingLines drops the first n lines of data
+func skipLeadingLines(data []byte, n int) []byte {
+	for ; n > 0 && len(data) > 0; n-- {
+		i := bytes.IndexByte(data, '\n')
+		if i < 0 {
+			return nil
+		}
+		data = data[i+1:]
+	}
+	return data
+}
+
+// generatedHeaders names n headerless columns col1..colN
+func generatedHeaders(n int) []string {
+	headers := make([]string, n)
+	for i := range headers {
+		headers[i] = fmt.Sprintf("col%d", i+1)
+	}
+	return headers
+}
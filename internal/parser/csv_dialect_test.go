@@ -0,0 +1,115 @@
+package parser
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/user/table-converter/internal/model"
+)
+
+func TestCSVParser_Dialect(t *testing.T) {
+	tests := []struct {
+		name        string
+		dialect     model.CSVDialect
+		input       string
+		wantHeaders []string
+		wantRows    [][]string
+	}{
+		{
+			name:        "multi-character delimiter",
+			dialect:     model.CSVDialect{Delimiter: "::"},
+			input:       "id::name\n1::Ann\n2::\"B::ob\"\n",
+			wantHeaders: []string{"id", "name"},
+			wantRows:    [][]string{{"1", "Ann"}, {"2", "B::ob"}},
+		},
+		{
+			name:        "single quotes",
+			dialect:     model.CSVDialect{Quote: '\''},
+			input:       "name,note\nAnn,'it''s, fine'\n",
+			wantHeaders: []string{"name", "note"},
+			wantRows:    [][]string{{"Ann", "it's, fine"}},
+		},
+		{
+			name:        "backslash escapes",
+			dialect:     model.CSVDialect{Escape: '\\'},
+			input:       "name,note\nAnn,\"say \\\"hi\\\"\"\nBob,a\\,b\\nc\n",
+			wantHeaders: []string{"name", "note"},
+			wantRows:    [][]string{{"Ann", `say "hi"`}, {"Bob", "a,b\nc"}},
+		},
+		{
+			name:        "unquoted tab-separated",
+			dialect:     model.CSVDialect{Delimiter: "\t", Quote: model.NoQuote, Escape: '\\'},
+			input:       "name\tnote\nAnn\t\"quoted\"\\tand tab\n",
+			wantHeaders: []string{"name", "note"},
+			wantRows:    [][]string{{"Ann", "\"quoted\"\tand tab"}},
+		},
+		{
+			name:        "comments and skipped lines",
+			dialect:     model.CSVDialect{Comment: "//", SkipLines: 1},
+			input:       "Report generated today\n// columns\nname,age\n// Bob left\nAnn,30\n",
+			wantHeaders: []string{"name", "age"},
+			wantRows:    [][]string{{"Ann", "30"}},
+		},
+		{
+			name:        "single-character comment",
+			dialect:     model.CSVDialect{Comment: "#"},
+			input:       "# header comment\nname;age\nAnn;30\n",
+			wantHeaders: []string{"name", "age"},
+			wantRows:    [][]string{{"Ann", "30"}},
+		},
+		{
+			name:        "no header",
+			dialect:     model.CSVDialect{NoHeader: true},
+			input:       "Ann,30\nBob,25,extra\n",
+			wantHeaders: []string{"col1", "col2", "col3"},
+			wantRows:    [][]string{{"Ann", "30", ""}, {"Bob", "25", "extra"}},
+		},
+		{
+			name:        "crlf line endings",
+			dialect:     model.CSVDialect{Delimiter: "||"},
+			input:       "a||b\r\n1||2\r\n",
+			wantHeaders: []string{"a", "b"},
+			wantRows:    [][]string{{"1", "2"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewCSVParser()
+			if err := p.SetDialect(tt.dialect); err != nil {
+				t.Fatalf("SetDialect failed: %v", err)
+			}
+			td, err := p.Parse(strings.NewReader(tt.input))
+			if err != nil {
+				t.Fatalf("Parse failed: %v", err)
+			}
+			if !reflect.DeepEqual(td.Headers, tt.wantHeaders) {
+				t.Errorf("headers = %q, want %q", td.Headers, tt.wantHeaders)
+			}
+			var rows [][]string
+			for _, row := range td.Rows {
+				var cells []string
+				for _, v := range row {
+					cells = append(cells, v.String())
+				}
+				rows = append(rows, cells)
+			}
+			if !reflect.DeepEqual(rows, tt.wantRows) {
+				t.Errorf("rows = %q, want %q", rows, tt.wantRows)
+			}
+		})
+	}
+}
+
+func TestCSVParser_DialectErrors(t *testing.T) {
+	p := NewCSVParser()
+	if err := p.SetDialect(model.CSVDialect{Delimiter: "::"}); err != nil {
+		t.Fatalf("SetDialect failed: %v", err)
+	}
+	for _, input := range []string{"a::b\n\"open::2\n", "a::b\n\"x\"y::2\n"} {
+		if _, err := p.Parse(strings.NewReader(input)); err == nil {
+			t.Errorf("Parse(%q) succeeded, want an error", input)
+		}
+	}
+}
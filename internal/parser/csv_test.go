@@ -88,3 +88,13 @@ func TestCSVMultipleRowsWithEmpty(t *testing.T) {
 		}
 	}
 }
+
+func TestCSVByteOrderMark(t *testing.T) {
+	td, err := NewCSVParser().Parse(strings.NewReader("\xEF\xBB\xBFname,city\nJosé,Málaga\n"))
+	if err != nil {
+		t.Fatalf("failed to parse: %v", err)
+	}
+	if td.Headers[0] != "name" {
+		t.Errorf("first header = %q, want %q", td.Headers[0], "name")
+	}
+}
@@ -0,0 +1,364 @@
+package parser
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/user/table-converter/internal/model"
+	"github.com/user/table-converter/internal/textwidth"
+)
+
+// TableBlock is a table found inside a larger text document
+type TableBlock struct {
+	StartLine int              // 1-based line number of the first table line
+	EndLine   int              // 1-based line number of the last table line
+	Indent    string           // Leading whitespace shared by every table line
+	Lines     []string         // Table lines with the shared indentation removed
+	Style     TableStyle       // Detected table style
+	Table     *model.TableData // Parsed table contents
+}
+
+// ScanDocument reads a document and returns every table block it contains.
+// markdown selects the Markdown rules of FindTables
+func ScanDocument(input io.Reader, markdown bool) ([]TableBlock, error) {
+	scanner := bufio.NewScanner(input)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+
+	var lines []string
+	for scanner.Scan() {
+		lines = append(lines, strings.TrimSuffix(scanner.Text(), "\r"))
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, NewParseError("failed to read input").WithErr(err)
+	}
+
+	return FindTables(lines, markdown), nil
+}
+
+// FindTables scans the lines of a Markdown, Org-mode, reStructuredText or
+// plain text document and returns every table block it contains. Lines
+// inside fenced code blocks (``` or ~~~) and Org-mode #+begin_ blocks are
+// skipped. In a Markdown document, code blocks indented by four spaces are
+// skipped too, and text above a dashed rule is read as prose rather than a
+// borderless table
+func FindTables(lines []string, markdown bool) []TableBlock {
+	var blocks []TableBlock
+	closer := ""
+
+	for i := 0; i < len(lines); i++ {
+		trimmed := strings.TrimSpace(lines[i])
+
+		if closer != "" {
+			if closesFence(trimmed, closer) {
+				closer = ""
+			}
+			continue
+		}
+		if closer = openingFence(trimmed); closer != "" {
+			continue
+		}
+
+		// An indented code block starts after a blank line and runs through
+		// the indented and blank lines that follow
+		if markdown && isIndentedCode(lines[i]) && (i == 0 || strings.TrimSpace(lines[i-1]) == "") {
+			for i+1 < len(lines) && (isIndentedCode(lines[i+1]) || strings.TrimSpace(lines[i+1]) == "") {
+				i++
+			}
+			continue
+		}
+
+		end := tableEnd(lines, i, markdown)
+		if end <= i {
+			continue
+		}
+		if block, ok := newTableBlock(lines, i, end); ok {
+			blocks = append(blocks, block)
+			i = end - 1
+		}
+	}
+
+	return blocks
+}
+
+// openingFence returns the marker that closes the code block opened by
+// line, or an empty string if line does not open a code block
+func openingFence(line string) string {
+	for _, fence := range []string{"```", "~~~"} {
+		if strings.HasPrefix(line, fence) {
+			return fence
+		}
+	}
+	if strings.HasPrefix(strings.ToLower(line), "#+begin_") {
+		return "#+end_"
+	}
+	return ""
+}
+
+// isIndentedCode reports whether a line is indented enough to be Markdown
+// code: four spaces or a tab
+func isIndentedCode(line string) bool {
+	if strings.TrimSpace(line) == "" {
+		return false
+	}
+	return strings.HasPrefix(line, "    ") || strings.HasPrefix(line, "\t")
+}
+
+// closesFence reports whether line closes a code block ended by closer
+func closesFence(line, closer string) bool {
+	return strings.HasPrefix(strings.ToLower(line), closer)
+}
+
+// tableEnd returns the index just past the table candidate starting at
+// lines[start], or start if no table starts there. Markdown has no
+// borderless tables, so dashed rules are not table rules there
+func tableEnd(lines []string, start int, markdown bool) int {
+	trimmed := strings.TrimSpace(lines[start])
+	if isRSTSimpleRule(trimmed) {
+		return rstSimpleEnd(lines, start)
+	}
+
+	if isPipeTableLine(trimmed) {
+		end := start
+		for end < len(lines) && isPipeTableLine(strings.TrimSpace(lines[end])) {
+			end++
+		}
+		return end
+	}
+
+	// Plain text tables have a header line directly above a dashed rule
+	if trimmed == "" || start+1 >= len(lines) {
+		return start
+	}
+	rule := strings.TrimSpace(lines[start+1])
+	switch {
+	case strings.Contains(trimmed, "|") && isPsqlRule(rule):
+		end := start + 2
+		for end < len(lines) {
+			row := strings.TrimSpace(lines[end])
+			if !strings.Contains(row, "|") || queryFooter.MatchString(row) {
+				break
+			}
+			end++
+		}
+		return end
+	case !markdown && isDashRule(rule):
+		end := start + 2
+		for end < len(lines) && strings.TrimSpace(lines[end]) != "" {
+			end++
+		}
+		return end
+	}
+	return start
+}
+
+// isPsqlRule reports whether a trimmed line is the header rule of psql
+// output, such as ----+------
+func isPsqlRule(line string) bool {
+	return strings.HasPrefix(line, "-") && strings.Contains(line, "+") &&
+		strings.Trim(line, "-+") == ""
+}
+
+// isDashRule reports whether a trimmed line is the header rule of a
+// borderless table with at least two columns. Single runs of dashes are
+// headings or horizontal rules
+func isDashRule(line string) bool {
+	if line == "" || strings.Trim(line, "- ") != "" {
+		return false
+	}
+	return len(strings.Fields(line)) >= 2
+}
+
+// isPipeTableLine reports whether a trimmed line can belong to a bordered
+// table: a row starting with |, a +---+ border or a box-drawing line
+func isPipeTableLine(line string) bool {
+	if line == "" {
+		return false
+	}
+	first, _ := utf8.DecodeRuneInString(line)
+	switch {
+	case first == '|':
+		return true
+	case first == '+':
+		// "+ item" is a list bullet; only border lines start with +
+		return isBorderLine(line)
+	default:
+		return isBoxDrawing(first)
+	}
+}
+
+// isBorderLine reports whether a line only contains border characters
+func isBorderLine(line string) bool {
+	for _, ch := range line {
+		if !strings.ContainsRune("+-=|: \t", ch) {
+			return false
+		}
+	}
+	return strings.ContainsAny(line, "-=")
+}
+
+// isRSTSimpleRule reports whether a trimmed line is a reStructuredText
+// simple table rule with at least two columns. Single runs of = are
+// section underlines
+func isRSTSimpleRule(line string) bool {
+	if line == "" || strings.Trim(line, "= ") != "" {
+		return false
+	}
+	return len(strings.Fields(line)) >= 2
+}
+
+// rstSimpleEnd returns the index just past a reStructuredText simple table
+// whose top rule is lines[start]. The table ends at a rule that is followed
+// by a blank line or the end of the document
+func rstSimpleEnd(lines []string, start int) int {
+	columns := len(strings.Fields(lines[start]))
+
+	for i := start + 1; i < len(lines); i++ {
+		trimmed := strings.TrimSpace(lines[i])
+		if isRSTSimpleRule(trimmed) && len(strings.Fields(trimmed)) == columns {
+			if i+1 == len(lines) || strings.TrimSpace(lines[i+1]) == "" {
+				return i + 1
+			}
+			continue
+		}
+		// The header must directly follow the top rule
+		if trimmed == "" && i == start+1 {
+			return start
+		}
+	}
+
+	return start
+}
+
+// newTableBlock parses lines[start:end] as a table, reporting false if the
+// lines do not form a table with a separator line
+func newTableBlock(lines []string, start, end int) (TableBlock, bool) {
+	if end-start < 2 {
+		return TableBlock{}, false
+	}
+
+	indent := commonIndent(lines[start:end])
+	body := make([]string, end-start)
+	for i, line := range lines[start:end] {
+		body[i] = strings.TrimPrefix(line, indent)
+	}
+	block := TableBlock{
+		StartLine: start + 1,
+		EndLine:   end,
+		Indent:    indent,
+		Lines:     body,
+	}
+	if len(block.SeparatorLines()) == 0 {
+		return TableBlock{}, false
+	}
+
+	p := NewUnifiedASCIIParser()
+	td, err := p.Parse(strings.NewReader(strings.Join(body, "\n")))
+	if err != nil || len(td.Headers) == 0 {
+		return TableBlock{}, false
+	}
+
+	block.Style = p.DetectedStyle
+	block.Table = td
+	return block, true
+}
+
+// Check parses the block again with the strict mode and warning handler of
+// checker, so that problems are reported at their line in the document
+func (b TableBlock) Check(checker RowChecker) error {
+	offset := b.StartLine - 1
+
+	p := NewUnifiedASCIIParser()
+	p.Strict = checker.Strict
+	if checker.Warn != nil {
+		p.Warn = func(w Warning) {
+			if w.Line > 0 {
+				w.Line += offset
+			}
+			checker.Warn(w)
+		}
+	}
+
+	_, err := p.Parse(strings.NewReader(strings.Join(b.Lines, "\n")))
+	var parseErr *ParseError
+	if errors.As(err, &parseErr) && parseErr.Line != nil {
+		line := *parseErr.Line + offset
+		parseErr.Line = &line
+	}
+	return err
+}
+
+// HasRegularColumns reports whether every line of a framed table has a
+// column separator at each column boundary of its first line. Cells that
+// span columns or rows leave gaps or partial rules that the parser cannot
+// read back, so such tables cannot be redrawn
+func (b TableBlock) HasRegularColumns() bool {
+	if !isFramedStyle(b.Style) {
+		return true
+	}
+
+	var lines []string
+	for _, line := range b.Lines {
+		if strings.TrimSpace(line) != "" {
+			lines = append(lines, normalizeBoxDrawing(line))
+		}
+	}
+	if len(lines) == 0 {
+		return true
+	}
+
+	boundaries := append(textwidth.Index(lines[0], '+'), textwidth.Index(lines[0], '|')...)
+	for _, line := range lines[1:] {
+		for _, pos := range boundaries {
+			if ch := textwidth.Slice(line, pos, pos+1); ch != "+" && ch != "|" {
+				return false
+			}
+		}
+		// A rule that runs through only some columns leaves blanks in the
+		// rule or sits on a line with cell text
+		trimmed := strings.TrimSpace(line)
+		if isBorderLine(trimmed) {
+			if strings.ContainsAny(trimmed, " \t") {
+				return false
+			}
+		} else if strings.Contains(line, "+-") || strings.Contains(line, "+=") {
+			return false
+		}
+	}
+	return true
+}
+
+// SeparatorLines returns the indexes into Lines of the block's border and
+// separator lines
+func (b TableBlock) SeparatorLines() []int {
+	var indexes []int
+	for i, line := range b.Lines {
+		if isBorderLine(normalizeBoxDrawing(strings.TrimSpace(line))) {
+			indexes = append(indexes, i)
+		}
+	}
+	return indexes
+}
+
+// commonIndent returns the leading whitespace shared by all non-blank lines
+func commonIndent(lines []string) string {
+	indent := ""
+	first := true
+	for _, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		lead := line[:len(line)-len(strings.TrimLeftFunc(line, unicode.IsSpace))]
+		if first {
+			indent, first = lead, false
+			continue
+		}
+		for !strings.HasPrefix(lead, indent) {
+			indent = indent[:len(indent)-1]
+		}
+	}
+	return indent
+}
@@ -0,0 +1,173 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFindTables(t *testing.T) {
+	doc := `# Notes
+
+Prose with a | pipe.
+
+| Name | Age |
+|---|---|
+| Alice | 30 |
+
++ a list bullet
+
+` + "```" + `
+| in | code |
+|----|------|
+` + "```" + `
+
+  +---+---+
+  | a | b |
+  +===+===+
+  | 1 | 2 |
+  +---+---+
+
+=====  =====
+col    other
+=====  =====
+a      b
+=====  =====
+
+|---+---|
+| k | v |
+|---+---|
+| x | 1 |
+
+┌───┬───┐
+│ p │ q │
+├───┼───┤
+│ 1 │ 2 │
+└───┴───┘
+
+Title
+=====
+`
+	blocks := FindTables(strings.Split(doc, "\n"), false)
+
+	expected := []struct {
+		start, end int
+		style      TableStyle
+		indent     string
+		headers    []string
+	}{
+		{5, 7, StyleMarkdown, "", []string{"Name", "Age"}},
+		{16, 20, StyleRSTGrid, "  ", []string{"a", "b"}},
+		{22, 26, StyleRSTSimple, "", []string{"col", "other"}},
+		{28, 31, StyleOrgMode, "", []string{"k", "v"}},
+		{33, 37, StyleUnicode, "", []string{"p", "q"}},
+	}
+
+	if len(blocks) != len(expected) {
+		for _, b := range blocks {
+			t.Logf("found %s table at lines %d-%d", b.Style, b.StartLine, b.EndLine)
+		}
+		t.Fatalf("expected %d tables, got %d", len(expected), len(blocks))
+	}
+
+	for i, want := range expected {
+		got := blocks[i]
+		if got.StartLine != want.start || got.EndLine != want.end {
+			t.Errorf("table %d: expected lines %d-%d, got %d-%d", i, want.start, want.end, got.StartLine, got.EndLine)
+		}
+		if got.Style != want.style {
+			t.Errorf("table %d: expected style %s, got %s", i, want.style, got.Style)
+		}
+		if got.Indent != want.indent {
+			t.Errorf("table %d: expected indent %q, got %q", i, want.indent, got.Indent)
+		}
+		if strings.Join(got.Table.Headers, ",") != strings.Join(want.headers, ",") {
+			t.Errorf("table %d: expected headers %v, got %v", i, want.headers, got.Table.Headers)
+		}
+	}
+}
+
+func TestFindTables_NoTables(t *testing.T) {
+	docs := []string{
+		"",
+		"Just prose.\n\nMore prose.",
+		"| a lone pipe line |",
+		"+ bullet\n+ another",
+		"Heading\n=======\n\ntext",
+	}
+
+	for _, doc := range docs {
+		if blocks := FindTables(strings.Split(doc, "\n"), false); len(blocks) != 0 {
+			t.Errorf("expected no tables in %q, got %d", doc, len(blocks))
+		}
+	}
+}
+
+func TestFindTables_Markdown(t *testing.T) {
+	doc := "Example:\n" +
+		"\n" +
+		"    | in | code |\n" +
+		"    |----|------|\n" +
+		"\n" +
+		"    | a | b |\n" +
+		"\n" +
+		"Key   Value\n" +
+		"----  -----\n" +
+		"foo   bar\n" +
+		"\n" +
+		"| Name | Age |\n" +
+		"|------|-----|\n" +
+		"| Ann  | 30  |\n"
+	lines := strings.Split(doc, "\n")
+
+	blocks := FindTables(lines, true)
+	if len(blocks) != 1 || blocks[0].StartLine != 12 || blocks[0].Style != StyleMarkdown {
+		t.Fatalf("expected only the Markdown table at line 12, got %+v", blocks)
+	}
+
+	// As plain text the indented table and the dashed rule are tables too
+	if blocks := FindTables(lines, false); len(blocks) != 3 {
+		t.Errorf("expected 3 tables in plain text, got %d", len(blocks))
+	}
+}
+
+func TestScanDocument_PlainText(t *testing.T) {
+	doc := "Query results:\r\n" +
+		"\r\n" +
+		" id | name\r\n" +
+		"----+------\r\n" +
+		"  1 | Ann\r\n" +
+		"  2 | Bo\r\n" +
+		"(2 rows)\r\n" +
+		"\r\n" +
+		"Stock\r\n" +
+		"-----\r\n" +
+		"\r\n" +
+		"name    qty\r\n" +
+		"------  ---\r\n" +
+		"tea     3\r\n" +
+		"cake    10\r\n"
+
+	blocks, err := ScanDocument(strings.NewReader(doc), false)
+	if err != nil {
+		t.Fatalf("ScanDocument failed: %v", err)
+	}
+	if len(blocks) != 2 {
+		t.Fatalf("expected 2 tables, got %d", len(blocks))
+	}
+
+	psql := blocks[0]
+	if psql.Style != StylePsql || psql.StartLine != 3 || psql.EndLine != 6 {
+		t.Errorf("expected psql table at lines 3-6, got %s at %d-%d", psql.Style, psql.StartLine, psql.EndLine)
+	}
+	if len(psql.Table.Rows) != 2 || psql.Table.Rows[1][1].Raw != "Bo" {
+		t.Errorf("unexpected psql rows: %v", psql.Table.Rows)
+	}
+
+	simple := blocks[1]
+	if simple.Style != StyleSimple || simple.StartLine != 12 || simple.EndLine != 15 {
+		t.Errorf("expected simple table at lines 12-15, got %s at %d-%d", simple.Style, simple.StartLine, simple.EndLine)
+	}
+	if len(simple.Table.Rows) != 2 || simple.Table.Rows[1][0].Raw != "cake" {
+		t.Errorf("unexpected simple rows: %v", simple.Table.Rows)
+	}
+}
@@ -12,6 +12,8 @@ import (
 type ExcelParser struct {
 	// SheetName specifies which sheet to parse (empty = first sheet)
 	SheetName string
+
+	RowChecker
 }
 
 // NewExcelParser creates a new Excel parser that reads the first sheet
@@ -102,6 +104,9 @@ func (p *ExcelParser) Parse(input io.Reader) (*model.TableData, error) {
 			headers[i] = rows[0][i]
 		}
 	}
+	if err := p.checkHeaders(headers, 0); err != nil {
+		return nil, err
+	}
 
 	// Determine number of data rows from dimension
 	numDataRows := endRow - startRow // -1 for header, but dimension is 1-indexed
@@ -119,6 +124,12 @@ func (p *ExcelParser) Parse(input io.Reader) (*model.TableData, error) {
 	for i := 0; i < numDataRows; i++ {
 		values := make([]model.Value, numCols)
 		rowIdx := i + 1 // Skip header row
+		// Trailing empty cells are not returned, so only longer rows are ragged
+		if rowIdx < len(rows) && len(rows[rowIdx]) > numCols {
+			if err := p.checkRow(i+1, len(rows[rowIdx]), numCols, 0); err != nil {
+				return nil, err
+			}
+		}
 		for j := 0; j < numCols; j++ {
 			cellRef, _ := excelize.CoordinatesToCellName(startCol+j, startRow+rowIdx)
 			var cellValue string
@@ -149,10 +160,19 @@ func (p *ExcelParser) parseWithGetRows(f *excelize.File, sheetName string) (*mod
 	if len(headers) == 0 {
 		return model.NewTableData([]string{}, [][]model.Value{}), nil
 	}
+	if err := p.checkHeaders(headers, 0); err != nil {
+		return nil, err
+	}
 
 	dataRows := make([][]model.Value, 0, len(rows)-1)
 	for rowIdx := 1; rowIdx < len(rows); rowIdx++ {
 		row := rows[rowIdx]
+		// Trailing empty cells are not returned, so only longer rows are ragged
+		if len(row) > len(headers) {
+			if err := p.checkRow(rowIdx, len(row), len(headers), 0); err != nil {
+				return nil, err
+			}
+		}
 		values := make([]model.Value, len(headers))
 		for colIdx := 0; colIdx < len(headers); colIdx++ {
 			if colIdx < len(row) {
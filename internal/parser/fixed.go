@@ -0,0 +1,212 @@
+package parser
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/user/table-converter/internal/model"
+	"github.com/user/table-converter/internal/textwidth"
+)
+
+// FixedWidthParser implements the Parser interface for fixed-width column text
+type FixedWidthParser struct {
+	// Columns declares the field positions. If empty, the columns are inferred
+	// from whitespace that lines up across all lines and the first line is
+	// read as the header
+	Columns []model.ColumnSpec
+	// PadChar is the padding character trimmed from fields (default: space)
+	PadChar rune
+
+	RowChecker
+}
+
+// NewFixedWidthParser creates a fixed-width parser that infers its columns
+func NewFixedWidthParser() *FixedWidthParser {
+	return &FixedWidthParser{
+		PadChar: ' ',
+	}
+}
+
+// NewFixedWidthParserWithColumns creates a fixed-width parser with declared columns
+func NewFixedWidthParserWithColumns(columns []model.ColumnSpec) *FixedWidthParser {
+	p := NewFixedWidthParser()
+	p.Columns = columns
+	return p
+}
+
+// SetColumns sets the declared columns; nil makes the parser infer them
+func (p *FixedWidthParser) SetColumns(columns []model.ColumnSpec) {
+	p.Columns = columns
+}
+
+// SetPadChar sets the padding character, which must be one display column
+// wide; zero resets it to a space
+func (p *FixedWidthParser) SetPadChar(pad rune) error {
+	if pad == 0 {
+		pad = ' '
+	}
+	if textwidth.Rune(pad) != 1 {
+		return fmt.Errorf("padding character %q must be one column wide", pad)
+	}
+	p.PadChar = pad
+	return nil
+}
+
+// Parse reads fixed-width text and converts it to TableData
+func (p *FixedWidthParser) Parse(input io.Reader) (*model.TableData, error) {
+	scanner := bufio.NewScanner(input)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+
+	// numbers holds the input line of each non-blank line
+	var lines []string
+	var numbers []int
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSuffix(scanner.Text(), "\r")
+		if strings.TrimSpace(line) != "" {
+			lines = append(lines, line)
+			numbers = append(numbers, n)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, NewParseError("failed to read fixed-width data").WithErr(err)
+	}
+
+	columns := p.Columns
+	headerLine := 0
+	if len(columns) == 0 {
+		if model.PadInValues(p.PadChar) {
+			return nil, NewParseError(fmt.Sprintf("padding character %q can appear in values, so it needs declared columns with a right alignment", p.PadChar))
+		}
+		if len(lines) == 0 {
+			return model.NewTableData([]string{}, [][]model.Value{}), nil
+		}
+		columns = inferFixedColumns(lines)
+		if len(columns) == 0 {
+			return nil, NewParseError("cannot infer fixed-width columns")
+		}
+
+		// Inferred columns take their names from the first line
+		for i, cell := range p.splitLine(lines[0], columns) {
+			columns[i].Name = cell
+		}
+		headerLine = numbers[0]
+		lines, numbers = lines[1:], numbers[1:]
+	}
+
+	headers := make([]string, len(columns))
+	end := 0
+	for i, column := range columns {
+		headers[i] = column.Name
+		end = max(end, column.End)
+	}
+	if err := p.checkHeaders(headers, headerLine); err != nil {
+		return nil, err
+	}
+
+	rows := make([][]model.Value, 0, len(lines))
+	for i, line := range lines {
+		if strings.TrimSpace(textwidth.Slice(line, end, textwidth.String(line))) != "" {
+			if err := p.checkExtraText(i+1, numbers[i]); err != nil {
+				return nil, err
+			}
+		}
+		cells := p.splitLine(line, columns)
+		values := make([]model.Value, len(cells))
+		for i, cell := range cells {
+			values[i] = model.NewValue(cell)
+		}
+		rows = append(rows, values)
+	}
+
+	return model.NewTableData(headers, rows), nil
+}
+
+// splitLine cuts a line into fields at the column positions and trims their padding
+func (p *FixedWidthParser) splitLine(line string, columns []model.ColumnSpec) []string {
+	cells := make([]string, len(columns))
+	for i, column := range columns {
+		field := textwidth.Slice(line, column.Start-1, column.End)
+		cells[i] = p.trimPadding(field, column.Align)
+	}
+	return cells
+}
+
+// trimPadding removes surrounding spaces and, for a custom pad character,
+// the padding on the side the field was aligned away from. A pad character
+// that can appear in values is only trimmed from the left of right-aligned
+// fields
+func (p *FixedWidthParser) trimPadding(field string, align model.Alignment) string {
+	field = strings.TrimSpace(field)
+	pad := p.PadChar
+	if pad == 0 || pad == ' ' || field == "" {
+		return field
+	}
+	if model.PadInValues(pad) && align != model.AlignRight {
+		return field
+	}
+
+	var trimmed string
+	switch align {
+	case model.AlignRight:
+		trimmed = strings.TrimLeft(field, string(pad))
+	case model.AlignCenter:
+		trimmed = strings.Trim(field, string(pad))
+	default:
+		trimmed = strings.TrimRight(field, string(pad))
+	}
+
+	// A field made only of padding, such as 0000 padded with zeros, keeps one
+	if trimmed == "" {
+		return string(pad)
+	}
+	return trimmed
+}
+
+// inferFixedColumns finds columns as runs of display positions that hold
+// text on at least one line, separated by positions that are blank on all
+// lines. This is the same idea as reading the column rule of an RST simple
+// table, with the rule built from the data itself. The first line is the
+// header
+func inferFixedColumns(lines []string) []model.ColumnSpec {
+	var occupied []bool
+	for _, line := range lines {
+		col := 0
+		for _, ch := range line {
+			w := textwidth.Rune(ch)
+			for col+w > len(occupied) {
+				occupied = append(occupied, false)
+			}
+			if ch != ' ' && ch != '\t' {
+				for i := col; i < col+w; i++ {
+					occupied[i] = true
+				}
+			}
+			col += w
+		}
+	}
+
+	var rule strings.Builder
+	for _, used := range occupied {
+		if used {
+			rule.WriteByte('=')
+		} else {
+			rule.WriteByte(' ')
+		}
+	}
+
+	// Every column needs a header, so a run with no header text above it is
+	// a value with spaces (such as "Alice Smith") and joins the previous run
+	var columns []model.ColumnSpec
+	for _, b := range (&UnifiedASCIIParser{}).findRuleColumns(rule.String(), '=') {
+		column := model.ColumnSpec{Start: b[0] + 1, End: b[1]}
+		header := strings.TrimSpace(textwidth.Slice(lines[0], b[0], b[1]))
+		if header == "" && len(columns) > 0 {
+			columns[len(columns)-1].End = column.End
+			continue
+		}
+		columns = append(columns, column)
+	}
+	return columns
+}
@@ -0,0 +1,79 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/user/table-converter/internal/model"
+)
+
+func TestFixedWidthParser_DeclaredColumns(t *testing.T) {
+	input := "000001Alice Smith         00001250.00\r\n" +
+		"000022Bob                 00000007.50\r\n"
+
+	p := NewFixedWidthParserWithColumns([]model.ColumnSpec{
+		{Name: "id", Start: 1, End: 6, Align: model.AlignRight},
+		{Name: "name", Start: 7, End: 26},
+		{Name: "amount", Start: 27, End: 37, Align: model.AlignRight},
+	})
+	if err := p.SetPadChar('0'); err != nil {
+		t.Fatalf("SetPadChar failed: %v", err)
+	}
+
+	td, err := p.Parse(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	if strings.Join(td.Headers, ",") != "id,name,amount" {
+		t.Errorf("unexpected headers: %v", td.Headers)
+	}
+	expected := [][]string{
+		{"1", "Alice Smith", "1250.00"},
+		{"22", "Bob", "7.50"},
+	}
+	for i, row := range expected {
+		for j, want := range row {
+			if got := td.Rows[i][j].Raw; got != want {
+				t.Errorf("row %d col %d = %q, want %q", i, j, got, want)
+			}
+		}
+	}
+}
+
+func TestFixedWidthParser_InferredColumns(t *testing.T) {
+	input := `id  name         amount  city
+1   Alice Smith    12.50  東京
+22  Bob             7.00
+333 Carol        1000.00  Paris`
+
+	td, err := NewFixedWidthParser().Parse(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	if strings.Join(td.Headers, ",") != "id,name,amount,city" {
+		t.Fatalf("unexpected headers: %v", td.Headers)
+	}
+	if len(td.Rows) != 3 {
+		t.Fatalf("expected 3 rows, got %d", len(td.Rows))
+	}
+	if got := td.Rows[0][1].Raw; got != "Alice Smith" {
+		t.Errorf("name = %q, want %q", got, "Alice Smith")
+	}
+	if got := td.Rows[0][3].Raw; got != "東京" {
+		t.Errorf("city = %q, want %q", got, "東京")
+	}
+	if td.Rows[1][3].Type != model.TypeNull {
+		t.Errorf("expected missing city to be null, got %q", td.Rows[1][3].Raw)
+	}
+	if got := td.Rows[2][2].Raw; got != "1000.00" {
+		t.Errorf("amount = %q, want %q", got, "1000.00")
+	}
+}
+
+func TestFixedWidthParser_SetPadCharInvalid(t *testing.T) {
+	if err := NewFixedWidthParser().SetPadChar('＊'); err == nil {
+		t.Error("expected error for a wide padding character")
+	}
+}
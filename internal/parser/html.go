@@ -1,6 +1,7 @@
 package parser
 
 import (
+	"bytes"
 	"io"
 	"strings"
 
@@ -10,7 +11,9 @@ import (
 )
 
 // HTMLParser implements the Parser interface for HTML table format
-type HTMLParser struct{}
+type HTMLParser struct {
+	RowChecker
+}
 
 // NewHTMLParser creates a new HTML parser
 func NewHTMLParser() *HTMLParser {
@@ -20,7 +23,11 @@ func NewHTMLParser() *HTMLParser {
 // Parse reads HTML data from the input reader and converts it to TableData
 // Expects input to contain at least one <table> element
 func (p *HTMLParser) Parse(input io.Reader) (*model.TableData, error) {
-	doc, err := html.Parse(input)
+	data, err := io.ReadAll(input)
+	if err != nil {
+		return nil, NewParseError("failed to read input").WithErr(err)
+	}
+	doc, err := html.Parse(bytes.NewReader(data))
 	if err != nil {
 		return nil, NewParseError("failed to parse HTML").WithErr(err)
 	}
@@ -32,12 +39,55 @@ func (p *HTMLParser) Parse(input io.Reader) (*model.TableData, error) {
 	}
 
 	// Extract headers and rows
-	headers, rows, err := parseTable(tableNode)
+	table, err := parseTable(tableNode)
 	if err != nil {
 		return nil, err
 	}
 
-	return model.NewTableData(headers, rows), nil
+	lines := htmlRowLines(data, doc)
+	if err := p.checkHeaders(table.headers, lines[table.headerRow]); err != nil {
+		return nil, err
+	}
+	for i, row := range table.rows {
+		if err := p.checkRow(i+1, len(row), len(table.headers), lines[table.rowNodes[i]]); err != nil {
+			return nil, err
+		}
+	}
+
+	return model.NewTableData(table.headers, table.rows), nil
+}
+
+// htmlRowLines maps the <tr> elements of a parsed document to the input
+// lines of their start tags. The map is empty when the HTML parser added
+// rows that have no tag of their own, since the elements then no longer
+// match the tags one for one
+func htmlRowLines(data []byte, doc *html.Node) map[*html.Node]int {
+	var tagLines []int
+	line := 1
+	z := html.NewTokenizer(bytes.NewReader(data))
+	for {
+		tt := z.Next()
+		if tt == html.ErrorToken {
+			break
+		}
+		breaks := bytes.Count(z.Raw(), []byte("\n"))
+		if tt == html.StartTagToken {
+			if name, _ := z.TagName(); string(name) == "tr" {
+				tagLines = append(tagLines, line)
+			}
+		}
+		line += breaks
+	}
+
+	rows := findAllElementsRecursive(doc, "tr")
+	lines := make(map[*html.Node]int, len(rows))
+	if len(rows) != len(tagLines) {
+		return lines
+	}
+	for i, tr := range rows {
+		lines[tr] = tagLines[i]
+	}
+	return lines
 }
 
 // findFirstElement recursively searches for the first element with the given tag name
@@ -92,10 +142,19 @@ func getTextContent(n *html.Node) string {
 	return sb.String()
 }
 
+// htmlTable holds the headers and rows read from a table element, with the
+// <tr> elements they came from
+type htmlTable struct {
+	headers   []string
+	rows      [][]model.Value
+	headerRow *html.Node
+	rowNodes  []*html.Node
+}
+
 // parseTable extracts headers and rows from a table element
-func parseTable(tableNode *html.Node) ([]string, [][]model.Value, error) {
+func parseTable(tableNode *html.Node) (*htmlTable, error) {
+	table := &htmlTable{}
 	var headers []string
-	var rows [][]model.Value
 
 	// Look for thead element
 	thead := findFirstElement(tableNode, "thead")
@@ -105,6 +164,7 @@ func parseTable(tableNode *html.Node) ([]string, [][]model.Value, error) {
 		// Extract headers from thead
 		headerRow := findFirstElement(thead, "tr")
 		if headerRow != nil {
+			table.headerRow = headerRow
 			headers = extractCellsAsStrings(headerRow, "th")
 			// If no th elements, try td
 			if len(headers) == 0 {
@@ -125,6 +185,7 @@ func parseTable(tableNode *html.Node) ([]string, [][]model.Value, error) {
 	// If no headers found yet, use first row as headers
 	if len(headers) == 0 && len(dataRows) > 0 {
 		firstRow := dataRows[0]
+		table.headerRow = firstRow
 		// Try th first, then td
 		headers = extractCellsAsStrings(firstRow, "th")
 		if len(headers) == 0 {
@@ -140,7 +201,7 @@ func parseTable(tableNode *html.Node) ([]string, [][]model.Value, error) {
 
 	// If still no headers, return empty table
 	if len(headers) == 0 {
-		return []string{}, [][]model.Value{}, nil
+		return &htmlTable{headers: []string{}, rows: [][]model.Value{}}, nil
 	}
 
 	// Parse data rows
@@ -149,11 +210,12 @@ func parseTable(tableNode *html.Node) ([]string, [][]model.Value, error) {
 		if thead != nil && isChildOf(tr, thead) {
 			continue
 		}
-		rowValues := extractCellsAsValues(tr)
-		rows = append(rows, rowValues)
+		table.rows = append(table.rows, extractCellsAsValues(tr))
+		table.rowNodes = append(table.rowNodes, tr)
 	}
 
-	return headers, rows, nil
+	table.headers = headers
+	return table, nil
 }
 
 
@@ -1,6 +1,7 @@
 package parser
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -10,7 +11,9 @@ import (
 )
 
 // JSONParser implements the Parser interface for JSON format
-type JSONParser struct{}
+type JSONParser struct {
+	RowChecker
+}
 
 // NewJSONParser creates a new JSON parser
 func NewJSONParser() *JSONParser {
@@ -48,6 +51,13 @@ func (p *JSONParser) Parse(input io.Reader) (*model.TableData, error) {
 		return model.NewTableData([]string{}, [][]model.Value{}), nil
 	}
 
+	// Unmarshal keeps the last value of a repeated key without a trace
+	if p.Strict || p.Warn != nil {
+		if err := findDuplicateKeys(data, p.checkDuplicateField); err != nil {
+			return nil, err
+		}
+	}
+
 	// Extract headers from union of all keys across all records
 	headerSet := make(map[string]bool)
 	for _, record := range records {
@@ -62,6 +72,9 @@ func (p *JSONParser) Parse(input io.Reader) (*model.TableData, error) {
 		headers = append(headers, key)
 	}
 	sort.Strings(headers)
+	if err := p.checkHeaders(headers, 0); err != nil {
+		return nil, err
+	}
 
 	// Parse rows
 	rows := make([][]model.Value, len(records))
@@ -81,6 +94,51 @@ func (p *JSONParser) Parse(input io.Reader) (*model.TableData, error) {
 	return model.NewTableData(headers, rows), nil
 }
 
+// findDuplicateKeys calls report with the 1-based record number of each key
+// repeated within an object of a JSON array. Input that is not an array of
+// objects is left to Unmarshal to reject
+func findDuplicateKeys(data []byte, report func(row int, name string, line int) error) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('[') {
+		return nil
+	}
+
+	for row := 1; dec.More(); row++ {
+		tok, err := dec.Token()
+		if err != nil || tok != json.Delim('{') {
+			// null records hold no keys
+			if tok == nil && err == nil {
+				continue
+			}
+			return nil
+		}
+
+		seen := make(map[string]bool)
+		for dec.More() {
+			tok, err := dec.Token()
+			if err != nil {
+				return nil
+			}
+			key, _ := tok.(string)
+			if seen[key] {
+				if err := report(row, key, 0); err != nil {
+					return err
+				}
+			}
+			seen[key] = true
+
+			var value json.RawMessage
+			if err := dec.Decode(&value); err != nil {
+				return nil
+			}
+		}
+		if _, err := dec.Token(); err != nil {
+			return nil
+		}
+	}
+	return nil
+}
+
 // jsonValueToModelValue converts a JSON value to a model.Value
 func jsonValueToModelValue(val interface{}) model.Value {
 	switch v := val.(type) {
@@ -9,7 +9,9 @@ import (
 )
 
 // XMLParser implements the Parser interface for XML format
-type XMLParser struct{}
+type XMLParser struct {
+	RowChecker
+}
 
 // NewXMLParser creates a new XML parser
 func NewXMLParser() *XMLParser {
@@ -55,6 +57,9 @@ func (p *XMLParser) Parse(input io.Reader) (*model.TableData, error) {
 		headers = append(headers, key)
 	}
 	sort.Strings(headers)
+	if err := p.checkHeaders(headers, 0); err != nil {
+		return nil, err
+	}
 
 	// Parse rows
 	rows := make([][]model.Value, len(dataset.Records))
@@ -62,7 +67,13 @@ func (p *XMLParser) Parse(input io.Reader) (*model.TableData, error) {
 		// Create a map of field name to value for this record
 		fieldMap := make(map[string]string)
 		for _, field := range record.Fields {
-			fieldMap[field.XMLName.Local] = field.Value
+			name := field.XMLName.Local
+			if _, repeated := fieldMap[name]; repeated {
+				if err := p.checkDuplicateField(i+1, name, 0); err != nil {
+					return nil, err
+				}
+			}
+			fieldMap[name] = field.Value
 		}
 
 		// Build row in header order
@@ -10,7 +10,9 @@ import (
 )
 
 // YAMLParser implements the Parser interface for YAML format
-type YAMLParser struct{}
+type YAMLParser struct {
+	RowChecker
+}
 
 // NewYAMLParser creates a new YAML parser
 func NewYAMLParser() *YAMLParser {
@@ -62,6 +64,9 @@ func (p *YAMLParser) Parse(input io.Reader) (*model.TableData, error) {
 		headers = append(headers, key)
 	}
 	sort.Strings(headers)
+	if err := p.checkHeaders(headers, 0); err != nil {
+		return nil, err
+	}
 
 	// Parse rows
 	rows := make([][]model.Value, len(records))
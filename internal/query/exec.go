@@ -0,0 +1,740 @@
+package query
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/user/table-converter/internal/expr"
+	"github.com/user/table-converter/internal/model"
+)
+
+// Tables are the tables a statement can read, keyed by name. Names are
+// matched case-insensitively
+type Tables map[string]*model.TableData
+
+// lookup finds a table by name, preferring an exact match
+func (t Tables) lookup(name string) (*model.TableData, error) {
+	if data, ok := t[name]; ok {
+		return data, nil
+	}
+	for key, data := range t {
+		if strings.EqualFold(key, name) {
+			return data, nil
+		}
+	}
+	names := make([]string, 0, len(t))
+	for key := range t {
+		names = append(names, key)
+	}
+	slices.Sort(names)
+	return nil, fmt.Errorf("unknown table %q (tables: %s)", name, strings.Join(names, ", "))
+}
+
+// Run parses a SELECT statement and executes it against tables
+func Run(sql string, tables Tables) (*model.TableData, error) {
+	stmt, err := Parse(sql)
+	if err != nil {
+		return nil, err
+	}
+	return stmt.Execute(tables)
+}
+
+// relColumn is a column of the rows being queried and the table it came from
+type relColumn struct {
+	table, name string
+}
+
+// relation is a set of rows with named columns
+type relation struct {
+	columns []relColumn
+	rows    [][]expr.Value
+}
+
+// load reads a table of FROM or JOIN into a relation
+func load(ref tableRef, tables Tables) (*relation, error) {
+	data, err := tables.lookup(ref.name)
+	if err != nil {
+		return nil, err
+	}
+	alias := ref.alias
+	if alias == "" {
+		alias = ref.name
+	}
+
+	rel := &relation{columns: make([]relColumn, len(data.Headers)), rows: make([][]expr.Value, len(data.Rows))}
+	for i, header := range data.Headers {
+		rel.columns[i] = relColumn{table: alias, name: header}
+	}
+	for r, row := range data.Rows {
+		values := make([]expr.Value, len(data.Headers))
+		for i, header := range data.Headers {
+			if i < len(row) {
+				values[i] = expr.FromModel(row[i], header)
+			} else {
+				values[i] = nullOf(header)
+			}
+		}
+		rel.rows[r] = values
+	}
+	return rel, nil
+}
+
+// nullOf returns a null value of a named column
+func nullOf(column string) expr.Value {
+	v := expr.Null()
+	v.Column = column
+	return v
+}
+
+// resolve finds the column a reference names: an exact match first, then
+// one that differs only in case
+func (r *relation) resolve(col *columnNode) (int, error) {
+	find := func(match func(a, b string) bool) (int, error) {
+		found := -1
+		for i, c := range r.columns {
+			if col.table != "" && !strings.EqualFold(c.table, col.table) {
+				continue
+			}
+			if !match(c.name, col.name) {
+				continue
+			}
+			if found >= 0 {
+				return -1, fmt.Errorf("column %q is ambiguous (in %s and %s)", col.name, r.columns[found].table, c.table)
+			}
+			found = i
+		}
+		return found, nil
+	}
+
+	index, err := find(func(a, b string) bool { return a == b })
+	if err == nil && index < 0 {
+		index, err = find(strings.EqualFold)
+	}
+	if err != nil {
+		return -1, err
+	}
+	if index < 0 {
+		if col.table != "" && !r.hasTable(col.table) {
+			return -1, fmt.Errorf("unknown table %q in %s", col.table, col)
+		}
+		return -1, fmt.Errorf("unknown column %q (columns: %s)", col.String(), strings.Join(r.names(), ", "))
+	}
+	return index, nil
+}
+
+// hasTable reports whether any column comes from the table alias
+func (r *relation) hasTable(table string) bool {
+	for _, c := range r.columns {
+		if strings.EqualFold(c.table, table) {
+			return true
+		}
+	}
+	return false
+}
+
+// names returns the column names, qualified where two tables share a name
+func (r *relation) names() []string {
+	count := make(map[string]int)
+	for _, c := range r.columns {
+		count[c.name]++
+	}
+	names := make([]string, len(r.columns))
+	for i, c := range r.columns {
+		names[i] = c.name
+		if count[c.name] > 1 {
+			names[i] = c.table + "." + c.name
+		}
+	}
+	return names
+}
+
+// bind resolves the column references below n
+func (r *relation) bind(n node) error {
+	return walk(n, func(n node) error {
+		if col, ok := n.(*columnNode); ok {
+			index, err := r.resolve(col)
+			if err != nil {
+				return err
+			}
+			col.index = index
+		}
+		return nil
+	})
+}
+
+// hasAggregate reports whether n contains an aggregate
+func hasAggregate(n node) bool {
+	found := false
+	walk(n, func(n node) error {
+		if _, ok := n.(*aggregateNode); ok {
+			found = true
+		}
+		return nil
+	})
+	return found
+}
+
+// Execute runs the statement against tables and returns its result
+func (s *Select) Execute(tables Tables) (*model.TableData, error) {
+	rel, err := s.joined(tables)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.where != nil {
+		if hasAggregate(s.where) {
+			return nil, fmt.Errorf("aggregate functions are not allowed in WHERE")
+		}
+		if err := rel.bind(s.where); err != nil {
+			return nil, err
+		}
+		kept := rel.rows[:0]
+		for i, row := range rel.rows {
+			ok, err := evalBool(s.where, &rowContext{row: row})
+			if err != nil {
+				return nil, fmt.Errorf("WHERE, row %d: %w", i+1, err)
+			}
+			if ok {
+				kept = append(kept, row)
+			}
+		}
+		rel.rows = kept
+	}
+
+	headers, outputs, err := s.outputs(rel)
+	if err != nil {
+		return nil, err
+	}
+	orderKeys, err := s.orderKeys(rel, headers, outputs)
+	if err != nil {
+		return nil, err
+	}
+
+	// Aggregates are numbered so that each group can hold their results
+	var aggs []*aggregateNode
+	collect := func(n node) error {
+		return walk(n, func(n node) error {
+			agg, ok := n.(*aggregateNode)
+			if !ok {
+				return nil
+			}
+			if agg.arg != nil && hasAggregate(agg.arg) {
+				return fmt.Errorf("aggregate functions cannot be nested")
+			}
+			agg.index = len(aggs)
+			aggs = append(aggs, agg)
+			return nil
+		})
+	}
+	for _, n := range outputs {
+		if err := collect(n); err != nil {
+			return nil, err
+		}
+	}
+	if err := collect(s.having); err != nil {
+		return nil, err
+	}
+	for _, k := range orderKeys {
+		if k.output < 0 {
+			if err := collect(k.expr); err != nil {
+				return nil, err
+			}
+		}
+	}
+	groupBy, err := s.groupKeys(rel, outputs)
+	if err != nil {
+		return nil, err
+	}
+	if s.having != nil {
+		if err := rel.bind(s.having); err != nil {
+			return nil, err
+		}
+	}
+
+	contexts, err := s.group(rel, groupBy, aggs)
+	if err != nil {
+		return nil, err
+	}
+
+	type result struct {
+		values []expr.Value
+		keys   []expr.Value
+	}
+	var results []result
+	seen := make(map[string]bool)
+	for _, ctx := range contexts {
+		if s.having != nil {
+			ok, err := evalBool(s.having, ctx)
+			if err != nil {
+				return nil, fmt.Errorf("HAVING: %w", err)
+			}
+			if !ok {
+				continue
+			}
+		}
+
+		values := make([]expr.Value, len(outputs))
+		for i, n := range outputs {
+			v, err := n.eval(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", headers[i], err)
+			}
+			values[i] = v
+		}
+		if s.distinct {
+			key := rowKey(values)
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+		}
+
+		keys := make([]expr.Value, len(orderKeys))
+		for i, k := range orderKeys {
+			if k.output >= 0 {
+				keys[i] = values[k.output]
+				continue
+			}
+			v, err := k.expr.eval(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("ORDER BY: %w", err)
+			}
+			keys[i] = v
+		}
+		results = append(results, result{values: values, keys: keys})
+	}
+
+	if len(orderKeys) > 0 {
+		slices.SortStableFunc(results, func(a, b result) int {
+			for i, k := range orderKeys {
+				if c := compareForSort(a.keys[i], b.keys[i], k.desc, k.nullsFirst); c != 0 {
+					return c
+				}
+			}
+			return 0
+		})
+	}
+
+	if s.offset > 0 {
+		results = results[min(s.offset, len(results)):]
+	}
+	if s.limit >= 0 && s.limit < len(results) {
+		results = results[:s.limit]
+	}
+
+	rows := make([][]model.Value, len(results))
+	for r, res := range results {
+		row := make([]model.Value, len(res.values))
+		for i, v := range res.values {
+			row[i] = v.ToModel()
+		}
+		rows[r] = row
+	}
+	return model.NewTableData(headers, rows), nil
+}
+
+// joined loads the tables of FROM and joins them
+func (s *Select) joined(tables Tables) (*relation, error) {
+	if s.from == nil {
+		// A SELECT without FROM evaluates its columns once
+		return &relation{rows: [][]expr.Value{{}}}, nil
+	}
+	rel, err := load(*s.from, tables)
+	if err != nil {
+		return nil, err
+	}
+	for _, j := range s.joins {
+		right, err := load(j.table, tables)
+		if err != nil {
+			return nil, err
+		}
+		if rel, err = joinRelations(rel, right, j); err != nil {
+			return nil, err
+		}
+	}
+	return rel, nil
+}
+
+// joinRelations joins two relations. Equality conditions between a column
+// of each side are used to match rows through a hash table; other
+// conditions are checked for each candidate pair
+func joinRelations(left, right *relation, j join) (*relation, error) {
+	out := &relation{columns: append(slices.Clone(left.columns), right.columns...)}
+	if j.on != nil {
+		if hasAggregate(j.on) {
+			return nil, fmt.Errorf("aggregate functions are not allowed in ON")
+		}
+		if err := out.bind(j.on); err != nil {
+			return nil, err
+		}
+	}
+
+	leftKeys, rightKeys := equiKeys(j.on, len(left.columns))
+	var index map[string][]int
+	if len(leftKeys) > 0 {
+		index = make(map[string][]int)
+		for r, row := range right.rows {
+			if key, ok := joinKey(row, rightKeys, len(left.columns)); ok {
+				index[key] = append(index[key], r)
+			}
+		}
+	}
+
+	rightMatched := make([]bool, len(right.rows))
+	for _, lrow := range left.rows {
+		var candidates []int
+		if index != nil {
+			key, ok := joinKey(lrow, leftKeys, 0)
+			if ok {
+				candidates = index[key]
+			}
+		} else {
+			candidates = make([]int, len(right.rows))
+			for i := range candidates {
+				candidates[i] = i
+			}
+		}
+
+		matched := false
+		for _, r := range candidates {
+			row := append(slices.Clone(lrow), right.rows[r]...)
+			if j.on != nil {
+				ok, err := evalBool(j.on, &rowContext{row: row})
+				if err != nil {
+					return nil, fmt.Errorf("JOIN %s ON: %w", j.table.name, err)
+				}
+				if !ok {
+					continue
+				}
+			}
+			matched = true
+			rightMatched[r] = true
+			out.rows = append(out.rows, row)
+		}
+		if !matched && (j.kind == joinLeft || j.kind == joinFull) {
+			out.rows = append(out.rows, append(slices.Clone(lrow), nullRow(right.columns)...))
+		}
+	}
+	if j.kind == joinRight || j.kind == joinFull {
+		for r, ok := range rightMatched {
+			if !ok {
+				out.rows = append(out.rows, append(nullRow(left.columns), right.rows[r]...))
+			}
+		}
+	}
+	return out, nil
+}
+
+// equiKeys finds the conditions of an ON clause of the form a = b where a
+// is a column of the left side and b of the right, and returns the column
+// indexes of each side
+func equiKeys(on node, split int) (left, right []int) {
+	switch n := on.(type) {
+	case *logicalNode:
+		if n.and {
+			l1, r1 := equiKeys(n.left, split)
+			l2, r2 := equiKeys(n.right, split)
+			return append(l1, l2...), append(r1, r2...)
+		}
+	case *compareNode:
+		a, ok1 := n.left.(*columnNode)
+		b, ok2 := n.right.(*columnNode)
+		if n.op != "==" || !ok1 || !ok2 {
+			return nil, nil
+		}
+		if a.index >= split {
+			a, b = b, a
+		}
+		if a.index < split && b.index >= split {
+			return []int{a.index}, []int{b.index}
+		}
+	}
+	return nil, nil
+}
+
+// joinKey returns the hash key of the join columns of a row. Rows with a
+// null join column match nothing
+func joinKey(row []expr.Value, columns []int, offset int) (string, bool) {
+	parts := make([]string, len(columns))
+	for i, col := range columns {
+		v := row[col-offset]
+		if v.Kind == expr.KindNull {
+			return "", false
+		}
+		parts[i] = expr.DistinctKey(v)
+	}
+	return strings.Join(parts, "\x00"), true
+}
+
+// nullRow returns a row of nulls for the columns
+func nullRow(columns []relColumn) []expr.Value {
+	row := make([]expr.Value, len(columns))
+	for i, c := range columns {
+		row[i] = nullOf(c.name)
+	}
+	return row
+}
+
+// rowKey returns a key that is equal for rows of equal values
+func rowKey(values []expr.Value) string {
+	parts := make([]string, len(values))
+	for i, v := range values {
+		parts[i] = expr.DistinctKey(v)
+	}
+	return strings.Join(parts, "\x00")
+}
+
+// outputs returns the headers and expressions of the result columns,
+// expanding * and table.*
+func (s *Select) outputs(rel *relation) ([]string, []node, error) {
+	var headers []string
+	var outputs []node
+	for _, item := range s.items {
+		if item.star {
+			found := false
+			for i, c := range rel.columns {
+				if item.starTable != "" && !strings.EqualFold(c.table, item.starTable) {
+					continue
+				}
+				found = true
+				headers = append(headers, c.name)
+				outputs = append(outputs, &columnNode{table: c.table, name: c.name, index: i})
+			}
+			if !found && item.starTable != "" {
+				return nil, nil, fmt.Errorf("unknown table %q in %s.*", item.starTable, item.starTable)
+			}
+			continue
+		}
+
+		if err := rel.bind(item.expr); err != nil {
+			return nil, nil, err
+		}
+		header := item.alias
+		if header == "" {
+			if col, ok := item.expr.(*columnNode); ok {
+				header = rel.columns[col.index].name
+			} else {
+				header = item.text
+			}
+		}
+		headers = append(headers, header)
+		outputs = append(outputs, item.expr)
+	}
+	return headers, outputs, nil
+}
+
+// orderKey is a bound ORDER BY key: either a result column or an
+// expression over the source rows
+type orderKey struct {
+	output     int // index of the result column, or -1
+	expr       node
+	desc       bool
+	nullsFirst bool
+}
+
+// orderKeys binds the ORDER BY keys. A key may name a result column by its
+// alias, give its 1-based position, or be an expression
+func (s *Select) orderKeys(rel *relation, headers []string, outputs []node) ([]orderKey, error) {
+	keys := make([]orderKey, len(s.orderBy))
+	for i, item := range s.orderBy {
+		key := orderKey{output: -1, expr: item.expr, desc: item.desc, nullsFirst: item.nullsFirst}
+
+		switch n := item.expr.(type) {
+		case *literalNode:
+			if n.value.Kind == expr.KindNumber {
+				pos := int(n.value.Num)
+				if float64(pos) != n.value.Num || pos < 1 || pos > len(outputs) {
+					return nil, fmt.Errorf("ORDER BY position %s is not in the select list", n.value)
+				}
+				key.output = pos - 1
+			}
+		case *columnNode:
+			if n.table == "" {
+				for j, item := range s.items {
+					if !item.star && item.alias != "" && item.alias == n.name {
+						key.output = s.outputIndex(j, rel)
+						break
+					}
+				}
+			}
+		}
+		if key.output < 0 {
+			if err := rel.bind(item.expr); err != nil {
+				return nil, err
+			}
+		}
+		keys[i] = key
+	}
+	return keys, nil
+}
+
+// outputIndex returns the result column of the select item at index item,
+// counting the columns that * items expand to
+func (s *Select) outputIndex(item int, rel *relation) int {
+	index := 0
+	for _, it := range s.items[:item] {
+		if !it.star {
+			index++
+			continue
+		}
+		for _, c := range rel.columns {
+			if it.starTable == "" || strings.EqualFold(c.table, it.starTable) {
+				index++
+			}
+		}
+	}
+	return index
+}
+
+// groupKeys binds the GROUP BY keys. As in ORDER BY, a key may give the
+// 1-based position of a result column, or name one by its alias when no
+// source column has that name
+func (s *Select) groupKeys(rel *relation, outputs []node) ([]node, error) {
+	keys := make([]node, len(s.groupBy))
+	for i, n := range s.groupBy {
+		keys[i] = n
+		switch n := n.(type) {
+		case *literalNode:
+			if n.value.Kind == expr.KindNumber {
+				pos := int(n.value.Num)
+				if float64(pos) != n.value.Num || pos < 1 || pos > len(outputs) {
+					return nil, fmt.Errorf("GROUP BY position %s is not in the select list", n.value)
+				}
+				keys[i] = outputs[pos-1]
+			}
+		case *columnNode:
+			if _, err := rel.resolve(n); err != nil && n.table == "" {
+				for _, item := range s.items {
+					if !item.star && item.alias != "" && strings.EqualFold(item.alias, n.name) {
+						keys[i] = item.expr
+						break
+					}
+				}
+			}
+		}
+
+		if hasAggregate(keys[i]) {
+			if keys[i] != n {
+				return nil, fmt.Errorf("GROUP BY key %d refers to an aggregate in the select list", i+1)
+			}
+			return nil, fmt.Errorf("aggregate functions are not allowed in GROUP BY")
+		}
+		if err := rel.bind(keys[i]); err != nil {
+			return nil, err
+		}
+	}
+	return keys, nil
+}
+
+// group returns the contexts the result columns are evaluated in: one per
+// row, or one per group when the statement groups or aggregates. Groups
+// are in the order their first row appears
+func (s *Select) group(rel *relation, groupBy []node, aggs []*aggregateNode) ([]*rowContext, error) {
+	if len(groupBy) == 0 && len(aggs) == 0 {
+		contexts := make([]*rowContext, len(rel.rows))
+		for i, row := range rel.rows {
+			contexts[i] = &rowContext{row: row}
+		}
+		return contexts, nil
+	}
+
+	type group struct {
+		first       []expr.Value
+		aggregators []expr.Aggregator
+	}
+	var groups []*group
+	byKey := make(map[string]*group)
+	newGroup := func(first []expr.Value) (*group, error) {
+		g := &group{first: first, aggregators: make([]expr.Aggregator, len(aggs))}
+		for i, agg := range aggs {
+			a, err := agg.newAggregator()
+			if err != nil {
+				return nil, err
+			}
+			g.aggregators[i] = a
+		}
+		groups = append(groups, g)
+		return g, nil
+	}
+
+	for r, row := range rel.rows {
+		ctx := &rowContext{row: row}
+		keys := make([]expr.Value, len(groupBy))
+		for i, n := range groupBy {
+			v, err := n.eval(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("GROUP BY, row %d: %w", r+1, err)
+			}
+			keys[i] = v
+		}
+		key := rowKey(keys)
+		g, ok := byKey[key]
+		if !ok {
+			var err error
+			if g, err = newGroup(row); err != nil {
+				return nil, err
+			}
+			byKey[key] = g
+		}
+
+		for i, agg := range aggs {
+			v := expr.Bool(true) // COUNT(*) counts every row
+			if agg.arg != nil {
+				var err error
+				if v, err = agg.arg.eval(ctx); err != nil {
+					return nil, fmt.Errorf("%s, row %d: %w", strings.ToUpper(agg.name), r+1, err)
+				}
+			}
+			if err := g.aggregators[i].Add(v); err != nil {
+				return nil, fmt.Errorf("%s, row %d: %w", strings.ToUpper(agg.name), r+1, err)
+			}
+		}
+	}
+
+	// Aggregating without GROUP BY gives one row even when there are none
+	if len(groups) == 0 && len(groupBy) == 0 {
+		if _, err := newGroup(nullRow(rel.columns)); err != nil {
+			return nil, err
+		}
+	}
+
+	contexts := make([]*rowContext, len(groups))
+	for i, g := range groups {
+		results := make([]expr.Value, len(aggs))
+		for j, a := range g.aggregators {
+			results[j] = a.Result()
+		}
+		contexts[i] = &rowContext{row: g.first, aggs: results}
+	}
+	return contexts, nil
+}
+
+// compareForSort orders two values of an ORDER BY key. Values that cannot
+// be compared by type are compared as text
+func compareForSort(a, b expr.Value, desc, nullsFirst bool) int {
+	aNull, bNull := a.Kind == expr.KindNull, b.Kind == expr.KindNull
+	switch {
+	case aNull && bNull:
+		return 0
+	case aNull:
+		if nullsFirst {
+			return -1
+		}
+		return 1
+	case bNull:
+		if nullsFirst {
+			return 1
+		}
+		return -1
+	}
+
+	c, err := expr.Compare(a, b)
+	if err != nil {
+		c = strings.Compare(a.String(), b.String())
+	}
+	if desc {
+		c = -c
+	}
+	return c
+}
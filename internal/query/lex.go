@@ -0,0 +1,171 @@
+package query
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// tokenKind is the type of a lexical token
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokNumber
+	tokString // 'string literal'
+	tokIdent  // keyword or unquoted name
+	tokQuoted // "quoted name" or `quoted name`
+	tokOp     // operator or punctuation
+)
+
+// token is a lexical token and its byte offset in the statement
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+// is reports whether the token is the keyword or operator s
+func (t token) is(s string) bool {
+	switch t.kind {
+	case tokIdent:
+		return strings.EqualFold(t.text, s)
+	case tokOp:
+		return t.text == s
+	}
+	return false
+}
+
+// operators lists the operators, longest first so that <= wins over <
+var operators = []string{
+	"<>", "!=", "<=", ">=", "==", "||",
+	"=", "<", ">", "+", "-", "*", "/", "%", "(", ")", ",", ".", ";",
+}
+
+// SyntaxError reports a statement that cannot be parsed
+type SyntaxError struct {
+	// Message describes the problem
+	Message string
+	// Pos is the 1-based character position of the problem
+	Pos int
+}
+
+// Error implements the error interface
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("syntax error at position %d: %s", e.Pos, e.Message)
+}
+
+// syntaxError creates a SyntaxError at a byte offset of the statement
+func syntaxError(src string, offset int, format string, args ...interface{}) *SyntaxError {
+	return &SyntaxError{
+		Message: fmt.Sprintf(format, args...),
+		Pos:     utf8.RuneCountInString(src[:offset]) + 1,
+	}
+}
+
+// lex splits a statement into tokens. Comments start with -- and run to
+// the end of the line
+func lex(src string) ([]token, error) {
+	var tokens []token
+	i := 0
+	for i < len(src) {
+		r, size := utf8.DecodeRuneInString(src[i:])
+		switch {
+		case unicode.IsSpace(r):
+			i += size
+
+		case strings.HasPrefix(src[i:], "--"):
+			end := strings.IndexByte(src[i:], '\n')
+			if end < 0 {
+				i = len(src)
+			} else {
+				i += end
+			}
+
+		case isDigit(src[i]) || src[i] == '.' && i+1 < len(src) && isDigit(src[i+1]):
+			start := i
+			for i < len(src) && (isDigit(src[i]) || src[i] == '.') {
+				i++
+			}
+			if i < len(src) && (src[i] == 'e' || src[i] == 'E') {
+				j := i + 1
+				if j < len(src) && (src[j] == '+' || src[j] == '-') {
+					j++
+				}
+				if j < len(src) && isDigit(src[j]) {
+					for i = j; i < len(src) && isDigit(src[i]); i++ {
+					}
+				}
+			}
+			tokens = append(tokens, token{tokNumber, src[start:i], start})
+
+		case r == '\'' || r == '"' || r == '`':
+			text, end, err := lexQuoted(src, i)
+			if err != nil {
+				return nil, err
+			}
+			kind := tokQuoted
+			if r == '\'' {
+				kind = tokString
+			}
+			tokens = append(tokens, token{kind, text, i})
+			i = end
+
+		case r == '_' || unicode.IsLetter(r):
+			start := i
+			for i < len(src) {
+				r, size := utf8.DecodeRuneInString(src[i:])
+				if r != '_' && !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+					break
+				}
+				i += size
+			}
+			tokens = append(tokens, token{tokIdent, src[start:i], start})
+
+		default:
+			op := ""
+			for _, candidate := range operators {
+				if strings.HasPrefix(src[i:], candidate) {
+					op = candidate
+					break
+				}
+			}
+			if op == "" {
+				return nil, syntaxError(src, i, "unexpected character %q", r)
+			}
+			tokens = append(tokens, token{tokOp, op, i})
+			i += len(op)
+		}
+	}
+	return append(tokens, token{tokEOF, "", len(src)}), nil
+}
+
+// lexQuoted reads a string or quoted name starting at src[start], returning
+// its text and the offset after the closing quote. A doubled quote stands
+// for the quote itself, as in 'it”s'
+func lexQuoted(src string, start int) (string, int, error) {
+	quote := src[start]
+	var b strings.Builder
+	for i := start + 1; i < len(src); i++ {
+		if src[i] != quote {
+			b.WriteByte(src[i])
+			continue
+		}
+		if i+1 < len(src) && src[i+1] == quote {
+			b.WriteByte(quote)
+			i++
+			continue
+		}
+		return b.String(), i + 1, nil
+	}
+	if quote == '\'' {
+		return "", 0, syntaxError(src, start, "unterminated string")
+	}
+	return "", 0, syntaxError(src, start, "unterminated quoted name")
+}
+
+// isDigit reports whether c is an ASCII digit
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
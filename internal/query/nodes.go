@@ -0,0 +1,488 @@
+package query
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strings"
+
+	"github.com/user/table-converter/internal/expr"
+)
+
+// node is a node of an expression tree
+type node interface {
+	eval(ctx *rowContext) (expr.Value, error)
+	children() []node
+}
+
+// rowContext is what an expression is evaluated against: a row of the
+// joined tables and, when aggregating, the aggregate results of its group
+type rowContext struct {
+	row  []expr.Value
+	aggs []expr.Value
+}
+
+// walk calls fn for n and every node below it
+func walk(n node, fn func(node) error) error {
+	if n == nil {
+		return nil
+	}
+	if err := fn(n); err != nil {
+		return err
+	}
+	for _, child := range n.children() {
+		if err := walk(child, fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// literalNode is a constant
+type literalNode struct {
+	value expr.Value
+}
+
+func (n *literalNode) eval(*rowContext) (expr.Value, error) { return n.value, nil }
+func (n *literalNode) children() []node                     { return nil }
+
+// columnNode reads a column of the row. index is set when the statement is
+// bound to its tables
+type columnNode struct {
+	table, name string
+	pos         int
+	index       int
+}
+
+func (n *columnNode) eval(ctx *rowContext) (expr.Value, error) {
+	return ctx.row[n.index], nil
+}
+
+func (n *columnNode) children() []node { return nil }
+
+// String returns the column as written
+func (n *columnNode) String() string {
+	if n.table != "" {
+		return n.table + "." + n.name
+	}
+	return n.name
+}
+
+// logicalNode is a AND b or a OR b. Null counts as false
+type logicalNode struct {
+	and         bool
+	left, right node
+}
+
+func (n *logicalNode) eval(ctx *rowContext) (expr.Value, error) {
+	left, err := evalBool(n.left, ctx)
+	if err != nil || left != n.and {
+		return expr.Bool(left), err
+	}
+	right, err := evalBool(n.right, ctx)
+	return expr.Bool(right), err
+}
+
+func (n *logicalNode) children() []node { return []node{n.left, n.right} }
+
+// notNode is NOT a
+type notNode struct {
+	operand node
+}
+
+func (n *notNode) eval(ctx *rowContext) (expr.Value, error) {
+	v, err := evalBool(n.operand, ctx)
+	return expr.Bool(!v), err
+}
+
+func (n *notNode) children() []node { return []node{n.operand} }
+
+// evalBool evaluates a node as a condition
+func evalBool(n node, ctx *rowContext) (bool, error) {
+	v, err := n.eval(ctx)
+	if err != nil {
+		return false, err
+	}
+	return v.AsBool()
+}
+
+// compareNode compares two values as the -where expressions do
+type compareNode struct {
+	op          string
+	left, right node
+}
+
+func (n *compareNode) eval(ctx *rowContext) (expr.Value, error) {
+	left, err := n.left.eval(ctx)
+	if err != nil {
+		return expr.Value{}, err
+	}
+	right, err := n.right.eval(ctx)
+	if err != nil {
+		return expr.Value{}, err
+	}
+	return expr.Comparison(n.op, left, right)
+}
+
+func (n *compareNode) children() []node { return []node{n.left, n.right} }
+
+// isNullNode is a IS [NOT] NULL
+type isNullNode struct {
+	operand node
+	not     bool
+}
+
+func (n *isNullNode) eval(ctx *rowContext) (expr.Value, error) {
+	v, err := n.operand.eval(ctx)
+	if err != nil {
+		return expr.Value{}, err
+	}
+	return expr.Bool((v.Kind == expr.KindNull) != n.not), nil
+}
+
+func (n *isNullNode) children() []node { return []node{n.operand} }
+
+// inNode is a [NOT] IN (b, c, ...)
+type inNode struct {
+	operand node
+	list    []node
+	not     bool
+}
+
+func (n *inNode) eval(ctx *rowContext) (expr.Value, error) {
+	v, err := n.operand.eval(ctx)
+	if err != nil {
+		return expr.Value{}, err
+	}
+	if v.Kind == expr.KindNull {
+		return expr.Bool(false), nil
+	}
+	for _, item := range n.list {
+		candidate, err := item.eval(ctx)
+		if err != nil {
+			return expr.Value{}, err
+		}
+		eq, err := expr.Comparison("==", v, candidate)
+		if err != nil {
+			return expr.Value{}, err
+		}
+		if eq.Bool {
+			return expr.Bool(!n.not), nil
+		}
+	}
+	return expr.Bool(n.not), nil
+}
+
+func (n *inNode) children() []node { return append([]node{n.operand}, n.list...) }
+
+// betweenNode is a [NOT] BETWEEN low AND high, inclusive
+type betweenNode struct {
+	operand, low, high node
+	not                bool
+}
+
+func (n *betweenNode) eval(ctx *rowContext) (expr.Value, error) {
+	v, err := n.operand.eval(ctx)
+	if err != nil {
+		return expr.Value{}, err
+	}
+	low, err := n.low.eval(ctx)
+	if err != nil {
+		return expr.Value{}, err
+	}
+	high, err := n.high.eval(ctx)
+	if err != nil {
+		return expr.Value{}, err
+	}
+	if v.Kind == expr.KindNull || low.Kind == expr.KindNull || high.Kind == expr.KindNull {
+		return expr.Bool(false), nil
+	}
+	above, err := expr.Comparison(">=", v, low)
+	if err != nil {
+		return expr.Value{}, err
+	}
+	below, err := expr.Comparison("<=", v, high)
+	if err != nil {
+		return expr.Value{}, err
+	}
+	return expr.Bool((above.Bool && below.Bool) != n.not), nil
+}
+
+func (n *betweenNode) children() []node { return []node{n.operand, n.low, n.high} }
+
+// likeNode is a [NOT] LIKE pattern, where % matches any text and _ any one
+// character. ILIKE ignores case
+type likeNode struct {
+	operand, pattern node
+	not, fold        bool
+	cache            map[string]*regexp.Regexp
+}
+
+func (n *likeNode) eval(ctx *rowContext) (expr.Value, error) {
+	v, err := n.operand.eval(ctx)
+	if err != nil {
+		return expr.Value{}, err
+	}
+	pattern, err := n.pattern.eval(ctx)
+	if err != nil {
+		return expr.Value{}, err
+	}
+	if v.Kind == expr.KindNull || pattern.Kind == expr.KindNull {
+		return expr.Bool(false), nil
+	}
+
+	re, ok := n.cache[pattern.String()]
+	if !ok {
+		re = likeRegexp(pattern.String(), n.fold)
+		if n.cache == nil {
+			n.cache = make(map[string]*regexp.Regexp)
+		}
+		n.cache[pattern.String()] = re
+	}
+	return expr.Bool(re.MatchString(v.String()) != n.not), nil
+}
+
+func (n *likeNode) children() []node { return []node{n.operand, n.pattern} }
+
+// likeRegexp converts a LIKE pattern to an anchored regexp
+func likeRegexp(pattern string, fold bool) *regexp.Regexp {
+	var b strings.Builder
+	b.WriteString("^(?s)")
+	if fold {
+		b.WriteString("(?i)")
+	}
+	for _, r := range pattern {
+		switch r {
+		case '%':
+			b.WriteString(".*")
+		case '_':
+			b.WriteString(".")
+		default:
+			b.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	b.WriteString("$")
+	return regexp.MustCompile(b.String())
+}
+
+// concatNode is a || b. Null operands give null
+type concatNode struct {
+	left, right node
+}
+
+func (n *concatNode) eval(ctx *rowContext) (expr.Value, error) {
+	left, err := n.left.eval(ctx)
+	if err != nil {
+		return expr.Value{}, err
+	}
+	right, err := n.right.eval(ctx)
+	if err != nil {
+		return expr.Value{}, err
+	}
+	if left.Kind == expr.KindNull || right.Kind == expr.KindNull {
+		return expr.Null(), nil
+	}
+	return expr.String(left.String() + right.String()), nil
+}
+
+func (n *concatNode) children() []node { return []node{n.left, n.right} }
+
+// arithNode is a + b, a - b, a * b, a / b or a % b
+type arithNode struct {
+	op          string
+	left, right node
+}
+
+func (n *arithNode) eval(ctx *rowContext) (expr.Value, error) {
+	left, err := n.left.eval(ctx)
+	if err != nil {
+		return expr.Value{}, err
+	}
+	right, err := n.right.eval(ctx)
+	if err != nil {
+		return expr.Value{}, err
+	}
+	return expr.Arithmetic(n.op, left, right)
+}
+
+func (n *arithNode) children() []node { return []node{n.left, n.right} }
+
+// callNode calls a scalar function
+type callNode struct {
+	name string
+	args []node
+}
+
+func (n *callNode) eval(ctx *rowContext) (expr.Value, error) {
+	args := make([]expr.Value, len(n.args))
+	for i, arg := range n.args {
+		v, err := arg.eval(ctx)
+		if err != nil {
+			return expr.Value{}, err
+		}
+		args[i] = v
+	}
+	return expr.CallFunction(n.name, args)
+}
+
+func (n *callNode) children() []node { return n.args }
+
+// aggregateNode is an aggregate such as SUM(x) or COUNT(*). Its result is
+// computed over each group before the output is evaluated; index is the
+// position of that result in the row context
+type aggregateNode struct {
+	name     string
+	arg      node // nil for COUNT(*)
+	star     bool
+	distinct bool
+	pos      int
+	index    int
+}
+
+func (n *aggregateNode) eval(ctx *rowContext) (expr.Value, error) {
+	return ctx.aggs[n.index], nil
+}
+
+func (n *aggregateNode) children() []node {
+	if n.arg == nil {
+		return nil
+	}
+	return []node{n.arg}
+}
+
+// newAggregator creates the aggregator that computes the node's result
+func (n *aggregateNode) newAggregator() (expr.Aggregator, error) {
+	name := n.name
+	if n.distinct && name == "count" {
+		name, n.distinct = "count_distinct", false
+	}
+	agg, err := expr.NewAggregator(name)
+	if err != nil {
+		return nil, err
+	}
+	if n.distinct {
+		return &distinctAggregator{Aggregator: agg, seen: make(map[string]bool)}, nil
+	}
+	return agg, nil
+}
+
+// distinctAggregator passes each distinct value to an aggregator once
+type distinctAggregator struct {
+	expr.Aggregator
+	seen map[string]bool
+}
+
+func (a *distinctAggregator) Add(v expr.Value) error {
+	key := expr.DistinctKey(v)
+	if a.seen[key] {
+		return nil
+	}
+	a.seen[key] = true
+	return a.Aggregator.Add(v)
+}
+
+// whenClause is a WHEN ... THEN ... of a CASE
+type whenClause struct {
+	cond, result node
+}
+
+// caseNode is CASE [operand] WHEN ... THEN ... [ELSE ...] END. With an
+// operand, each WHEN value is compared with it; without one, each WHEN is
+// a condition
+type caseNode struct {
+	operand node
+	whens   []whenClause
+	els     node
+}
+
+func (n *caseNode) eval(ctx *rowContext) (expr.Value, error) {
+	var operand expr.Value
+	if n.operand != nil {
+		var err error
+		if operand, err = n.operand.eval(ctx); err != nil {
+			return expr.Value{}, err
+		}
+	}
+	for _, w := range n.whens {
+		var matched bool
+		if n.operand != nil {
+			v, err := w.cond.eval(ctx)
+			if err != nil {
+				return expr.Value{}, err
+			}
+			if operand.Kind != expr.KindNull && v.Kind != expr.KindNull {
+				eq, err := expr.Comparison("==", operand, v)
+				if err != nil {
+					return expr.Value{}, err
+				}
+				matched = eq.Bool
+			}
+		} else {
+			var err error
+			if matched, err = evalBool(w.cond, ctx); err != nil {
+				return expr.Value{}, err
+			}
+		}
+		if matched {
+			return w.result.eval(ctx)
+		}
+	}
+	if n.els != nil {
+		return n.els.eval(ctx)
+	}
+	return expr.Null(), nil
+}
+
+func (n *caseNode) children() []node {
+	children := []node{n.operand, n.els}
+	for _, w := range n.whens {
+		children = append(children, w.cond, w.result)
+	}
+	var out []node
+	for _, c := range children {
+		if c != nil {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// castNode is CAST(x AS type)
+type castNode struct {
+	operand node
+	kind    expr.Kind
+	integer bool
+}
+
+func (n *castNode) eval(ctx *rowContext) (expr.Value, error) {
+	v, err := n.operand.eval(ctx)
+	if err != nil || v.Kind == expr.KindNull {
+		return v, err
+	}
+	switch n.kind {
+	case expr.KindNumber:
+		num, err := v.AsNumber()
+		if err != nil {
+			return expr.Value{}, fmt.Errorf("CAST: %w", err)
+		}
+		if n.integer {
+			num = math.Trunc(num)
+		}
+		return expr.Number(num), nil
+	case expr.KindDate:
+		t, err := v.AsDate()
+		if err != nil {
+			return expr.Value{}, fmt.Errorf("CAST: %w", err)
+		}
+		return expr.Date(t), nil
+	case expr.KindBool:
+		b, err := v.AsBool()
+		if err != nil {
+			return expr.Value{}, fmt.Errorf("CAST: %w", err)
+		}
+		return expr.Bool(b), nil
+	}
+	return expr.String(v.String()), nil
+}
+
+func (n *castNode) children() []node { return []node{n.operand} }
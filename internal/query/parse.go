@@ -0,0 +1,818 @@
+package query
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/user/table-converter/internal/expr"
+)
+
+// Select is a parsed SELECT statement
+type Select struct {
+	src      string
+	distinct bool
+	items    []selectItem
+	from     *tableRef // nil for a SELECT without FROM
+	joins    []join
+	where    node
+	groupBy  []node
+	having   node
+	orderBy  []orderItem
+	limit    int // -1 = no limit
+	offset   int
+}
+
+// selectItem is an output column, or * or table.* for all columns
+type selectItem struct {
+	expr      node
+	alias     string
+	text      string // the expression as written, naming unaliased columns
+	star      bool
+	starTable string
+}
+
+// tableRef is a table in FROM or JOIN
+type tableRef struct {
+	name  string
+	alias string
+	pos   int
+}
+
+// joinKind is the type of a JOIN
+type joinKind int
+
+const (
+	joinInner joinKind = iota
+	joinLeft
+	joinRight
+	joinFull
+	joinCross
+)
+
+// join is a JOIN clause
+type join struct {
+	kind  joinKind
+	table tableRef
+	on    node // nil for CROSS JOIN
+}
+
+// orderItem is an ORDER BY key
+type orderItem struct {
+	expr       node
+	desc       bool
+	nullsFirst bool
+}
+
+// reserved lists the keywords that cannot be used as unquoted aliases
+var reserved = map[string]bool{
+	"select": true, "from": true, "where": true, "group": true, "by": true, "having": true,
+	"order": true, "limit": true, "offset": true, "join": true, "inner": true, "left": true,
+	"right": true, "full": true, "outer": true, "cross": true, "on": true, "as": true,
+	"and": true, "or": true, "not": true, "is": true, "null": true, "in": true,
+	"between": true, "like": true, "ilike": true, "case": true, "when": true, "then": true,
+	"else": true, "end": true, "distinct": true, "asc": true, "desc": true, "nulls": true,
+	"true": true, "false": true, "union": true,
+}
+
+// Parse parses a SELECT statement
+func Parse(sql string) (*Select, error) {
+	tokens, err := lex(sql)
+	if err != nil {
+		return nil, err
+	}
+	p := &sqlParser{src: sql, tokens: tokens}
+	stmt, err := p.parseSelect()
+	if err != nil {
+		return nil, err
+	}
+	p.accept(";")
+	if tok := p.peek(); tok.kind != tokEOF {
+		return nil, p.unexpected(tok)
+	}
+	return stmt, nil
+}
+
+// sqlParser is a recursive descent parser over the tokens of a statement
+type sqlParser struct {
+	src    string
+	tokens []token
+	pos    int
+}
+
+// peek returns the next token without consuming it
+func (p *sqlParser) peek() token {
+	return p.tokens[p.pos]
+}
+
+// peekAt returns the token n places ahead without consuming anything
+func (p *sqlParser) peekAt(n int) token {
+	if p.pos+n < len(p.tokens) {
+		return p.tokens[p.pos+n]
+	}
+	return p.tokens[len(p.tokens)-1]
+}
+
+// next consumes and returns the next token
+func (p *sqlParser) next() token {
+	tok := p.tokens[p.pos]
+	if tok.kind != tokEOF {
+		p.pos++
+	}
+	return tok
+}
+
+// accept consumes the next token if it is the keyword or operator s
+func (p *sqlParser) accept(s string) bool {
+	if p.peek().is(s) {
+		p.next()
+		return true
+	}
+	return false
+}
+
+// expect consumes the keyword or operator s or fails
+func (p *sqlParser) expect(s string) error {
+	if !p.accept(s) {
+		tok := p.peek()
+		if tok.kind == tokEOF {
+			return syntaxError(p.src, tok.pos, "expected %s at end of statement", strings.ToUpper(s))
+		}
+		return syntaxError(p.src, tok.pos, "expected %s, found %s", strings.ToUpper(s), describeToken(tok))
+	}
+	return nil
+}
+
+// unexpected creates the error for a token that cannot appear where it is
+func (p *sqlParser) unexpected(tok token) *SyntaxError {
+	if tok.kind == tokEOF {
+		return syntaxError(p.src, tok.pos, "unexpected end of statement")
+	}
+	return syntaxError(p.src, tok.pos, "unexpected %s", describeToken(tok))
+}
+
+// describeToken returns a token as written, for error messages
+func describeToken(tok token) string {
+	switch tok.kind {
+	case tokString:
+		return "'" + tok.text + "'"
+	case tokQuoted:
+		return strconv.Quote(tok.text)
+	}
+	return tok.text
+}
+
+// name consumes a table, column or alias name
+func (p *sqlParser) name(what string) (string, error) {
+	tok := p.peek()
+	if tok.kind == tokQuoted || (tok.kind == tokIdent && !reserved[strings.ToLower(tok.text)]) {
+		p.next()
+		return tok.text, nil
+	}
+	if tok.kind == tokEOF {
+		return "", syntaxError(p.src, tok.pos, "expected %s at end of statement", what)
+	}
+	return "", syntaxError(p.src, tok.pos, "expected %s, found %s", what, describeToken(tok))
+}
+
+// alias consumes an optional AS alias
+func (p *sqlParser) alias() (string, error) {
+	if p.accept("as") {
+		return p.name("alias")
+	}
+	tok := p.peek()
+	if tok.kind == tokQuoted || (tok.kind == tokIdent && !reserved[strings.ToLower(tok.text)]) {
+		p.next()
+		return tok.text, nil
+	}
+	return "", nil
+}
+
+// parseSelect parses SELECT ... [FROM ...] [WHERE ...] [GROUP BY ...]
+// [HAVING ...] [ORDER BY ...] [LIMIT n [OFFSET m]]
+func (p *sqlParser) parseSelect() (*Select, error) {
+	if err := p.expect("select"); err != nil {
+		return nil, err
+	}
+	stmt := &Select{src: p.src, limit: -1}
+	if p.accept("distinct") {
+		stmt.distinct = true
+	} else {
+		p.accept("all")
+	}
+
+	for {
+		item, err := p.parseSelectItem()
+		if err != nil {
+			return nil, err
+		}
+		stmt.items = append(stmt.items, item)
+		if !p.accept(",") {
+			break
+		}
+	}
+
+	if p.accept("from") {
+		if err := p.parseFrom(stmt); err != nil {
+			return nil, err
+		}
+	}
+
+	var err error
+	if p.accept("where") {
+		if stmt.where, err = p.parseExpr(); err != nil {
+			return nil, err
+		}
+	}
+	if p.accept("group") {
+		if err := p.expect("by"); err != nil {
+			return nil, err
+		}
+		for {
+			e, err := p.parseExpr()
+			if err != nil {
+				return nil, err
+			}
+			stmt.groupBy = append(stmt.groupBy, e)
+			if !p.accept(",") {
+				break
+			}
+		}
+	}
+	if p.accept("having") {
+		if stmt.having, err = p.parseExpr(); err != nil {
+			return nil, err
+		}
+	}
+	if p.accept("order") {
+		if err := p.expect("by"); err != nil {
+			return nil, err
+		}
+		for {
+			item, err := p.parseOrderItem()
+			if err != nil {
+				return nil, err
+			}
+			stmt.orderBy = append(stmt.orderBy, item)
+			if !p.accept(",") {
+				break
+			}
+		}
+	}
+	if p.accept("limit") {
+		if stmt.limit, err = p.count("LIMIT"); err != nil {
+			return nil, err
+		}
+		if p.accept("offset") {
+			if stmt.offset, err = p.count("OFFSET"); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if tok := p.peek(); tok.is("union") {
+		return nil, syntaxError(p.src, tok.pos, "UNION is not supported")
+	}
+	return stmt, nil
+}
+
+// parseSelectItem parses an output column with an optional alias, *, or
+// table.*
+func (p *sqlParser) parseSelectItem() (selectItem, error) {
+	if p.accept("*") {
+		return selectItem{star: true}, nil
+	}
+	if tok := p.peek(); (tok.kind == tokIdent || tok.kind == tokQuoted) && p.peekAt(1).is(".") && p.peekAt(2).is("*") {
+		p.pos += 3
+		return selectItem{star: true, starTable: tok.text}, nil
+	}
+
+	start := p.peek().pos
+	e, err := p.parseExpr()
+	if err != nil {
+		return selectItem{}, err
+	}
+	item := selectItem{expr: e, text: strings.TrimSpace(p.src[start:p.peek().pos])}
+	if item.alias, err = p.alias(); err != nil {
+		return selectItem{}, err
+	}
+	return item, nil
+}
+
+// parseFrom parses the tables of FROM and their joins. Tables separated by
+// commas are cross joined
+func (p *sqlParser) parseFrom(stmt *Select) error {
+	table, err := p.parseTableRef()
+	if err != nil {
+		return err
+	}
+	stmt.from = &table
+
+	for {
+		var j join
+		switch {
+		case p.accept(","):
+			j.kind = joinCross
+		case p.accept("cross"):
+			if err := p.expect("join"); err != nil {
+				return err
+			}
+			j.kind = joinCross
+		case p.accept("join"):
+			j.kind = joinInner
+		case p.accept("inner"):
+			if err := p.expect("join"); err != nil {
+				return err
+			}
+			j.kind = joinInner
+		case p.peek().is("left") || p.peek().is("right") || p.peek().is("full"):
+			switch strings.ToLower(p.next().text) {
+			case "left":
+				j.kind = joinLeft
+			case "right":
+				j.kind = joinRight
+			default:
+				j.kind = joinFull
+			}
+			p.accept("outer")
+			if err := p.expect("join"); err != nil {
+				return err
+			}
+		default:
+			return nil
+		}
+
+		if j.table, err = p.parseTableRef(); err != nil {
+			return err
+		}
+		if j.kind != joinCross {
+			if err := p.expect("on"); err != nil {
+				return err
+			}
+			if j.on, err = p.parseExpr(); err != nil {
+				return err
+			}
+		}
+		stmt.joins = append(stmt.joins, j)
+	}
+}
+
+// parseTableRef parses a table name with an optional alias
+func (p *sqlParser) parseTableRef() (tableRef, error) {
+	tok := p.peek()
+	if tok.is("(") {
+		return tableRef{}, syntaxError(p.src, tok.pos, "subqueries are not supported")
+	}
+	name, err := p.name("table name")
+	if err != nil {
+		return tableRef{}, err
+	}
+	alias, err := p.alias()
+	if err != nil {
+		return tableRef{}, err
+	}
+	return tableRef{name: name, alias: alias, pos: tok.pos}, nil
+}
+
+// parseOrderItem parses an ORDER BY key with its direction and null order.
+// Nulls come last in ascending order and first in descending order unless
+// NULLS FIRST or NULLS LAST says otherwise
+func (p *sqlParser) parseOrderItem() (orderItem, error) {
+	e, err := p.parseExpr()
+	if err != nil {
+		return orderItem{}, err
+	}
+	item := orderItem{expr: e}
+	if p.accept("desc") {
+		item.desc = true
+	} else {
+		p.accept("asc")
+	}
+	item.nullsFirst = item.desc
+	if p.accept("nulls") {
+		switch {
+		case p.accept("first"):
+			item.nullsFirst = true
+		case p.accept("last"):
+			item.nullsFirst = false
+		default:
+			return orderItem{}, p.unexpected(p.peek())
+		}
+	}
+	return item, nil
+}
+
+// count parses the non-negative integer of LIMIT or OFFSET
+func (p *sqlParser) count(clause string) (int, error) {
+	tok := p.next()
+	n, err := strconv.Atoi(tok.text)
+	if tok.kind != tokNumber || err != nil || n < 0 {
+		return 0, syntaxError(p.src, tok.pos, "%s must be a non-negative integer", clause)
+	}
+	return n, nil
+}
+
+// parseExpr parses an expression: OR binds loosest, then AND, NOT,
+// comparisons, ||, + and -, then *, / and %
+func (p *sqlParser) parseExpr() (node, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.accept("or") {
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &logicalNode{and: false, left: left, right: right}
+	}
+	return left, nil
+}
+
+// parseAnd parses a AND b
+func (p *sqlParser) parseAnd() (node, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for p.accept("and") {
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		left = &logicalNode{and: true, left: left, right: right}
+	}
+	return left, nil
+}
+
+// parseNot parses NOT a
+func (p *sqlParser) parseNot() (node, error) {
+	if p.accept("not") {
+		operand, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return &notNode{operand: operand}, nil
+	}
+	return p.parseComparison()
+}
+
+// parseComparison parses comparisons, IS [NOT] NULL, [NOT] IN (...),
+// [NOT] BETWEEN a AND b and [NOT] LIKE pattern
+func (p *sqlParser) parseComparison() (node, error) {
+	left, err := p.parseConcat()
+	if err != nil {
+		return nil, err
+	}
+
+	tok := p.peek()
+	if tok.kind == tokOp {
+		op := tok.text
+		switch op {
+		case "=", "==":
+			op = "=="
+		case "<>", "!=":
+			op = "!="
+		case "<", "<=", ">", ">=":
+		default:
+			return left, nil
+		}
+		p.next()
+		right, err := p.parseConcat()
+		if err != nil {
+			return nil, err
+		}
+		return &compareNode{op: op, left: left, right: right}, nil
+	}
+
+	if p.accept("is") {
+		not := p.accept("not")
+		if err := p.expect("null"); err != nil {
+			return nil, err
+		}
+		return &isNullNode{operand: left, not: not}, nil
+	}
+
+	not := false
+	if p.peek().is("not") && (p.peekAt(1).is("in") || p.peekAt(1).is("between") || p.peekAt(1).is("like") || p.peekAt(1).is("ilike")) {
+		p.next()
+		not = true
+	}
+	switch {
+	case p.accept("in"):
+		if err := p.expect("("); err != nil {
+			return nil, err
+		}
+		if tok := p.peek(); tok.is("select") {
+			return nil, syntaxError(p.src, tok.pos, "subqueries are not supported")
+		}
+		n := &inNode{operand: left, not: not}
+		for {
+			e, err := p.parseExpr()
+			if err != nil {
+				return nil, err
+			}
+			n.list = append(n.list, e)
+			if !p.accept(",") {
+				break
+			}
+		}
+		if err := p.expect(")"); err != nil {
+			return nil, err
+		}
+		return n, nil
+
+	case p.accept("between"):
+		low, err := p.parseConcat()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect("and"); err != nil {
+			return nil, err
+		}
+		high, err := p.parseConcat()
+		if err != nil {
+			return nil, err
+		}
+		return &betweenNode{operand: left, low: low, high: high, not: not}, nil
+
+	case p.peek().is("like") || p.peek().is("ilike"):
+		fold := strings.EqualFold(p.next().text, "ilike")
+		pattern, err := p.parseConcat()
+		if err != nil {
+			return nil, err
+		}
+		return &likeNode{operand: left, pattern: pattern, not: not, fold: fold}, nil
+	}
+	return left, nil
+}
+
+// parseConcat parses a || b
+func (p *sqlParser) parseConcat() (node, error) {
+	left, err := p.parseSum()
+	if err != nil {
+		return nil, err
+	}
+	for p.accept("||") {
+		right, err := p.parseSum()
+		if err != nil {
+			return nil, err
+		}
+		left = &concatNode{left: left, right: right}
+	}
+	return left, nil
+}
+
+// parseSum parses a + b and a - b
+func (p *sqlParser) parseSum() (node, error) {
+	left, err := p.parseProduct()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		tok := p.peek()
+		if !tok.is("+") && !tok.is("-") {
+			return left, nil
+		}
+		p.next()
+		right, err := p.parseProduct()
+		if err != nil {
+			return nil, err
+		}
+		left = &arithNode{op: tok.text, left: left, right: right}
+	}
+}
+
+// parseProduct parses a * b, a / b and a % b
+func (p *sqlParser) parseProduct() (node, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		tok := p.peek()
+		if !tok.is("*") && !tok.is("/") && !tok.is("%") {
+			return left, nil
+		}
+		p.next()
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = &arithNode{op: tok.text, left: left, right: right}
+	}
+}
+
+// parseUnary parses -a and +a
+func (p *sqlParser) parseUnary() (node, error) {
+	if p.accept("-") {
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &arithNode{op: "-", left: &literalNode{value: expr.Number(0)}, right: operand}, nil
+	}
+	if p.accept("+") {
+		return p.parseUnary()
+	}
+	return p.parsePrimary()
+}
+
+// parsePrimary parses a literal, column, function call, CASE, CAST or
+// parenthesized expression
+func (p *sqlParser) parsePrimary() (node, error) {
+	tok := p.next()
+	switch tok.kind {
+	case tokNumber:
+		n, err := strconv.ParseFloat(tok.text, 64)
+		if err != nil {
+			return nil, syntaxError(p.src, tok.pos, "invalid number %q", tok.text)
+		}
+		return &literalNode{value: expr.Number(n)}, nil
+
+	case tokString:
+		return &literalNode{value: expr.String(tok.text)}, nil
+
+	case tokQuoted:
+		return p.columnRef(tok)
+
+	case tokIdent:
+		switch strings.ToLower(tok.text) {
+		case "null":
+			return &literalNode{value: expr.Null()}, nil
+		case "true":
+			return &literalNode{value: expr.Bool(true)}, nil
+		case "false":
+			return &literalNode{value: expr.Bool(false)}, nil
+		case "case":
+			return p.parseCase()
+		case "cast":
+			if p.peek().is("(") {
+				return p.parseCast()
+			}
+		}
+		if p.peek().is("(") {
+			p.next()
+			return p.parseCall(tok)
+		}
+		if reserved[strings.ToLower(tok.text)] {
+			return nil, p.unexpected(tok)
+		}
+		return p.columnRef(tok)
+
+	case tokOp:
+		if tok.text == "(" {
+			if next := p.peek(); next.is("select") {
+				return nil, syntaxError(p.src, next.pos, "subqueries are not supported")
+			}
+			inner, err := p.parseExpr()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expect(")"); err != nil {
+				return nil, err
+			}
+			return inner, nil
+		}
+	}
+	return nil, p.unexpected(tok)
+}
+
+// columnRef parses a column name, optionally qualified as table.column
+func (p *sqlParser) columnRef(first token) (node, error) {
+	if p.accept(".") {
+		name, err := p.name("column name")
+		if err != nil {
+			return nil, err
+		}
+		return &columnNode{table: first.text, name: name, pos: first.pos}, nil
+	}
+	return &columnNode{name: first.text, pos: first.pos}, nil
+}
+
+// parseCall parses the arguments of a function or aggregate call after
+// the opening parenthesis
+func (p *sqlParser) parseCall(name token) (node, error) {
+	lower := strings.ToLower(name.text)
+	if expr.IsAggregate(lower) {
+		call := &aggregateNode{name: lower, pos: name.pos}
+		if p.accept("*") {
+			if lower != "count" {
+				return nil, syntaxError(p.src, name.pos, "only COUNT takes *")
+			}
+			call.star = true
+		} else {
+			call.distinct = p.accept("distinct")
+			arg, err := p.parseExpr()
+			if err != nil {
+				return nil, err
+			}
+			call.arg = arg
+		}
+		if err := p.expect(")"); err != nil {
+			return nil, err
+		}
+		return call, nil
+	}
+
+	var args []node
+	if !p.accept(")") {
+		for {
+			arg, err := p.parseExpr()
+			if err != nil {
+				return nil, err
+			}
+			args = append(args, arg)
+			if !p.accept(",") {
+				break
+			}
+		}
+		if err := p.expect(")"); err != nil {
+			return nil, err
+		}
+	}
+	if err := expr.CheckFunction(name.text, len(args)); err != nil {
+		return nil, syntaxError(p.src, name.pos, "%v", err)
+	}
+	return &callNode{name: name.text, args: args}, nil
+}
+
+// parseCase parses CASE [operand] WHEN ... THEN ... [ELSE ...] END
+func (p *sqlParser) parseCase() (node, error) {
+	n := &caseNode{}
+	if !p.peek().is("when") {
+		operand, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		n.operand = operand
+	}
+	for p.accept("when") {
+		cond, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect("then"); err != nil {
+			return nil, err
+		}
+		result, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		n.whens = append(n.whens, whenClause{cond: cond, result: result})
+	}
+	if len(n.whens) == 0 {
+		return nil, p.unexpected(p.peek())
+	}
+	if p.accept("else") {
+		els, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		n.els = els
+	}
+	if err := p.expect("end"); err != nil {
+		return nil, err
+	}
+	return n, nil
+}
+
+// parseCast parses CAST(x AS type), where type is a number, text, date or
+// boolean type name such as INTEGER or VARCHAR(20)
+func (p *sqlParser) parseCast() (node, error) {
+	p.next()
+	operand, err := p.parseExpr()
+	if err != nil {
+		return nil, err
+	}
+	if err := p.expect("as"); err != nil {
+		return nil, err
+	}
+	tok := p.next()
+	kind, ok := castTypes[strings.ToLower(tok.text)]
+	if tok.kind != tokIdent || !ok {
+		return nil, syntaxError(p.src, tok.pos, "unknown type %s", describeToken(tok))
+	}
+	// Ignore a length such as VARCHAR(20)
+	if p.accept("(") {
+		for !p.accept(")") {
+			if p.next().kind == tokEOF {
+				return nil, syntaxError(p.src, len(p.src), "expected ) at end of statement")
+			}
+		}
+	}
+	if err := p.expect(")"); err != nil {
+		return nil, err
+	}
+	return &castNode{operand: operand, kind: kind, integer: integerTypes[strings.ToLower(tok.text)]}, nil
+}
+
+// castTypes maps SQL type names to the kind of value they cast to
+var castTypes = map[string]expr.Kind{
+	"integer": expr.KindNumber, "int": expr.KindNumber, "bigint": expr.KindNumber, "smallint": expr.KindNumber,
+	"real": expr.KindNumber, "float": expr.KindNumber, "double": expr.KindNumber, "numeric": expr.KindNumber, "decimal": expr.KindNumber,
+	"text": expr.KindString, "varchar": expr.KindString, "char": expr.KindString, "string": expr.KindString,
+	"date": expr.KindDate, "timestamp": expr.KindDate, "datetime": expr.KindDate,
+	"boolean": expr.KindBool, "bool": expr.KindBool,
+}
+
+// integerTypes lists the types that truncate numbers to integers
+var integerTypes = map[string]bool{"integer": true, "int": true, "bigint": true, "smallint": true}
@@ -0,0 +1,221 @@
+package query
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/user/table-converter/internal/model"
+)
+
+// newTable builds a table of string cells, as the CSV parser produces
+// them. Empty cells are null
+func newTable(headers []string, rows ...[]string) *model.TableData {
+	values := make([][]model.Value, len(rows))
+	for i, row := range rows {
+		values[i] = make([]model.Value, len(row))
+		for j, cell := range row {
+			if cell == "" {
+				values[i][j] = model.NewNullValue()
+			} else {
+				values[i][j] = model.NewStringValue(cell)
+			}
+		}
+	}
+	return model.NewTableData(headers, values)
+}
+
+// cells returns the headers and raw cell strings of a table
+func cells(data *model.TableData) [][]string {
+	out := [][]string{data.Headers}
+	for _, row := range data.Rows {
+		line := make([]string, len(row))
+		for j, v := range row {
+			line[j] = v.Raw
+		}
+		out = append(out, line)
+	}
+	return out
+}
+
+func testTables() Tables {
+	return Tables{
+		"sales": newTable([]string{"id", "region", "customer", "amount", "date"},
+			[]string{"1", "north", "alice", "100", "2024-01-05"},
+			[]string{"2", "south", "bob", "250", "2024-02-10"},
+			[]string{"3", "north", "carol", "75.5", "2024-01-20"},
+			[]string{"4", "east", "alice", "", "2024-03-01"},
+			[]string{"5", "south", "dave", "30", "2024-02-28"},
+		),
+		"customers": newTable([]string{"name", "city"},
+			[]string{"alice", "Oslo"},
+			[]string{"bob", "Rome"},
+			[]string{"erin", "Lima"},
+		),
+	}
+}
+
+func TestRun(t *testing.T) {
+	tests := []struct {
+		name string
+		sql  string
+		want [][]string
+	}{
+		{
+			name: "projection and filter",
+			sql:  "SELECT id, amount * 2 AS double FROM sales WHERE region = 'north'",
+			want: [][]string{{"id", "double"}, {"1", "200"}, {"3", "151"}},
+		},
+		{
+			name: "star",
+			sql:  "select * from customers where city like '%o%'",
+			want: [][]string{{"name", "city"}, {"alice", "Oslo"}, {"bob", "Rome"}},
+		},
+		{
+			name: "numeric order descending",
+			sql:  "SELECT customer FROM sales WHERE amount IS NOT NULL ORDER BY amount DESC",
+			want: [][]string{{"customer"}, {"bob"}, {"alice"}, {"carol"}, {"dave"}},
+		},
+		{
+			name: "nulls last ascending",
+			sql:  "SELECT id FROM sales ORDER BY amount",
+			want: [][]string{{"id"}, {"5"}, {"3"}, {"1"}, {"2"}, {"4"}},
+		},
+		{
+			name: "nulls first",
+			sql:  "SELECT id FROM sales ORDER BY amount NULLS FIRST LIMIT 2",
+			want: [][]string{{"id"}, {"4"}, {"5"}},
+		},
+		{
+			name: "group by with aggregates, nulls first descending",
+			sql: `SELECT region, count(*) AS n, sum(amount) AS total, max(date) AS last
+				FROM sales GROUP BY region ORDER BY total DESC`,
+			want: [][]string{
+				{"region", "n", "total", "last"},
+				{"east", "1", "", "2024-03-01"},
+				{"south", "2", "280", "2024-02-28"},
+				{"north", "2", "175.5", "2024-01-20"},
+			},
+		},
+		{
+			name: "having",
+			sql:  "SELECT region FROM sales GROUP BY region HAVING count(*) > 1 ORDER BY 1",
+			want: [][]string{{"region"}, {"north"}, {"south"}},
+		},
+		{
+			name: "group by position and alias",
+			sql:  "SELECT region, count(*) AS n FROM sales GROUP BY 1 ORDER BY 1",
+			want: [][]string{{"region", "n"}, {"east", "1"}, {"north", "2"}, {"south", "2"}},
+		},
+		{
+			name: "group by alias of an expression",
+			sql:  "SELECT upper(substr(region, 1, 1)) AS initial, sum(amount) AS total FROM sales GROUP BY initial ORDER BY initial",
+			want: [][]string{{"initial", "total"}, {"E", ""}, {"N", "175.5"}, {"S", "280"}},
+		},
+		{
+			name: "aggregate without rows",
+			sql:  "SELECT count(*), sum(amount) FROM sales WHERE region = 'west'",
+			want: [][]string{{"count(*)", "sum(amount)"}, {"0", ""}},
+		},
+		{
+			name: "count distinct",
+			sql:  "SELECT count(DISTINCT customer) AS customers, count(amount) AS priced FROM sales",
+			want: [][]string{{"customers", "priced"}, {"4", "4"}},
+		},
+		{
+			name: "distinct",
+			sql:  "SELECT DISTINCT customer FROM sales ORDER BY customer LIMIT 2 OFFSET 1",
+			want: [][]string{{"customer"}, {"bob"}, {"carol"}},
+		},
+		{
+			name: "inner join",
+			sql: `SELECT s.id, c.city FROM sales s JOIN customers c ON s.customer = c.name
+				ORDER BY s.id`,
+			want: [][]string{{"id", "city"}, {"1", "Oslo"}, {"2", "Rome"}, {"4", "Oslo"}},
+		},
+		{
+			name: "left join",
+			sql: `SELECT customer, city FROM sales LEFT JOIN customers ON customer = name
+				WHERE region = 'south'`,
+			want: [][]string{{"customer", "city"}, {"bob", "Rome"}, {"dave", ""}},
+		},
+		{
+			name: "full join",
+			sql: `SELECT s.id, c.name FROM sales s FULL JOIN customers c
+				ON s.customer = c.name AND s.region <> 'east' WHERE s.id IS NULL OR c.name IS NULL`,
+			want: [][]string{{"id", "name"}, {"3", ""}, {"4", ""}, {"5", ""}, {"", "erin"}},
+		},
+		{
+			name: "case and cast",
+			sql: `SELECT id, CASE WHEN amount >= 100 THEN 'big' WHEN amount IS NULL THEN 'none'
+				ELSE 'small' END AS size, CAST(amount AS INTEGER) AS whole FROM sales WHERE id IN (3, 4)`,
+			want: [][]string{{"id", "size", "whole"}, {"3", "small", "75"}, {"4", "none", ""}},
+		},
+		{
+			name: "between and functions",
+			sql:  "SELECT upper(customer) || '!' AS who FROM sales WHERE date BETWEEN '2024-02-01' AND '2024-02-29'",
+			want: [][]string{{"who"}, {"BOB!"}, {"DAVE!"}},
+		},
+		{
+			name: "quoted identifiers",
+			sql:  `SELECT "customer" AS "Customer Name" FROM sales WHERE id = 1`,
+			want: [][]string{{"Customer Name"}, {"alice"}},
+		},
+		{
+			name: "without from",
+			sql:  "SELECT 1 + 2 AS three",
+			want: [][]string{{"three"}, {"3"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Run(tt.sql, testTables())
+			if err != nil {
+				t.Fatalf("Run() error: %v", err)
+			}
+			if !reflect.DeepEqual(cells(got), tt.want) {
+				t.Errorf("Run() = %q, want %q", cells(got), tt.want)
+			}
+		})
+	}
+}
+
+func TestRunErrors(t *testing.T) {
+	tests := []struct {
+		sql    string
+		want   string
+		syntax bool
+	}{
+		{"SELECT FROM sales", "unexpected FROM", true},
+		{"SELECT id FROM sales WHERE", "unexpected end", true},
+		{"SELECT id FROM sales UNION SELECT id FROM sales", "UNION is not supported", true},
+		{"SELECT nosuch(id) FROM sales", "unknown function", true},
+		{"SELECT id FROM nosuch", `unknown table "nosuch" (tables: customers, sales)`, false},
+		{"SELECT nosuch FROM sales", `unknown column "nosuch"`, false},
+		{"SELECT name FROM sales, customers c JOIN customers ON true", `column "name" is ambiguous`, false},
+		{"SELECT id FROM sales WHERE count(*) > 1", "not allowed in WHERE", false},
+		{"SELECT sum(count(*)) FROM sales", "cannot be nested", false},
+		{"SELECT sum(customer) FROM sales", "SUM, row 1", false},
+		{"SELECT id FROM sales ORDER BY 3", "ORDER BY position 3", false},
+		{"SELECT region, count(*) FROM sales GROUP BY 3", "GROUP BY position 3", false},
+		{"SELECT region, count(*) FROM sales GROUP BY 2", "refers to an aggregate", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.sql, func(t *testing.T) {
+			_, err := Run(tt.sql, testTables())
+			if err == nil {
+				t.Fatal("Run() succeeded, want error")
+			}
+			if !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Run() error = %q, want it to contain %q", err, tt.want)
+			}
+			var syntaxErr *SyntaxError
+			if errors.As(err, &syntaxErr) != tt.syntax {
+				t.Errorf("Run() error %T, syntax error = %v", err, tt.syntax)
+			}
+		})
+	}
+}
@@ -21,6 +21,7 @@ const (
 	FormatXML      Format = "xml"
 	FormatMarkdown Format = "markdown"
 	FormatASCII    Format = "ascii"
+	FormatFixed    Format = "fixed"
 )
 
 // FormatInfo holds parser and serializer for a format
@@ -6,6 +6,7 @@ import (
 	"strings"
 
 	"github.com/user/table-converter/internal/model"
+	"github.com/user/table-converter/internal/textwidth"
 )
 
 // TableStyle represents the output table style
@@ -18,12 +19,102 @@ const (
 	StyleOrgMode   TableStyle = "org"        // Emacs org-mode
 	StyleRSTGrid   TableStyle = "rst-grid"   // reStructuredText grid table
 	StyleRSTSimple TableStyle = "rst-simple" // reStructuredText simple table
+	StyleUnicode   TableStyle = "unicode"    // Unicode single-line box drawing
+	StyleDouble    TableStyle = "double"     // Unicode double-line box drawing
+	StyleRounded   TableStyle = "rounded"    // Unicode box drawing with rounded corners
+	StyleHeavy     TableStyle = "heavy"      // Unicode heavy-line box drawing
+	StyleSimple    TableStyle = "simple"     // Borderless columns with a dashed header rule
+
+	StylePsqlExpanded  TableStyle = "psql-expanded"  // PostgreSQL expanded display (\x), one field per line
+	StyleMySQLVertical TableStyle = "mysql-vertical" // MySQL vertical output (\G), one field per line
+	StyleSQLiteLine    TableStyle = "sqlite-line"    // SQLite .mode line, one field per line
 )
 
+// borderRunes holds the characters used to draw one horizontal border line
+type borderRunes struct {
+	left, fill, sep, right rune
+}
+
+// frameStyle holds the characters used to draw a fully bordered table
+type frameStyle struct {
+	top, header, row, bottom borderRunes
+	vertical                 rune
+}
+
+// frameStyles maps bordered table styles to their drawing characters
+var frameStyles = map[TableStyle]frameStyle{
+	StyleBox: {
+		top:      borderRunes{'+', '-', '+', '+'},
+		header:   borderRunes{'+', '-', '+', '+'},
+		row:      borderRunes{'+', '-', '+', '+'},
+		bottom:   borderRunes{'+', '-', '+', '+'},
+		vertical: '|',
+	},
+	StyleRSTGrid: {
+		top:      borderRunes{'+', '-', '+', '+'},
+		header:   borderRunes{'+', '=', '+', '+'},
+		row:      borderRunes{'+', '-', '+', '+'},
+		bottom:   borderRunes{'+', '-', '+', '+'},
+		vertical: '|',
+	},
+	StyleUnicode: {
+		top:      borderRunes{'┌', '─', '┬', '┐'},
+		header:   borderRunes{'├', '─', '┼', '┤'},
+		row:      borderRunes{'├', '─', '┼', '┤'},
+		bottom:   borderRunes{'└', '─', '┴', '┘'},
+		vertical: '│',
+	},
+	StyleDouble: {
+		top:      borderRunes{'╔', '═', '╦', '╗'},
+		header:   borderRunes{'╠', '═', '╬', '╣'},
+		row:      borderRunes{'╠', '═', '╬', '╣'},
+		bottom:   borderRunes{'╚', '═', '╩', '╝'},
+		vertical: '║',
+	},
+	StyleRounded: {
+		top:      borderRunes{'╭', '─', '┬', '╮'},
+		header:   borderRunes{'├', '─', '┼', '┤'},
+		row:      borderRunes{'├', '─', '┼', '┤'},
+		bottom:   borderRunes{'╰', '─', '┴', '╯'},
+		vertical: '│',
+	},
+	StyleHeavy: {
+		top:      borderRunes{'┏', '━', '┳', '┓'},
+		header:   borderRunes{'┣', '━', '╋', '┫'},
+		row:      borderRunes{'┣', '━', '╋', '┫'},
+		bottom:   borderRunes{'┗', '━', '┻', '┛'},
+		vertical: '┃',
+	},
+}
+
+// OverflowMode controls how cells wider than their column limit are fitted
+type OverflowMode string
+
+const (
+	OverflowTruncate OverflowMode = "truncate" // Cut the cell and end it with an ellipsis
+	OverflowWrap     OverflowMode = "wrap"     // Word-wrap the cell across several lines
+)
+
+// NumericAlignment controls how columns holding only numbers are aligned
+type NumericAlignment string
+
+const (
+	NumericRight   NumericAlignment = "right"   // Right-align numbers (default)
+	NumericLeft    NumericAlignment = "left"    // Left-align numbers like any other text
+	NumericDecimal NumericAlignment = "decimal" // Right-align numbers with their decimal points lined up
+)
+
+// minColumnWidth is the narrowest a column is ever made
+const minColumnWidth = 3
+
 // UnifiedASCIISerializer implements the Serializer interface for all ASCII-style table formats
 type UnifiedASCIISerializer struct {
-	Style          TableStyle
-	RowSeparators  bool // Whether to add separators between data rows
+	Style           TableStyle
+	RowSeparators   bool           // Whether to add separators between data rows
+	MaxWidth        int            // Maximum total table width in display columns (0 = unlimited)
+	MaxColumnWidths map[string]int // Maximum width of individual columns, keyed by header
+	Overflow        OverflowMode   // How to fit cells that exceed a width limit (default: truncate)
+	NumericAlign    NumericAlignment
 }
 
 // NewUnifiedASCIISerializer creates a new unified ASCII table serializer
@@ -34,6 +125,7 @@ func NewUnifiedASCIISerializer(style TableStyle) *UnifiedASCIISerializer {
 	return &UnifiedASCIISerializer{
 		Style:         style,
 		RowSeparators: false,
+		NumericAlign:  NumericRight,
 	}
 }
 
@@ -47,6 +139,15 @@ func (s *UnifiedASCIISerializer) SetStyle(style string) error {
 		"org":        StyleOrgMode,
 		"rst-grid":   StyleRSTGrid,
 		"rst-simple": StyleRSTSimple,
+		"unicode":    StyleUnicode,
+		"double":     StyleDouble,
+		"rounded":    StyleRounded,
+		"heavy":      StyleHeavy,
+		"simple":     StyleSimple,
+
+		"psql-expanded":  StylePsqlExpanded,
+		"mysql-vertical": StyleMySQLVertical,
+		"sqlite-line":    StyleSQLiteLine,
 	}
 
 	if ts, ok := validStyles[style]; ok {
@@ -54,9 +155,45 @@ func (s *UnifiedASCIISerializer) SetStyle(style string) error {
 		return nil
 	}
 
-	return fmt.Errorf("unsupported style %q, valid styles: box, psql, md, org, rst-grid, rst-simple", style)
+	return fmt.Errorf("unsupported style %q, valid styles: box, psql, md, org, rst-grid, rst-simple, unicode, double, rounded, heavy, simple, psql-expanded, mysql-vertical, sqlite-line", style)
 }
 
+// SetWidthLimits limits the total table width and the width of individual
+// columns. overflow selects how oversized cells are fitted: "truncate" (the
+// default when empty) or "wrap"
+func (s *UnifiedASCIISerializer) SetWidthLimits(maxWidth int, columnWidths map[string]int, overflow string) error {
+	if maxWidth < 0 {
+		return fmt.Errorf("invalid maximum width %d", maxWidth)
+	}
+	for name, w := range columnWidths {
+		if w < 1 {
+			return fmt.Errorf("invalid maximum width %d for column %q", w, name)
+		}
+	}
+
+	switch OverflowMode(overflow) {
+	case "", OverflowTruncate:
+		s.Overflow = OverflowTruncate
+	case OverflowWrap:
+		s.Overflow = OverflowWrap
+	default:
+		return fmt.Errorf("unsupported overflow mode %q, valid modes: truncate, wrap", overflow)
+	}
+
+	s.MaxWidth = maxWidth
+	s.MaxColumnWidths = columnWidths
+	return nil
+}
+
+// SetNumericAlignment sets how numeric columns are aligned: "right", "left" or "decimal"
+func (s *UnifiedASCIISerializer) SetNumericAlignment(align string) error {
+	switch NumericAlignment(align) {
+	case NumericRight, NumericLeft, NumericDecimal:
+		s.NumericAlign = NumericAlignment(align)
+		return nil
+	}
+	return fmt.Errorf("unsupported numeric alignment %q, valid alignments: right, left, decimal", align)
+}
 
 // Serialize writes TableData to the output writer in the specified style
 func (s *UnifiedASCIISerializer) Serialize(data *model.TableData, output io.Writer) error {
@@ -72,6 +209,24 @@ func (s *UnifiedASCIISerializer) Serialize(data *model.TableData, output io.Writ
 		return nil // Empty table
 	}
 
+	// Record-per-block styles print one field per line, so column alignment
+	// and width limits do not apply
+	switch s.Style {
+	case StylePsqlExpanded:
+		return s.serializePsqlExpanded(data, output)
+	case StyleMySQLVertical:
+		return s.serializeMySQLVertical(data, output)
+	case StyleSQLiteLine:
+		return s.serializeSQLiteLine(data, output)
+	}
+
+	// Resolve column alignments from the column types before cells are
+	// rewritten, then apply width limits
+	data = s.alignTable(data)
+	if s.MaxWidth > 0 || len(s.MaxColumnWidths) > 0 {
+		data = s.fitTable(data)
+	}
+
 	// Route to appropriate serializer based on style
 	switch s.Style {
 	case StyleRSTSimple:
@@ -82,8 +237,10 @@ func (s *UnifiedASCIISerializer) Serialize(data *model.TableData, output io.Writ
 		return s.serializeOrgMode(data, output)
 	case StylePsql:
 		return s.serializePsql(data, output)
-	case StyleRSTGrid:
-		return s.serializeRSTGrid(data, output)
+	case StyleSimple:
+		return s.serializeSimple(data, output)
+	case StyleRSTGrid, StyleUnicode, StyleDouble, StyleRounded, StyleHeavy:
+		return s.serializeFramed(data, output, frameStyles[s.Style])
 	case StyleBox:
 		fallthrough
 	default:
@@ -91,16 +248,16 @@ func (s *UnifiedASCIISerializer) Serialize(data *model.TableData, output io.Writ
 	}
 }
 
-// calculateWidths computes the maximum width for each column
+// calculateWidths computes the maximum display width for each column
 func (s *UnifiedASCIISerializer) calculateWidths(data *model.TableData) []int {
 	widths := make([]int, len(data.Headers))
-	for i, header := range data.Headers {
-		widths[i] = len(header)
+	for i, header := range s.headerCells(data.Headers) {
+		widths[i] = cellWidth(header)
 	}
 	for _, row := range data.Rows {
-		for i, value := range row {
+		for i, cell := range s.rowToCells(row, data.Headers) {
 			if i < len(widths) {
-				cellLen := len(unifiedValueToString(value))
+				cellLen := cellWidth(cell)
 				if cellLen > widths[i] {
 					widths[i] = cellLen
 				}
@@ -109,44 +266,289 @@ func (s *UnifiedASCIISerializer) calculateWidths(data *model.TableData) []int {
 	}
 	// Ensure minimum width of 3
 	for i := range widths {
-		if widths[i] < 3 {
-			widths[i] = 3
+		if widths[i] < minColumnWidth {
+			widths[i] = minColumnWidth
 		}
 	}
 	return widths
 }
 
+// alignTable returns a copy of data with an alignment for every column.
+// Explicit alignments are kept; columns holding only numbers are right-aligned
+// unless NumericAlign is left, and with decimal alignment their values are
+// padded so the decimal points line up
+func (s *UnifiedASCIISerializer) alignTable(data *model.TableData) *model.TableData {
+	aligned := &model.TableData{
+		Headers:    data.Headers,
+		Rows:       data.Rows,
+		Alignments: make([]model.Alignment, len(data.Headers)),
+	}
+
+	var decimalCols []int
+	for i := range data.Headers {
+		aligned.Alignments[i] = data.ColumnAlignment(i)
+		if aligned.Alignments[i] != model.AlignDefault || s.NumericAlign == NumericLeft {
+			continue
+		}
+		if isNumericColumn(data, i) {
+			aligned.Alignments[i] = model.AlignRight
+			if s.NumericAlign == NumericDecimal {
+				decimalCols = append(decimalCols, i)
+			}
+		}
+	}
+
+	if len(decimalCols) > 0 {
+		aligned.Rows = alignDecimals(data.Rows, decimalCols)
+	}
+
+	return aligned
+}
+
+// isNumericColumn reports whether a column holds at least one number and
+// nothing but numbers and nulls
+func isNumericColumn(data *model.TableData, col int) bool {
+	hasNumber := false
+	for _, row := range data.Rows {
+		switch row[col].Type {
+		case model.TypeNumber:
+			hasNumber = true
+		case model.TypeNull:
+		default:
+			return false
+		}
+	}
+	return hasNumber
+}
+
+// alignDecimals pads the numbers in the given columns so that, once
+// right-aligned, their decimal points line up
+func alignDecimals(rows [][]model.Value, cols []int) [][]model.Value {
+	padded := make([][]model.Value, len(rows))
+	for r, row := range rows {
+		padded[r] = make([]model.Value, len(row))
+		copy(padded[r], row)
+	}
+
+	for _, col := range cols {
+		// Measure the widest fractional part (including the point)
+		maxFrac := 0
+		for _, row := range rows {
+			if row[col].Type != model.TypeNumber {
+				continue
+			}
+			if idx := strings.IndexByte(row[col].Raw, '.'); idx >= 0 {
+				if frac := len(row[col].Raw) - idx; frac > maxFrac {
+					maxFrac = frac
+				}
+			}
+		}
+
+		for r, row := range rows {
+			if row[col].Type != model.TypeNumber {
+				continue
+			}
+			frac := 0
+			if idx := strings.IndexByte(row[col].Raw, '.'); idx >= 0 {
+				frac = len(row[col].Raw) - idx
+			}
+			padded[r][col] = model.Value{
+				Type:   model.TypeNumber,
+				Raw:    row[col].Raw + strings.Repeat(" ", maxFrac-frac),
+				Parsed: row[col].Parsed,
+			}
+		}
+	}
+
+	return padded
+}
+
+// alignmentAt returns the alignment of a column, defaulting to left
+func alignmentAt(aligns []model.Alignment, col int) model.Alignment {
+	if col < len(aligns) && aligns[col] != model.AlignDefault {
+		return aligns[col]
+	}
+	return model.AlignLeft
+}
+
+// padCell pads a cell with spaces to the given display width
+func padCell(cell string, width int, align model.Alignment) string {
+	return padCellWith(cell, width, align, ' ')
+}
+
+// padCellWith pads a cell to width display columns with the given character
+func padCellWith(cell string, width int, align model.Alignment, pad rune) string {
+	gap := width - textwidth.String(cell)
+	if gap <= 0 {
+		return cell
+	}
+
+	fill := func(n int) string { return strings.Repeat(string(pad), n) }
+	switch align {
+	case model.AlignRight:
+		return fill(gap) + cell
+	case model.AlignCenter:
+		left := gap / 2
+		return fill(left) + cell + fill(gap-left)
+	default:
+		return cell + fill(gap)
+	}
+}
+
+// tableWidth returns the total display width of a table with the given column widths
+func (s *UnifiedASCIISerializer) tableWidth(widths []int) int {
+	total := 0
+	for _, w := range widths {
+		total += w
+	}
+
+	gaps := len(widths) - 1
+	switch s.Style {
+	case StylePsql:
+		// "a | b", plus room for the " +" continuation marker of wrapped cells
+		if s.Overflow == OverflowWrap {
+			total += 2
+		}
+		return total + 3*gaps
+	case StyleRSTSimple, StyleSimple:
+		// "a  b"
+		return total + 2*gaps
+	default:
+		// "| a | b |"
+		return total + 3*len(widths) + 1
+	}
+}
+
+// limitWidths applies the per-column limits and then shrinks the widest
+// columns, one column at a time, until the table fits within MaxWidth
+func (s *UnifiedASCIISerializer) limitWidths(headers []string, widths []int) []int {
+	limited := make([]int, len(widths))
+	copy(limited, widths)
+
+	for i, header := range headers {
+		if limit, ok := s.MaxColumnWidths[header]; ok && limited[i] > limit {
+			limited[i] = limit
+		}
+	}
+
+	if s.MaxWidth <= 0 {
+		return limited
+	}
+
+	for s.tableWidth(limited) > s.MaxWidth {
+		widest := 0
+		for i, w := range limited {
+			if w > limited[widest] {
+				widest = i
+			}
+		}
+		if limited[widest] <= minColumnWidth {
+			break // Cannot shrink any further
+		}
+		limited[widest]--
+	}
+
+	return limited
+}
+
+// fitTable returns a copy of data whose headers and cells are wrapped or
+// truncated to fit the width limits
+func (s *UnifiedASCIISerializer) fitTable(data *model.TableData) *model.TableData {
+	widths := s.limitWidths(data.Headers, s.calculateWidths(data))
+
+	headers := make([]string, len(data.Headers))
+	for i, header := range data.Headers {
+		headers[i] = s.fitCell(header, widths[i])
+	}
+
+	rows := make([][]model.Value, len(data.Rows))
+	for r, row := range data.Rows {
+		fitted := make([]model.Value, len(row))
+		for i, value := range row {
+			text := unifiedValueToString(value)
+			if cell := s.fitCell(text, widths[i]); cell != text {
+				fitted[i] = model.NewStringValue(cell)
+			} else {
+				fitted[i] = value
+			}
+		}
+		rows[r] = fitted
+	}
+
+	return &model.TableData{Headers: headers, Rows: rows, Alignments: data.Alignments}
+}
+
+// fitCell wraps or truncates a single cell to at most width display columns.
+// Styles that cannot show multi-line cells always truncate
+func (s *UnifiedASCIISerializer) fitCell(cell string, width int) string {
+	if cellWidth(s.formatCell(cell)) <= width {
+		return cell
+	}
+
+	if s.Overflow == OverflowWrap && s.supportsMultiLine() {
+		return strings.Join(textwidth.Wrap(cell, width), "\n")
+	}
+
+	lines := strings.Split(cell, "\n")
+	if !s.supportsMultiLine() {
+		lines = []string{s.formatCell(cell)}
+	}
+	for i, line := range lines {
+		lines[i] = textwidth.Truncate(line, width, "…")
+	}
+	return strings.Join(lines, "\n")
+}
+
+// supportsMultiLine reports whether the style can show cells spanning several lines
+func (s *UnifiedASCIISerializer) supportsMultiLine() bool {
+	switch s.Style {
+	case StyleMarkdown, StyleOrgMode, StyleRSTSimple, StyleSimple:
+		return false
+	}
+	return true
+}
+
 // serializeBox outputs traditional ASCII box format
 func (s *UnifiedASCIISerializer) serializeBox(data *model.TableData, output io.Writer) error {
+	return s.serializeFramed(data, output, frameStyles[StyleBox])
+}
+
+// serializeFramed outputs a fully bordered table using the given drawing characters
+func (s *UnifiedASCIISerializer) serializeFramed(data *model.TableData, output io.Writer, frame frameStyle) error {
 	widths := s.calculateWidths(data)
 	var sb strings.Builder
 
 	// Top border
-	sb.WriteString(s.buildBorder(widths, '+', '-', '+'))
+	sb.WriteString(s.buildFrameBorder(widths, frame.top))
 	sb.WriteString("\n")
 
 	// Header row
-	sb.WriteString(s.buildRow(data.Headers, widths, '|'))
-	sb.WriteString("\n")
+	writeLines(&sb, s.buildRowLines(s.headerCells(data.Headers), widths, data.Alignments, frame.vertical))
 
 	// Header separator
-	sb.WriteString(s.buildBorder(widths, '+', '-', '+'))
+	sb.WriteString(s.buildFrameBorder(widths, frame.header))
 	sb.WriteString("\n")
 
+	// Multi-line rows need separators so they can be told apart when parsed back
+	multiLine := hasMultiLineCells(data)
+	rowSeparators := s.RowSeparators || multiLine
+
 	// Data rows
 	for i, row := range data.Rows {
 		cells := s.rowToCells(row, data.Headers)
-		sb.WriteString(s.buildRow(cells, widths, '|'))
-		sb.WriteString("\n")
+		writeLines(&sb, s.buildRowLines(cells, widths, data.Alignments, frame.vertical))
 
-		if s.RowSeparators && i < len(data.Rows)-1 {
-			sb.WriteString(s.buildBorder(widths, '+', '-', '+'))
+		// A lone multi-line row is closed with a row rule as well, or it
+		// would read back as a table without row separators, one row per line
+		loneMultiLine := multiLine && len(data.Rows) == 1
+		if rowSeparators && (i < len(data.Rows)-1 || loneMultiLine) {
+			sb.WriteString(s.buildFrameBorder(widths, frame.row))
 			sb.WriteString("\n")
 		}
 	}
 
 	// Bottom border
-	sb.WriteString(s.buildBorder(widths, '+', '-', '+'))
+	sb.WriteString(s.buildFrameBorder(widths, frame.bottom))
 	sb.WriteString("\n")
 
 	_, err := output.Write([]byte(sb.String()))
@@ -159,8 +561,7 @@ func (s *UnifiedASCIISerializer) serializePsql(data *model.TableData, output io.
 	var sb strings.Builder
 
 	// Header row (no leading/trailing borders)
-	sb.WriteString(s.buildPsqlRow(data.Headers, widths))
-	sb.WriteString("\n")
+	writeLines(&sb, s.buildPsqlRowLines(s.headerCells(data.Headers), widths, data.Alignments))
 
 	// Header separator
 	sb.WriteString(s.buildPsqlSeparator(widths))
@@ -169,8 +570,7 @@ func (s *UnifiedASCIISerializer) serializePsql(data *model.TableData, output io.
 	// Data rows
 	for _, row := range data.Rows {
 		cells := s.rowToCells(row, data.Headers)
-		sb.WriteString(s.buildPsqlRow(cells, widths))
-		sb.WriteString("\n")
+		writeLines(&sb, s.buildPsqlRowLines(cells, widths, data.Alignments))
 	}
 
 	_, err := output.Write([]byte(sb.String()))
@@ -183,14 +583,14 @@ func (s *UnifiedASCIISerializer) serializeMarkdown(data *model.TableData, output
 	var sb strings.Builder
 
 	// Header row
-	sb.WriteString(s.buildRow(data.Headers, widths, '|'))
+	sb.WriteString(s.buildRow(s.headerCells(data.Headers), widths, data.Alignments, '|'))
 	sb.WriteString("\n")
 
-	// Separator row (all dashes, no +)
+	// Separator row (dashes with GFM alignment markers, no +)
 	sb.WriteString("|")
-	for _, w := range widths {
+	for i, w := range widths {
 		sb.WriteString(" ")
-		sb.WriteString(strings.Repeat("-", w))
+		sb.WriteString(markdownRule(w, data.ColumnAlignment(i)))
 		sb.WriteString(" |")
 	}
 	sb.WriteString("\n")
@@ -198,7 +598,7 @@ func (s *UnifiedASCIISerializer) serializeMarkdown(data *model.TableData, output
 	// Data rows
 	for _, row := range data.Rows {
 		cells := s.rowToCells(row, data.Headers)
-		sb.WriteString(s.buildRow(cells, widths, '|'))
+		sb.WriteString(s.buildRow(cells, widths, data.Alignments, '|'))
 		sb.WriteString("\n")
 	}
 
@@ -206,13 +606,28 @@ func (s *UnifiedASCIISerializer) serializeMarkdown(data *model.TableData, output
 	return err
 }
 
+// markdownRule creates a separator cell of the given width with GFM alignment
+// markers: :--- (left), :---: (center) or ---: (right)
+func markdownRule(width int, align model.Alignment) string {
+	switch align {
+	case model.AlignLeft:
+		return ":" + strings.Repeat("-", width-1)
+	case model.AlignCenter:
+		return ":" + strings.Repeat("-", width-2) + ":"
+	case model.AlignRight:
+		return strings.Repeat("-", width-1) + ":"
+	default:
+		return strings.Repeat("-", width)
+	}
+}
+
 // serializeOrgMode outputs Emacs org-mode format
 func (s *UnifiedASCIISerializer) serializeOrgMode(data *model.TableData, output io.Writer) error {
 	widths := s.calculateWidths(data)
 	var sb strings.Builder
 
 	// Header row
-	sb.WriteString(s.buildRow(data.Headers, widths, '|'))
+	sb.WriteString(s.buildRow(s.headerCells(data.Headers), widths, data.Alignments, '|'))
 	sb.WriteString("\n")
 
 	// Separator row (with + at intersections)
@@ -230,47 +645,10 @@ func (s *UnifiedASCIISerializer) serializeOrgMode(data *model.TableData, output
 	// Data rows
 	for _, row := range data.Rows {
 		cells := s.rowToCells(row, data.Headers)
-		sb.WriteString(s.buildRow(cells, widths, '|'))
-		sb.WriteString("\n")
-	}
-
-	_, err := output.Write([]byte(sb.String()))
-	return err
-}
-
-// serializeRSTGrid outputs reStructuredText grid table format
-func (s *UnifiedASCIISerializer) serializeRSTGrid(data *model.TableData, output io.Writer) error {
-	widths := s.calculateWidths(data)
-	var sb strings.Builder
-
-	// Top border
-	sb.WriteString(s.buildBorder(widths, '+', '-', '+'))
-	sb.WriteString("\n")
-
-	// Header row
-	sb.WriteString(s.buildRow(data.Headers, widths, '|'))
-	sb.WriteString("\n")
-
-	// Header separator (uses = instead of -)
-	sb.WriteString(s.buildBorder(widths, '+', '=', '+'))
-	sb.WriteString("\n")
-
-	// Data rows
-	for i, row := range data.Rows {
-		cells := s.rowToCells(row, data.Headers)
-		sb.WriteString(s.buildRow(cells, widths, '|'))
+		sb.WriteString(s.buildRow(cells, widths, data.Alignments, '|'))
 		sb.WriteString("\n")
-
-		if s.RowSeparators && i < len(data.Rows)-1 {
-			sb.WriteString(s.buildBorder(widths, '+', '-', '+'))
-			sb.WriteString("\n")
-		}
 	}
 
-	// Bottom border
-	sb.WriteString(s.buildBorder(widths, '+', '-', '+'))
-	sb.WriteString("\n")
-
 	_, err := output.Write([]byte(sb.String()))
 	return err
 }
@@ -285,7 +663,7 @@ func (s *UnifiedASCIISerializer) serializeRSTSimple(data *model.TableData, outpu
 	sb.WriteString("\n")
 
 	// Header row
-	sb.WriteString(s.buildRSTSimpleRow(data.Headers, widths))
+	sb.WriteString(s.buildRSTSimpleRow(s.headerCells(data.Headers), widths, data.Alignments))
 	sb.WriteString("\n")
 
 	// Header separator
@@ -295,7 +673,7 @@ func (s *UnifiedASCIISerializer) serializeRSTSimple(data *model.TableData, outpu
 	// Data rows
 	for _, row := range data.Rows {
 		cells := s.rowToCells(row, data.Headers)
-		sb.WriteString(s.buildRSTSimpleRow(cells, widths))
+		sb.WriteString(s.buildRSTSimpleRow(cells, widths, data.Alignments))
 		sb.WriteString("\n")
 	}
 
@@ -307,30 +685,31 @@ func (s *UnifiedASCIISerializer) serializeRSTSimple(data *model.TableData, outpu
 	return err
 }
 
-// buildBorder creates a border line like +------+------+
-func (s *UnifiedASCIISerializer) buildBorder(widths []int, corner, fill, sep rune) string {
+// buildFrameBorder creates a border line like ┌──────┬──────┐
+func (s *UnifiedASCIISerializer) buildFrameBorder(widths []int, border borderRunes) string {
 	var sb strings.Builder
-	sb.WriteRune(corner)
+	sb.WriteRune(border.left)
 	for i, w := range widths {
-		sb.WriteString(strings.Repeat(string(fill), w+2))
+		sb.WriteString(strings.Repeat(string(border.fill), w+2))
 		if i < len(widths)-1 {
-			sb.WriteRune(sep)
+			sb.WriteRune(border.sep)
 		} else {
-			sb.WriteRune(corner)
+			sb.WriteRune(border.right)
 		}
 	}
 	return sb.String()
 }
 
 // buildRow creates a data row like | val1 | val2 |
-func (s *UnifiedASCIISerializer) buildRow(cells []string, widths []int, border rune) string {
+func (s *UnifiedASCIISerializer) buildRow(cells []string, widths []int, aligns []model.Alignment, border rune) string {
 	var sb strings.Builder
 	sb.WriteRune(border)
 	for i, cell := range cells {
 		sb.WriteString(" ")
-		sb.WriteString(cell)
 		if i < len(widths) {
-			sb.WriteString(strings.Repeat(" ", widths[i]-len(cell)))
+			sb.WriteString(padCell(cell, widths[i], alignmentAt(aligns, i)))
+		} else {
+			sb.WriteString(cell)
 		}
 		sb.WriteString(" ")
 		sb.WriteRune(border)
@@ -338,19 +717,63 @@ func (s *UnifiedASCIISerializer) buildRow(cells []string, widths []int, border r
 	return sb.String()
 }
 
-// buildPsqlRow creates a psql-style row without leading/trailing borders
-func (s *UnifiedASCIISerializer) buildPsqlRow(cells []string, widths []int) string {
+// serializeSimple outputs a borderless table with a dashed header rule
+func (s *UnifiedASCIISerializer) serializeSimple(data *model.TableData, output io.Writer) error {
+	widths := s.calculateWidths(data)
 	var sb strings.Builder
-	for i, cell := range cells {
-		sb.WriteString(cell)
-		if i < len(widths) {
-			sb.WriteString(strings.Repeat(" ", widths[i]-len(cell)))
-		}
-		if i < len(cells)-1 {
-			sb.WriteString(" | ")
+
+	// Header row
+	sb.WriteString(s.buildRSTSimpleRow(s.headerCells(data.Headers), widths, data.Alignments))
+	sb.WriteString("\n")
+
+	// Header rule
+	sb.WriteString(s.buildRuleSeparator(widths, '-'))
+	sb.WriteString("\n")
+
+	// Data rows
+	for _, row := range data.Rows {
+		cells := s.rowToCells(row, data.Headers)
+		sb.WriteString(s.buildRSTSimpleRow(cells, widths, data.Alignments))
+		sb.WriteString("\n")
+	}
+
+	_, err := output.Write([]byte(sb.String()))
+	return err
+}
+
+// buildPsqlRowLines renders a psql row whose cells may span several lines.
+// Like psql itself, a + after a cell marks that the cell continues on the next line
+func (s *UnifiedASCIISerializer) buildPsqlRowLines(cells []string, widths []int, aligns []model.Alignment) []string {
+	cellLines, height := splitCellLines(cells)
+	lines := make([]string, height)
+
+	for j := 0; j < height; j++ {
+		var sb strings.Builder
+		for i := range cells {
+			text := ""
+			if j < len(cellLines[i]) {
+				text = cellLines[i][j]
+			}
+			continues := j < len(cellLines[i])-1
+
+			if i < len(widths) {
+				text = padCell(text, widths[i], alignmentAt(aligns, i))
+			}
+			sb.WriteString(text)
+
+			switch {
+			case i < len(cells)-1 && continues:
+				sb.WriteString("+| ")
+			case i < len(cells)-1:
+				sb.WriteString(" | ")
+			case continues:
+				sb.WriteString(" +")
+			}
 		}
+		lines[j] = sb.String()
 	}
-	return sb.String()
+
+	return lines
 }
 
 // buildPsqlSeparator creates a psql-style separator line
@@ -367,9 +790,14 @@ func (s *UnifiedASCIISerializer) buildPsqlSeparator(widths []int) string {
 
 // buildRSTSimpleSeparator creates an RST simple separator line
 func (s *UnifiedASCIISerializer) buildRSTSimpleSeparator(widths []int) string {
+	return s.buildRuleSeparator(widths, '=')
+}
+
+// buildRuleSeparator creates a rule line of fill characters with two-space column gaps
+func (s *UnifiedASCIISerializer) buildRuleSeparator(widths []int, fill rune) string {
 	var sb strings.Builder
 	for i, w := range widths {
-		sb.WriteString(strings.Repeat("=", w))
+		sb.WriteString(strings.Repeat(string(fill), w))
 		if i < len(widths)-1 {
 			sb.WriteString("  ")
 		}
@@ -378,12 +806,13 @@ func (s *UnifiedASCIISerializer) buildRSTSimpleSeparator(widths []int) string {
 }
 
 // buildRSTSimpleRow creates an RST simple data row
-func (s *UnifiedASCIISerializer) buildRSTSimpleRow(cells []string, widths []int) string {
+func (s *UnifiedASCIISerializer) buildRSTSimpleRow(cells []string, widths []int, aligns []model.Alignment) string {
 	var sb strings.Builder
 	for i, cell := range cells {
-		sb.WriteString(cell)
 		if i < len(widths) {
-			sb.WriteString(strings.Repeat(" ", widths[i]-len(cell)))
+			sb.WriteString(padCell(cell, widths[i], alignmentAt(aligns, i)))
+		} else {
+			sb.WriteString(cell)
 		}
 		if i < len(cells)-1 {
 			sb.WriteString("  ")
@@ -392,17 +821,110 @@ func (s *UnifiedASCIISerializer) buildRSTSimpleRow(cells []string, widths []int)
 	return sb.String()
 }
 
+// buildRowLines renders a bordered row whose cells may span several lines
+func (s *UnifiedASCIISerializer) buildRowLines(cells []string, widths []int, aligns []model.Alignment, border rune) []string {
+	cellLines, height := splitCellLines(cells)
+	lines := make([]string, height)
+
+	for j := 0; j < height; j++ {
+		line := make([]string, len(cells))
+		for i := range cells {
+			if j < len(cellLines[i]) {
+				line[i] = cellLines[i][j]
+			}
+		}
+		lines[j] = s.buildRow(line, widths, aligns, border)
+	}
+
+	return lines
+}
+
 // rowToCells converts a row of Values to strings
 func (s *UnifiedASCIISerializer) rowToCells(row []model.Value, headers []string) []string {
 	cells := make([]string, len(headers))
 	for i := 0; i < len(headers); i++ {
 		if i < len(row) {
-			cells[i] = unifiedValueToString(row[i])
+			cells[i] = s.formatCell(unifiedValueToString(row[i]))
 		}
 	}
 	return cells
 }
 
+// headerCells prepares the header names for output in the current style
+func (s *UnifiedASCIISerializer) headerCells(headers []string) []string {
+	cells := make([]string, len(headers))
+	for i, header := range headers {
+		cells[i] = s.formatCell(header)
+	}
+	return cells
+}
+
+// formatCell prepares embedded line breaks for the current style.
+// Bordered styles and psql wrap them onto several lines, Markdown uses <br>,
+// and styles without multi-line cells (org, rst-simple, simple) use a space
+func (s *UnifiedASCIISerializer) formatCell(cell string) string {
+	if !strings.ContainsAny(cell, "\r\n") {
+		return cell
+	}
+
+	cell = strings.ReplaceAll(cell, "\r\n", "\n")
+	cell = strings.ReplaceAll(cell, "\r", "\n")
+
+	switch {
+	case s.Style == StyleMarkdown:
+		return strings.ReplaceAll(cell, "\n", "<br>")
+	case !s.supportsMultiLine():
+		return strings.ReplaceAll(cell, "\n", " ")
+	default:
+		return cell
+	}
+}
+
+// cellWidth returns the display width of the widest line in a cell
+func cellWidth(cell string) int {
+	widest := 0
+	for _, line := range strings.Split(cell, "\n") {
+		if w := textwidth.String(line); w > widest {
+			widest = w
+		}
+	}
+	return widest
+}
+
+// splitCellLines splits each cell on line breaks and returns the lines
+// together with the number of physical lines the row needs
+func splitCellLines(cells []string) ([][]string, int) {
+	cellLines := make([][]string, len(cells))
+	height := 1
+	for i, cell := range cells {
+		cellLines[i] = strings.Split(cell, "\n")
+		if len(cellLines[i]) > height {
+			height = len(cellLines[i])
+		}
+	}
+	return cellLines, height
+}
+
+// hasMultiLineCells reports whether any data cell contains a line break
+func hasMultiLineCells(data *model.TableData) bool {
+	for _, row := range data.Rows {
+		for _, value := range row {
+			if strings.ContainsAny(unifiedValueToString(value), "\r\n") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// writeLines writes each line followed by a newline
+func writeLines(sb *strings.Builder, lines []string) {
+	for _, line := range lines {
+		sb.WriteString(line)
+		sb.WriteString("\n")
+	}
+}
+
 // unifiedValueToString converts a model.Value to its string representation
 func unifiedValueToString(val model.Value) string {
 	switch val.Type {
@@ -0,0 +1,410 @@
+package serializer
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/user/table-converter/internal/model"
+	"github.com/user/table-converter/internal/textwidth"
+)
+
+// sampleTable returns a small table used by the ASCII serializer tests
+func sampleTable() *model.TableData {
+	return model.NewTableData(
+		[]string{"name", "age"},
+		[][]model.Value{
+			{model.NewStringValue("Alice"), model.NewNumberValue(30)},
+			{model.NewStringValue("Bob"), model.NewNumberValue(25)},
+		},
+	)
+}
+
+// TestUnifiedASCIISerializer_UnicodeStyles tests the Unicode box-drawing styles
+func TestUnifiedASCIISerializer_UnicodeStyles(t *testing.T) {
+	tests := []struct {
+		style    string
+		expected string
+	}{
+		{
+			style: "unicode",
+			expected: `┌───────┬─────┐
+│ name  │ age │
+├───────┼─────┤
+│ Alice │  30 │
+│ Bob   │  25 │
+└───────┴─────┘
+`,
+		},
+		{
+			style: "double",
+			expected: `╔═══════╦═════╗
+║ name  ║ age ║
+╠═══════╬═════╣
+║ Alice ║  30 ║
+║ Bob   ║  25 ║
+╚═══════╩═════╝
+`,
+		},
+		{
+			style: "rounded",
+			expected: `╭───────┬─────╮
+│ name  │ age │
+├───────┼─────┤
+│ Alice │  30 │
+│ Bob   │  25 │
+╰───────┴─────╯
+`,
+		},
+		{
+			style: "heavy",
+			expected: `┏━━━━━━━┳━━━━━┓
+┃ name  ┃ age ┃
+┣━━━━━━━╋━━━━━┫
+┃ Alice ┃  30 ┃
+┃ Bob   ┃  25 ┃
+┗━━━━━━━┻━━━━━┛
+`,
+		},
+		{
+			style: "simple",
+			expected: `name   age
+-----  ---
+Alice   30
+Bob     25
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.style, func(t *testing.T) {
+			s := NewUnifiedASCIISerializer("")
+			if err := s.SetStyle(tt.style); err != nil {
+				t.Fatalf("SetStyle(%q) failed: %v", tt.style, err)
+			}
+
+			var buf bytes.Buffer
+			if err := s.Serialize(sampleTable(), &buf); err != nil {
+				t.Fatalf("Serialize failed: %v", err)
+			}
+
+			if buf.String() != tt.expected {
+				t.Errorf("unexpected output:\n%s\nwant:\n%s", buf.String(), tt.expected)
+			}
+		})
+	}
+}
+
+// TestUnifiedASCIISerializer_UnicodeRowSeparators tests row separators in Unicode styles
+func TestUnifiedASCIISerializer_UnicodeRowSeparators(t *testing.T) {
+	s := NewUnifiedASCIISerializer(StyleUnicode)
+	s.RowSeparators = true
+
+	var buf bytes.Buffer
+	if err := s.Serialize(sampleTable(), &buf); err != nil {
+		t.Fatalf("Serialize failed: %v", err)
+	}
+
+	if got := strings.Count(buf.String(), "├"); got != 2 {
+		t.Errorf("expected 2 separator lines, got %d", got)
+	}
+}
+
+// TestUnifiedASCIISerializer_MultiLineCells tests rendering of cells containing line breaks
+func TestUnifiedASCIISerializer_MultiLineCells(t *testing.T) {
+	td := model.NewTableData(
+		[]string{"name", "note"},
+		[][]model.Value{
+			{model.NewStringValue("Alice"), model.NewStringValue("one\ntwo")},
+			{model.NewStringValue("Bob"), model.NewStringValue("three")},
+		},
+	)
+
+	tests := []struct {
+		style    TableStyle
+		expected string
+	}{
+		{
+			style: StyleBox,
+			expected: `+-------+-------+
+| name  | note  |
++-------+-------+
+| Alice | one   |
+|       | two   |
++-------+-------+
+| Bob   | three |
++-------+-------+
+`,
+		},
+		{
+			style: StylePsql,
+			expected: `name  | note 
+------+------
+Alice | one   +
+      | two  
+Bob   | three
+`,
+		},
+		{
+			style: StyleMarkdown,
+			expected: `| name  | note       |
+| ----- | ---------- |
+| Alice | one<br>two |
+| Bob   | three      |
+`,
+		},
+		{
+			style: StyleOrgMode,
+			expected: `| name  | note    |
+|-------+---------|
+| Alice | one two |
+| Bob   | three   |
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.style), func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := NewUnifiedASCIISerializer(tt.style).Serialize(td, &buf); err != nil {
+				t.Fatalf("Serialize failed: %v", err)
+			}
+
+			if buf.String() != tt.expected {
+				t.Errorf("unexpected output:\n%s\nwant:\n%s", buf.String(), tt.expected)
+			}
+		})
+	}
+}
+
+// TestUnifiedASCIISerializer_WidthLimits tests truncation, wrapping and total width limits
+func TestUnifiedASCIISerializer_WidthLimits(t *testing.T) {
+	td := model.NewTableData(
+		[]string{"id", "description"},
+		[][]model.Value{
+			{model.NewNumberValue(1), model.NewStringValue("the quick brown fox jumps")},
+			{model.NewNumberValue(2), model.NewStringValue("short")},
+		},
+	)
+
+	tests := []struct {
+		name     string
+		maxWidth int
+		columns  map[string]int
+		overflow string
+		expected string
+	}{
+		{
+			name:     "truncate column",
+			columns:  map[string]int{"description": 12},
+			overflow: "truncate",
+			expected: `+-----+--------------+
+|  id | description  |
++-----+--------------+
+|   1 | the quick b… |
+|   2 | short        |
++-----+--------------+
+`,
+		},
+		{
+			name:     "wrap column",
+			columns:  map[string]int{"description": 11},
+			overflow: "wrap",
+			expected: `+-----+-------------+
+|  id | description |
++-----+-------------+
+|   1 | the quick   |
+|     | brown fox   |
+|     | jumps       |
++-----+-------------+
+|   2 | short       |
++-----+-------------+
+`,
+		},
+		{
+			name:     "total width shrinks widest column",
+			maxWidth: 20,
+			expected: `+-----+------------+
+|  id | descripti… |
++-----+------------+
+|   1 | the quick… |
+|   2 | short      |
++-----+------------+
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewUnifiedASCIISerializer(StyleBox)
+			if err := s.SetWidthLimits(tt.maxWidth, tt.columns, tt.overflow); err != nil {
+				t.Fatalf("SetWidthLimits failed: %v", err)
+			}
+
+			var buf bytes.Buffer
+			if err := s.Serialize(td, &buf); err != nil {
+				t.Fatalf("Serialize failed: %v", err)
+			}
+
+			if buf.String() != tt.expected {
+				t.Errorf("unexpected output:\n%s\nwant:\n%s", buf.String(), tt.expected)
+			}
+		})
+	}
+}
+
+// TestUnifiedASCIISerializer_MaxWidthFits tests that every style fits within the total width
+func TestUnifiedASCIISerializer_MaxWidthFits(t *testing.T) {
+	long := strings.Repeat("word ", 40)
+	td := model.NewTableData(
+		[]string{"a", "b", "c"},
+		[][]model.Value{
+			{model.NewStringValue(long), model.NewStringValue("x"), model.NewStringValue(long)},
+		},
+	)
+
+	styles := []TableStyle{StyleBox, StylePsql, StyleMarkdown, StyleOrgMode, StyleRSTGrid, StyleRSTSimple, StyleUnicode, StyleSimple}
+	for _, style := range styles {
+		for _, overflow := range []string{"truncate", "wrap"} {
+			s := NewUnifiedASCIISerializer(style)
+			if err := s.SetWidthLimits(40, nil, overflow); err != nil {
+				t.Fatalf("SetWidthLimits failed: %v", err)
+			}
+
+			var buf bytes.Buffer
+			if err := s.Serialize(td, &buf); err != nil {
+				t.Fatalf("Serialize failed: %v", err)
+			}
+
+			for _, line := range strings.Split(strings.TrimRight(buf.String(), "\n"), "\n") {
+				if w := textwidth.String(line); w > 40 {
+					t.Errorf("%s/%s: line %q is %d columns wide", style, overflow, line, w)
+				}
+			}
+		}
+	}
+}
+
+// TestUnifiedASCIISerializer_SetWidthLimitsInvalid tests validation of width limits
+func TestUnifiedASCIISerializer_SetWidthLimitsInvalid(t *testing.T) {
+	s := NewUnifiedASCIISerializer(StyleBox)
+	if err := s.SetWidthLimits(-1, nil, ""); err == nil {
+		t.Error("expected error for negative width")
+	}
+	if err := s.SetWidthLimits(0, map[string]int{"a": 0}, ""); err == nil {
+		t.Error("expected error for zero column width")
+	}
+	if err := s.SetWidthLimits(80, nil, "squash"); err == nil {
+		t.Error("expected error for unknown overflow mode")
+	}
+}
+
+// TestUnifiedASCIISerializer_NumericAlignment tests alignment of numeric columns
+func TestUnifiedASCIISerializer_NumericAlignment(t *testing.T) {
+	td := model.NewTableData(
+		[]string{"item", "price"},
+		[][]model.Value{
+			{model.NewStringValue("tea"), model.NewNumberValue(1.5)},
+			{model.NewStringValue("cake"), model.NewNumberValue(10)},
+			{model.NewStringValue("jam"), model.NewNumberValue(3.25)},
+		},
+	)
+
+	tests := []struct {
+		name     string
+		style    TableStyle
+		align    string
+		expected string
+	}{
+		{
+			name:  "psql right",
+			style: StylePsql,
+			align: "right",
+			expected: `item | price
+-----+------
+tea  |   1.5
+cake |    10
+jam  |  3.25
+`,
+		},
+		{
+			name:  "psql left",
+			style: StylePsql,
+			align: "left",
+			expected: `item | price
+-----+------
+tea  | 1.5  
+cake | 10   
+jam  | 3.25 
+`,
+		},
+		{
+			name:  "org decimal",
+			style: StyleOrgMode,
+			align: "decimal",
+			expected: `| item | price |
+|------+-------|
+| tea  |  1.5  |
+| cake | 10    |
+| jam  |  3.25 |
+`,
+		},
+		{
+			name:  "markdown right marker",
+			style: StyleMarkdown,
+			align: "right",
+			expected: `| item | price |
+| ---- | ----: |
+| tea  |   1.5 |
+| cake |    10 |
+| jam  |  3.25 |
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewUnifiedASCIISerializer(tt.style)
+			if err := s.SetNumericAlignment(tt.align); err != nil {
+				t.Fatalf("SetNumericAlignment failed: %v", err)
+			}
+
+			var buf bytes.Buffer
+			if err := s.Serialize(td, &buf); err != nil {
+				t.Fatalf("Serialize failed: %v", err)
+			}
+
+			if buf.String() != tt.expected {
+				t.Errorf("unexpected output:\n%s\nwant:\n%s", buf.String(), tt.expected)
+			}
+		})
+	}
+
+	if err := NewUnifiedASCIISerializer(StyleBox).SetNumericAlignment("middle"); err == nil {
+		t.Error("expected error for unknown numeric alignment")
+	}
+}
+
+// TestUnifiedASCIISerializer_MarkdownAlignmentMarkers tests explicit column alignments
+func TestUnifiedASCIISerializer_MarkdownAlignmentMarkers(t *testing.T) {
+	td := model.NewTableData(
+		[]string{"left", "center", "right", "plain"},
+		[][]model.Value{
+			{model.NewStringValue("a"), model.NewStringValue("b"), model.NewStringValue("c"), model.NewStringValue("d")},
+		},
+	)
+	td.Alignments = []model.Alignment{model.AlignLeft, model.AlignCenter, model.AlignRight, model.AlignDefault}
+
+	var buf bytes.Buffer
+	if err := NewUnifiedASCIISerializer(StyleMarkdown).Serialize(td, &buf); err != nil {
+		t.Fatalf("Serialize failed: %v", err)
+	}
+
+	expected := `| left | center | right | plain |
+| :--- | :----: | ----: | ----- |
+| a    |   b    |     c | d     |
+`
+	if buf.String() != expected {
+		t.Errorf("unexpected output:\n%s\nwant:\n%s", buf.String(), expected)
+	}
+}
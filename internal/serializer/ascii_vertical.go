@@ -0,0 +1,143 @@
+package serializer
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/user/table-converter/internal/model"
+	"github.com/user/table-converter/internal/textwidth"
+)
+
+// mysqlRowStars is the run of stars on each side of a MySQL vertical row header
+var mysqlRowStars = strings.Repeat("*", 27)
+
+// serializePsqlExpanded outputs PostgreSQL expanded display (\x):
+//
+//	-[ RECORD 1 ]-
+//	id   | 1
+//	name | Alice
+//
+// Multi-line values end each continued line with + like psql does
+func (s *UnifiedASCIISerializer) serializePsqlExpanded(data *model.TableData, output io.Writer) error {
+	nameWidth := maxDisplayWidth(data.Headers)
+	valueWidth := 0
+	for _, row := range data.Rows {
+		for _, val := range row {
+			for _, line := range strings.Split(verticalValue(val, ""), "\n") {
+				if w := textwidth.String(line); w > valueWidth {
+					valueWidth = w
+				}
+			}
+		}
+	}
+
+	var sb strings.Builder
+	for r, row := range data.Rows {
+		// The record header runs across both columns, with a + over the
+		// separator when the label is short enough to leave room for it
+		label := fmt.Sprintf("-[ RECORD %d ]", r+1)
+		if w := textwidth.String(label); w < nameWidth+1 {
+			label += strings.Repeat("-", nameWidth+1-w) + "+" + strings.Repeat("-", valueWidth+1)
+		} else if total := nameWidth + 3 + valueWidth; w < total {
+			label += strings.Repeat("-", total-w)
+		}
+		sb.WriteString(label)
+		sb.WriteString("\n")
+
+		for i, header := range data.Headers {
+			lines := strings.Split(verticalValue(cellAt(row, i), ""), "\n")
+			for j, line := range lines {
+				name := ""
+				if j == 0 {
+					name = header
+				}
+				text := padCell(name, nameWidth, model.AlignLeft) + " | " + line
+				if j < len(lines)-1 {
+					text = padCell(name, nameWidth, model.AlignLeft) + " | " + padCell(line, valueWidth, model.AlignLeft) + "+"
+				}
+				sb.WriteString(strings.TrimRight(text, " "))
+				sb.WriteString("\n")
+			}
+		}
+	}
+
+	_, err := output.Write([]byte(sb.String()))
+	return err
+}
+
+// serializeMySQLVertical outputs MySQL vertical output (\G):
+//
+//	*************************** 1. row ***************************
+//	  id: 1
+//	name: Alice
+//
+// Null values are written as NULL
+func (s *UnifiedASCIISerializer) serializeMySQLVertical(data *model.TableData, output io.Writer) error {
+	nameWidth := maxDisplayWidth(data.Headers)
+
+	var sb strings.Builder
+	for r, row := range data.Rows {
+		fmt.Fprintf(&sb, "%s %d. row %s\n", mysqlRowStars, r+1, mysqlRowStars)
+		for i, header := range data.Headers {
+			line := padCell(header, nameWidth, model.AlignRight) + ": " + verticalValue(cellAt(row, i), "NULL")
+			sb.WriteString(strings.TrimRight(line, " "))
+			sb.WriteString("\n")
+		}
+	}
+
+	_, err := output.Write([]byte(sb.String()))
+	return err
+}
+
+// serializeSQLiteLine outputs SQLite .mode line output, with a blank line
+// between records:
+//
+//	  id = 1
+//	name = Alice
+func (s *UnifiedASCIISerializer) serializeSQLiteLine(data *model.TableData, output io.Writer) error {
+	nameWidth := maxDisplayWidth(data.Headers)
+
+	var sb strings.Builder
+	for r, row := range data.Rows {
+		if r > 0 {
+			sb.WriteString("\n")
+		}
+		for i, header := range data.Headers {
+			line := padCell(header, nameWidth, model.AlignRight) + " = " + verticalValue(cellAt(row, i), "")
+			sb.WriteString(strings.TrimRight(line, " "))
+			sb.WriteString("\n")
+		}
+	}
+
+	_, err := output.Write([]byte(sb.String()))
+	return err
+}
+
+// maxDisplayWidth returns the display width of the widest string
+func maxDisplayWidth(values []string) int {
+	width := 0
+	for _, v := range values {
+		if w := textwidth.String(v); w > width {
+			width = w
+		}
+	}
+	return width
+}
+
+// cellAt returns the value in column i of a row, or null for short rows
+func cellAt(row []model.Value, i int) model.Value {
+	if i < len(row) {
+		return row[i]
+	}
+	return model.NewNullValue()
+}
+
+// verticalValue returns the text of a value as it was read, so a field such
+// as 1 is not rewritten as true, and null as the given marker
+func verticalValue(val model.Value, null string) string {
+	if val.Type == model.TypeNull {
+		return null
+	}
+	return strings.ReplaceAll(val.Raw, "\r\n", "\n")
+}
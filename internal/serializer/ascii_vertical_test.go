@@ -0,0 +1,96 @@
+package serializer
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/user/table-converter/internal/model"
+)
+
+func verticalSampleTable() *model.TableData {
+	return model.NewTableData(
+		[]string{"id", "name", "note"},
+		[][]model.Value{
+			{model.NewValue("1"), model.NewStringValue("Alice"), model.NewNullValue()},
+			{model.NewValue("2"), model.NewStringValue("Bob Smith"), model.NewStringValue("a\nb")},
+		},
+	)
+}
+
+func TestUnifiedASCIISerializer_VerticalStyles(t *testing.T) {
+	tests := []struct {
+		style    TableStyle
+		expected string
+	}{
+		{
+			style: StylePsqlExpanded,
+			expected: `-[ RECORD 1 ]---
+id   | 1
+name | Alice
+note |
+-[ RECORD 2 ]---
+id   | 2
+name | Bob Smith
+note | a        +
+     | b
+`,
+		},
+		{
+			style: StyleMySQLVertical,
+			expected: `*************************** 1. row ***************************
+  id: 1
+name: Alice
+note: NULL
+*************************** 2. row ***************************
+  id: 2
+name: Bob Smith
+note: a
+b
+`,
+		},
+		{
+			style: StyleSQLiteLine,
+			expected: `  id = 1
+name = Alice
+note =
+
+  id = 2
+name = Bob Smith
+note = a
+b
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.style), func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := NewUnifiedASCIISerializer(tt.style).Serialize(verticalSampleTable(), &buf); err != nil {
+				t.Fatalf("Serialize failed: %v", err)
+			}
+			if buf.String() != tt.expected {
+				t.Errorf("unexpected output:\n%s\nwant:\n%s", buf.String(), tt.expected)
+			}
+		})
+	}
+}
+
+func TestUnifiedASCIISerializer_PsqlExpandedLongNames(t *testing.T) {
+	data := model.NewTableData(
+		[]string{"id", "a_rather_long_name"},
+		[][]model.Value{{model.NewValue("7"), model.NewStringValue("x")}},
+	)
+
+	var buf bytes.Buffer
+	if err := NewUnifiedASCIISerializer(StylePsqlExpanded).Serialize(data, &buf); err != nil {
+		t.Fatalf("Serialize failed: %v", err)
+	}
+
+	expected := `-[ RECORD 1 ]------+--
+id                 | 7
+a_rather_long_name | x
+`
+	if buf.String() != expected {
+		t.Errorf("unexpected output:\n%s\nwant:\n%s", buf.String(), expected)
+	}
+}
@@ -2,8 +2,10 @@ package serializer
 
 import (
 	"encoding/csv"
+	"fmt"
 	"io"
 	"strings"
+	"unicode/utf8"
 
 	"github.com/user/table-converter/internal/model"
 )
@@ -16,6 +18,18 @@ type CSVSerializer struct {
 	LineTerminator string
 	// AlwaysQuote forces all fields to be quoted
 	AlwaysQuote bool
+	// Separator is a delimiter of several characters. If set, it is used
+	// instead of Delimiter
+	Separator string
+	// Quote is the quote character (0 = '"', model.NoQuote = no quoting)
+	Quote rune
+	// Escape is written before quote characters inside quoted fields
+	// instead of doubling them, and before special characters in unquoted
+	// fields (0 = no escaping)
+	Escape rune
+	// NeverQuote writes every field unquoted, escaping delimiters and line
+	// breaks with Escape
+	NeverQuote bool
 }
 
 // NewCSVSerializer creates a new CSV serializer with default settings
@@ -60,6 +74,30 @@ func NewCSVSerializerWithOptions(opts ...CSVSerializerOption) *CSVSerializer {
 	return s
 }
 
+// SetDialect sets the CSV dialect of the output
+func (s *CSVSerializer) SetDialect(dialect model.CSVDialect) error {
+	if err := dialect.Validate(); err != nil {
+		return err
+	}
+
+	s.Delimiter, s.Separator = ',', ""
+	if r, size := utf8.DecodeRuneInString(dialect.Delimiter); size > 0 && size == len(dialect.Delimiter) {
+		s.Delimiter = r
+	} else if dialect.Delimiter != "" {
+		s.Separator = dialect.Delimiter
+	}
+
+	s.LineTerminator = dialect.LineTerminator
+	if s.LineTerminator == "" {
+		s.LineTerminator = "\n"
+	}
+	s.Quote = dialect.Quote
+	s.Escape = dialect.Escape
+	s.AlwaysQuote = dialect.Quoting == model.QuoteAll
+	s.NeverQuote = dialect.Quoting == model.QuoteNever || dialect.Quote == model.NoQuote
+	return nil
+}
+
 // Serialize writes TableData to the output writer in CSV format
 func (s *CSVSerializer) Serialize(data *model.TableData, output io.Writer) error {
 	if data == nil {
@@ -71,9 +109,10 @@ func (s *CSVSerializer) Serialize(data *model.TableData, output io.Writer) error
 		return NewSerializeError("invalid TableData").WithErr(err)
 	}
 
-	// If always quoting, use custom writer
-	if s.AlwaysQuote {
-		return s.serializeWithQuotes(data, output)
+	// encoding/csv only writes comma-style quoting with a single-character
+	// delimiter; other dialects use a custom writer
+	if s.AlwaysQuote || s.NeverQuote || s.Separator != "" || s.Escape != 0 || (s.Quote != 0 && s.Quote != '"') {
+		return s.serializeWithDialect(data, output)
 	}
 
 	writer := csv.NewWriter(output)
@@ -124,10 +163,11 @@ func (s *CSVSerializer) Serialize(data *model.TableData, output io.Writer) error
 	return nil
 }
 
-// serializeWithQuotes writes CSV with all fields quoted
-func (s *CSVSerializer) serializeWithQuotes(data *model.TableData, output io.Writer) error {
+// serializeWithDialect writes CSV with a custom delimiter, quote, escape or
+// quoting style
+func (s *CSVSerializer) serializeWithDialect(data *model.TableData, output io.Writer) error {
 	// Write headers
-	if err := s.writeQuotedRow(data.Headers, output); err != nil {
+	if err := s.writeDialectRow(data.Headers, output); err != nil {
 		return err
 	}
 
@@ -137,7 +177,7 @@ func (s *CSVSerializer) serializeWithQuotes(data *model.TableData, output io.Wri
 		for j, value := range row {
 			record[j] = value.String()
 		}
-		if err := s.writeQuotedRow(record, output); err != nil {
+		if err := s.writeDialectRow(record, output); err != nil {
 			return err
 		}
 	}
@@ -145,24 +185,52 @@ func (s *CSVSerializer) serializeWithQuotes(data *model.TableData, output io.Wri
 	return nil
 }
 
-// writeQuotedRow writes a single row with all fields quoted
-func (s *CSVSerializer) writeQuotedRow(fields []string, output io.Writer) error {
+// writeDialectRow writes a single row, quoting the fields that need it
+func (s *CSVSerializer) writeDialectRow(fields []string, output io.Writer) error {
 	var builder strings.Builder
 
+	delimiter := s.Separator
+	if delimiter == "" {
+		delimiter = string(s.Delimiter)
+	}
+	quote := s.Quote
+	if quote == 0 {
+		quote = '"'
+	}
+
 	for i, field := range fields {
 		if i > 0 {
-			builder.WriteRune(s.Delimiter)
+			builder.WriteString(delimiter)
+		}
+
+		needsQuotes := s.AlwaysQuote || (len(fields) == 1 && field == "") ||
+			strings.Contains(field, delimiter) || strings.ContainsAny(field, "\r\n") ||
+			strings.ContainsRune(field, quote) || strings.HasPrefix(field, " ") ||
+			(s.Escape != 0 && strings.ContainsRune(field, s.Escape))
+		if s.NeverQuote {
+			if err := s.writeEscaped(&builder, field, delimiter); err != nil {
+				return err
+			}
+			continue
 		}
-		builder.WriteByte('"')
-		// Escape any quotes in the field
+		if !needsQuotes {
+			builder.WriteString(field)
+			continue
+		}
+
+		builder.WriteRune(quote)
 		for _, ch := range field {
-			if ch == '"' {
-				builder.WriteString("\"\"")
-			} else {
-				builder.WriteRune(ch)
+			switch {
+			case ch == quote && s.Escape != 0:
+				builder.WriteRune(s.Escape)
+			case ch == quote:
+				builder.WriteRune(quote)
+			case ch == s.Escape && s.Escape != 0:
+				builder.WriteRune(s.Escape)
 			}
+			builder.WriteRune(ch)
 		}
-		builder.WriteByte('"')
+		builder.WriteRune(quote)
 	}
 	builder.WriteString(s.LineTerminator)
 
@@ -173,6 +241,47 @@ func (s *CSVSerializer) writeQuotedRow(fields []string, output io.Writer) error
 	return nil
 }
 
+// writeEscaped writes an unquoted field, escaping line breaks, tabs, the
+// escape character and the delimiter. Without an escape character, fields
+// containing the delimiter or line breaks cannot be written
+func (s *CSVSerializer) writeEscaped(builder *strings.Builder, field, delimiter string) error {
+	if s.Escape == 0 {
+		if strings.Contains(field, delimiter) || strings.ContainsAny(field, "\r\n") {
+			return NewSerializeError(fmt.Sprintf("field %q contains the delimiter or a line break and cannot be written unquoted without an escape character", field))
+		}
+		builder.WriteString(field)
+		return nil
+	}
+
+	for len(field) > 0 {
+		if strings.HasPrefix(field, delimiter) && delimiter != "\t" {
+			builder.WriteRune(s.Escape)
+			builder.WriteString(delimiter)
+			field = field[len(delimiter):]
+			continue
+		}
+		ch, size := utf8.DecodeRuneInString(field)
+		field = field[size:]
+		switch ch {
+		case '\n':
+			builder.WriteRune(s.Escape)
+			builder.WriteByte('n')
+		case '\r':
+			builder.WriteRune(s.Escape)
+			builder.WriteByte('r')
+		case '\t':
+			builder.WriteRune(s.Escape)
+			builder.WriteByte('t')
+		case s.Escape:
+			builder.WriteRune(s.Escape)
+			builder.WriteRune(ch)
+		default:
+			builder.WriteRune(ch)
+		}
+	}
+	return nil
+}
+
 // ParseLineTerminator converts a string to a line terminator
 func ParseLineTerminator(s string) string {
 	switch strings.ToLower(s) {
@@ -0,0 +1,77 @@
+package serializer
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/user/table-converter/internal/model"
+)
+
+func TestCSVSerializer_Dialect(t *testing.T) {
+	data := model.NewTableData([]string{"name", "note"}, [][]model.Value{
+		{model.NewStringValue("Ann"), model.NewStringValue(`say "hi", then	go`)},
+		{model.NewStringValue("Bob"), model.NewStringValue("line1\nline2")},
+	})
+
+	tests := []struct {
+		name    string
+		dialect model.CSVDialect
+		want    string
+	}{
+		{
+			name:    "default",
+			dialect: model.CSVDialect{},
+			want:    "name,note\nAnn,\"say \"\"hi\"\", then\tgo\"\nBob,\"line1\nline2\"\n",
+		},
+		{
+			name:    "multi-character delimiter and crlf",
+			dialect: model.CSVDialect{Delimiter: "::", LineTerminator: "\r\n"},
+			want:    "name::note\r\nAnn::\"say \"\"hi\"\", then\tgo\"\r\nBob::\"line1\nline2\"\r\n",
+		},
+		{
+			name:    "single quotes, all quoted",
+			dialect: model.CSVDialect{Quote: '\'', Quoting: model.QuoteAll},
+			want:    "'name','note'\n'Ann','say \"hi\", then\tgo'\n'Bob','line1\nline2'\n",
+		},
+		{
+			name:    "backslash escapes",
+			dialect: model.CSVDialect{Escape: '\\'},
+			want:    "name,note\nAnn,\"say \\\"hi\\\", then\tgo\"\nBob,\"line1\nline2\"\n",
+		},
+		{
+			name:    "tsv without quoting",
+			dialect: model.CSVDialect{Delimiter: "\t", Quoting: model.QuoteNever, Escape: '\\'},
+			want:    "name\tnote\nAnn\tsay \"hi\", then\\tgo\nBob\tline1\\nline2\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewCSVSerializer()
+			if err := s.SetDialect(tt.dialect); err != nil {
+				t.Fatalf("SetDialect failed: %v", err)
+			}
+			var buf bytes.Buffer
+			if err := s.Serialize(data, &buf); err != nil {
+				t.Fatalf("Serialize failed: %v", err)
+			}
+			if buf.String() != tt.want {
+				t.Errorf("output = %q, want %q", buf.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestCSVSerializer_NeverQuoteWithoutEscape(t *testing.T) {
+	data := model.NewTableData([]string{"note"}, [][]model.Value{{model.NewStringValue("a,b")}})
+
+	s := NewCSVSerializer()
+	if err := s.SetDialect(model.CSVDialect{Quoting: model.QuoteNever}); err != nil {
+		t.Fatalf("SetDialect failed: %v", err)
+	}
+	err := s.Serialize(data, &bytes.Buffer{})
+	if err == nil || !strings.Contains(err.Error(), "cannot be written unquoted") {
+		t.Errorf("expected an unquoted field error, got %v", err)
+	}
+}
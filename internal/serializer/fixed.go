@@ -0,0 +1,190 @@
+package serializer
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/user/table-converter/internal/model"
+	"github.com/user/table-converter/internal/textwidth"
+)
+
+// FixedWidthSerializer implements the Serializer interface for fixed-width column text
+type FixedWidthSerializer struct {
+	// Columns declares the field positions. Declared columns are matched to
+	// table columns by header and no header line is written. If empty, each
+	// column is as wide as its widest value and a header line is written
+	Columns []model.ColumnSpec
+	// PadChar fills fields up to their width (default: space)
+	PadChar rune
+}
+
+// NewFixedWidthSerializer creates a fixed-width serializer that sizes columns to fit
+func NewFixedWidthSerializer() *FixedWidthSerializer {
+	return &FixedWidthSerializer{
+		PadChar: ' ',
+	}
+}
+
+// NewFixedWidthSerializerWithColumns creates a fixed-width serializer with declared columns
+func NewFixedWidthSerializerWithColumns(columns []model.ColumnSpec) *FixedWidthSerializer {
+	s := NewFixedWidthSerializer()
+	s.Columns = columns
+	return s
+}
+
+// SetColumns sets the declared columns; nil makes the serializer size columns to fit
+func (s *FixedWidthSerializer) SetColumns(columns []model.ColumnSpec) {
+	s.Columns = columns
+}
+
+// SetPadChar sets the padding character, which must be one display column
+// wide; zero resets it to a space
+func (s *FixedWidthSerializer) SetPadChar(pad rune) error {
+	if pad == 0 {
+		pad = ' '
+	}
+	if textwidth.Rune(pad) != 1 {
+		return fmt.Errorf("padding character %q must be one column wide", pad)
+	}
+	s.PadChar = pad
+	return nil
+}
+
+// Serialize writes TableData as fixed-width text, padding each value to its
+// field width and truncating values that do not fit
+func (s *FixedWidthSerializer) Serialize(data *model.TableData, output io.Writer) error {
+	if data == nil {
+		return NewSerializeError("table data is nil")
+	}
+
+	if len(s.Columns) == 0 && model.PadInValues(s.PadChar) {
+		return NewSerializeError(fmt.Sprintf("padding character %q can appear in values, so it needs declared columns with a right alignment", s.PadChar))
+	}
+
+	columns, indexes, err := s.layout(data)
+	if err != nil {
+		return err
+	}
+
+	var sb strings.Builder
+	if len(s.Columns) == 0 {
+		sb.WriteString(s.buildLine(data.Headers, columns, make([]model.Alignment, len(columns)), s.PadChar))
+		sb.WriteString("\n")
+	}
+
+	aligns := make([]model.Alignment, len(columns))
+	for i, column := range columns {
+		aligns[i] = column.Align
+		if aligns[i] == model.AlignDefault {
+			aligns[i] = model.AlignLeft
+			if isNumericColumn(data, indexes[i]) {
+				aligns[i] = model.AlignRight
+			}
+		}
+	}
+
+	for _, row := range data.Rows {
+		cells := make([]string, len(columns))
+		for i, idx := range indexes {
+			if idx < len(row) {
+				cells[i] = fixedValue(row[idx])
+			}
+		}
+		sb.WriteString(s.buildLine(cells, columns, aligns, s.PadChar))
+		sb.WriteString("\n")
+	}
+
+	_, err = output.Write([]byte(sb.String()))
+	return err
+}
+
+// layout returns the columns to write and, for each, the index of the table
+// column it holds. Declared columns must all be present in the table
+func (s *FixedWidthSerializer) layout(data *model.TableData) ([]model.ColumnSpec, []int, error) {
+	if len(s.Columns) > 0 {
+		indexes := make([]int, len(s.Columns))
+		for i, column := range s.Columns {
+			indexes[i] = columnIndex(data.Headers, column.Name)
+			if indexes[i] < 0 {
+				return nil, nil, NewSerializeError(fmt.Sprintf("declared column %q not found in table", column.Name)).
+					WithContext(fmt.Sprintf("table columns: %s", strings.Join(data.Headers, ", ")))
+			}
+		}
+		return s.Columns, indexes, nil
+	}
+
+	// Size each column to its widest value, one space apart
+	columns := make([]model.ColumnSpec, len(data.Headers))
+	indexes := make([]int, len(data.Headers))
+	start := 1
+	for i, header := range data.Headers {
+		width := textwidth.String(fixedCell(header))
+		for _, row := range data.Rows {
+			if i < len(row) {
+				if w := textwidth.String(fixedCell(fixedValue(row[i]))); w > width {
+					width = w
+				}
+			}
+		}
+		if width == 0 {
+			width = 1
+		}
+		columns[i] = model.ColumnSpec{Name: header, Start: start, End: start + width - 1}
+		indexes[i] = i
+		start += width + 1
+	}
+	return columns, indexes, nil
+}
+
+// buildLine lays cells out at their column positions, padding fields with
+// pad. Gaps between declared columns are filled with spaces, and so are
+// fields that a pad character found in values cannot pad unambiguously
+func (s *FixedWidthSerializer) buildLine(cells []string, columns []model.ColumnSpec, aligns []model.Alignment, pad rune) string {
+	var sb strings.Builder
+	pos := 1
+	for i, column := range columns {
+		if column.Start > pos {
+			sb.WriteString(strings.Repeat(" ", column.Start-pos))
+		}
+		fieldPad := pad
+		if model.PadInValues(pad) && aligns[i] != model.AlignRight {
+			fieldPad = ' '
+		}
+		cell := textwidth.Truncate(fixedCell(cells[i]), column.Width(), "")
+		sb.WriteString(padCellWith(cell, column.Width(), aligns[i], fieldPad))
+		pos = column.End + 1
+	}
+
+	// Declared columns keep the full record length; sized-to-fit output
+	// drops trailing padding that carries no data
+	if len(s.Columns) == 0 && pad == ' ' {
+		return strings.TrimRight(sb.String(), " ")
+	}
+	return sb.String()
+}
+
+// columnIndex returns the index of the named header, or -1 if absent
+func columnIndex(headers []string, name string) int {
+	for i, header := range headers {
+		if header == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// fixedValue returns the text of a value as it was read, so flags such as
+// 1 and Y keep their original form
+func fixedValue(val model.Value) string {
+	if val.Type == model.TypeNull {
+		return ""
+	}
+	return val.Raw
+}
+
+// fixedCell flattens a value onto one line
+func fixedCell(cell string) string {
+	cell = strings.ReplaceAll(cell, "\r\n", " ")
+	return strings.NewReplacer("\n", " ", "\r", " ", "\t", " ").Replace(cell)
+}
@@ -0,0 +1,69 @@
+package serializer
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/user/table-converter/internal/model"
+)
+
+func fixedSampleTable() *model.TableData {
+	return model.NewTableData(
+		[]string{"id", "name", "amount"},
+		[][]model.Value{
+			{model.NewValue("1"), model.NewStringValue("Alice Smith"), model.NewNumberValue(1250)},
+			{model.NewValue("22"), model.NewStringValue("Bob"), model.NewValue("7.5")},
+		},
+	)
+}
+
+func TestFixedWidthSerializer_SizedToFit(t *testing.T) {
+	var buf bytes.Buffer
+	if err := NewFixedWidthSerializer().Serialize(fixedSampleTable(), &buf); err != nil {
+		t.Fatalf("Serialize failed: %v", err)
+	}
+
+	expected := `id name        amount
+1  Alice Smith   1250
+22 Bob            7.5
+`
+	if buf.String() != expected {
+		t.Errorf("unexpected output:\n%s\nwant:\n%s", buf.String(), expected)
+	}
+}
+
+func TestFixedWidthSerializer_DeclaredColumns(t *testing.T) {
+	s := NewFixedWidthSerializerWithColumns([]model.ColumnSpec{
+		{Name: "id", Start: 1, End: 4, Align: model.AlignRight},
+		{Name: "name", Start: 6, End: 13},
+		{Name: "amount", Start: 14, End: 21, Align: model.AlignRight},
+	})
+	if err := s.SetPadChar('0'); err != nil {
+		t.Fatalf("SetPadChar failed: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := s.Serialize(fixedSampleTable(), &buf); err != nil {
+		t.Fatalf("Serialize failed: %v", err)
+	}
+
+	// No header line, names are truncated and records keep their full length.
+	// Zeros only pad the right-aligned fields, names are padded with spaces
+	expected := "0001 Alice Sm00001250\n" +
+		"0022 Bob     000007.5\n"
+	if buf.String() != expected {
+		t.Errorf("unexpected output:\n%q\nwant:\n%q", buf.String(), expected)
+	}
+}
+
+func TestFixedWidthSerializer_Errors(t *testing.T) {
+	s := NewFixedWidthSerializerWithColumns([]model.ColumnSpec{{Name: "missing", Start: 1, End: 5}})
+	var buf bytes.Buffer
+	if err := s.Serialize(fixedSampleTable(), &buf); err == nil {
+		t.Error("expected error for a declared column missing from the table")
+	}
+
+	if err := NewFixedWidthSerializer().SetPadChar('東'); err == nil {
+		t.Error("expected error for a wide padding character")
+	}
+}
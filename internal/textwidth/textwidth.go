@@ -0,0 +1,246 @@
+// Package textwidth measures strings in terminal display columns
+package textwidth
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/width"
+)
+
+// Rune returns the number of terminal columns occupied by r:
+// 2 for East Asian wide and fullwidth characters, 0 for combining marks
+// and other zero-width characters, and 1 otherwise
+func Rune(r rune) int {
+	switch {
+	case r < 0x20 || (r >= 0x7F && r < 0xA0):
+		// Control characters
+		return 0
+	case unicode.In(r, unicode.Mn, unicode.Me, unicode.Cf):
+		// Combining marks, variation selectors, zero-width joiners and the BOM
+		return 0
+	}
+
+	switch width.LookupRune(r).Kind() {
+	case width.EastAsianWide, width.EastAsianFullwidth:
+		return 2
+	}
+	return 1
+}
+
+// String returns the number of terminal columns occupied by s,
+// ignoring ANSI escape sequences
+func String(s string) int {
+	total := 0
+	for i := 0; i < len(s); {
+		if n := escapeLen(s[i:]); n > 0 {
+			i += n
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		total += Rune(r)
+		i += size
+	}
+	return total
+}
+
+// StripANSI removes ANSI escape sequences from s
+func StripANSI(s string) string {
+	if !strings.Contains(s, "\x1b") {
+		return s
+	}
+
+	var sb strings.Builder
+	for i := 0; i < len(s); {
+		if n := escapeLen(s[i:]); n > 0 {
+			i += n
+			continue
+		}
+		sb.WriteByte(s[i])
+		i++
+	}
+	return sb.String()
+}
+
+// Pad right-pads s with spaces so that it occupies w columns
+func Pad(s string, w int) string {
+	if gap := w - String(s); gap > 0 {
+		return s + strings.Repeat(" ", gap)
+	}
+	return s
+}
+
+// Slice returns the part of s whose characters start within display columns
+// [start, end). Zero-width characters stay with the character they follow,
+// and escape sequences are kept if they fall inside the range
+func Slice(s string, start, end int) string {
+	var sb strings.Builder
+	col := 0
+	included := start <= 0 && end > 0
+	for i := 0; i < len(s); {
+		if n := escapeLen(s[i:]); n > 0 {
+			if col >= start && col < end {
+				sb.WriteString(s[i : i+n])
+			}
+			i += n
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		w := Rune(r)
+		if w > 0 {
+			if col >= end {
+				break
+			}
+			included = col >= start
+		}
+		if included {
+			sb.WriteString(s[i : i+size])
+		}
+		col += w
+		i += size
+	}
+	return sb.String()
+}
+
+// Index returns the display column of each occurrence of target in s
+func Index(s string, target rune) []int {
+	var cols []int
+	col := 0
+	for i := 0; i < len(s); {
+		if n := escapeLen(s[i:]); n > 0 {
+			i += n
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if r == target {
+			cols = append(cols, col)
+		}
+		col += Rune(r)
+		i += size
+	}
+	return cols
+}
+
+// escapeLen returns the length of the ANSI escape sequence at the start of s,
+// or 0 if s does not start with one
+func escapeLen(s string) int {
+	if len(s) < 2 || s[0] != '\x1b' {
+		return 0
+	}
+
+	switch s[1] {
+	case '[':
+		// CSI: ESC [ parameters final-byte
+		for i := 2; i < len(s); i++ {
+			if s[i] >= 0x40 && s[i] <= 0x7E {
+				return i + 1
+			}
+		}
+		return len(s)
+	case ']':
+		// OSC: ESC ] ... terminated by BEL or ESC \
+		for i := 2; i < len(s); i++ {
+			if s[i] == '\a' {
+				return i + 1
+			}
+			if s[i] == '\x1b' && i+1 < len(s) && s[i+1] == '\\' {
+				return i + 2
+			}
+		}
+		return len(s)
+	default:
+		return 2
+	}
+}
+
+// Truncate shortens s to at most w columns, ending it with tail when
+// anything was cut off
+func Truncate(s string, w int, tail string) string {
+	if String(s) <= w {
+		return s
+	}
+
+	tailWidth := String(tail)
+	if tailWidth > w {
+		tail, tailWidth = "", 0
+	}
+
+	var sb strings.Builder
+	col := 0
+	for _, r := range StripANSI(s) {
+		rw := Rune(r)
+		if col+rw > w-tailWidth {
+			break
+		}
+		sb.WriteRune(r)
+		col += rw
+	}
+	sb.WriteString(tail)
+	return sb.String()
+}
+
+// Wrap breaks s into lines of at most w columns, splitting at spaces where
+// possible and inside words that are wider than w. Existing line breaks are kept
+func Wrap(s string, w int) []string {
+	if w < 1 {
+		w = 1
+	}
+
+	var lines []string
+	for _, paragraph := range strings.Split(StripANSI(s), "\n") {
+		lines = append(lines, wrapParagraph(paragraph, w)...)
+	}
+	return lines
+}
+
+// wrapParagraph word-wraps a single line of text
+func wrapParagraph(s string, w int) []string {
+	words := strings.Fields(s)
+	if len(words) == 0 {
+		return []string{""}
+	}
+
+	var lines []string
+	var current strings.Builder
+	currentWidth := 0
+
+	for _, word := range words {
+		wordWidth := String(word)
+
+		// Start a new line if the word does not fit after the current text
+		if currentWidth > 0 && currentWidth+1+wordWidth > w {
+			lines = append(lines, current.String())
+			current.Reset()
+			currentWidth = 0
+		}
+
+		// Break words that are wider than a whole line
+		for wordWidth > w-currentWidth && currentWidth == 0 {
+			head := Truncate(word, w, "")
+			if head == "" {
+				// A single character wider than the line still has to go somewhere
+				_, size := utf8.DecodeRuneInString(word)
+				head = word[:size]
+			}
+			lines = append(lines, head)
+			word = word[len(head):]
+			wordWidth = String(word)
+		}
+
+		if wordWidth == 0 {
+			continue
+		}
+		if currentWidth > 0 {
+			current.WriteByte(' ')
+			currentWidth++
+		}
+		current.WriteString(word)
+		currentWidth += wordWidth
+	}
+
+	if currentWidth > 0 {
+		lines = append(lines, current.String())
+	}
+	return lines
+}
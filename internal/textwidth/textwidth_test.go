@@ -0,0 +1,139 @@
+package textwidth
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestString(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  int
+	}{
+		{"ascii", "hello", 5},
+		{"empty", "", 0},
+		{"latin precomposed", "café", 4},
+		{"latin combining", "cafe\u0301", 4},
+		{"japanese", "日本語", 6},
+		{"fullwidth", "ＡＢ", 4},
+		{"hangul", "한국", 4},
+		{"emoji", "😀", 2},
+		{"emoji with variation selector", "\u2764\uFE0F", 1},
+		{"zero width joiner", "a\u200Db", 2},
+		{"ansi color", "\x1b[31mred\x1b[0m", 3},
+		{"ansi hyperlink", "\x1b]8;;http://x\x1b\\link\x1b]8;;\x1b\\", 4},
+		{"mixed", "Zoë 東京 🎉", 11},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := String(tt.input); got != tt.want {
+				t.Errorf("String(%q) = %d, want %d", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPad(t *testing.T) {
+	tests := []struct {
+		input string
+		width int
+		want  string
+	}{
+		{"ab", 4, "ab  "},
+		{"日本", 6, "日本  "},
+		{"\x1b[1mab\x1b[0m", 3, "\x1b[1mab\x1b[0m "},
+		{"toolong", 3, "toolong"},
+	}
+
+	for _, tt := range tests {
+		if got := Pad(tt.input, tt.width); got != tt.want {
+			t.Errorf("Pad(%q, %d) = %q, want %q", tt.input, tt.width, got, tt.want)
+		}
+	}
+}
+
+func TestSlice(t *testing.T) {
+	tests := []struct {
+		input      string
+		start, end int
+		want       string
+	}{
+		{"| ab | cd |", 1, 5, " ab "},
+		{"| 日本 | cd |", 1, 7, " 日本 "},
+		{"| 日本 | cd |", 8, 12, " cd "},
+		{"abc", 5, 8, ""},
+	}
+
+	for _, tt := range tests {
+		if got := Slice(tt.input, tt.start, tt.end); got != tt.want {
+			t.Errorf("Slice(%q, %d, %d) = %q, want %q", tt.input, tt.start, tt.end, got, tt.want)
+		}
+	}
+}
+
+func TestIndex(t *testing.T) {
+	got := Index("| 日本 | é |", '|')
+	want := []int{0, 7, 11}
+	if len(got) != len(want) {
+		t.Fatalf("Index() = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("Index() = %v, want %v", got, want)
+		}
+	}
+}
+
+func TestStripANSI(t *testing.T) {
+	if got := StripANSI("\x1b[32;1mok\x1b[0m done"); got != "ok done" {
+		t.Errorf("StripANSI() = %q, want %q", got, "ok done")
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	tests := []struct {
+		input string
+		width int
+		want  string
+	}{
+		{"hello world", 20, "hello world"},
+		{"hello world", 8, "hello w…"},
+		{"日本語テキスト", 7, "日本語…"},
+		{"abc", 0, ""},
+	}
+
+	for _, tt := range tests {
+		if got := Truncate(tt.input, tt.width, "…"); got != tt.want {
+			t.Errorf("Truncate(%q, %d) = %q, want %q", tt.input, tt.width, got, tt.want)
+		}
+		if got := String(Truncate(tt.input, tt.width, "…")); got > tt.width {
+			t.Errorf("Truncate(%q, %d) has width %d", tt.input, tt.width, got)
+		}
+	}
+}
+
+func TestWrap(t *testing.T) {
+	tests := []struct {
+		input string
+		width int
+		want  []string
+	}{
+		{"the quick brown fox", 10, []string{"the quick", "brown fox"}},
+		{"short", 10, []string{"short"}},
+		{"abcdefghij", 4, []string{"abcd", "efgh", "ij"}},
+		{"a verylongword b", 5, []string{"a", "veryl", "ongwo", "rd b"}},
+		{"日本語のテキスト", 6, []string{"日本語", "のテキ", "スト"}},
+		{"line one\nline two", 20, []string{"line one", "line two"}},
+		{"", 5, []string{""}},
+		{"日本", 1, []string{"日", "本"}},
+	}
+
+	for _, tt := range tests {
+		got := Wrap(tt.input, tt.width)
+		if strings.Join(got, "|") != strings.Join(tt.want, "|") {
+			t.Errorf("Wrap(%q, %d) = %q, want %q", tt.input, tt.width, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,308 @@
+package transform
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/user/table-converter/internal/model"
+)
+
+// ColumnMatcher matches column headers by exact name, by a glob pattern
+// with * and ? wildcards, or by a regular expression written as /regexp/
+type ColumnMatcher struct {
+	name string
+	re   *regexp.Regexp // nil for an exact name
+}
+
+// ParseColumnMatcher parses a column name, glob pattern or /regexp/
+func ParseColumnMatcher(spec string) (ColumnMatcher, error) {
+	if spec == "" {
+		return ColumnMatcher{}, fmt.Errorf("column name must not be empty")
+	}
+	if len(spec) > 2 && strings.HasPrefix(spec, "/") && strings.HasSuffix(spec, "/") {
+		re, err := regexp.Compile(spec[1 : len(spec)-1])
+		if err != nil {
+			return ColumnMatcher{}, fmt.Errorf("invalid column pattern %s: %w", spec, err)
+		}
+		return ColumnMatcher{name: spec, re: re}, nil
+	}
+	if strings.ContainsAny(spec, "*?") {
+		return ColumnMatcher{name: spec, re: globRegexp(spec)}, nil
+	}
+	return ColumnMatcher{name: spec}, nil
+}
+
+// ParseColumnList parses a comma-separated list of column names, glob
+// patterns and /regexps/. A backslash escapes a comma in a name
+func ParseColumnList(spec string) ([]ColumnMatcher, error) {
+	var matchers []ColumnMatcher
+	for _, item := range splitList(spec, ',') {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		if !strings.HasPrefix(item, "/") {
+			item = unescape(item)
+		}
+		m, err := ParseColumnMatcher(item)
+		if err != nil {
+			return nil, err
+		}
+		matchers = append(matchers, m)
+	}
+	if len(matchers) == 0 {
+		return nil, fmt.Errorf("no columns given")
+	}
+	return matchers, nil
+}
+
+// IsPattern reports whether the matcher is a glob or regular expression
+// rather than an exact name
+func (m ColumnMatcher) IsPattern() bool {
+	return m.re != nil
+}
+
+// Match reports whether a header matches
+func (m ColumnMatcher) Match(header string) bool {
+	if m.re != nil {
+		return m.re.MatchString(header)
+	}
+	return header == m.name
+}
+
+// String returns the matcher as it was written
+func (m ColumnMatcher) String() string {
+	return m.name
+}
+
+// indices returns the columns whose headers match, in table order. An
+// exact name that matches no column is an error, as it is most likely a
+// typo; a pattern may match nothing
+func (m ColumnMatcher) indices(headers []string) ([]int, error) {
+	var columns []int
+	for i, header := range headers {
+		if m.Match(header) {
+			columns = append(columns, i)
+		}
+	}
+	if len(columns) == 0 && !m.IsPattern() {
+		return nil, columnNotFound(m.name, headers)
+	}
+	return columns, nil
+}
+
+// columnNotFound creates the error for a column name missing from the headers
+func columnNotFound(name string, headers []string) *TransformError {
+	return NewTransformError(fmt.Sprintf("column %q not found (columns: %s)", name, strings.Join(headers, ", ")))
+}
+
+// Select keeps the matching columns, in the order the matchers are given.
+// Columns matched by a pattern keep their table order, and a column
+// matched twice appears once
+type Select struct {
+	Columns []ColumnMatcher
+}
+
+// NewSelect creates a Select from a comma-separated column list
+func NewSelect(spec string) (*Select, error) {
+	columns, err := ParseColumnList(spec)
+	if err != nil {
+		return nil, err
+	}
+	return &Select{Columns: columns}, nil
+}
+
+// Transform implements Transformer
+func (s *Select) Transform(data *model.TableData) (*model.TableData, error) {
+	var picked []int
+	seen := make(map[int]bool)
+	for _, m := range s.Columns {
+		columns, err := m.indices(data.Headers)
+		if err != nil {
+			return nil, err
+		}
+		for _, col := range columns {
+			if !seen[col] {
+				seen[col] = true
+				picked = append(picked, col)
+			}
+		}
+	}
+	if len(picked) == 0 {
+		return nil, NewTransformError("no columns selected")
+	}
+	return pickColumns(data, picked), nil
+}
+
+// Exclude drops the matching columns
+type Exclude struct {
+	Columns []ColumnMatcher
+}
+
+// NewExclude creates an Exclude from a comma-separated column list
+func NewExclude(spec string) (*Exclude, error) {
+	columns, err := ParseColumnList(spec)
+	if err != nil {
+		return nil, err
+	}
+	return &Exclude{Columns: columns}, nil
+}
+
+// Transform implements Transformer
+func (e *Exclude) Transform(data *model.TableData) (*model.TableData, error) {
+	dropped := make(map[int]bool)
+	for _, m := range e.Columns {
+		columns, err := m.indices(data.Headers)
+		if err != nil {
+			return nil, err
+		}
+		for _, col := range columns {
+			dropped[col] = true
+		}
+	}
+
+	var kept []int
+	for i := range data.Headers {
+		if !dropped[i] {
+			kept = append(kept, i)
+		}
+	}
+	if len(kept) == 0 {
+		return nil, NewTransformError("all columns excluded")
+	}
+	return pickColumns(data, kept), nil
+}
+
+// Rename gives columns new names
+type Rename struct {
+	// Names maps old header names to new ones
+	Names map[string]string
+}
+
+// NewRename creates a Rename from a comma-separated list of old:new pairs.
+// A backslash escapes a comma or colon in a name
+func NewRename(spec string) (*Rename, error) {
+	names := make(map[string]string)
+	for _, pair := range splitList(spec, ',') {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+		parts := splitList(pair, ':')
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("invalid rename %q (expected old:new)", unescape(pair))
+		}
+		from, to := unescape(strings.TrimSpace(parts[0])), unescape(strings.TrimSpace(parts[1]))
+		if from == "" || to == "" {
+			return nil, fmt.Errorf("invalid rename %q: names must not be empty", unescape(pair))
+		}
+		if _, ok := names[from]; ok {
+			return nil, fmt.Errorf("column %q is renamed twice", from)
+		}
+		names[from] = to
+	}
+	if len(names) == 0 {
+		return nil, fmt.Errorf("no columns given")
+	}
+	return &Rename{Names: names}, nil
+}
+
+// Transform implements Transformer
+func (r *Rename) Transform(data *model.TableData) (*model.TableData, error) {
+	found := make(map[string]bool, len(r.Names))
+	headers := make([]string, len(data.Headers))
+	for i, header := range data.Headers {
+		headers[i] = header
+		if to, ok := r.Names[header]; ok {
+			headers[i] = to
+			found[header] = true
+		}
+	}
+	for from := range r.Names {
+		if !found[from] {
+			return nil, columnNotFound(from, data.Headers)
+		}
+	}
+
+	// Two columns must not be renamed to the same name
+	renamedTo := make(map[string]string, len(r.Names))
+	for i, header := range data.Headers {
+		to, ok := r.Names[header]
+		if !ok {
+			continue
+		}
+		if other, taken := renamedTo[to]; taken && other != header {
+			return nil, NewTransformError(fmt.Sprintf("cannot rename both %q and %q to %q", other, header, to))
+		}
+		renamedTo[headers[i]] = header
+	}
+
+	// A new name must not clash with a column that keeps its name
+	for i, header := range headers {
+		if _, renamed := r.Names[data.Headers[i]]; renamed {
+			continue
+		}
+		for j, other := range headers {
+			if _, renamed := r.Names[data.Headers[j]]; renamed && other == header {
+				return nil, NewTransformError(fmt.Sprintf("cannot rename %q to %q: column already exists", data.Headers[j], header))
+			}
+		}
+	}
+
+	data.Headers = headers
+	return data, nil
+}
+
+// splitList splits s at sep, except where sep is escaped with a backslash
+// or inside a /regexp/ list item. Escapes are kept for the caller
+func splitList(s string, sep byte) []string {
+	var items []string
+	start := 0
+	inRegexp := false
+	for i := 0; i < len(s); i++ {
+		switch {
+		case s[i] == '\\':
+			i++
+		case s[i] == '/' && (inRegexp || strings.TrimSpace(s[start:i]) == ""):
+			inRegexp = !inRegexp
+		case s[i] == sep && !inRegexp:
+			items = append(items, s[start:i])
+			start = i + 1
+		}
+	}
+	return append(items, s[start:])
+}
+
+// unescape removes the backslashes escaping list separators
+func unescape(s string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+1 < len(s) {
+			i++
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
+
+// globRegexp converts a glob pattern with * and ? wildcards to an anchored
+// regular expression
+func globRegexp(glob string) *regexp.Regexp {
+	var b strings.Builder
+	b.WriteString("^")
+	for _, r := range glob {
+		switch r {
+		case '*':
+			b.WriteString(".*")
+		case '?':
+			b.WriteString(".")
+		default:
+			b.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	b.WriteString("$")
+	return regexp.MustCompile(b.String())
+}
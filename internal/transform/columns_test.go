@@ -0,0 +1,202 @@
+package transform
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/user/table-converter/internal/model"
+)
+
+// newTable builds a table of string values
+func newTable(headers []string, rows ...[]string) *model.TableData {
+	values := make([][]model.Value, len(rows))
+	for i, row := range rows {
+		values[i] = make([]model.Value, len(row))
+		for j, cell := range row {
+			values[i][j] = model.NewValue(cell)
+		}
+	}
+	return model.NewTableData(headers, values)
+}
+
+// cells returns the raw cell strings of a table
+func cells(data *model.TableData) [][]string {
+	out := make([][]string, len(data.Rows))
+	for i, row := range data.Rows {
+		out[i] = make([]string, len(row))
+		for j, v := range row {
+			out[i][j] = v.Raw
+		}
+	}
+	return out
+}
+
+func TestParseColumnMatcher(t *testing.T) {
+	tests := []struct {
+		spec    string
+		header  string
+		want    bool
+		pattern bool
+	}{
+		{"name", "name", true, false},
+		{"name", "Name", false, false},
+		{"addr_*", "addr_city", true, true},
+		{"addr_*", "email", false, true},
+		{"col?", "col1", true, true},
+		{"col?", "col10", false, true},
+		{"/^(?i)e-?mail$/", "E-Mail", true, true},
+		{"/id$/", "user_id", true, true},
+		{"a.b", "axb", false, false},
+	}
+	for _, tt := range tests {
+		m, err := ParseColumnMatcher(tt.spec)
+		if err != nil {
+			t.Fatalf("ParseColumnMatcher(%q) error = %v", tt.spec, err)
+		}
+		if got := m.Match(tt.header); got != tt.want {
+			t.Errorf("%q.Match(%q) = %v, want %v", tt.spec, tt.header, got, tt.want)
+		}
+		if m.IsPattern() != tt.pattern {
+			t.Errorf("%q.IsPattern() = %v, want %v", tt.spec, m.IsPattern(), tt.pattern)
+		}
+	}
+
+	if _, err := ParseColumnMatcher("/[/"); err == nil {
+		t.Error("expected an error for an invalid regexp")
+	}
+}
+
+func TestParseColumnList(t *testing.T) {
+	matchers, err := ParseColumnList(`id, /a{1,2}/ ,last\, first`)
+	if err != nil {
+		t.Fatalf("ParseColumnList() error = %v", err)
+	}
+	var got []string
+	for _, m := range matchers {
+		got = append(got, m.String())
+	}
+	want := []string{"id", "/a{1,2}/", "last, first"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseColumnList() = %q, want %q", got, want)
+	}
+
+	if _, err := ParseColumnList(" , "); err == nil {
+		t.Error("expected an error for an empty list")
+	}
+}
+
+func TestSelectExclude(t *testing.T) {
+	headers := []string{"id", "name", "email", "addr_city", "addr_zip"}
+	row := []string{"1", "Alice", "a@example.com", "Paris", "75001"}
+
+	tests := []struct {
+		name    string
+		t       func() (Transformer, error)
+		want    []string
+		wantRow []string
+		wantErr string
+	}{
+		{"select reorders", func() (Transformer, error) { return NewSelect("email,id") },
+			[]string{"email", "id"}, []string{"a@example.com", "1"}, ""},
+		{"select glob", func() (Transformer, error) { return NewSelect("name,addr_*") },
+			[]string{"name", "addr_city", "addr_zip"}, []string{"Alice", "Paris", "75001"}, ""},
+		{"select once", func() (Transformer, error) { return NewSelect("addr_zip,addr_*") },
+			[]string{"addr_zip", "addr_city"}, []string{"75001", "Paris"}, ""},
+		{"select missing", func() (Transformer, error) { return NewSelect("id,phone") },
+			nil, nil, `column "phone" not found`},
+		{"select nothing", func() (Transformer, error) { return NewSelect("x*") },
+			nil, nil, "no columns selected"},
+		{"exclude regexp", func() (Transformer, error) { return NewExclude("/^addr_/,email") },
+			[]string{"id", "name"}, []string{"1", "Alice"}, ""},
+		{"exclude all", func() (Transformer, error) { return NewExclude("*") },
+			nil, nil, "all columns excluded"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tr, err := tt.t()
+			if err != nil {
+				t.Fatalf("constructor error = %v", err)
+			}
+			got, err := tr.Transform(newTable(headers, row))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Transform() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Transform() error = %v", err)
+			}
+			if !reflect.DeepEqual(got.Headers, tt.want) {
+				t.Errorf("headers = %q, want %q", got.Headers, tt.want)
+			}
+			if !reflect.DeepEqual(cells(got)[0], tt.wantRow) {
+				t.Errorf("row = %q, want %q", cells(got)[0], tt.wantRow)
+			}
+		})
+	}
+}
+
+func TestSelectKeepsAlignments(t *testing.T) {
+	data := newTable([]string{"a", "b"}, []string{"1", "2"})
+	data.Alignments = []model.Alignment{model.AlignLeft, model.AlignRight}
+	s, _ := NewSelect("b,a")
+	got, err := s.Transform(data)
+	if err != nil {
+		t.Fatalf("Transform() error = %v", err)
+	}
+	if !reflect.DeepEqual(got.Alignments, []model.Alignment{model.AlignRight, model.AlignLeft}) {
+		t.Errorf("alignments = %v", got.Alignments)
+	}
+}
+
+func TestRename(t *testing.T) {
+	r, err := NewRename(`cust_id:id, a\:b:ab`)
+	if err != nil {
+		t.Fatalf("NewRename() error = %v", err)
+	}
+	got, err := r.Transform(newTable([]string{"cust_id", "a:b", "x"}))
+	if err != nil {
+		t.Fatalf("Transform() error = %v", err)
+	}
+	if want := []string{"id", "ab", "x"}; !reflect.DeepEqual(got.Headers, want) {
+		t.Errorf("headers = %q, want %q", got.Headers, want)
+	}
+
+	// Swapping two names is allowed; clashing with a kept column is not
+	r, _ = NewRename("a:b,b:a")
+	if got, err := r.Transform(newTable([]string{"a", "b"})); err != nil || got.Headers[0] != "b" {
+		t.Errorf("swap = %v, %v", got, err)
+	}
+	r, _ = NewRename("a:b")
+	if _, err := r.Transform(newTable([]string{"a", "b"})); err == nil || !strings.Contains(err.Error(), "already exists") {
+		t.Errorf("clash error = %v", err)
+	}
+	r, _ = NewRename("a:x,b:x")
+	if _, err := r.Transform(newTable([]string{"a", "b"})); err == nil || !strings.Contains(err.Error(), `cannot rename both "a" and "b" to "x"`) {
+		t.Errorf("same target error = %v", err)
+	}
+	r, _ = NewRename("c:d")
+	if _, err := r.Transform(newTable([]string{"a", "b"})); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("missing error = %v", err)
+	}
+
+	for _, spec := range []string{"a", "a:b:c", ":b", "a:b,a:c"} {
+		if _, err := NewRename(spec); err == nil {
+			t.Errorf("NewRename(%q) error = nil", spec)
+		}
+	}
+}
+
+func TestPipeline(t *testing.T) {
+	s, _ := NewSelect("b,c")
+	r, _ := NewRename("b:B")
+	got, err := Pipeline{s, r}.Transform(newTable([]string{"a", "b", "c"}, []string{"1", "2", "3"}))
+	if err != nil {
+		t.Fatalf("Transform() error = %v", err)
+	}
+	if !reflect.DeepEqual(got.Headers, []string{"B", "c"}) || !reflect.DeepEqual(cells(got), [][]string{{"2", "3"}}) {
+		t.Errorf("got %q %q", got.Headers, cells(got))
+	}
+}
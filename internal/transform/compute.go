@@ -0,0 +1,99 @@
+package transform
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/user/table-converter/internal/expr"
+	"github.com/user/table-converter/internal/model"
+)
+
+// Compute sets a column to the value of an expression, evaluated for each
+// row. The expression may use any column, including the one it sets
+type Compute struct {
+	Column string
+	Expr   *expr.Expr
+	// Replace overwrites an existing column; otherwise a new column is
+	// added last
+	Replace bool
+}
+
+// NewCompute creates a Compute from an assignment such as
+// total = price * qty. A column name with spaces or an = is written
+// between backticks: `Unit Price` = price / 100
+func NewCompute(assignment string, replace bool) (*Compute, error) {
+	name, src, err := splitAssignment(assignment)
+	if err != nil {
+		return nil, err
+	}
+	e, err := expr.Parse(src)
+	if err != nil {
+		return nil, err
+	}
+	return &Compute{Column: name, Expr: e, Replace: replace}, nil
+}
+
+// splitAssignment splits name = expression at the first =
+func splitAssignment(s string) (string, string, error) {
+	s = strings.TrimSpace(s)
+	var name, rest string
+	if strings.HasPrefix(s, "`") {
+		end := strings.Index(s[1:], "`")
+		if end < 0 {
+			return "", "", fmt.Errorf("unterminated ` in %q", s)
+		}
+		name, rest = s[1:end+1], strings.TrimSpace(s[end+2:])
+		if !strings.HasPrefix(rest, "=") {
+			return "", "", fmt.Errorf("invalid assignment %q (expected column = expression)", s)
+		}
+		rest = rest[1:]
+	} else {
+		var ok bool
+		if name, rest, ok = strings.Cut(s, "="); !ok {
+			return "", "", fmt.Errorf("invalid assignment %q (expected column = expression)", s)
+		}
+		name = strings.TrimSpace(name)
+	}
+	if strings.HasPrefix(rest, "=") {
+		return "", "", fmt.Errorf("invalid assignment %q (use a single = to assign)", s)
+	}
+	if name == "" {
+		return "", "", fmt.Errorf("invalid assignment %q: missing column name", s)
+	}
+	if strings.TrimSpace(rest) == "" {
+		return "", "", fmt.Errorf("invalid assignment %q: missing expression", s)
+	}
+	return name, rest, nil
+}
+
+// Transform implements Transformer
+func (c *Compute) Transform(data *model.TableData) (*model.TableData, error) {
+	col := slices.Index(data.Headers, c.Column)
+	switch {
+	case c.Replace && col < 0:
+		return nil, columnNotFound(c.Column, data.Headers)
+	case !c.Replace && col >= 0:
+		return nil, NewTransformError(fmt.Sprintf("column %q already exists (use -set to replace it)", c.Column))
+	}
+
+	bound, err := c.Expr.Bind(data.Headers)
+	if err != nil {
+		return nil, NewTransformError(err.Error())
+	}
+
+	if !c.Replace {
+		col = len(data.Headers)
+		data.Headers = append(data.Headers, c.Column)
+	}
+	for i, row := range data.Rows {
+		v, err := bound.Eval(row)
+		if err != nil {
+			return nil, NewTransformError(fmt.Sprintf("row %d: %s: %v", i+1, c.Column, err))
+		}
+		row = padRow(row, len(data.Headers))
+		row[col] = v.ToModel()
+		data.Rows[i] = row
+	}
+	return data, nil
+}
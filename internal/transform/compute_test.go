@@ -0,0 +1,111 @@
+package transform
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestNewCompute(t *testing.T) {
+	tests := []struct {
+		assignment string
+		column     string
+		expr       string
+		wantErr    bool
+	}{
+		{assignment: "total = price * qty", column: "total", expr: " price * qty"},
+		{assignment: "flag=a == b", column: "flag", expr: "a == b"},
+		{assignment: "`Unit = Price` = price / 100", column: "Unit = Price", expr: " price / 100"},
+		{assignment: "total", wantErr: true},
+		{assignment: "total == 1", wantErr: true},
+		{assignment: " = 1", wantErr: true},
+		{assignment: "total = ", wantErr: true},
+		{assignment: "total = (1", wantErr: true},
+		{assignment: "`total = 1", wantErr: true},
+	}
+	for _, tt := range tests {
+		c, err := NewCompute(tt.assignment, false)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("NewCompute(%q) error = nil, want error", tt.assignment)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("NewCompute(%q) error = %v", tt.assignment, err)
+		}
+		if c.Column != tt.column || c.Expr.String() != tt.expr {
+			t.Errorf("NewCompute(%q) = %q, %q, want %q, %q", tt.assignment, c.Column, c.Expr.String(), tt.column, tt.expr)
+		}
+	}
+}
+
+func TestCompute(t *testing.T) {
+	tests := []struct {
+		assignment string
+		replace    bool
+		headers    []string
+		rows       [][]string
+	}{
+		{
+			assignment: "total = price * qty",
+			headers:    []string{"email", "price", "qty", "total"},
+			rows:       [][]string{{" Ann@Example.com", "2.5", "4", "10"}, {"BOB@example.com ", "3", "", ""}},
+		},
+		{
+			assignment: "email = lower(trim(email))",
+			replace:    true,
+			headers:    []string{"email", "price", "qty"},
+			rows:       [][]string{{"ann@example.com", "2.5", "4"}, {"bob@example.com", "3", ""}},
+		},
+		{
+			assignment: "size = if(coalesce(qty, 0) > 1, \"bulk\", \"single\")",
+			headers:    []string{"email", "price", "qty", "size"},
+			rows:       [][]string{{" Ann@Example.com", "2.5", "4", "bulk"}, {"BOB@example.com ", "3", "", "single"}},
+		},
+	}
+	for _, tt := range tests {
+		data := newTable([]string{"email", "price", "qty"},
+			[]string{" Ann@Example.com", "2.5", "4"},
+			[]string{"BOB@example.com ", "3", ""},
+		)
+		c, err := NewCompute(tt.assignment, tt.replace)
+		if err != nil {
+			t.Fatalf("NewCompute(%q) error = %v", tt.assignment, err)
+		}
+		got, err := c.Transform(data)
+		if err != nil {
+			t.Fatalf("Compute(%q) error = %v", tt.assignment, err)
+		}
+		if !reflect.DeepEqual(got.Headers, tt.headers) {
+			t.Errorf("Compute(%q) headers = %v, want %v", tt.assignment, got.Headers, tt.headers)
+		}
+		if !reflect.DeepEqual(cells(got), tt.rows) {
+			t.Errorf("Compute(%q) rows = %v, want %v", tt.assignment, cells(got), tt.rows)
+		}
+	}
+}
+
+func TestCompute_Errors(t *testing.T) {
+	tests := []struct {
+		assignment string
+		replace    bool
+		want       string
+	}{
+		{assignment: "price = 1", want: `column "price" already exists`},
+		{assignment: "total = 1", replace: true, want: `"total"`},
+		{assignment: "total = cost * 2", want: `unknown column "cost"`},
+		{assignment: "total = name * 2", want: `row 2: total: cannot use *: column "name" ("n/a") is not a number`},
+	}
+	for _, tt := range tests {
+		data := newTable([]string{"name", "price"}, []string{"7", "1"}, []string{"n/a", "2"})
+		c, err := NewCompute(tt.assignment, tt.replace)
+		if err != nil {
+			t.Fatalf("NewCompute(%q) error = %v", tt.assignment, err)
+		}
+		_, err = c.Transform(data)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("Compute(%q) error = %v, want it to contain %q", tt.assignment, err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,180 @@
+package transform
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/user/table-converter/internal/expr"
+	"github.com/user/table-converter/internal/model"
+)
+
+// Dedupe drops the rows whose key columns repeat those of another row.
+// Values compare as the expression language compares them, so 1 and 1.0
+// are one value
+type Dedupe struct {
+	// Columns are the key columns (nil = the whole row)
+	Columns []string
+	// KeepLast keeps the last row of each group instead of the first
+	KeepLast bool
+	// IgnoreCase compares text without regard to case
+	IgnoreCase bool
+	// Report replaces the table with a report of the duplicate groups
+	// instead of dropping rows: the key columns, the number of rows in the
+	// group and their 1-based row numbers
+	Report bool
+
+	// Removed is the number of rows dropped by the last Transform
+	Removed int
+}
+
+// DuplicateGroup is a set of rows with equal keys
+type DuplicateGroup struct {
+	Key  []model.Value // The key column values of the first row
+	Rows []int         // 0-based indices of the rows, in table order
+}
+
+// Column names of a duplicate report
+const (
+	DuplicateCountColumn = "duplicate_count"
+	DuplicateRowsColumn  = "duplicate_rows"
+)
+
+// NewDedupe creates a Dedupe from a comma-separated list of key columns
+// ("" = the whole row)
+func NewDedupe(columns string) (*Dedupe, error) {
+	d := &Dedupe{}
+	for _, item := range splitList(columns, ',') {
+		if name := unescape(strings.TrimSpace(item)); name != "" {
+			d.Columns = append(d.Columns, name)
+		}
+	}
+	if columns != "" && len(d.Columns) == 0 {
+		return nil, fmt.Errorf("no columns given")
+	}
+	return d, nil
+}
+
+// ParseKeep parses the row to keep of each group of duplicates, first or
+// last, reporting whether it is the last
+func ParseKeep(keep string) (bool, error) {
+	switch strings.ToLower(keep) {
+	case "", "first":
+		return false, nil
+	case "last":
+		return true, nil
+	}
+	return false, fmt.Errorf("expected first or last, got %q", keep)
+}
+
+// Groups returns every group of rows with equal keys, in the order of
+// their first rows. Rows without duplicates form groups of one
+func (d *Dedupe) Groups(data *model.TableData) ([]DuplicateGroup, error) {
+	columns, err := d.keyColumns(data.Headers)
+	if err != nil {
+		return nil, err
+	}
+
+	var groups []DuplicateGroup
+	byKey := make(map[string]int)
+	keys := make([]string, len(columns))
+	for r, row := range data.Rows {
+		for i, col := range columns {
+			v := expr.FromModel(cell(row, col), "")
+			if d.IgnoreCase && v.Kind == expr.KindString {
+				v = expr.String(strings.ToLower(v.Str))
+			}
+			keys[i] = expr.DistinctKey(v)
+		}
+		key := strings.Join(keys, "\x00")
+		g, ok := byKey[key]
+		if !ok {
+			g = len(groups)
+			byKey[key] = g
+			key := make([]model.Value, len(columns))
+			for i, col := range columns {
+				key[i] = cell(row, col)
+			}
+			groups = append(groups, DuplicateGroup{Key: key})
+		}
+		groups[g].Rows = append(groups[g].Rows, r)
+	}
+	return groups, nil
+}
+
+// keyColumns returns the indices of the key columns
+func (d *Dedupe) keyColumns(headers []string) ([]int, error) {
+	if d.Columns == nil {
+		columns := make([]int, len(headers))
+		for i := range columns {
+			columns[i] = i
+		}
+		return columns, nil
+	}
+	columns := make([]int, len(d.Columns))
+	for i, name := range d.Columns {
+		if columns[i] = slices.Index(headers, name); columns[i] < 0 {
+			return nil, columnNotFound(name, headers)
+		}
+	}
+	return columns, nil
+}
+
+// Transform implements Transformer. The kept rows stay in table order
+func (d *Dedupe) Transform(data *model.TableData) (*model.TableData, error) {
+	d.Removed = 0
+	groups, err := d.Groups(data)
+	if err != nil {
+		return nil, err
+	}
+	if d.Report {
+		return d.report(data.Headers, groups)
+	}
+
+	kept := make([]int, len(groups))
+	for i, g := range groups {
+		kept[i] = g.Rows[0]
+		if d.KeepLast {
+			kept[i] = g.Rows[len(g.Rows)-1]
+		}
+	}
+	slices.Sort(kept)
+
+	rows := make([][]model.Value, len(kept))
+	for i, r := range kept {
+		rows[i] = data.Rows[r]
+	}
+	d.Removed = len(data.Rows) - len(rows)
+	data.Rows = rows
+	return data, nil
+}
+
+// report builds the table of duplicate groups
+func (d *Dedupe) report(headers []string, groups []DuplicateGroup) (*model.TableData, error) {
+	keyHeaders := d.Columns
+	if keyHeaders == nil {
+		keyHeaders = headers
+	}
+	for _, name := range []string{DuplicateCountColumn, DuplicateRowsColumn} {
+		if slices.Contains(keyHeaders, name) {
+			return nil, NewTransformError(fmt.Sprintf("the duplicate report needs a column %q, which the table already has", name))
+		}
+	}
+
+	out := &model.TableData{Headers: append(slices.Clone(keyHeaders), DuplicateCountColumn, DuplicateRowsColumn)}
+	for _, g := range groups {
+		if len(g.Rows) < 2 {
+			continue
+		}
+		numbers := make([]string, len(g.Rows))
+		for i, r := range g.Rows {
+			numbers[i] = strconv.Itoa(r + 1)
+		}
+		row := append(slices.Clone(g.Key),
+			model.NewNumberValue(float64(len(g.Rows))),
+			model.NewStringValue(strings.Join(numbers, ",")))
+		out.Rows = append(out.Rows, row)
+	}
+	return out, nil
+}
@@ -0,0 +1,129 @@
+package transform
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/user/table-converter/internal/model"
+)
+
+func TestNewDedupe(t *testing.T) {
+	d, err := NewDedupe("email, name")
+	if err != nil {
+		t.Fatalf("NewDedupe() error = %v", err)
+	}
+	if !reflect.DeepEqual(d.Columns, []string{"email", "name"}) {
+		t.Errorf("NewDedupe() = %+v", d)
+	}
+
+	if d, _ := NewDedupe(""); d.Columns != nil {
+		t.Errorf("NewDedupe(\"\") = %+v, want whole rows", d)
+	}
+	for _, columns := range []string{",", " , "} {
+		if _, err := NewDedupe(columns); err == nil {
+			t.Errorf("NewDedupe(%q) error = nil, want no columns given", columns)
+		}
+	}
+}
+
+func TestParseKeep(t *testing.T) {
+	tests := []struct {
+		keep    string
+		want    bool
+		wantErr bool
+	}{
+		{"", false, false},
+		{"first", false, false},
+		{"LAST", true, false},
+		{"middle", false, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseKeep(tt.keep)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseKeep(%q) = %v, %v; want %v, error %v", tt.keep, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestDedupe(t *testing.T) {
+	newData := func() *model.TableData {
+		return model.NewTableData([]string{"email", "amount"}, [][]model.Value{
+			{model.NewStringValue("ann@example.com"), model.NewStringValue("1")},
+			{model.NewStringValue("bob@example.com"), model.NewStringValue("2")},
+			{model.NewStringValue("ann@example.com"), model.NewNumberValue(1)},
+			{model.NewStringValue("Bob@Example.com"), model.NewStringValue("3")},
+			{model.NewStringValue("ann@example.com"), model.NewStringValue("1.0")},
+			{model.NewNullValue(), model.NewStringValue("4")},
+			{model.NewNullValue(), model.NewStringValue("5")},
+		})
+	}
+
+	tests := []struct {
+		name    string
+		dedupe  Dedupe
+		rows    [][]string
+		removed int
+	}{
+		{
+			name:    "whole row",
+			dedupe:  Dedupe{},
+			rows:    [][]string{{"ann@example.com", "1"}, {"bob@example.com", "2"}, {"Bob@Example.com", "3"}, {"", "4"}, {"", "5"}},
+			removed: 2,
+		},
+		{
+			name:    "key column",
+			dedupe:  Dedupe{Columns: []string{"email"}},
+			rows:    [][]string{{"ann@example.com", "1"}, {"bob@example.com", "2"}, {"Bob@Example.com", "3"}, {"", "4"}},
+			removed: 3,
+		},
+		{
+			name:    "keep last ignoring case",
+			dedupe:  Dedupe{Columns: []string{"email"}, KeepLast: true, IgnoreCase: true},
+			rows:    [][]string{{"Bob@Example.com", "3"}, {"ann@example.com", "1.0"}, {"", "5"}},
+			removed: 4,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := tt.dedupe
+			got, err := d.Transform(newData())
+			if err != nil {
+				t.Fatalf("Transform() error = %v", err)
+			}
+			if !reflect.DeepEqual(cells(got), tt.rows) {
+				t.Errorf("Transform() rows = %v, want %v", cells(got), tt.rows)
+			}
+			if d.Removed != tt.removed {
+				t.Errorf("Removed = %d, want %d", d.Removed, tt.removed)
+			}
+		})
+	}
+
+	d := &Dedupe{Columns: []string{"email"}, IgnoreCase: true, Report: true}
+	got, err := d.Transform(newData())
+	if err != nil {
+		t.Fatalf("Transform() report error = %v", err)
+	}
+	wantHeaders := []string{"email", DuplicateCountColumn, DuplicateRowsColumn}
+	if !reflect.DeepEqual(got.Headers, wantHeaders) {
+		t.Errorf("report headers = %v, want %v", got.Headers, wantHeaders)
+	}
+	wantRows := [][]string{{"ann@example.com", "3", "1,3,5"}, {"bob@example.com", "2", "2,4"}, {"", "2", "6,7"}}
+	if !reflect.DeepEqual(cells(got), wantRows) {
+		t.Errorf("report rows = %v, want %v", cells(got), wantRows)
+	}
+}
+
+func TestDedupe_Errors(t *testing.T) {
+	data := newTable([]string{"email", "duplicate_count"}, []string{"a", "1"})
+
+	d := &Dedupe{Columns: []string{"mail"}}
+	if _, err := d.Transform(data); err == nil || !strings.Contains(err.Error(), `"mail"`) {
+		t.Errorf("Transform() error = %v, want unknown column", err)
+	}
+	d = &Dedupe{Report: true}
+	if _, err := d.Transform(data); err == nil || !strings.Contains(err.Error(), DuplicateCountColumn) {
+		t.Errorf("Transform() error = %v, want a column name clash", err)
+	}
+}
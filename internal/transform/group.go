@@ -0,0 +1,193 @@
+package transform
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/user/table-converter/internal/expr"
+	"github.com/user/table-converter/internal/model"
+)
+
+// Aggregation is one summary column of a GroupBy, such as sum(amount)
+type Aggregation struct {
+	Func   string // count, count_distinct, sum, avg, min or max
+	Column string // "" for count(*), which counts rows
+	Name   string // Name of the output column
+}
+
+// String returns the aggregation as it is written, such as sum(amount)
+func (a Aggregation) String() string {
+	column := a.Column
+	if column == "" {
+		column = "*"
+	}
+	return a.Func + "(" + column + ")"
+}
+
+// aggregationSpec matches one item of an aggregation list:
+// func(column) with an optional "as name"
+var aggregationSpec = regexp.MustCompile(`(?is)^([a-z_]+)\s*\(\s*(.*?)\s*\)(?:\s+as\s+(.+))?$`)
+
+// ParseAggregations parses a comma-separated list of aggregations such as
+// count(*),sum(amount) as total. Without "as", the output column is named
+// after the function and column: count for count(*), sum_amount for
+// sum(amount)
+func ParseAggregations(spec string) ([]Aggregation, error) {
+	var aggs []Aggregation
+	for _, item := range splitList(spec, ',') {
+		item = strings.TrimSpace(unescape(item))
+		if item == "" {
+			continue
+		}
+		m := aggregationSpec.FindStringSubmatch(item)
+		if m == nil {
+			return nil, fmt.Errorf("invalid aggregation %q (expected function(column), such as sum(amount))", item)
+		}
+		a := Aggregation{Func: strings.ToLower(m[1]), Column: strings.Trim(m[2], "`"), Name: strings.TrimSpace(m[3])}
+		if !expr.IsAggregate(a.Func) {
+			return nil, fmt.Errorf("unknown aggregate function %q (expected count, count_distinct, sum, avg, min or max)", m[1])
+		}
+		switch {
+		case a.Column == "*" && a.Func != "count":
+			return nil, fmt.Errorf("%s(*) is not supported: only count counts rows", a.Func)
+		case a.Column == "*":
+			a.Column = ""
+		case a.Column == "":
+			return nil, fmt.Errorf("invalid aggregation %q: missing column", item)
+		}
+		if a.Name == "" {
+			a.Name = a.Func
+			if a.Column != "" {
+				a.Name += "_" + a.Column
+			}
+		}
+		aggs = append(aggs, a)
+	}
+	if len(aggs) == 0 {
+		return nil, fmt.Errorf("no aggregations given")
+	}
+	return aggs, nil
+}
+
+// GroupBy summarizes the rows that share the values of the group columns.
+// The result has one row per group, holding the group columns followed by
+// one column per aggregation. Without group columns the whole table is one
+// group
+type GroupBy struct {
+	Columns      []string
+	Aggregations []Aggregation
+}
+
+// NewGroupBy creates a GroupBy from a comma-separated list of group columns
+// and a list of aggregations as ParseAggregations takes it
+func NewGroupBy(columns, aggregations string) (*GroupBy, error) {
+	g := &GroupBy{}
+	for _, item := range splitList(columns, ',') {
+		if name := unescape(strings.TrimSpace(item)); name != "" {
+			g.Columns = append(g.Columns, name)
+		}
+	}
+	var err error
+	if g.Aggregations, err = ParseAggregations(aggregations); err != nil {
+		return nil, err
+	}
+	return g, nil
+}
+
+// Transform implements Transformer. Groups appear in the order their first
+// row is seen, and empty cells form a group of their own. Aggregates skip
+// empty cells; sum and avg fail on a value that is not a number
+func (g *GroupBy) Transform(data *model.TableData) (*model.TableData, error) {
+	headers := slices.Clone(g.Columns)
+	for _, a := range g.Aggregations {
+		if slices.Contains(headers, a.Name) {
+			return nil, NewTransformError(fmt.Sprintf("output column %q is given twice (name aggregations with \"as\")", a.Name))
+		}
+		headers = append(headers, a.Name)
+	}
+
+	groupCols := make([]int, len(g.Columns))
+	for i, name := range g.Columns {
+		if groupCols[i] = slices.Index(data.Headers, name); groupCols[i] < 0 {
+			return nil, columnNotFound(name, data.Headers)
+		}
+	}
+	aggCols := make([]int, len(g.Aggregations))
+	for i, a := range g.Aggregations {
+		aggCols[i] = -1
+		if a.Column == "" {
+			continue
+		}
+		if aggCols[i] = slices.Index(data.Headers, a.Column); aggCols[i] < 0 {
+			return nil, columnNotFound(a.Column, data.Headers)
+		}
+	}
+
+	type group struct {
+		values []model.Value
+		aggs   []expr.Aggregator
+	}
+	newGroup := func(values []model.Value) (*group, error) {
+		gr := &group{values: values, aggs: make([]expr.Aggregator, len(g.Aggregations))}
+		for i, a := range g.Aggregations {
+			var err error
+			if gr.aggs[i], err = expr.NewAggregator(a.Func); err != nil {
+				return nil, NewTransformError(err.Error())
+			}
+		}
+		return gr, nil
+	}
+
+	var groups []*group
+	byKey := make(map[string]*group)
+	if len(groupCols) == 0 {
+		// The whole table is one group, even when it has no rows
+		gr, err := newGroup(nil)
+		if err != nil {
+			return nil, err
+		}
+		groups = append(groups, gr)
+		byKey[""] = gr
+	}
+
+	for r, row := range data.Rows {
+		keys := make([]string, len(groupCols))
+		values := make([]model.Value, len(groupCols))
+		for i, col := range groupCols {
+			values[i] = cell(row, col)
+			keys[i] = expr.DistinctKey(expr.FromModel(values[i], ""))
+		}
+		key := strings.Join(keys, "\x00")
+		gr, ok := byKey[key]
+		if !ok {
+			var err error
+			if gr, err = newGroup(values); err != nil {
+				return nil, err
+			}
+			byKey[key] = gr
+			groups = append(groups, gr)
+		}
+
+		for i, a := range g.Aggregations {
+			v := expr.Bool(true) // count(*) counts rows
+			if aggCols[i] >= 0 {
+				v = expr.FromModel(cell(row, aggCols[i]), a.Column)
+			}
+			if err := gr.aggs[i].Add(v); err != nil {
+				return nil, NewTransformError(fmt.Sprintf("row %d: %s: %v", r+1, a, err))
+			}
+		}
+	}
+
+	rows := make([][]model.Value, len(groups))
+	for i, gr := range groups {
+		row := slices.Clone(gr.values)
+		for _, agg := range gr.aggs {
+			row = append(row, agg.Result().ToModel())
+		}
+		rows[i] = row
+	}
+	return model.NewTableData(headers, rows), nil
+}
@@ -0,0 +1,152 @@
+package transform
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseAggregations(t *testing.T) {
+	tests := []struct {
+		spec    string
+		want    []Aggregation
+		wantErr bool
+	}{
+		{spec: "count(*)", want: []Aggregation{{Func: "count", Name: "count"}}},
+		{spec: "SUM(amount), avg( amount ) as mean", want: []Aggregation{
+			{Func: "sum", Column: "amount", Name: "sum_amount"},
+			{Func: "avg", Column: "amount", Name: "mean"},
+		}},
+		{spec: "max(`Order Date`)", want: []Aggregation{{Func: "max", Column: "Order Date", Name: "max_Order Date"}}},
+		{spec: "count_distinct(customer) AS customers", want: []Aggregation{{Func: "count_distinct", Column: "customer", Name: "customers"}}},
+		{spec: "", wantErr: true},
+		{spec: "amount", wantErr: true},
+		{spec: "median(amount)", wantErr: true},
+		{spec: "sum(*)", wantErr: true},
+		{spec: "sum()", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := ParseAggregations(tt.spec)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("ParseAggregations(%q) error = nil, want error", tt.spec)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("ParseAggregations(%q) error = %v", tt.spec, err)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParseAggregations(%q) = %+v, want %+v", tt.spec, got, tt.want)
+		}
+	}
+}
+
+func TestGroupBy(t *testing.T) {
+	sales := newTable([]string{"region", "customer", "amount", "date"},
+		[]string{"north", "ann", "100", "2024-03-01"},
+		[]string{"south", "bob", "40", "2024-01-15"},
+		[]string{"north", "cid", "", "2024-02-10"},
+		[]string{"north", "ann", "20.5", "2024-04-02"},
+		[]string{"", "dee", "7", "2024-05-05"},
+	)
+
+	tests := []struct {
+		columns string
+		aggs    string
+		headers []string
+		rows    [][]string
+	}{
+		{
+			columns: "region",
+			aggs:    "count(*),sum(amount),avg(amount),min(date),max(date),count_distinct(customer)",
+			headers: []string{"region", "count", "sum_amount", "avg_amount", "min_date", "max_date", "count_distinct_customer"},
+			rows: [][]string{
+				{"north", "3", "120.5", "60.25", "2024-02-10", "2024-04-02", "2"},
+				{"south", "1", "40", "40", "2024-01-15", "2024-01-15", "1"},
+				{"", "1", "7", "7", "2024-05-05", "2024-05-05", "1"},
+			},
+		},
+		{
+			columns: "",
+			aggs:    "count(amount) as n,max(amount)",
+			headers: []string{"n", "max_amount"},
+			rows:    [][]string{{"4", "100"}},
+		},
+		{
+			columns: "region,customer",
+			aggs:    "sum(amount)",
+			headers: []string{"region", "customer", "sum_amount"},
+			rows: [][]string{
+				{"north", "ann", "120.5"},
+				{"south", "bob", "40"},
+				{"north", "cid", ""},
+				{"", "dee", "7"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		g, err := NewGroupBy(tt.columns, tt.aggs)
+		if err != nil {
+			t.Fatalf("NewGroupBy(%q, %q) error = %v", tt.columns, tt.aggs, err)
+		}
+		got, err := g.Transform(sales)
+		if err != nil {
+			t.Fatalf("GroupBy(%q, %q) error = %v", tt.columns, tt.aggs, err)
+		}
+		if !reflect.DeepEqual(got.Headers, tt.headers) {
+			t.Errorf("GroupBy(%q, %q) headers = %v, want %v", tt.columns, tt.aggs, got.Headers, tt.headers)
+		}
+		if !reflect.DeepEqual(cells(got), tt.rows) {
+			t.Errorf("GroupBy(%q, %q) rows = %v, want %v", tt.columns, tt.aggs, cells(got), tt.rows)
+		}
+	}
+}
+
+func TestGroupBy_EmptyTable(t *testing.T) {
+	empty := newTable([]string{"region", "amount"})
+
+	g, _ := NewGroupBy("", "count(*),sum(amount)")
+	got, err := g.Transform(empty)
+	if err != nil {
+		t.Fatalf("GroupBy() error = %v", err)
+	}
+	if want := [][]string{{"0", ""}}; !reflect.DeepEqual(cells(got), want) {
+		t.Errorf("GroupBy() rows = %v, want %v", cells(got), want)
+	}
+
+	g, _ = NewGroupBy("region", "count(*)")
+	if got, err = g.Transform(empty); err != nil {
+		t.Fatalf("GroupBy() error = %v", err)
+	}
+	if len(got.Rows) != 0 {
+		t.Errorf("GroupBy() rows = %v, want none", cells(got))
+	}
+}
+
+func TestGroupBy_Errors(t *testing.T) {
+	data := newTable([]string{"region", "amount"},
+		[]string{"north", "10"},
+		[]string{"north", "n/a"},
+	)
+
+	tests := []struct {
+		columns, aggs string
+		want          string
+	}{
+		{columns: "region", aggs: "sum(amount)", want: `row 2: sum(amount): column "amount" ("n/a") is not a number`},
+		{columns: "area", aggs: "count(*)", want: `"area"`},
+		{columns: "region", aggs: "max(price)", want: `"price"`},
+		{columns: "region", aggs: "count(*) as region", want: "given twice"},
+	}
+	for _, tt := range tests {
+		g, err := NewGroupBy(tt.columns, tt.aggs)
+		if err != nil {
+			t.Fatalf("NewGroupBy(%q, %q) error = %v", tt.columns, tt.aggs, err)
+		}
+		_, err = g.Transform(data)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("GroupBy(%q, %q) error = %v, want it to contain %q", tt.columns, tt.aggs, err, tt.want)
+		}
+	}
+}
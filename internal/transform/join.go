@@ -0,0 +1,253 @@
+package transform
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/user/table-converter/internal/expr"
+	"github.com/user/table-converter/internal/model"
+)
+
+// JoinKind selects which rows a join keeps
+type JoinKind int
+
+const (
+	JoinInner JoinKind = iota // Rows with a match on both sides
+	JoinLeft                  // Every left row, with nulls where the right side has no match
+	JoinOuter                 // Every row of both sides, with nulls where the other side has no match
+	JoinAnti                  // Left rows without a match, with only the left columns
+)
+
+// ParseJoinKind parses a join kind name: inner, left, outer (or full) or anti
+func ParseJoinKind(name string) (JoinKind, error) {
+	switch strings.ToLower(name) {
+	case "inner":
+		return JoinInner, nil
+	case "left":
+		return JoinLeft, nil
+	case "outer", "full":
+		return JoinOuter, nil
+	case "anti":
+		return JoinAnti, nil
+	}
+	return 0, fmt.Errorf("invalid join type %q (expected inner, left, outer or anti)", name)
+}
+
+// JoinKey pairs a key column of the left table with one of the right
+type JoinKey struct {
+	Left, Right string
+}
+
+// DefaultJoinSuffixes are added to columns that both tables have
+var DefaultJoinSuffixes = [2]string{"_left", "_right"}
+
+// Join combines the rows of two tables whose key columns are equal. Keys
+// are compared by value, so 42 in a spreadsheet matches "42" or "42.0" in
+// CSV, and a row with an empty key matches nothing
+type Join struct {
+	Keys []JoinKey
+	Kind JoinKind
+	// Suffixes are added to the left and right names of a column that
+	// both tables have
+	Suffixes [2]string
+}
+
+// NewJoin creates a Join from a comma-separated list of key columns. A
+// column named differently in the right table is given as left=right, as
+// in "customer_id=id,region"
+func NewJoin(on string, kind JoinKind) (*Join, error) {
+	var keys []JoinKey
+	for _, item := range splitList(on, ',') {
+		if strings.TrimSpace(item) == "" {
+			continue
+		}
+		parts := splitList(item, '=')
+		if len(parts) > 2 {
+			return nil, fmt.Errorf("invalid join key %q (expected column or left=right)", unescape(item))
+		}
+		key := JoinKey{Left: unescape(strings.TrimSpace(parts[0]))}
+		key.Right = key.Left
+		if len(parts) == 2 {
+			key.Right = unescape(strings.TrimSpace(parts[1]))
+		}
+		if key.Left == "" || key.Right == "" {
+			return nil, fmt.Errorf("invalid join key %q: names must not be empty", unescape(item))
+		}
+		keys = append(keys, key)
+	}
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("no key columns given")
+	}
+	return &Join{Keys: keys, Kind: kind, Suffixes: DefaultJoinSuffixes}, nil
+}
+
+// Join joins two tables. The result has the left columns followed by the
+// right ones; a key column with the same name on both sides appears once.
+// Rows keep the left table's order, each followed by its matches in the
+// right table's order; an outer join adds the unmatched right rows last
+func (j *Join) Join(left, right *model.TableData) (*model.TableData, error) {
+	leftKeys, err := keyColumns(j.Keys, left.Headers, "left", func(k JoinKey) string { return k.Left })
+	if err != nil {
+		return nil, err
+	}
+	rightKeys, err := keyColumns(j.Keys, right.Headers, "right", func(k JoinKey) string { return k.Right })
+	if err != nil {
+		return nil, err
+	}
+
+	// Index the right rows by key; the left rows probe the index
+	index := make(map[string][]int)
+	for r, row := range right.Rows {
+		if key, ok := joinKey(row, rightKeys); ok {
+			index[key] = append(index[key], r)
+		}
+	}
+
+	if j.Kind == JoinAnti {
+		out := &model.TableData{Headers: append([]string(nil), left.Headers...)}
+		for _, row := range left.Rows {
+			if key, ok := joinKey(row, leftKeys); !ok || len(index[key]) == 0 {
+				out.Rows = append(out.Rows, padRow(row, len(left.Headers)))
+			}
+		}
+		return out, nil
+	}
+
+	// Right key columns named like their left key are merged into it
+	merged := make(map[int]int) // right column -> left column
+	for i, k := range j.Keys {
+		if k.Left == k.Right {
+			merged[rightKeys[i]] = leftKeys[i]
+		}
+	}
+	var rightColumns []int
+	for c := range right.Headers {
+		if _, ok := merged[c]; !ok {
+			rightColumns = append(rightColumns, c)
+		}
+	}
+
+	headers, err := j.joinHeaders(left.Headers, right.Headers, rightColumns)
+	if err != nil {
+		return nil, err
+	}
+	out := &model.TableData{Headers: headers}
+	combine := func(lrow, rrow []model.Value) []model.Value {
+		row := make([]model.Value, 0, len(headers))
+		for c := range left.Headers {
+			row = append(row, cell(lrow, c))
+		}
+		for _, c := range rightColumns {
+			row = append(row, cell(rrow, c))
+		}
+		if lrow == nil {
+			for rc, lc := range merged {
+				row[lc] = cell(rrow, rc)
+			}
+		}
+		return row
+	}
+
+	matched := make([]bool, len(right.Rows))
+	for _, lrow := range left.Rows {
+		var matches []int
+		if key, ok := joinKey(lrow, leftKeys); ok {
+			matches = index[key]
+		}
+		for _, r := range matches {
+			matched[r] = true
+			out.Rows = append(out.Rows, combine(lrow, right.Rows[r]))
+		}
+		if len(matches) == 0 && j.Kind != JoinInner {
+			out.Rows = append(out.Rows, combine(lrow, nil))
+		}
+	}
+	if j.Kind == JoinOuter {
+		for r, ok := range matched {
+			if !ok {
+				out.Rows = append(out.Rows, combine(nil, right.Rows[r]))
+			}
+		}
+	}
+	return out, nil
+}
+
+// joinHeaders names the columns of a join, adding the suffixes to names
+// that both sides have
+func (j *Join) joinHeaders(left, right []string, rightColumns []int) ([]string, error) {
+	inLeft := make(map[string]bool, len(left))
+	for _, h := range left {
+		inLeft[h] = true
+	}
+	inRight := make(map[string]bool, len(rightColumns))
+	for _, c := range rightColumns {
+		inRight[right[c]] = true
+	}
+
+	headers := make([]string, 0, len(left)+len(rightColumns))
+	for _, h := range left {
+		if inRight[h] {
+			h += j.Suffixes[0]
+		}
+		headers = append(headers, h)
+	}
+	for _, c := range rightColumns {
+		h := right[c]
+		if inLeft[h] {
+			h += j.Suffixes[1]
+		}
+		headers = append(headers, h)
+	}
+
+	seen := make(map[string]bool, len(headers))
+	for _, h := range headers {
+		if seen[h] {
+			return nil, NewTransformError(fmt.Sprintf("column %q appears twice in the joined table (use other suffixes)", h))
+		}
+		seen[h] = true
+	}
+	return headers, nil
+}
+
+// keyColumns finds the key columns of one side of a join
+func keyColumns(keys []JoinKey, headers []string, side string, name func(JoinKey) string) ([]int, error) {
+	columns := make([]int, len(keys))
+	for i, k := range keys {
+		columns[i] = -1
+		for c, h := range headers {
+			if h == name(k) {
+				columns[i] = c
+				break
+			}
+		}
+		if columns[i] < 0 {
+			err := columnNotFound(name(k), headers)
+			err.Message = side + " table: " + err.Message
+			return nil, err
+		}
+	}
+	return columns, nil
+}
+
+// joinKey returns the key of a row's key columns. Rows with an empty key
+// column have no key
+func joinKey(row []model.Value, columns []int) (string, bool) {
+	parts := make([]string, len(columns))
+	for i, c := range columns {
+		v := expr.FromModel(cell(row, c), "")
+		if v.Kind == expr.KindNull {
+			return "", false
+		}
+		parts[i] = expr.DistinctKey(v)
+	}
+	return strings.Join(parts, "\x00"), true
+}
+
+// padRow pads or truncates a row to n cells
+func padRow(row []model.Value, n int) []model.Value {
+	out := make([]model.Value, n)
+	for i := range out {
+		out[i] = cell(row, i)
+	}
+	return out
+}
@@ -0,0 +1,148 @@
+package transform
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/user/table-converter/internal/model"
+)
+
+func TestNewJoin(t *testing.T) {
+	tests := []struct {
+		on      string
+		want    []JoinKey
+		wantErr bool
+	}{
+		{on: "id", want: []JoinKey{{"id", "id"}}},
+		{on: "customer_id=id, region", want: []JoinKey{{"customer_id", "id"}, {"region", "region"}}},
+		{on: `a\=b=c`, want: []JoinKey{{"a=b", "c"}}},
+		{on: "", wantErr: true},
+		{on: "a=b=c", wantErr: true},
+		{on: "a=", wantErr: true},
+	}
+	for _, tt := range tests {
+		j, err := NewJoin(tt.on, JoinInner)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("NewJoin(%q) error = nil, want error", tt.on)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("NewJoin(%q) error = %v", tt.on, err)
+		}
+		if !reflect.DeepEqual(j.Keys, tt.want) {
+			t.Errorf("NewJoin(%q) keys = %v, want %v", tt.on, j.Keys, tt.want)
+		}
+	}
+}
+
+func TestJoin(t *testing.T) {
+	orders := newTable([]string{"order", "customer_id", "name"},
+		[]string{"o1", "1", "tea"},
+		[]string{"o2", "2", "cake"},
+		[]string{"o3", "9", "jam"},
+		[]string{"o4", "1", "milk"},
+		[]string{"o5", "", "bread"},
+	)
+	customers := model.NewTableData([]string{"customer_id", "name"}, [][]model.Value{
+		{model.NewNumberValue(1), model.NewStringValue("Alice")},
+		{model.NewNumberValue(2), model.NewStringValue("Bob")},
+		{model.NewNumberValue(3), model.NewStringValue("Carol")},
+	})
+
+	tests := []struct {
+		kind    JoinKind
+		headers []string
+		rows    [][]string
+	}{
+		{
+			kind:    JoinInner,
+			headers: []string{"order", "customer_id", "name_left", "name_right"},
+			rows:    [][]string{{"o1", "1", "tea", "Alice"}, {"o2", "2", "cake", "Bob"}, {"o4", "1", "milk", "Alice"}},
+		},
+		{
+			kind:    JoinLeft,
+			headers: []string{"order", "customer_id", "name_left", "name_right"},
+			rows: [][]string{
+				{"o1", "1", "tea", "Alice"}, {"o2", "2", "cake", "Bob"}, {"o3", "9", "jam", ""},
+				{"o4", "1", "milk", "Alice"}, {"o5", "", "bread", ""},
+			},
+		},
+		{
+			kind:    JoinOuter,
+			headers: []string{"order", "customer_id", "name_left", "name_right"},
+			rows: [][]string{
+				{"o1", "1", "tea", "Alice"}, {"o2", "2", "cake", "Bob"}, {"o3", "9", "jam", ""},
+				{"o4", "1", "milk", "Alice"}, {"o5", "", "bread", ""}, {"", "3", "", "Carol"},
+			},
+		},
+		{
+			kind:    JoinAnti,
+			headers: []string{"order", "customer_id", "name"},
+			rows:    [][]string{{"o3", "9", "jam"}, {"o5", "", "bread"}},
+		},
+	}
+
+	for _, tt := range tests {
+		j, err := NewJoin("customer_id", tt.kind)
+		if err != nil {
+			t.Fatalf("NewJoin() error = %v", err)
+		}
+		got, err := j.Join(orders, customers)
+		if err != nil {
+			t.Fatalf("Join(%v) error = %v", tt.kind, err)
+		}
+		if !reflect.DeepEqual(got.Headers, tt.headers) {
+			t.Errorf("Join(%v) headers = %q, want %q", tt.kind, got.Headers, tt.headers)
+		}
+		if !reflect.DeepEqual(cells(got), tt.rows) {
+			t.Errorf("Join(%v) rows = %q, want %q", tt.kind, cells(got), tt.rows)
+		}
+	}
+}
+
+func TestJoinCompositeKeys(t *testing.T) {
+	left := newTable([]string{"region", "year", "sales"},
+		[]string{"north", "2023", "10"},
+		[]string{"north", "2024", "20"},
+	)
+	right := newTable([]string{"area", "yr", "target"},
+		[]string{"north", "2024.0", "25"},
+		[]string{"south", "2024", "30"},
+	)
+	j, err := NewJoin("region=area,year=yr", JoinLeft)
+	if err != nil {
+		t.Fatalf("NewJoin() error = %v", err)
+	}
+	got, err := j.Join(left, right)
+	if err != nil {
+		t.Fatalf("Join() error = %v", err)
+	}
+	if want := []string{"region", "year", "sales", "area", "yr", "target"}; !reflect.DeepEqual(got.Headers, want) {
+		t.Errorf("headers = %q, want %q", got.Headers, want)
+	}
+	want := [][]string{{"north", "2023", "10", "", "", ""}, {"north", "2024", "20", "north", "2024.0", "25"}}
+	if !reflect.DeepEqual(cells(got), want) {
+		t.Errorf("rows = %q, want %q", cells(got), want)
+	}
+}
+
+func TestJoinErrors(t *testing.T) {
+	left := newTable([]string{"id", "x", "x_left"}, []string{"1", "a", "b"})
+	right := newTable([]string{"id", "x"}, []string{"1", "c"})
+
+	j, _ := NewJoin("key", JoinInner)
+	if _, err := j.Join(left, right); err == nil || !strings.Contains(err.Error(), `left table: column "key" not found`) {
+		t.Errorf("Join() error = %v, want a missing left key", err)
+	}
+	j, _ = NewJoin("id=key", JoinInner)
+	if _, err := j.Join(left, right); err == nil || !strings.Contains(err.Error(), `right table: column "key" not found`) {
+		t.Errorf("Join() error = %v, want a missing right key", err)
+	}
+	j, _ = NewJoin("id", JoinInner)
+	if _, err := j.Join(left, right); err == nil || !strings.Contains(err.Error(), `column "x_left" appears twice`) {
+		t.Errorf("Join() error = %v, want a suffix clash", err)
+	}
+}
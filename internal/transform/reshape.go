@@ -0,0 +1,345 @@
+package transform
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/user/table-converter/internal/expr"
+	"github.com/user/table-converter/internal/model"
+)
+
+// Pivot turns the distinct values of one column into columns, making a
+// long table wide. Each output row is one combination of the index
+// columns, and each cell holds the value for its row and column
+type Pivot struct {
+	Index   []string
+	Columns string
+	Values  string // "" only with Agg "count"
+	// Agg combines the values that fall into one cell, such as sum; without
+	// it a cell with more than one value is an error
+	Agg string
+}
+
+// NewPivot creates a Pivot from a spec such as
+// index=region,columns=month,values=amount,agg=sum. index may list several
+// columns: index=region,country,columns=month
+func NewPivot(spec string) (*Pivot, error) {
+	opts, err := parseOptions(spec, "index", "columns", "values", "agg")
+	if err != nil {
+		return nil, err
+	}
+	p := &Pivot{Index: opts["index"]}
+	if len(p.Index) == 0 {
+		return nil, fmt.Errorf("index= is required")
+	}
+	if p.Columns, err = singleOption(opts, "columns", true); err != nil {
+		return nil, err
+	}
+	if p.Agg, err = singleOption(opts, "agg", false); err != nil {
+		return nil, err
+	}
+	p.Agg = strings.ToLower(p.Agg)
+	if p.Agg != "" && !expr.IsAggregate(p.Agg) {
+		return nil, fmt.Errorf("unknown agg %q (expected count, count_distinct, sum, avg, min or max)", p.Agg)
+	}
+	if p.Values, err = singleOption(opts, "values", p.Agg != "count"); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// Transform implements Transformer. Rows and new columns appear in the
+// order their values are first seen. Rows whose pivot column is empty are
+// skipped
+func (p *Pivot) Transform(data *model.TableData) (*model.TableData, error) {
+	index := make([]int, len(p.Index))
+	for i, name := range p.Index {
+		if index[i] = slices.Index(data.Headers, name); index[i] < 0 {
+			return nil, columnNotFound(name, data.Headers)
+		}
+	}
+	pivotCol := slices.Index(data.Headers, p.Columns)
+	if pivotCol < 0 {
+		return nil, columnNotFound(p.Columns, data.Headers)
+	}
+	valueCol := -1
+	if p.Values != "" {
+		if valueCol = slices.Index(data.Headers, p.Values); valueCol < 0 {
+			return nil, columnNotFound(p.Values, data.Headers)
+		}
+	}
+
+	// A cell collects the values of one output row and column
+	type pivotCell struct {
+		agg   expr.Aggregator
+		value model.Value
+		set   bool
+	}
+	type pivotRow struct {
+		index []model.Value
+		cells map[string]*pivotCell
+	}
+	var rows []*pivotRow
+	rowsByKey := make(map[string]*pivotRow)
+	var columns []string
+	seenColumn := make(map[string]bool)
+
+	for r, row := range data.Rows {
+		pivot := cell(row, pivotCol)
+		if pivot.Type == model.TypeNull {
+			continue
+		}
+		name := pivot.Raw
+		if !seenColumn[name] {
+			if slices.Contains(p.Index, name) {
+				return nil, NewTransformError(fmt.Sprintf("pivoted column %q has the name of an index column", name))
+			}
+			seenColumn[name] = true
+			columns = append(columns, name)
+		}
+
+		keys := make([]string, len(index))
+		values := make([]model.Value, len(index))
+		for i, col := range index {
+			values[i] = cell(row, col)
+			keys[i] = expr.DistinctKey(expr.FromModel(values[i], ""))
+		}
+		key := strings.Join(keys, "\x00")
+		out, ok := rowsByKey[key]
+		if !ok {
+			out = &pivotRow{index: values, cells: make(map[string]*pivotCell)}
+			rowsByKey[key] = out
+			rows = append(rows, out)
+		}
+
+		c, ok := out.cells[name]
+		if !ok {
+			c = &pivotCell{}
+			if p.Agg != "" {
+				var err error
+				if c.agg, err = expr.NewAggregator(p.Agg); err != nil {
+					return nil, NewTransformError(err.Error())
+				}
+			}
+			out.cells[name] = c
+		}
+
+		value := model.NewNullValue()
+		if valueCol >= 0 {
+			value = cell(row, valueCol)
+		}
+		if c.agg != nil {
+			v := expr.FromModel(value, p.Values)
+			if valueCol < 0 {
+				v = expr.Bool(true) // count counts rows
+			}
+			if err := c.agg.Add(v); err != nil {
+				return nil, NewTransformError(fmt.Sprintf("row %d: %s: %v", r+1, p.Agg, err))
+			}
+			continue
+		}
+		if c.set {
+			return nil, NewTransformError(fmt.Sprintf("row %d: more than one value for %s and %s %q (use agg= to combine them)",
+				r+1, describeIndex(p.Index, out.index), p.Columns, name))
+		}
+		c.value, c.set = value, true
+	}
+
+	out := &model.TableData{Headers: append(slices.Clone(p.Index), columns...)}
+	for _, pr := range rows {
+		row := slices.Clone(pr.index)
+		for _, name := range columns {
+			c, ok := pr.cells[name]
+			switch {
+			case !ok:
+				row = append(row, model.NewNullValue())
+			case c.agg != nil:
+				row = append(row, c.agg.Result().ToModel())
+			default:
+				row = append(row, c.value)
+			}
+		}
+		out.Rows = append(out.Rows, row)
+	}
+	return out, nil
+}
+
+// describeIndex names an output row of a pivot for an error message
+func describeIndex(names []string, values []model.Value) string {
+	parts := make([]string, len(names))
+	for i, name := range names {
+		parts[i] = fmt.Sprintf("%s %q", name, values[i].Raw)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Unpivot turns columns into rows, making a wide table long. Each input
+// row gives one output row per value column, holding the id columns, the
+// value column's name and its value
+type Unpivot struct {
+	IDColumns []string
+	// ValueColumns are unpivoted (nil = every column that is not an id)
+	ValueColumns []string
+	VarName      string
+	ValueName    string
+}
+
+// NewUnpivot creates an Unpivot from a spec such as id_cols=id,name.
+// value_cols limits the columns turned into rows, and var_name and
+// value_name name the new columns (default variable and value)
+func NewUnpivot(spec string) (*Unpivot, error) {
+	opts, err := parseOptions(spec, "id_cols", "value_cols", "var_name", "value_name")
+	if err != nil {
+		return nil, err
+	}
+	u := &Unpivot{IDColumns: opts["id_cols"], ValueColumns: opts["value_cols"], VarName: "variable", ValueName: "value"}
+	if len(u.IDColumns) == 0 && len(u.ValueColumns) == 0 {
+		return nil, fmt.Errorf("id_cols= or value_cols= is required")
+	}
+	if name, err := singleOption(opts, "var_name", false); err != nil {
+		return nil, err
+	} else if name != "" {
+		u.VarName = name
+	}
+	if name, err := singleOption(opts, "value_name", false); err != nil {
+		return nil, err
+	} else if name != "" {
+		u.ValueName = name
+	}
+	if u.VarName == u.ValueName {
+		return nil, fmt.Errorf("var_name and value_name must differ")
+	}
+	return u, nil
+}
+
+// Transform implements Transformer. Empty values are kept, so every input
+// row gives the same number of output rows
+func (u *Unpivot) Transform(data *model.TableData) (*model.TableData, error) {
+	ids := make([]int, len(u.IDColumns))
+	for i, name := range u.IDColumns {
+		if ids[i] = slices.Index(data.Headers, name); ids[i] < 0 {
+			return nil, columnNotFound(name, data.Headers)
+		}
+		if name == u.VarName || name == u.ValueName {
+			return nil, NewTransformError(fmt.Sprintf("id column %q has the name of a new column (set var_name= or value_name=)", name))
+		}
+	}
+
+	var values []int
+	if u.ValueColumns != nil {
+		for _, name := range u.ValueColumns {
+			col := slices.Index(data.Headers, name)
+			if col < 0 {
+				return nil, columnNotFound(name, data.Headers)
+			}
+			values = append(values, col)
+		}
+	} else {
+		for col := range data.Headers {
+			if !slices.Contains(ids, col) {
+				values = append(values, col)
+			}
+		}
+	}
+
+	out := &model.TableData{Headers: append(slices.Clone(u.IDColumns), u.VarName, u.ValueName)}
+	for _, row := range data.Rows {
+		for _, col := range values {
+			line := make([]model.Value, 0, len(out.Headers))
+			for _, id := range ids {
+				line = append(line, cell(row, id))
+			}
+			line = append(line, model.NewStringValue(data.Headers[col]), cell(row, col))
+			out.Rows = append(out.Rows, line)
+		}
+	}
+	return out, nil
+}
+
+// Transpose swaps rows and columns. The first column becomes the header:
+// its name stays the first header and its values name the new columns,
+// while the other headers become the first column
+type Transpose struct{}
+
+// Transform implements Transformer. The first column's values must be
+// unique and not empty, since they name the new columns
+func (Transpose) Transform(data *model.TableData) (*model.TableData, error) {
+	if len(data.Headers) == 0 {
+		return data, nil
+	}
+
+	out := &model.TableData{Headers: make([]string, 0, len(data.Rows)+1)}
+	out.Headers = append(out.Headers, data.Headers[0])
+	seen := map[string]bool{data.Headers[0]: true}
+	for r, row := range data.Rows {
+		name := cell(row, 0).Raw
+		switch {
+		case strings.TrimSpace(name) == "":
+			return nil, NewTransformError(fmt.Sprintf("row %d: %s is empty, so it cannot name a transposed column", r+1, data.Headers[0]))
+		case seen[name]:
+			return nil, NewTransformError(fmt.Sprintf("row %d: %s %q names two transposed columns", r+1, data.Headers[0], name))
+		}
+		seen[name] = true
+		out.Headers = append(out.Headers, name)
+	}
+	for col := 1; col < len(data.Headers); col++ {
+		line := make([]model.Value, 0, len(out.Headers))
+		line = append(line, model.NewStringValue(data.Headers[col]))
+		for _, row := range data.Rows {
+			line = append(line, cell(row, col))
+		}
+		out.Rows = append(out.Rows, line)
+	}
+	return out, nil
+}
+
+// parseOptions parses a comma-separated list of key=value options. An
+// item without = continues the list of the key before it, so
+// index=region,country gives index the values region and country
+func parseOptions(spec string, keys ...string) (map[string][]string, error) {
+	opts := make(map[string][]string)
+	key := ""
+	for _, item := range splitList(spec, ',') {
+		if strings.TrimSpace(item) == "" {
+			continue
+		}
+		parts := splitList(item, '=')
+		switch len(parts) {
+		case 1:
+			if key == "" {
+				return nil, fmt.Errorf("invalid option %q (expected key=value)", unescape(item))
+			}
+		case 2:
+			key = strings.ToLower(strings.TrimSpace(parts[0]))
+			if !slices.Contains(keys, key) {
+				return nil, fmt.Errorf("unknown option %q (expected %s)", key, strings.Join(keys, ", "))
+			}
+			if _, ok := opts[key]; ok {
+				return nil, fmt.Errorf("option %s given twice", key)
+			}
+		default:
+			return nil, fmt.Errorf("invalid option %q (expected key=value)", unescape(item))
+		}
+		value := unescape(strings.TrimSpace(parts[len(parts)-1]))
+		if value == "" {
+			return nil, fmt.Errorf("option %s has an empty value", key)
+		}
+		opts[key] = append(opts[key], value)
+	}
+	return opts, nil
+}
+
+// singleOption returns the one value of an option
+func singleOption(opts map[string][]string, key string, required bool) (string, error) {
+	values := opts[key]
+	switch {
+	case len(values) == 0 && required:
+		return "", fmt.Errorf("%s= is required", key)
+	case len(values) > 1:
+		return "", fmt.Errorf("%s= takes one column", key)
+	case len(values) == 0:
+		return "", nil
+	}
+	return values[0], nil
+}
@@ -0,0 +1,154 @@
+package transform
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestNewPivot(t *testing.T) {
+	p, err := NewPivot("index=region,country,columns=month,values=amount,agg=SUM")
+	if err != nil {
+		t.Fatalf("NewPivot() error = %v", err)
+	}
+	want := &Pivot{Index: []string{"region", "country"}, Columns: "month", Values: "amount", Agg: "sum"}
+	if !reflect.DeepEqual(p, want) {
+		t.Errorf("NewPivot() = %+v, want %+v", p, want)
+	}
+
+	for _, spec := range []string{
+		"columns=month,values=amount",
+		"index=region,values=amount",
+		"index=region,columns=month",
+		"index=region,columns=month,values=amount,agg=median",
+		"index=region,columns=month,year,values=amount",
+		"region,columns=month",
+		"index=region,colums=month",
+	} {
+		if _, err := NewPivot(spec); err == nil {
+			t.Errorf("NewPivot(%q) error = nil, want error", spec)
+		}
+	}
+	if _, err := NewPivot("index=region,columns=month,agg=count"); err != nil {
+		t.Errorf("NewPivot() without values for count: %v", err)
+	}
+}
+
+func TestPivot(t *testing.T) {
+	data := newTable([]string{"region", "month", "amount"},
+		[]string{"north", "jan", "10"},
+		[]string{"south", "jan", "5"},
+		[]string{"north", "feb", "7"},
+		[]string{"north", "jan", "3"},
+		[]string{"south", "", "99"},
+	)
+
+	p, _ := NewPivot("index=region,columns=month,values=amount,agg=sum")
+	got, err := p.Transform(data)
+	if err != nil {
+		t.Fatalf("Transform() error = %v", err)
+	}
+	if want := []string{"region", "jan", "feb"}; !reflect.DeepEqual(got.Headers, want) {
+		t.Errorf("headers = %q, want %q", got.Headers, want)
+	}
+	if want := [][]string{{"north", "13", "7"}, {"south", "5", ""}}; !reflect.DeepEqual(cells(got), want) {
+		t.Errorf("rows = %q, want %q", cells(got), want)
+	}
+
+	p, _ = NewPivot("index=region,columns=month,agg=count")
+	got, err = p.Transform(data)
+	if err != nil {
+		t.Fatalf("Transform() error = %v", err)
+	}
+	if want := [][]string{{"north", "2", "1"}, {"south", "1", ""}}; !reflect.DeepEqual(cells(got), want) {
+		t.Errorf("count rows = %q, want %q", cells(got), want)
+	}
+
+	p, _ = NewPivot("index=region,columns=month,values=amount")
+	if _, err := p.Transform(data); err == nil || !strings.Contains(err.Error(), `row 4: more than one value for region "north" and month "jan"`) {
+		t.Errorf("Transform() error = %v, want a duplicate cell", err)
+	}
+}
+
+func TestUnpivot(t *testing.T) {
+	data := newTable([]string{"id", "name", "q1", "q2"},
+		[]string{"1", "tea", "10", ""},
+		[]string{"2", "jam", "3", "4"},
+	)
+
+	u, err := NewUnpivot("id_cols=id,name")
+	if err != nil {
+		t.Fatalf("NewUnpivot() error = %v", err)
+	}
+	got, err := u.Transform(data)
+	if err != nil {
+		t.Fatalf("Transform() error = %v", err)
+	}
+	if want := []string{"id", "name", "variable", "value"}; !reflect.DeepEqual(got.Headers, want) {
+		t.Errorf("headers = %q, want %q", got.Headers, want)
+	}
+	want := [][]string{
+		{"1", "tea", "q1", "10"},
+		{"1", "tea", "q2", ""},
+		{"2", "jam", "q1", "3"},
+		{"2", "jam", "q2", "4"},
+	}
+	if !reflect.DeepEqual(cells(got), want) {
+		t.Errorf("rows = %q, want %q", cells(got), want)
+	}
+
+	u, _ = NewUnpivot("id_cols=id,value_cols=q2,var_name=quarter,value_name=sales")
+	got, err = u.Transform(newTable([]string{"id", "name", "q1", "q2"}, []string{"1", "tea", "10", "11"}))
+	if err != nil {
+		t.Fatalf("Transform() error = %v", err)
+	}
+	if want := []string{"id", "quarter", "sales"}; !reflect.DeepEqual(got.Headers, want) {
+		t.Errorf("headers = %q, want %q", got.Headers, want)
+	}
+	if want := [][]string{{"1", "q2", "11"}}; !reflect.DeepEqual(cells(got), want) {
+		t.Errorf("rows = %q, want %q", cells(got), want)
+	}
+
+	u, _ = NewUnpivot("id_cols=value")
+	if _, err := u.Transform(newTable([]string{"value", "x"})); err == nil {
+		t.Error("Transform() error = nil for an id column named value")
+	}
+}
+
+func TestTranspose(t *testing.T) {
+	data := newTable([]string{"metric", "jan", "feb"},
+		[]string{"sales", "10", "12"},
+		[]string{"costs", "4", ""},
+	)
+	got, err := Transpose{}.Transform(data)
+	if err != nil {
+		t.Fatalf("Transform() error = %v", err)
+	}
+	if want := []string{"metric", "sales", "costs"}; !reflect.DeepEqual(got.Headers, want) {
+		t.Errorf("headers = %q, want %q", got.Headers, want)
+	}
+	if want := [][]string{{"jan", "10", "4"}, {"feb", "12", ""}}; !reflect.DeepEqual(cells(got), want) {
+		t.Errorf("rows = %q, want %q", cells(got), want)
+	}
+
+	// Transposing twice gives back the table
+	back, _ := Transpose{}.Transform(got)
+	if !reflect.DeepEqual(back.Headers, data.Headers) || !reflect.DeepEqual(cells(back), cells(data)) {
+		t.Errorf("transposed twice = %q %q, want %q %q", back.Headers, cells(back), data.Headers, cells(data))
+	}
+
+	// The first column names the new columns, so its values must be unique
+	for _, tt := range []struct {
+		rows [][]string
+		want string
+	}{
+		{[][]string{{"x", "1"}, {"x", "2"}}, `row 2: k "x" names two transposed columns`},
+		{[][]string{{"k", "1"}}, `row 1: k "k" names two transposed columns`},
+		{[][]string{{"x", "1"}, {"", "2"}}, "row 2: k is empty"},
+	} {
+		_, err := Transpose{}.Transform(newTable([]string{"k", "v"}, tt.rows...))
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("Transform(%q) error = %v, want %q", tt.rows, err, tt.want)
+		}
+	}
+}
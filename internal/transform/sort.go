@@ -0,0 +1,260 @@
+package transform
+
+import (
+	"bytes"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/text/collate"
+	"golang.org/x/text/language"
+
+	"github.com/user/table-converter/internal/expr"
+	"github.com/user/table-converter/internal/model"
+)
+
+// SortKey is a column to sort by and its direction
+type SortKey struct {
+	Column     string
+	Descending bool
+}
+
+// Sort orders rows by one or more columns. The sort is stable, so rows
+// with equal keys keep their input order
+type Sort struct {
+	Keys []SortKey
+	// Collator orders text by the rules of a language (nil = by code point)
+	Collator *collate.Collator
+}
+
+// NewSort creates a Sort from a comma-separated list of column[:asc|:desc]
+// keys, such as "amount:desc,name". locale is a BCP 47 language tag such
+// as de or sv for ordering text ("" = by code point)
+func NewSort(spec, locale string) (*Sort, error) {
+	var keys []SortKey
+	for _, item := range splitList(spec, ',') {
+		if strings.TrimSpace(item) == "" {
+			continue
+		}
+		key := SortKey{Column: strings.TrimSpace(item)}
+		parts := splitList(item, ':')
+		if len(parts) > 1 {
+			last := strings.ToLower(strings.TrimSpace(parts[len(parts)-1]))
+			switch last {
+			case "asc", "ascending", "desc", "descending":
+				key.Column = strings.TrimSpace(strings.Join(parts[:len(parts)-1], ":"))
+				key.Descending = strings.HasPrefix(last, "desc")
+			default:
+				return nil, fmt.Errorf("invalid sort direction %q in %q (expected asc or desc)", last, unescape(item))
+			}
+		}
+		key.Column = unescape(key.Column)
+		if key.Column == "" {
+			return nil, fmt.Errorf("column name must not be empty")
+		}
+		keys = append(keys, key)
+	}
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("no columns given")
+	}
+
+	s := &Sort{Keys: keys}
+	if locale != "" {
+		tag, err := language.Parse(locale)
+		if err != nil {
+			return nil, fmt.Errorf("invalid locale %q: %v", locale, err)
+		}
+		s.Collator = collate.New(tag)
+	}
+	return s, nil
+}
+
+// sortKind is how the values of a sort column are compared
+type sortKind int
+
+const (
+	sortText sortKind = iota
+	sortNumber
+	sortDate
+	sortBool
+)
+
+// sortValue is a cell prepared for comparison
+type sortValue struct {
+	null bool
+	num  float64
+	time time.Time
+	text []byte // text, or its collation key
+}
+
+// Transform implements Transformer
+func (s *Sort) Transform(data *model.TableData) (*model.TableData, error) {
+	columns := make([]int, len(s.Keys))
+	for i, key := range s.Keys {
+		col := slices.Index(data.Headers, key.Column)
+		if col < 0 {
+			return nil, columnNotFound(key.Column, data.Headers)
+		}
+		columns[i] = col
+	}
+
+	// Convert each key cell once, rather than on every comparison
+	type keyedRow struct {
+		row  []model.Value
+		keys []sortValue
+	}
+	rows := make([]keyedRow, len(data.Rows))
+	for i, row := range data.Rows {
+		rows[i] = keyedRow{row: row, keys: make([]sortValue, len(columns))}
+	}
+	kinds := make([]sortKind, len(columns))
+	var buf collate.Buffer
+	for k, col := range columns {
+		kinds[k] = columnSortKind(data.Rows, col)
+		for i := range rows {
+			rows[i].keys[k] = s.sortValue(cell(rows[i].row, col), kinds[k], &buf)
+		}
+	}
+
+	slices.SortStableFunc(rows, func(a, b keyedRow) int {
+		for k, key := range s.Keys {
+			if c := compareSortValues(a.keys[k], b.keys[k], kinds[k], key.Descending); c != 0 {
+				return c
+			}
+		}
+		return 0
+	})
+
+	for i := range rows {
+		data.Rows[i] = rows[i].row
+	}
+	return data, nil
+}
+
+// cell returns a cell of a row, or null if the row is short
+func cell(row []model.Value, col int) model.Value {
+	if col < len(row) {
+		return row[col]
+	}
+	return model.NewNullValue()
+}
+
+// columnSortKind picks how a column is compared from its values. A column
+// is numeric if every non-empty cell is a number, a date column if every
+// cell is an ISO 8601 date, and boolean if every cell is true or false;
+// anything else is compared as text
+func columnSortKind(rows [][]model.Value, col int) sortKind {
+	numbers, dates, bools := true, true, true
+	for _, row := range rows {
+		v := cell(row, col)
+		if v.Type == model.TypeNull {
+			continue
+		}
+		if v.Type != model.TypeNumber {
+			if _, err := strconv.ParseFloat(strings.TrimSpace(v.Raw), 64); err != nil {
+				numbers = false
+			}
+		}
+		if dates {
+			if _, ok := expr.ParseDate(v.Raw); !ok {
+				dates = false
+			}
+		}
+		if bools {
+			if _, ok := parseBool(v); !ok {
+				bools = false
+			}
+		}
+		if !numbers && !dates && !bools {
+			return sortText
+		}
+	}
+	switch {
+	case numbers:
+		return sortNumber
+	case dates:
+		return sortDate
+	case bools:
+		return sortBool
+	}
+	return sortText
+}
+
+// parseBool reads a boolean cell: a typed boolean, or true, false, yes or no
+func parseBool(v model.Value) (bool, bool) {
+	if v.Type == model.TypeBoolean {
+		if b, ok := v.Parsed.(bool); ok {
+			return b, true
+		}
+	}
+	switch strings.ToLower(strings.TrimSpace(v.Raw)) {
+	case "true", "yes":
+		return true, true
+	case "false", "no":
+		return false, true
+	}
+	return false, false
+}
+
+// sortValue prepares a cell for comparison as the given kind
+func (s *Sort) sortValue(v model.Value, kind sortKind, buf *collate.Buffer) sortValue {
+	if v.Type == model.TypeNull {
+		return sortValue{null: true}
+	}
+	switch kind {
+	case sortNumber:
+		if n, ok := v.Parsed.(float64); ok && v.Type == model.TypeNumber {
+			return sortValue{num: n}
+		}
+		n, _ := strconv.ParseFloat(strings.TrimSpace(v.Raw), 64)
+		return sortValue{num: n}
+	case sortDate:
+		t, _ := expr.ParseDate(v.Raw)
+		return sortValue{time: t}
+	case sortBool:
+		if b, _ := parseBool(v); b {
+			return sortValue{num: 1}
+		}
+		return sortValue{num: 0}
+	}
+	if s.Collator != nil {
+		key := slices.Clone(s.Collator.KeyFromString(buf, v.Raw))
+		buf.Reset()
+		return sortValue{text: key}
+	}
+	return sortValue{text: []byte(v.Raw)}
+}
+
+// compareSortValues orders two prepared cells. Nulls sort last in either
+// direction
+func compareSortValues(a, b sortValue, kind sortKind, descending bool) int {
+	switch {
+	case a.null && b.null:
+		return 0
+	case a.null:
+		return 1
+	case b.null:
+		return -1
+	}
+
+	var c int
+	switch kind {
+	case sortNumber, sortBool:
+		switch {
+		case a.num < b.num:
+			c = -1
+		case a.num > b.num:
+			c = 1
+		}
+	case sortDate:
+		c = a.time.Compare(b.time)
+	default:
+		c = bytes.Compare(a.text, b.text)
+	}
+	if descending {
+		return -c
+	}
+	return c
+}
@@ -0,0 +1,97 @@
+package transform
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/user/table-converter/internal/model"
+)
+
+// column returns the raw cells of one column
+func column(data *model.TableData, col int) []string {
+	out := make([]string, len(data.Rows))
+	for i, row := range data.Rows {
+		out[i] = row[col].Raw
+	}
+	return out
+}
+
+func TestSort(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    string
+		locale  string
+		headers []string
+		rows    [][]string
+		col     int
+		want    []string
+	}{
+		{"numbers", "n", "", []string{"n"},
+			[][]string{{"10"}, {"9"}, {"-1.5"}, {"100"}}, 0, []string{"-1.5", "9", "10", "100"}},
+		{"numbers descending, nulls last", "n:desc", "", []string{"n"},
+			[][]string{{"10"}, {""}, {"9"}, {"100"}}, 0, []string{"100", "10", "9", ""}},
+		{"nulls last ascending", "n", "", []string{"n"},
+			[][]string{{""}, {"2"}, {"1"}}, 0, []string{"1", "2", ""}},
+		{"dates", "d", "", []string{"d"},
+			[][]string{{"2024-03-01"}, {"2023-12-31T23:00:00Z"}, {"2024-01-15"}}, 0, []string{"2023-12-31T23:00:00Z", "2024-01-15", "2024-03-01"}},
+		{"booleans", "b", "", []string{"b"},
+			[][]string{{"true"}, {"no"}, {"false"}}, 0, []string{"no", "false", "true"}},
+		{"text", "s", "", []string{"s"},
+			[][]string{{"b"}, {"B"}, {"a"}, {"10"}}, 0, []string{"10", "B", "a", "b"}},
+		{"locale", "s", "de", []string{"s"},
+			[][]string{{"Zebra"}, {"Äpfel"}, {"apfel"}, {"Birne"}}, 0, []string{"apfel", "Äpfel", "Birne", "Zebra"}},
+		{"stable multi-key", "group,n:desc", "", []string{"group", "n", "id"},
+			[][]string{{"b", "1", "1"}, {"a", "1", "2"}, {"b", "2", "3"}, {"a", "1", "4"}, {"a", "3", "5"}}, 2,
+			[]string{"5", "2", "4", "3", "1"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := NewSort(tt.spec, tt.locale)
+			if err != nil {
+				t.Fatalf("NewSort() error = %v", err)
+			}
+			got, err := s.Transform(newTable(tt.headers, tt.rows...))
+			if err != nil {
+				t.Fatalf("Transform() error = %v", err)
+			}
+			if c := column(got, tt.col); !reflect.DeepEqual(c, tt.want) {
+				t.Errorf("sorted = %q, want %q", c, tt.want)
+			}
+		})
+	}
+}
+
+func TestSortTypedValues(t *testing.T) {
+	data := model.NewTableData([]string{"n"}, [][]model.Value{
+		{model.NewNumberValue(10)}, {model.NewNumberValue(2)}, {model.NewNullValue()}, {model.NewNumberValue(-3)},
+	})
+	s, _ := NewSort("n", "")
+	got, err := s.Transform(data)
+	if err != nil {
+		t.Fatalf("Transform() error = %v", err)
+	}
+	if c := column(got, 0); !reflect.DeepEqual(c, []string{"-3", "2", "10", ""}) {
+		t.Errorf("sorted = %q", c)
+	}
+}
+
+func TestNewSortErrors(t *testing.T) {
+	for _, tt := range []struct{ spec, locale string }{
+		{"", ""},
+		{"a:up", ""},
+		{":desc", ""},
+		{"a", "not a locale!"},
+	} {
+		if _, err := NewSort(tt.spec, tt.locale); err == nil {
+			t.Errorf("NewSort(%q, %q) error = nil", tt.spec, tt.locale)
+		}
+	}
+
+	s, _ := NewSort(`a\:b:desc`, "")
+	if want := []SortKey{{Column: "a:b", Descending: true}}; !reflect.DeepEqual(s.Keys, want) {
+		t.Errorf("Keys = %+v, want %+v", s.Keys, want)
+	}
+	if _, err := s.Transform(newTable([]string{"a"})); err == nil {
+		t.Error("Transform() error = nil for a missing column")
+	}
+}
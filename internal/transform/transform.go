@@ -0,0 +1,96 @@
+// Package transform reshapes parsed tables between parsing and
+// serialization, independently of the input and output formats
+package transform
+
+import (
+	"fmt"
+
+	"github.com/user/table-converter/internal/model"
+)
+
+// Transformer changes a parsed table. It may modify the table in place or
+// return a new one
+type Transformer interface {
+	Transform(data *model.TableData) (*model.TableData, error)
+}
+
+// Pipeline applies transformers in order
+type Pipeline []Transformer
+
+// Transform passes the table through each transformer in turn
+func (p Pipeline) Transform(data *model.TableData) (*model.TableData, error) {
+	var err error
+	for _, t := range p {
+		if data, err = t.Transform(data); err != nil {
+			return nil, err
+		}
+	}
+	return data, nil
+}
+
+// TransformError represents an error that occurred while transforming a table
+type TransformError struct {
+	// Message describes what went wrong
+	Message string
+	// Err is the underlying error if any
+	Err error
+}
+
+// Error implements the error interface
+func (e *TransformError) Error() string {
+	msg := fmt.Sprintf("transform error: %s", e.Message)
+	if e.Err != nil {
+		msg += fmt.Sprintf("\n  Caused by: %v", e.Err)
+	}
+	return msg
+}
+
+// Unwrap returns the underlying error
+func (e *TransformError) Unwrap() error {
+	return e.Err
+}
+
+// NewTransformError creates a new TransformError with the given message
+func NewTransformError(message string) *TransformError {
+	return &TransformError{
+		Message: message,
+	}
+}
+
+// WithErr wraps an underlying error
+func (e *TransformError) WithErr(err error) *TransformError {
+	e.Err = err
+	return e
+}
+
+// pickColumns returns a table with the given columns of data, in the given
+// order, keeping their alignments
+func pickColumns(data *model.TableData, columns []int) *model.TableData {
+	out := &model.TableData{
+		Headers: make([]string, len(columns)),
+		Rows:    make([][]model.Value, len(data.Rows)),
+	}
+	for i, col := range columns {
+		out.Headers[i] = data.Headers[col]
+	}
+	if data.Alignments != nil {
+		out.Alignments = make([]model.Alignment, len(columns))
+		for i, col := range columns {
+			if col < len(data.Alignments) {
+				out.Alignments[i] = data.Alignments[col]
+			}
+		}
+	}
+	for r, row := range data.Rows {
+		picked := make([]model.Value, len(columns))
+		for i, col := range columns {
+			if col < len(row) {
+				picked[i] = row[col]
+			} else {
+				picked[i] = model.NewNullValue()
+			}
+		}
+		out.Rows[r] = picked
+	}
+	return out
+}
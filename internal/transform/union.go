@@ -0,0 +1,121 @@
+package transform
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/user/table-converter/internal/model"
+)
+
+// UnionInput is a table to stack and the name of its source, such as the
+// file it was read from
+type UnionInput struct {
+	Source string
+	Data   *model.TableData
+}
+
+// Union stacks the rows of several tables into one. Columns are matched by
+// name: the result has every column of every input, in the order they
+// first appear, and rows get nulls for the columns their input lacks
+type Union struct {
+	// SourceColumn names a column added first that holds each row's
+	// source ("" = none)
+	SourceColumn string
+}
+
+// TypeConflict reports a column whose values have a different type in
+// different inputs, such as numbers in one file and text in another
+type TypeConflict struct {
+	Column string
+	// Types lists each input that has values in the column, with their type
+	Types []SourceType
+}
+
+// SourceType is the type of a column's values in one input
+type SourceType struct {
+	Source string
+	Type   string // number, date, boolean or text
+}
+
+// String describes the conflict
+func (c TypeConflict) String() string {
+	parts := make([]string, len(c.Types))
+	for i, t := range c.Types {
+		parts[i] = fmt.Sprintf("%s in %s", t.Type, t.Source)
+	}
+	return fmt.Sprintf("column %q has mixed types: %s", c.Column, strings.Join(parts, ", "))
+}
+
+// Union stacks the inputs and reports the columns whose type differs
+// between them. Empty columns have no type and never conflict
+func (u *Union) Union(inputs []UnionInput) (*model.TableData, []TypeConflict, error) {
+	var headers []string
+	position := make(map[string]int)
+	if u.SourceColumn != "" {
+		headers = append(headers, u.SourceColumn)
+		position[u.SourceColumn] = 0
+	}
+
+	for _, in := range inputs {
+		for _, h := range in.Data.Headers {
+			if h == u.SourceColumn {
+				return nil, nil, NewTransformError(fmt.Sprintf("%s already has a column %q (choose another source column name)", in.Source, h))
+			}
+			if _, ok := position[h]; !ok {
+				position[h] = len(headers)
+				headers = append(headers, h)
+			}
+		}
+	}
+
+	var rows [][]model.Value
+	types := make(map[string][]SourceType)
+	for _, in := range inputs {
+		for c, h := range in.Data.Headers {
+			if kind, ok := columnType(in.Data.Rows, c); ok {
+				types[h] = append(types[h], SourceType{Source: in.Source, Type: kind})
+			}
+		}
+
+		for _, row := range in.Data.Rows {
+			out := make([]model.Value, len(headers))
+			for i := range out {
+				out[i] = model.NewNullValue()
+			}
+			if u.SourceColumn != "" {
+				out[0] = model.NewStringValue(in.Source)
+			}
+			for c, h := range in.Data.Headers {
+				out[position[h]] = cell(row, c)
+			}
+			rows = append(rows, out)
+		}
+	}
+
+	var conflicts []TypeConflict
+	for _, h := range headers {
+		t := types[h]
+		if slices.ContainsFunc(t, func(st SourceType) bool { return st.Type != t[0].Type }) {
+			conflicts = append(conflicts, TypeConflict{Column: h, Types: t})
+		}
+	}
+	return model.NewTableData(headers, rows), conflicts, nil
+}
+
+// columnType names the type of a column's values as sorting infers it. ok
+// is false when the column has no values
+func columnType(rows [][]model.Value, col int) (string, bool) {
+	if !slices.ContainsFunc(rows, func(row []model.Value) bool { return cell(row, col).Type != model.TypeNull }) {
+		return "", false
+	}
+	switch columnSortKind(rows, col) {
+	case sortNumber:
+		return "number", true
+	case sortDate:
+		return "date", true
+	case sortBool:
+		return "boolean", true
+	}
+	return "text", true
+}
@@ -0,0 +1,70 @@
+package transform
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/user/table-converter/internal/model"
+)
+
+func TestUnion(t *testing.T) {
+	jan := newTable([]string{"date", "amount"},
+		[]string{"2024-01-02", "10"},
+		[]string{"2024-01-09", "12.5"},
+	)
+	feb := model.NewTableData([]string{"amount", "region"}, [][]model.Value{
+		{model.NewNumberValue(7), model.NewStringValue("north")},
+	})
+	mar := newTable([]string{"date", "amount", "region"},
+		[]string{"2024-03-01", "n/a", "south"},
+	)
+
+	u := &Union{SourceColumn: "source_file"}
+	got, conflicts, err := u.Union([]UnionInput{{"jan.csv", jan}, {"feb.xlsx", feb}, {"mar.json", mar}})
+	if err != nil {
+		t.Fatalf("Union() error = %v", err)
+	}
+	if want := []string{"source_file", "date", "amount", "region"}; !reflect.DeepEqual(got.Headers, want) {
+		t.Errorf("headers = %q, want %q", got.Headers, want)
+	}
+	want := [][]string{
+		{"jan.csv", "2024-01-02", "10", ""},
+		{"jan.csv", "2024-01-09", "12.5", ""},
+		{"feb.xlsx", "", "7", "north"},
+		{"mar.json", "2024-03-01", "n/a", "south"},
+	}
+	if !reflect.DeepEqual(cells(got), want) {
+		t.Errorf("rows = %q, want %q", cells(got), want)
+	}
+
+	if len(conflicts) != 1 {
+		t.Fatalf("conflicts = %v, want one", conflicts)
+	}
+	if want := `column "amount" has mixed types: number in jan.csv, number in feb.xlsx, text in mar.json`; conflicts[0].String() != want {
+		t.Errorf("conflict = %q, want %q", conflicts[0], want)
+	}
+}
+
+func TestUnionWithoutSource(t *testing.T) {
+	a := newTable([]string{"x"}, []string{"1"})
+	b := newTable([]string{"y", "x"}, []string{"", "2"})
+	got, conflicts, err := (&Union{}).Union([]UnionInput{{"a", a}, {"b", b}})
+	if err != nil {
+		t.Fatalf("Union() error = %v", err)
+	}
+	if want := [][]string{{"1", ""}, {"2", ""}}; !reflect.DeepEqual(cells(got), want) {
+		t.Errorf("rows = %q, want %q", cells(got), want)
+	}
+	if len(conflicts) != 0 {
+		t.Errorf("conflicts = %v, want none for an empty column", conflicts)
+	}
+}
+
+func TestUnionSourceColumnClash(t *testing.T) {
+	a := newTable([]string{"source"}, []string{"x"})
+	_, _, err := (&Union{SourceColumn: "source"}).Union([]UnionInput{{"a.csv", a}})
+	if err == nil || !strings.Contains(err.Error(), `a.csv already has a column "source"`) {
+		t.Errorf("Union() error = %v, want a clash", err)
+	}
+}
@@ -0,0 +1,44 @@
+package transform
+
+import (
+	"fmt"
+
+	"github.com/user/table-converter/internal/expr"
+	"github.com/user/table-converter/internal/model"
+)
+
+// Where keeps the rows for which a condition is true
+type Where struct {
+	Condition *expr.Expr
+}
+
+// NewWhere creates a Where from a condition such as
+// status == "active" && amount > 100
+func NewWhere(condition string) (*Where, error) {
+	e, err := expr.Parse(condition)
+	if err != nil {
+		return nil, err
+	}
+	return &Where{Condition: e}, nil
+}
+
+// Transform implements Transformer
+func (w *Where) Transform(data *model.TableData) (*model.TableData, error) {
+	cond, err := w.Condition.Bind(data.Headers)
+	if err != nil {
+		return nil, NewTransformError(err.Error())
+	}
+
+	kept := data.Rows[:0]
+	for i, row := range data.Rows {
+		ok, err := cond.Test(row)
+		if err != nil {
+			return nil, NewTransformError(fmt.Sprintf("row %d: %v", i+1, err))
+		}
+		if ok {
+			kept = append(kept, row)
+		}
+	}
+	data.Rows = kept
+	return data, nil
+}
@@ -0,0 +1,51 @@
+package transform
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestWhere(t *testing.T) {
+	data := newTable([]string{"status", "amount"},
+		[]string{"active", "150"},
+		[]string{"active", "50"},
+		[]string{"closed", "500"},
+		[]string{"active", ""},
+	)
+	w, err := NewWhere(`status == "active" && amount > 100`)
+	if err != nil {
+		t.Fatalf("NewWhere() error = %v", err)
+	}
+	got, err := w.Transform(data)
+	if err != nil {
+		t.Fatalf("Transform() error = %v", err)
+	}
+	if want := [][]string{{"active", "150"}}; !reflect.DeepEqual(cells(got), want) {
+		t.Errorf("rows = %q, want %q", cells(got), want)
+	}
+
+	if _, err := NewWhere(`amount >`); err == nil {
+		t.Error("NewWhere() error = nil for a syntax error")
+	}
+}
+
+func TestWhereErrors(t *testing.T) {
+	tests := []struct {
+		condition string
+		want      string
+	}{
+		{`amout > 1`, `unknown column "amout"`},
+		{`amount > 1`, `row 2: cannot compare with a number: column "amount" ("n/a") is not a number`},
+	}
+	for _, tt := range tests {
+		w, err := NewWhere(tt.condition)
+		if err != nil {
+			t.Fatalf("NewWhere(%q) error = %v", tt.condition, err)
+		}
+		_, err = w.Transform(newTable([]string{"amount"}, []string{"5"}, []string{"n/a"}))
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("Transform(%q) error = %v, want %q", tt.condition, err, tt.want)
+		}
+	}
+}